@@ -30,9 +30,11 @@ const (
 	ColumnOp               = "op"
 	ColumnData             = "data"
 	ColumnIdentityId       = "identity_id"
+	ColumnImpersonatorId   = "impersonator_id"
 	ColumnTraceId          = "trace_id"
 	ColumnCreatedAt        = "created_at"
 	ColumnEventProcessedAt = "event_processed_at"
+	ColumnTransactionId    = "transaction_id"
 )
 
 type AuditLog struct {
@@ -40,8 +42,24 @@ type AuditLog struct {
 	TableName        string
 	Op               string
 	Data             map[string]any
+	IdentityId       string
 	CreatedAt        time.Time
 	EventProcessedAt time.Time
+	TransactionId    int64
+}
+
+// Cursor is a position in the audit trail, after which logs can be read in the order their transactions were written.
+type Cursor struct {
+	TransactionId int64
+	Id            string
+}
+
+// Cursor returns the position of this log in the audit trail.
+func (log *AuditLog) Cursor() *Cursor {
+	return &Cursor{
+		TransactionId: log.TransactionId,
+		Id:            log.Id,
+	}
 }
 
 // Previous returns the previous log entry for the given data row.
@@ -65,6 +83,82 @@ func Previous(ctx context.Context, log *AuditLog) (*AuditLog, error) {
 	return fromRow(result.Rows[0])
 }
 
+// LatestCursor returns the position in the audit trail before the logs of any transaction which is still in
+// progress, so that the logs written from now on are read after it. The logs of transactions which completed
+// just beforehand may also be read after it.
+func LatestCursor(ctx context.Context) (*Cursor, error) {
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := database.ExecuteQuery(ctx, "SELECT txid_snapshot_xmin(txid_current_snapshot()) AS xmin")
+	if err != nil {
+		return nil, err
+	}
+
+	xmin, ok := typed.New(result.Rows[0]).IntIf("xmin")
+	if !ok {
+		return nil, errors.New("current transaction snapshot cannot be parsed")
+	}
+
+	// Any log of the oldest transaction in progress is after a cursor with an empty id
+	return &Cursor{TransactionId: int64(xmin)}, nil
+}
+
+// CursorAt returns the position of the log with the given id in the audit trail, or nil if there is no such log.
+func CursorAt(ctx context.Context, id string) (*Cursor, error) {
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf("SELECT * FROM %s WHERE %s = ?", TableName, ColumnId)
+
+	result, err := database.ExecuteQuery(ctx, sql, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Rows) != 1 {
+		return nil, nil
+	}
+
+	log, err := fromRow(result.Rows[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return log.Cursor(), nil
+}
+
+// LogsAfter returns up to limit logs for the given tables which are after the cursor, in the order their
+// transactions were written. Unlike ProcessEventsFromAuditTrail, the logs are not marked as processed.
+func LogsAfter(ctx context.Context, tableNames []string, cursor *Cursor, limit int) ([]*AuditLog, error) {
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sql, args := logsAfterSql(tableNames, cursor, limit)
+
+	result, err := database.ExecuteQuery(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	auditLogs := []*AuditLog{}
+	for _, row := range result.Rows {
+		log, err := fromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		auditLogs = append(auditLogs, log)
+	}
+
+	return auditLogs, nil
+}
+
 // ProcessEventsFromAuditTrail inspects the audit table for logs which need to be
 // turned into events, updates their event_processed_at column, and then returns them.
 func ProcessEventsFromAuditTrail(ctx context.Context, schema *proto.Schema, traceId string) ([]*AuditLog, error) {
@@ -125,14 +219,27 @@ func fromRow(row map[string]any) (*AuditLog, error) {
 	}
 
 	return &AuditLog{
-		Id:        id,
-		TableName: tableName,
-		Op:        op,
-		Data:      data,
-		CreatedAt: createdAt,
+		Id:            id,
+		TableName:     tableName,
+		Op:            op,
+		Data:          data,
+		IdentityId:    audit.String(ColumnIdentityId),
+		CreatedAt:     createdAt,
+		TransactionId: int64(audit.Int(ColumnTransactionId)),
 	}, nil
 }
 
+// logsAfterSql generates SQL which returns the logs for the given tables which are after the cursor, ordered by
+// the transaction which wrote them and then by their id. Only the logs of transactions older than any transaction
+// still in progress are returned, so that a transaction which commits late cannot write logs behind the cursor.
+func logsAfterSql(tableNames []string, cursor *Cursor, limit int) (string, []any) {
+	sql := fmt.Sprintf(
+		"SELECT * FROM %s WHERE %s = ANY(?) AND (%s, %s) > (?, ?) AND %s < txid_snapshot_xmin(txid_current_snapshot()) ORDER BY %s, %s LIMIT ?",
+		TableName, ColumnTableName, ColumnTransactionId, ColumnId, ColumnTransactionId, ColumnTransactionId, ColumnId)
+
+	return sql, []any{tableNames, cursor.TransactionId, cursor.Id, limit}
+}
+
 // processEventsSql generates SQL which updates and returns the relevant audit log
 // entries which are to be turned into events.
 func processEventsSql(schema *proto.Schema, traceId string) (string, []any, error) {
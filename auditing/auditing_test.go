@@ -297,3 +297,11 @@ func TestProcessEventSqlWithMultipleModels(t *testing.T) {
 func clean(sql string) string {
 	return strings.Join(strings.Fields(strings.TrimSpace(sql)), " ")
 }
+
+func TestLogsAfterSql(t *testing.T) {
+	t.Parallel()
+	sql, args := logsAfterSql([]string{"person", "company_employee"}, &Cursor{TransactionId: 1042, Id: "2abc"}, 100)
+
+	require.Equal(t, "SELECT * FROM keel_audit WHERE table_name = ANY(?) AND (transaction_id, id) > (?, ?) AND transaction_id < txid_snapshot_xmin(txid_current_snapshot()) ORDER BY transaction_id, id LIMIT ?", sql)
+	require.Equal(t, []any{[]string{"person", "company_employee"}, int64(1042), "2abc", 100}, args)
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/teamkeel/keel/cmd/program"
+)
+
+var flagClientRoles []string
+var flagExportOutput string
+
+// authCmd represents the auth command
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage authentication for your Keel App",
+	Run: func(cmd *cobra.Command, args []string) {
+		// list subcommands
+		_ = cmd.Help()
+	},
+}
+
+// authClientsCmd represents the auth clients command
+var authClientsCmd = &cobra.Command{
+	Use:   "clients",
+	Short: "Manage service clients for your Keel App",
+	Long: `The clients command allows you to manage the service clients
+of your Keel App running locally. Service clients are used for
+machine-to-machine access and authenticate using the client_credentials
+grant with a client id and secret.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// list subcommands
+		_ = cmd.Help()
+	},
+}
+
+// authIdentityCmd represents the auth identity command
+var authIdentityCmd = &cobra.Command{
+	Use:   "identity",
+	Short: "Manage the data of identities in your Keel App",
+	Long: `The identity command allows you to export and erase the data of an
+identity in your Keel App running locally, for example to fulfil a data
+subject access or erasure request.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// list subcommands
+		_ = cmd.Help()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authClientsCmd)
+	authClientsCmd.AddCommand(authClientsCreateCmd)
+	authClientsCmd.AddCommand(authClientsListCmd)
+	authClientsCmd.AddCommand(authClientsDeleteCmd)
+	authCmd.AddCommand(authIdentityCmd)
+	authIdentityCmd.AddCommand(authIdentityExportCmd)
+	authIdentityCmd.AddCommand(authIdentityEraseCmd)
+
+	authClientsCreateCmd.Flags().StringSliceVar(&flagClientRoles, "roles", []string{}, "roles which the client can be granted, e.g. --roles Admin,Staff")
+	authIdentityExportCmd.Flags().StringVarP(&flagExportOutput, "output", "o", "", "file to write the export to, otherwise it is written to stdout")
+}
+
+var authClientsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a service client for your Keel App",
+	Long: `The create command will create a service client with the provided
+roles. The client secret is only displayed once and cannot be retrieved again.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, secret, err := program.CreateServiceClient(flagProjectDir, args[0], flagClientRoles)
+		if err != nil {
+			return program.RenderError(err)
+		}
+
+		program.RenderSuccess(fmt.Sprintf("Service client %s created", client.Name))
+		fmt.Printf("Client ID:     %s\n", client.ClientId)
+		fmt.Printf("Client secret: %s\n", secret)
+
+		return nil
+	},
+}
+
+var authClientsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all service clients for your Keel App",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clients, err := program.ListServiceClients(flagProjectDir)
+		if err != nil {
+			return program.RenderError(err)
+		}
+		if len(clients) == 0 {
+			return program.RenderError(errors.New("No service clients found"))
+		}
+
+		fmt.Println(program.RenderServiceClients(clients))
+
+		return nil
+	},
+}
+
+var authClientsDeleteCmd = &cobra.Command{
+	Use:   "delete <client-id>",
+	Short: "Delete a service client for your Keel App",
+	Long: `The delete command will delete a service client. Its credentials and
+any access tokens already issued to it will no longer be accepted.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := program.DeleteServiceClient(flagProjectDir, args[0])
+		if err != nil {
+			return program.RenderError(err)
+		}
+
+		program.RenderSuccess(fmt.Sprintf("Service client %s deleted", args[0]))
+
+		return nil
+	},
+}
+
+var authIdentityExportCmd = &cobra.Command{
+	Use:   "export <identity-id>",
+	Short: "Export the data of an identity in your Keel App",
+	Long: `The export command will export the identity and every record which
+relates to it as JSON. Records are found by following the relationships
+of each model back to the identity.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		export, err := program.ExportIdentityData(flagProjectDir, args[0])
+		if err != nil {
+			return program.RenderError(err)
+		}
+
+		b, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			return program.RenderError(err)
+		}
+
+		if flagExportOutput == "" {
+			fmt.Println(string(b))
+			return nil
+		}
+
+		err = os.WriteFile(flagExportOutput, b, 0644)
+		if err != nil {
+			return program.RenderError(err)
+		}
+
+		program.RenderSuccess(fmt.Sprintf("Data of identity %s exported to %s", args[0], flagExportOutput))
+
+		return nil
+	},
+}
+
+var authIdentityEraseCmd = &cobra.Command{
+	Use:   "erase <identity-id>",
+	Short: "Erase the personal data of an identity in your Keel App",
+	Long: `The erase command will delete the records of models marked with @pii
+which relate to the identity, and remove the values of fields marked with
+@pii from the other records. The identity is anonymised and disabled, and
+the erased data is also removed from the audit log. This cannot be undone.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		erasure, err := program.EraseIdentityData(flagProjectDir, args[0])
+		if err != nil {
+			return program.RenderError(err)
+		}
+
+		program.RenderSuccess(fmt.Sprintf("Data of identity %s erased: %d records deleted and %d records anonymised", args[0], erasure.Deleted, erasure.Anonymised))
+
+		return nil
+	},
+}
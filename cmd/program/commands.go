@@ -30,6 +30,8 @@ import (
 	"github.com/teamkeel/keel/migrations"
 	"github.com/teamkeel/keel/node"
 	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/actions"
+	"github.com/teamkeel/keel/runtime/oauth"
 	"github.com/teamkeel/keel/schema"
 	"github.com/teamkeel/keel/schema/reader"
 	v1 "go.opentelemetry.io/proto/otlp/trace/v1"
@@ -209,54 +211,62 @@ func CheckDependencies() tea.Cmd {
 	}
 }
 
-type ParsePrivateKeyMsg struct {
-	PrivateKey *rsa.PrivateKey
-	Err        error
+type ParsePrivateKeysMsg struct {
+	PrivateKeys []*rsa.PrivateKey
+	Err         error
 }
 
-func ParsePrivateKey(path string) tea.Cmd {
+func ParsePrivateKeys(paths []string) tea.Cmd {
 	return func() tea.Msg {
 		// Uses the embedded default private key if a custom key isn't provided
 		// This allows for a smooth DX in a local env where the signing of the token isn't important
 		// but avoids a code path where we skip token validation
-		var privateKeyPem []byte
+		if len(paths) == 0 {
+			privateKey, err := parsePrivateKeyPem(defaultPem)
+			return ParsePrivateKeysMsg{
+				PrivateKeys: []*rsa.PrivateKey{privateKey},
+				Err:         err,
+			}
+		}
 
-		if path == "" {
-			privateKeyPem = defaultPem
-		} else {
+		privateKeys := []*rsa.PrivateKey{}
+		for _, path := range paths {
 			customPem, err := os.ReadFile(path)
 			if errors.Is(err, os.ErrNotExist) {
-				return ParsePrivateKeyMsg{
+				return ParsePrivateKeysMsg{
 					Err: fmt.Errorf("cannot locate private key file at: %s", path),
 				}
 			} else if err != nil {
-				return ParsePrivateKeyMsg{
+				return ParsePrivateKeysMsg{
 					Err: fmt.Errorf("cannot read private key file: %s", err.Error()),
 				}
 			}
-			privateKeyPem = customPem
-		}
 
-		privateKeyBlock, _ := pem.Decode(privateKeyPem)
-		if privateKeyBlock == nil {
-			return ParsePrivateKeyMsg{
-				Err: errors.New("private key PEM either invalid or empty"),
+			privateKey, err := parsePrivateKeyPem(customPem)
+			if err != nil {
+				return ParsePrivateKeysMsg{
+					Err: err,
+				}
 			}
-		}
 
-		privateKey, err := x509.ParsePKCS1PrivateKey(privateKeyBlock.Bytes)
-		if err != nil {
-			return ParsePrivateKeyMsg{
-				Err: err,
-			}
+			privateKeys = append(privateKeys, privateKey)
 		}
 
-		return ParsePrivateKeyMsg{
-			PrivateKey: privateKey,
+		return ParsePrivateKeysMsg{
+			PrivateKeys: privateKeys,
 		}
 	}
 }
 
+func parsePrivateKeyPem(privateKeyPem []byte) (*rsa.PrivateKey, error) {
+	privateKeyBlock, _ := pem.Decode(privateKeyPem)
+	if privateKeyBlock == nil {
+		return nil, errors.New("private key PEM either invalid or empty")
+	}
+
+	return x509.ParsePKCS1PrivateKey(privateKeyBlock.Bytes)
+}
+
 type StartDatabaseMsg struct {
 	ConnInfo *db.ConnectionInfo
 	Err      error
@@ -357,9 +367,9 @@ func (e *ApplyMigrationsError) Unwrap() error {
 	return e.Err
 }
 
-func RunMigrations(schema *proto.Schema, database db.Database) tea.Cmd {
+func RunMigrations(schema *proto.Schema, cfg *config.ProjectConfig, database db.Database) tea.Cmd {
 	return func() tea.Msg {
-		m, err := migrations.New(context.Background(), schema, database)
+		m, err := migrations.New(context.Background(), schema, database, migrations.WithRowLevelSecurity(cfg.Database.RowLevelSecurity))
 		if err != nil {
 			return RunMigrationsMsg{
 				Err: &ApplyMigrationsError{
@@ -408,6 +418,9 @@ func StartFunctions(m *Model) tea.Cmd {
 		envVars["KEEL_DB_CONN"] = m.DatabaseConnInfo.String()
 		envVars["KEEL_TRACING_ENABLED"] = "true"
 		envVars["OTEL_RESOURCE_ATTRIBUTES"] = "service.name=functions"
+		if m.Config.Database.RowLevelSecurity {
+			envVars["KEEL_DB_ROW_LEVEL_SECURITY"] = "true"
+		}
 
 		output := &FunctionsOutputWriter{
 			// Initially buffer output inside the writer in case there's an error
@@ -666,3 +679,96 @@ func RemoveSecret(path, environment, key string) error {
 
 	return config.RemoveSecret(path, environment, key)
 }
+
+// withProjectDatabase starts the local database for the project and passes a context
+// with the database connection to fn.
+func withProjectDatabase(path string, fn func(ctx context.Context) error) error {
+	connInfo, err := database.Start(false, path)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	dbConn, err := db.New(ctx, connInfo.String())
+	if err != nil {
+		return err
+	}
+	defer dbConn.Close()
+
+	return fn(db.WithDatabase(ctx, dbConn))
+}
+
+func CreateServiceClient(path, name string, roles []string) (client *oauth.ServiceClient, secret string, err error) {
+	b := schema.Builder{}
+	s, err := b.MakeFromDirectory(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, role := range roles {
+		if proto.FindRole(role, s) == nil {
+			return nil, "", fmt.Errorf("role %s does not exist in the schema", role)
+		}
+	}
+
+	err = withProjectDatabase(path, func(ctx context.Context) error {
+		client, secret, err = oauth.NewServiceClient(ctx, name, roles)
+		return err
+	})
+
+	return client, secret, err
+}
+
+func ListServiceClients(path string) (clients []*oauth.ServiceClient, err error) {
+	err = withProjectDatabase(path, func(ctx context.Context) error {
+		clients, err = oauth.ListServiceClients(ctx)
+		return err
+	})
+
+	return clients, err
+}
+
+func DeleteServiceClient(path, clientId string) error {
+	return withProjectDatabase(path, func(ctx context.Context) error {
+		deleted, err := oauth.DeleteServiceClient(ctx, clientId)
+		if err != nil {
+			return err
+		}
+
+		if !deleted {
+			return fmt.Errorf("client %s does not exist", clientId)
+		}
+
+		return nil
+	})
+}
+
+func ExportIdentityData(path, identityId string) (export map[string]any, err error) {
+	b := schema.Builder{}
+	s, err := b.MakeFromDirectory(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = withProjectDatabase(path, func(ctx context.Context) error {
+		export, err = actions.ExportSubjectData(ctx, s, identityId)
+		return err
+	})
+
+	return export, err
+}
+
+func EraseIdentityData(path, identityId string) (erasure *actions.SubjectErasure, err error) {
+	b := schema.Builder{}
+	s, err := b.MakeFromDirectory(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = withProjectDatabase(path, func(ctx context.Context) error {
+		erasure, err = actions.EraseSubjectData(ctx, s, identityId)
+		return err
+	})
+
+	return erasure, err
+}
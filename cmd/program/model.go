@@ -30,6 +30,8 @@ import (
 	"github.com/teamkeel/keel/rpc/rpc"
 	rpcApi "github.com/teamkeel/keel/rpc/rpcApi"
 	"github.com/teamkeel/keel/runtime"
+	"github.com/teamkeel/keel/runtime/actions"
+	"github.com/teamkeel/keel/runtime/oauth"
 	"github.com/teamkeel/keel/runtime/runtimectx"
 	"github.com/teamkeel/keel/schema/reader"
 	"github.com/teamkeel/keel/storage"
@@ -143,12 +145,15 @@ type Model struct {
 	// Either 'npm' or 'pnpm'
 	PackageManager string
 
-	// If set then runtime will be configured with private key
-	// located at this path in pem format.
-	PrivateKeyPath string
+	// If set then runtime will be configured with the private keys
+	// located at these paths in pem format.
+	PrivateKeyPaths []string
 
-	// The private key to configure on runtime, or nil.
-	PrivateKey *rsa.PrivateKey
+	// The private keys to configure on runtime, or nil.
+	PrivateKeys []*rsa.PrivateKey
+
+	// The primary and active private keys, determined using the project config.
+	PrivateKeySet *runtimectx.PrivateKeySet
 
 	// Pattern to pass to vitest to isolate specific tests
 	TestPattern string
@@ -281,8 +286,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		m.Database = database
 		m.Status = StatusParsePrivateKey
-		return m, ParsePrivateKey(m.PrivateKeyPath)
-	case ParsePrivateKeyMsg:
+		return m, ParsePrivateKeys(m.PrivateKeyPaths)
+	case ParsePrivateKeysMsg:
 		m.Err = msg.Err
 
 		// If the private key can't be parsed we exit
@@ -290,7 +295,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
-		m.PrivateKey = msg.PrivateKey
+		m.PrivateKeys = msg.PrivateKeys
 
 		m.Status = StatusSetupFunctions
 		return m, SetupFunctions(m.ProjectDir, m.NodePackagesPath, m.PackageManager)
@@ -336,6 +341,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if len(m.PrivateKeys) > 0 {
+			m.PrivateKeySet, m.Err = oauth.NewPrivateKeySet(m.PrivateKeys, &m.Config.Auth.SigningKeys)
+			if m.Err != nil {
+				return m, nil
+			}
+		}
+
 		cors := cors.New(cors.Options{
 			AllowOriginFunc: func(origin string) bool {
 				return true
@@ -355,7 +367,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		m.RuntimeHandler = cors.Handler(runtime.NewHttpHandler(m.Schema))
 		m.Status = StatusRunMigrations
-		return m, RunMigrations(m.Schema, m.Database)
+		return m, RunMigrations(m.Schema, m.Config, m.Database)
 	case RunMigrationsMsg:
 		m.Err = msg.Err
 		m.MigrationChanges = msg.Changes
@@ -446,9 +458,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.RuntimeRequests = append(m.RuntimeRequests, request)
 
 		// log runtime requests for the run cmd
-		if m.Mode == ModeRun && m.Err == nil && m.Status >= StatusLoadSchema {
-			cmds = append(cmds, tea.Println(renderRequestLog(request)))
-		}
+		logRequest := m.Mode == ModeRun && m.Err == nil && m.Status >= StatusLoadSchema
 
 		if strings.HasSuffix(r.URL.Path, "/graphiql") {
 			handler := playground.Handler("GraphiQL", strings.TrimSuffix(r.URL.Path, "/graphiql")+"/graphql")
@@ -475,8 +485,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			attribute.String("http.path", request.Path),
 		)
 
-		if m.PrivateKey != nil {
-			ctx = runtimectx.WithPrivateKey(ctx, m.PrivateKey)
+		if m.PrivateKeySet != nil {
+			ctx = runtimectx.WithPrivateKeys(ctx, m.PrivateKeySet)
+		}
+
+		// Permissions are only explained when asked for by the request header or over RPC,
+		// as explaining requires additional database queries
+		var explainer *actions.PermissionExplainer
+		if r.Header.Get(actions.ExplainPermissionsHeader) != "" || rpcApi.ExplainPermissionsEnabled() {
+			explainer = actions.NewPermissionExplainer()
+			ctx = actions.WithPermissionExplainer(ctx, explainer)
 		}
 
 		ctx = db.WithDatabase(ctx, m.Database)
@@ -516,12 +534,45 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		r = msg.r.WithContext(ctx)
+
+		// Streaming requests stay open for as long as the client is connected,
+		// so they're served without blocking any other requests
+		if runtime.IsStreamRequest(r) {
+			handler := m.RuntimeHandler
+			go func() {
+				handler.ServeHTTP(msg.w, r)
+				msg.done <- true
+			}()
+
+			if logRequest {
+				cmds = append(cmds, tea.Println(renderRequestLog(request)))
+			}
+
+			return m, tea.Batch(cmds...)
+		}
+
 		m.RuntimeHandler.ServeHTTP(msg.w, r)
 
 		for k := range envVars {
 			os.Unsetenv(k)
 		}
 
+		// keep the permission explanations so that they can be retrieved over RPC
+		if explainer != nil {
+			rpcApi.RecordPermissionExplanations(r.Method, r.URL.Path, explainer.Explanations())
+		}
+
+		// log the request along with any permission explanations for the run cmd
+		if logRequest {
+			logs := []string{renderRequestLog(request)}
+			if explainer != nil {
+				for _, explanation := range explainer.Explanations() {
+					logs = append(logs, renderPermissionExplanation(explanation))
+				}
+			}
+			cmds = append(cmds, tea.Println(strings.Join(logs, "\n")))
+		}
+
 		msg.done <- true
 		return m, tea.Batch(cmds...)
 	case RpcRequestMsg:
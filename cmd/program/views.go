@@ -15,6 +15,8 @@ import (
 	"github.com/teamkeel/keel/migrations"
 	"github.com/teamkeel/keel/node"
 	"github.com/teamkeel/keel/runtime"
+	"github.com/teamkeel/keel/runtime/actions"
+	"github.com/teamkeel/keel/runtime/oauth"
 	"github.com/teamkeel/keel/schema"
 	"github.com/teamkeel/keel/schema/validation/errorhandling"
 )
@@ -290,6 +292,64 @@ func renderRequestLog(request *RuntimeRequest) string {
 	return b.String()
 }
 
+func renderPermissionExplanation(explanation *actions.PermissionExplanation) string {
+	b := strings.Builder{}
+
+	b.WriteString(colors.Magenta("[Permissions]").String())
+	b.WriteString(" ")
+	if explanation.Authorised {
+		b.WriteString(colors.Green("Authorised").String())
+	} else {
+		b.WriteString(colors.Red("Denied").String())
+	}
+	b.WriteString(" ")
+	b.WriteString(colors.White(explanation.Action).String())
+	if explanation.Model != "" {
+		b.WriteString(fmt.Sprintf(" (%s)", explanation.Model))
+	}
+	if explanation.Reason != "" {
+		b.WriteString(fmt.Sprintf(": %s", explanation.Reason))
+	}
+
+	for _, rule := range explanation.Rules {
+		b.WriteString("\n  - ")
+		if rule.Expression != "" {
+			b.WriteString(fmt.Sprintf("expression: %s", rule.Expression))
+		} else {
+			b.WriteString(fmt.Sprintf("roles: %s", strings.Join(rule.Roles, ", ")))
+		}
+
+		switch {
+		case rule.Authorised == nil:
+			b.WriteString(colors.Gray(" (resolved in database)").String())
+		case *rule.Authorised:
+			b.WriteString(colors.Gray(" (resolved early: ").String())
+			b.WriteString(colors.Green("satisfied").String())
+			b.WriteString(colors.Gray(")").String())
+		default:
+			b.WriteString(colors.Gray(" (resolved early: ").String())
+			b.WriteString(colors.Red("not satisfied").String())
+			b.WriteString(colors.Gray(")").String())
+		}
+	}
+
+	if explanation.Sql != "" {
+		b.WriteString("\n  ")
+		b.WriteString(colors.Gray(fmt.Sprintf("SQL: %s %v", explanation.Sql, explanation.SqlArgs)).String())
+	}
+
+	for _, row := range explanation.Rows {
+		b.WriteString(fmt.Sprintf("\n  row %s: ", row.Id))
+		if row.Authorised {
+			b.WriteString(colors.Green("authorised").String())
+		} else {
+			b.WriteString(colors.Red("denied").String())
+		}
+	}
+
+	return b.String()
+}
+
 func RenderSecrets(secrets map[string]string) string {
 	var rows []table.Row
 	var keys []string
@@ -331,6 +391,41 @@ func RenderSecrets(secrets map[string]string) string {
 	return secretsStyle.Render(t.View()) + "\n"
 }
 
+func RenderServiceClients(clients []*oauth.ServiceClient) string {
+	var rows []table.Row
+	for _, c := range clients {
+		rows = append(rows, table.Row{c.ClientId, c.Name, strings.Join(c.Roles, ", ")})
+	}
+
+	columns := []table.Column{
+		{Title: "Client ID", Width: 30},
+		{Title: "Name", Width: 30},
+		{Title: "Roles", Width: 40},
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithHeight(len(clients)),
+	)
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.NoColor{}).
+		Bold(false)
+	s.Cell = s.Cell.
+		Foreground(colors.HighlightWhiteBright)
+
+	t.SetStyles(s)
+
+	clientsStyle := lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder())
+
+	return clientsStyle.Render(t.View()) + "\n"
+}
+
 func RenderError(message error) error {
 	return errors.New(colors.Red(message.Error()).Highlight().String())
 }
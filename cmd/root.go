@@ -19,7 +19,7 @@ var (
 	flagReset            bool
 	flagPort             string
 	flagNodePackagesPath string
-	flagPrivateKeyPath   string
+	flagPrivateKeyPaths  []string
 	flagPattern          string
 	flagTracing          bool
 	flagVersion          bool
@@ -29,6 +29,7 @@ var (
 	flagJsonOutput       bool
 	flagSchema           string
 	flagConfig           string
+	flagPermissions      bool
 )
 
 var rootCmd = &cobra.Command{
@@ -28,7 +28,7 @@ var runCmd = &cobra.Command{
 			CustomTracing:    flagTracing,
 			NodePackagesPath: flagNodePackagesPath,
 			PackageManager:   packageManager,
-			PrivateKeyPath:   flagPrivateKeyPath,
+			PrivateKeyPaths:  flagPrivateKeyPaths,
 		})
 	},
 }
@@ -39,7 +39,7 @@ func init() {
 	runCmd.Flags().BoolVar(&flagReset, "reset", false, "if set the database will be reset")
 	runCmd.Flags().StringVar(&flagHostname, "hostname", "", "custom hostname to handle HTTP requests")
 	runCmd.Flags().StringVar(&flagPort, "port", "8000", "the local port to handle Keel HTTP requests")
-	runCmd.Flags().StringVar(&flagPrivateKeyPath, "private-key-path", "", "path to the private key .pem file")
+	runCmd.Flags().StringArrayVar(&flagPrivateKeyPaths, "private-key-path", []string{}, "path to a private key .pem file, which can be provided more than once to rotate keys")
 
 	if enabledDebugFlags == "true" {
 		runCmd.Flags().StringVar(&flagNodePackagesPath, "node-packages-path", "", "path to local @teamkeel npm packages")
@@ -95,7 +95,7 @@ func init() {
 	rootCmd.AddCommand(testCmd)
 
 	testCmd.Flags().StringVarP(&flagPattern, "pattern", "p", "(.*)", "pattern to isolate test")
-	testCmd.Flags().StringVar(&flagPrivateKeyPath, "private-key-path", "", "path to the private key .pem file")
+	testCmd.Flags().StringArrayVar(&flagPrivateKeyPaths, "private-key-path", []string{}, "path to a private key .pem file, which can be provided more than once to rotate keys")
 
 	if enabledDebugFlags == "true" {
 		testCmd.Flags().StringVar(&flagNodePackagesPath, "node-packages-path", "", "path to local @teamkeel npm packages")
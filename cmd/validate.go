@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/teamkeel/keel/colors"
 	"github.com/teamkeel/keel/config"
+	"github.com/teamkeel/keel/permissions/coverage"
+	"github.com/teamkeel/keel/proto"
 	"github.com/teamkeel/keel/schema"
 	"github.com/teamkeel/keel/schema/validation/errorhandling"
 )
@@ -16,6 +19,7 @@ import (
 type JsonResponse struct {
 	ValidationErrors errorhandling.ValidationErrors `json:"validationErrors"`
 	ConfigErrors     config.ConfigErrors            `json:"configErrors"`
+	Permissions      *coverage.Report               `json:"permissions,omitempty"`
 }
 
 var validateCmd = &cobra.Command{
@@ -25,6 +29,7 @@ var validateCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		b := schema.Builder{}
 
+		var protoSchema *proto.Schema
 		var err error
 		if flagSchema != "" || flagConfig != "" {
 			var schema []byte
@@ -39,13 +44,19 @@ var validateCmd = &cobra.Command{
 				return err
 			}
 
-			_, err = b.MakeFromString(string(schema), string(config))
+			protoSchema, err = b.MakeFromString(string(schema), string(config))
 		} else {
-			_, err = b.MakeFromDirectory(flagProjectDir)
+			protoSchema, err = b.MakeFromDirectory(flagProjectDir)
 		}
 
 		if err == nil && !flagJsonOutput {
 			fmt.Println("✨ Everything's looking good!")
+
+			if flagPermissions {
+				fmt.Println("")
+				return coverage.NewReport(protoSchema).WriteTable(os.Stdout)
+			}
+
 			return nil
 		}
 
@@ -64,6 +75,10 @@ var validateCmd = &cobra.Command{
 				ConfigErrors:     *configErrors,
 			}
 
+			if flagPermissions && err == nil {
+				resp.Permissions = coverage.NewReport(protoSchema)
+			}
+
 			switch {
 			case errors.As(err, &validationErrors):
 				resp.ValidationErrors = *validationErrors
@@ -111,4 +126,5 @@ func init() {
 	validateCmd.Flags().BoolVar(&flagJsonOutput, "json", false, "output validation and config errors as json")
 	validateCmd.Flags().StringVar(&flagSchema, "schema", "", "the Keel schema as base64 passed as an argument")
 	validateCmd.Flags().StringVar(&flagConfig, "config", "", "the Keel config as base64 passed as an argument")
+	validateCmd.Flags().BoolVar(&flagPermissions, "permissions", false, "output the permission rules and warnings for every action")
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"os"
 	"regexp"
@@ -17,6 +18,17 @@ const (
 	DefaultAccessTokenExpiry time.Duration = time.Hour * 24
 	// 3 months is the default refresh token expiry period
 	DefaultRefreshTokenExpiry time.Duration = time.Hour * 24 * 90
+	// 15 minutes is the default period an identity or IP address is locked out for
+	DefaultLockoutDuration time.Duration = time.Minute * 15
+	// 1 second is the default delay after the first failed attempt, which doubles with each further failure
+	DefaultLockoutDelay time.Duration = time.Second
+)
+
+const (
+	// Failed password attempts for an email address before it is locked out
+	DefaultLockoutMaxAttempts = 5
+	// Failed password attempts from a single IP address before it is locked out
+	DefaultLockoutMaxAttemptsPerIp = 50
 )
 
 const ProviderSecretPrefix = "AUTH_PROVIDER_SECRET_"
@@ -47,21 +59,32 @@ type FunctionHook string
 const (
 	HookAfterAuthentication  FunctionHook = "afterAuthentication"
 	HookAfterIdentityCreated FunctionHook = "afterIdentityCreated"
+	HookAfterLockout         FunctionHook = "afterLockout"
 )
 
 var (
 	supportedAuthHooks = []FunctionHook{
 		HookAfterAuthentication,
 		HookAfterIdentityCreated,
+		HookAfterLockout,
 	}
 )
 
 type AuthConfig struct {
 	Tokens      TokensConfig    `yaml:"tokens"`
+	Pkce        PkceConfig      `yaml:"pkce"`
+	Email       EmailConfig     `yaml:"email"`
+	Lockout     LockoutConfig   `yaml:"lockout"`
+	Linking     LinkingConfig   `yaml:"linking"`
+	SigningKeys SigningKeys     `yaml:"signingKeys"`
 	RedirectUrl *string         `yaml:"redirectUrl,omitempty"`
 	Providers   []Provider      `yaml:"providers"`
 	Claims      []IdentityClaim `yaml:"claims"`
 	Hooks       []FunctionHook  `yaml:"hooks"`
+	// AdminRoles are the roles which are permitted to administer other identities, such as their sessions and roles.
+	AdminRoles []string `yaml:"adminRoles"`
+	// ImpersonatorRoles are the roles which are permitted to use the impersonation grant to act as another identity.
+	ImpersonatorRoles []string `yaml:"impersonatorRoles"`
 }
 
 type TokensConfig struct {
@@ -70,6 +93,42 @@ type TokensConfig struct {
 	RefreshTokenRotationEnabled *bool `yaml:"refreshTokenRotationEnabled,omitempty"`
 }
 
+type PkceConfig struct {
+	Required *bool `yaml:"required,omitempty"`
+}
+
+type EmailConfig struct {
+	VerificationRequired *bool `yaml:"verificationRequired,omitempty"`
+}
+
+// LockoutConfig configures brute-force protection on the password grant. Failed attempts are
+// counted per email address and per client IP address, and once exceeded further attempts are
+// rejected until the lockout duration has passed. Durations are in seconds.
+//
+// The client IP address is the address of the connection unless that is one of the trusted proxies
+// (IP addresses or CIDR ranges), in which case it is the last address in X-Forwarded-For which is not.
+type LockoutConfig struct {
+	Enabled          *bool    `yaml:"enabled,omitempty"`
+	MaxAttempts      *int     `yaml:"maxAttempts,omitempty"`
+	MaxAttemptsPerIp *int     `yaml:"maxAttemptsPerIp,omitempty"`
+	Duration         *int     `yaml:"duration,omitempty"`
+	Delay            *int     `yaml:"delay,omitempty"`
+	TrustedProxies   []string `yaml:"trustedProxies,omitempty"`
+}
+
+// LinkingConfig configures how identities at external providers are linked to existing identities.
+type LinkingConfig struct {
+	VerifiedEmail *bool `yaml:"verifiedEmail,omitempty"`
+}
+
+// SigningKeys configures which of the private keys is used to sign new tokens,
+// and which keys have been retired and are no longer accepted. Keys are identified
+// by their key id (kid), as published at the JWKS endpoint.
+type SigningKeys struct {
+	Primary *string  `yaml:"primary,omitempty"`
+	Retired []string `yaml:"retired"`
+}
+
 type Provider struct {
 	Type             string `yaml:"type"`
 	Name             string `yaml:"name"`
@@ -116,6 +175,111 @@ func (c *AuthConfig) RefreshTokenRotationEnabled() bool {
 	}
 }
 
+// PkceRequired retrieves the configured or default setting for whether the authorization code flow
+// requires a Proof Key for Code Exchange (PKCE) code challenge
+func (c *AuthConfig) PkceRequired() bool {
+	if c.Pkce.Required != nil {
+		return *c.Pkce.Required
+	} else {
+		return false
+	}
+}
+
+// LinkVerifiedEmailEnabled determines if signing in with a provider for the first time links the provider to an
+// existing identity with the same email address, when both the provider and the existing identity have verified it
+func (c *AuthConfig) LinkVerifiedEmailEnabled() bool {
+	if c.Linking.VerifiedEmail != nil {
+		return *c.Linking.VerifiedEmail
+	} else {
+		return false
+	}
+}
+
+// EmailVerificationRequired determines if an identity must verify their email address before they can use the password grant
+func (c *AuthConfig) EmailVerificationRequired() bool {
+	if c.Email.VerificationRequired != nil {
+		return *c.Email.VerificationRequired
+	} else {
+		return false
+	}
+}
+
+// LockoutEnabled determines if failed password attempts are limited
+func (c *AuthConfig) LockoutEnabled() bool {
+	if c.Lockout.Enabled != nil {
+		return *c.Lockout.Enabled
+	} else {
+		return false
+	}
+}
+
+// LockoutMaxAttempts retrieves the configured or default number of failed attempts allowed for an email address
+func (c *AuthConfig) LockoutMaxAttempts() int {
+	if c.Lockout.MaxAttempts != nil {
+		return *c.Lockout.MaxAttempts
+	} else {
+		return DefaultLockoutMaxAttempts
+	}
+}
+
+// LockoutMaxAttemptsPerIp retrieves the configured or default number of failed attempts allowed from an IP address
+func (c *AuthConfig) LockoutMaxAttemptsPerIp() int {
+	if c.Lockout.MaxAttemptsPerIp != nil {
+		return *c.Lockout.MaxAttemptsPerIp
+	} else {
+		return DefaultLockoutMaxAttemptsPerIp
+	}
+}
+
+// LockoutDuration retrieves the configured or default period for which further attempts are rejected
+func (c *AuthConfig) LockoutDuration() time.Duration {
+	if c.Lockout.Duration != nil {
+		return time.Duration(*c.Lockout.Duration) * time.Second
+	} else {
+		return DefaultLockoutDuration
+	}
+}
+
+// LockoutDelay retrieves the configured or default delay required after the first failed attempt.
+// The delay doubles with each consecutive failure, and a zero delay disables progressive delays.
+func (c *AuthConfig) LockoutDelay() time.Duration {
+	if c.Lockout.Delay != nil {
+		return time.Duration(*c.Lockout.Delay) * time.Second
+	} else {
+		return DefaultLockoutDelay
+	}
+}
+
+// LockoutTrustedProxies retrieves the IP ranges of the configured trusted proxies. A single IP address is a range containing only that address.
+func (c *AuthConfig) LockoutTrustedProxies() []*net.IPNet {
+	proxies := []*net.IPNet{}
+	for _, p := range c.Lockout.TrustedProxies {
+		if proxy, ok := parseTrustedProxy(p); ok {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+func parseTrustedProxy(proxy string) (*net.IPNet, bool) {
+	if _, ipNet, err := net.ParseCIDR(proxy); err == nil {
+		return ipNet, true
+	}
+
+	ip := net.ParseIP(proxy)
+	if ip == nil {
+		return nil, false
+	}
+
+	bits := 8 * net.IPv6len
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = 8 * net.IPv4len
+	}
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, true
+}
+
 // AddOidcProvider adds an OpenID Connect provider to the list of supported authentication providers
 func (c *AuthConfig) AddOidcProvider(name string, issuerUrl string, clientId string) error {
 	if invalidName(name) {
@@ -19,11 +19,21 @@ const Empty = ""
 
 // ProjectConfig is the configuration for a keel project
 type ProjectConfig struct {
-	Environment   []Input    `yaml:"environment"`
-	UseDefaultApi *bool      `yaml:"useDefaultApi,omitempty"`
-	Secrets       []Input    `yaml:"secrets"`
-	Auth          AuthConfig `yaml:"auth"`
-	DisableAuth   bool       `yaml:"disableKeelAuth"`
+	Environment   []Input        `yaml:"environment"`
+	UseDefaultApi *bool          `yaml:"useDefaultApi,omitempty"`
+	Secrets       []Input        `yaml:"secrets"`
+	Auth          AuthConfig     `yaml:"auth"`
+	DisableAuth   bool           `yaml:"disableKeelAuth"`
+	Database      DatabaseConfig `yaml:"database"`
+}
+
+// DatabaseConfig is the configuration for the project's database
+type DatabaseConfig struct {
+	// RowLevelSecurity enables Postgres row-level security policies generated from the model permission rules,
+	// which apply to any database role other than the one which owns the tables, e.g. analysts querying directly.
+	// Functions run their queries as the keel_rls role so that the policies also apply to them, which requires
+	// the database role used by Keel to be able to create roles.
+	RowLevelSecurity bool `yaml:"rowLevelSecurity"`
 }
 
 func (p *ProjectConfig) GetEnvVars() map[string]string {
@@ -106,6 +116,10 @@ const (
 	ConfigAuthProviderInvalidHttpUrlErrorString      = "auth provider '%s' has missing or invalid https url for field: %s"
 	ConfigAuthInvalidRedirectUrlErrorString          = "auth redirectUrl '%s' is not a valid url"
 	ConfigAuthInvalidHook                            = "%s is not a recognised hook"
+	ConfigAuthPrimarySigningKeyRetired               = "auth signing key '%s' cannot be both the primary and retired"
+	ConfigAuthLockoutMustBePositive                  = "auth lockout cannot be negative or zero for field: %s"
+	ConfigAuthLockoutMustNotBeNegative               = "auth lockout cannot be negative for field: %s"
+	ConfigAuthLockoutInvalidTrustedProxy             = "auth lockout trusted proxy '%s' must be an IP address or CIDR range"
 )
 
 type ConfigErrors struct {
@@ -219,6 +233,43 @@ func Validate(config *ProjectConfig) *ConfigErrors {
 		})
 	}
 
+	if config.Auth.LockoutMaxAttempts() <= 0 {
+		errors = append(errors, &ConfigError{
+			Type:    "invalid",
+			Message: fmt.Sprintf(ConfigAuthLockoutMustBePositive, "maxAttempts"),
+		})
+	}
+
+	if config.Auth.LockoutMaxAttemptsPerIp() <= 0 {
+		errors = append(errors, &ConfigError{
+			Type:    "invalid",
+			Message: fmt.Sprintf(ConfigAuthLockoutMustBePositive, "maxAttemptsPerIp"),
+		})
+	}
+
+	if config.Auth.LockoutDuration() <= 0 {
+		errors = append(errors, &ConfigError{
+			Type:    "invalid",
+			Message: fmt.Sprintf(ConfigAuthLockoutMustBePositive, "duration"),
+		})
+	}
+
+	if config.Auth.LockoutDelay() < 0 {
+		errors = append(errors, &ConfigError{
+			Type:    "invalid",
+			Message: fmt.Sprintf(ConfigAuthLockoutMustNotBeNegative, "delay"),
+		})
+	}
+
+	for _, proxy := range config.Auth.Lockout.TrustedProxies {
+		if _, ok := parseTrustedProxy(proxy); !ok {
+			errors = append(errors, &ConfigError{
+				Type:    "invalid",
+				Message: fmt.Sprintf(ConfigAuthLockoutInvalidTrustedProxy, proxy),
+			})
+		}
+	}
+
 	invalidProviderNames := findAuthProviderInvalidName(config.Auth.Providers)
 	for _, p := range invalidProviderNames {
 		errors = append(errors, &ConfigError{
@@ -322,6 +373,13 @@ func Validate(config *ProjectConfig) *ConfigErrors {
 		}
 	}
 
+	if config.Auth.SigningKeys.Primary != nil && slices.Contains(config.Auth.SigningKeys.Retired, *config.Auth.SigningKeys.Primary) {
+		errors = append(errors, &ConfigError{
+			Type:    "invalid",
+			Message: fmt.Sprintf(ConfigAuthPrimarySigningKeyRetired, *config.Auth.SigningKeys.Primary),
+		})
+	}
+
 	if config.Auth.Hooks != nil {
 		for _, v := range config.Auth.Hooks {
 			if !slices.Contains(supportedAuthHooks, v) {
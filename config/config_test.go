@@ -97,6 +97,21 @@ func TestAuthTokens(t *testing.T) {
 	assert.Equal(t, time.Duration(3600)*time.Second, config.Auth.AccessTokenExpiry())
 	assert.Equal(t, time.Duration(604800)*time.Second, config.Auth.RefreshTokenExpiry())
 	assert.Equal(t, false, config.Auth.RefreshTokenRotationEnabled())
+	assert.Equal(t, true, config.Auth.PkceRequired())
+	assert.Equal(t, true, config.Auth.EmailVerificationRequired())
+	assert.Equal(t, []string{"Admin"}, config.Auth.AdminRoles)
+	assert.Equal(t, []string{"Support"}, config.Auth.ImpersonatorRoles)
+	assert.Equal(t, true, config.Auth.LockoutEnabled())
+	assert.Equal(t, true, config.Auth.LinkVerifiedEmailEnabled())
+	assert.Equal(t, 3, config.Auth.LockoutMaxAttempts())
+	assert.Equal(t, 10, config.Auth.LockoutMaxAttemptsPerIp())
+	assert.Equal(t, 10*time.Minute, config.Auth.LockoutDuration())
+	assert.Equal(t, time.Duration(0), config.Auth.LockoutDelay())
+	assert.Len(t, config.Auth.LockoutTrustedProxies(), 2)
+	assert.Equal(t, "10.0.0.0/8", config.Auth.LockoutTrustedProxies()[0].String())
+	assert.Equal(t, "192.0.2.1/32", config.Auth.LockoutTrustedProxies()[1].String())
+	assert.Equal(t, "key_2", *config.Auth.SigningKeys.Primary)
+	assert.Equal(t, []string{"key_0"}, config.Auth.SigningKeys.Retired)
 }
 
 func TestAuthInvalidRedirectUrl(t *testing.T) {
@@ -118,6 +133,19 @@ func TestAuthDefaults(t *testing.T) {
 	assert.Equal(t, time.Duration(24)*time.Hour, config.Auth.AccessTokenExpiry())
 	assert.Equal(t, time.Duration(24)*time.Hour*90, config.Auth.RefreshTokenExpiry())
 	assert.Equal(t, true, config.Auth.RefreshTokenRotationEnabled())
+	assert.Nil(t, config.Auth.Pkce.Required)
+	assert.Equal(t, false, config.Auth.PkceRequired())
+	assert.Equal(t, false, config.Auth.EmailVerificationRequired())
+	assert.Empty(t, config.Auth.AdminRoles)
+	assert.Empty(t, config.Auth.ImpersonatorRoles)
+	assert.Equal(t, false, config.Auth.LockoutEnabled())
+	assert.Equal(t, false, config.Auth.LinkVerifiedEmailEnabled())
+	assert.Equal(t, 5, config.Auth.LockoutMaxAttempts())
+	assert.Equal(t, 50, config.Auth.LockoutMaxAttemptsPerIp())
+	assert.Equal(t, 15*time.Minute, config.Auth.LockoutDuration())
+	assert.Equal(t, time.Second, config.Auth.LockoutDelay())
+	assert.Nil(t, config.Auth.SigningKeys.Primary)
+	assert.Empty(t, config.Auth.SigningKeys.Retired)
 }
 
 func TestAuthNegativeTokenLifespan(t *testing.T) {
@@ -346,14 +374,34 @@ func TestAuthHooksInvalid(t *testing.T) {
 	assert.ErrorContains(t, err, "afterIdentity is not a recognised hook")
 }
 
+func TestAuthPrimarySigningKeyRetired(t *testing.T) {
+	t.Parallel()
+	_, err := Load("fixtures/test_auth_primary_signing_key_retired.yaml")
+
+	assert.ErrorContains(t, err, "auth signing key 'key_1' cannot be both the primary and retired")
+}
+
+func TestAuthInvalidLockout(t *testing.T) {
+	t.Parallel()
+	_, err := Load("fixtures/test_auth_invalid_lockout.yaml")
+
+	assert.ErrorContains(t, err, "auth lockout cannot be negative or zero for field: maxAttempts")
+	assert.ErrorContains(t, err, "auth lockout cannot be negative or zero for field: maxAttemptsPerIp")
+	assert.ErrorContains(t, err, "auth lockout cannot be negative or zero for field: duration")
+	assert.ErrorContains(t, err, "auth lockout cannot be negative for field: delay")
+	assert.ErrorContains(t, err, "auth lockout trusted proxy 'proxy.example.com' must be an IP address or CIDR range")
+	assert.NotContains(t, err.Error(), "10.0.0.0/8")
+}
+
 func TestAuthHooksAsList(t *testing.T) {
 	t.Parallel()
 	config, err := Load("fixtures/test_auth_valid_hooks_as_list.yaml")
 	assert.NoError(t, err)
 
-	assert.Len(t, config.Auth.Hooks, 2)
+	assert.Len(t, config.Auth.Hooks, 3)
 	assert.Equal(t, HookAfterAuthentication, config.Auth.Hooks[0])
 	assert.Equal(t, HookAfterIdentityCreated, config.Auth.Hooks[1])
+	assert.Equal(t, HookAfterLockout, config.Auth.Hooks[2])
 }
 
 func TestAuthHooksAsArray(t *testing.T) {
@@ -365,3 +413,11 @@ func TestAuthHooksAsArray(t *testing.T) {
 	assert.Equal(t, HookAfterAuthentication, config.Auth.Hooks[0])
 	assert.Equal(t, HookAfterIdentityCreated, config.Auth.Hooks[1])
 }
+
+func TestDatabaseRowLevelSecurity(t *testing.T) {
+	t.Parallel()
+	config, err := Load("fixtures/test_database_row_level_security.yaml")
+	assert.NoError(t, err)
+
+	assert.True(t, config.Database.RowLevelSecurity)
+}
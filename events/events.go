@@ -153,16 +153,9 @@ func SendEvents(ctx context.Context, schema *proto.Schema) error {
 		}
 
 		for _, subscriber := range subscribers {
-			event := &Event{
-				EventName:  eventName,
-				OccurredAt: time.Now().UTC(),
-				IdentityId: identityId,
-				Target: &EventTarget{
-					Id:           log.Data["id"].(string),
-					Type:         strcase.ToCamel(log.TableName),
-					Data:         toLowerCamelMap(log.Data),
-					PreviousData: toLowerCamelMap(previous),
-				},
+			event, err := newEvent(log, identityId, time.Now().UTC(), previous)
+			if err != nil {
+				return err
 			}
 
 			err = handler(ctx, subscriber.Name, event, traceparent)
@@ -179,6 +172,26 @@ func SendEvents(ctx context.Context, schema *proto.Schema) error {
 	return handlerErrors
 }
 
+// newEvent creates an event for the change to model data recorded in the audit log.
+func newEvent(log *auditing.AuditLog, identityId string, occurredAt time.Time, previous map[string]any) (*Event, error) {
+	eventName, err := eventNameFromAudit(log.TableName, log.Op)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		EventName:  eventName,
+		OccurredAt: occurredAt,
+		IdentityId: identityId,
+		Target: &EventTarget{
+			Id:           log.Data["id"].(string),
+			Type:         strcase.ToCamel(log.TableName),
+			Data:         toLowerCamelMap(log.Data),
+			PreviousData: toLowerCamelMap(previous),
+		},
+	}, nil
+}
+
 // eventNameFromAudit generates an event name from audit table columns.
 func eventNameFromAudit(tableName string, op string) (string, error) {
 	var action string
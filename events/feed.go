@@ -0,0 +1,93 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/teamkeel/keel/auditing"
+	"github.com/teamkeel/keel/casing"
+	"github.com/teamkeel/keel/proto"
+)
+
+// FeedPollInterval is how often clients streaming a feed should read the events which have occurred since.
+var FeedPollInterval = time.Second
+
+// feedBatchSize is the maximum number of events read from a feed at once.
+const feedBatchSize = 100
+
+// ErrEventNotFound is returned when resuming a feed after an event which does not exist.
+var ErrEventNotFound = errors.New("event does not exist")
+
+// FeedEvent is an event read from a feed, along with the id of the audit log which it was created from.
+type FeedEvent struct {
+	// The id of the audit log, which can be used to resume reading a feed after this event.
+	Id    string
+	Event *Event
+}
+
+// Feed reads the events for changes to the data of a set of models from the audit trail, in the order in
+// which they occurred, so that they can be streamed to clients. Unlike SendEvents, events are read for every
+// change to the models' data regardless of the events defined in the schema, and the audit logs are not marked
+// as processed. The previous data of the model is not included in the events.
+type Feed struct {
+	tableNames []string
+	cursor     *auditing.Cursor
+}
+
+// NewFeed creates a feed of the events for the given models. If lastEventId is provided, then the feed resumes
+// after the event with that id, otherwise only events which occur from now on are read.
+func NewFeed(ctx context.Context, models []*proto.Model, lastEventId string) (*Feed, error) {
+	var cursor *auditing.Cursor
+	var err error
+
+	if lastEventId != "" {
+		cursor, err = auditing.CursorAt(ctx, lastEventId)
+		if err != nil {
+			return nil, err
+		}
+
+		if cursor == nil {
+			return nil, fmt.Errorf("%w: %s", ErrEventNotFound, lastEventId)
+		}
+	} else {
+		cursor, err = auditing.LatestCursor(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Feed{
+		tableNames: lo.Map(models, func(m *proto.Model, _ int) string {
+			return casing.ToSnake(m.Name)
+		}),
+		cursor: cursor,
+	}, nil
+}
+
+// Next reads the events which have occurred since the events last read from the feed.
+func (f *Feed) Next(ctx context.Context) ([]*FeedEvent, error) {
+	logs, err := auditing.LogsAfter(ctx, f.tableNames, f.cursor, feedBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []*FeedEvent{}
+	for _, log := range logs {
+		event, err := newEvent(log, log.IdentityId, log.CreatedAt.UTC(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, &FeedEvent{
+			Id:    log.Id,
+			Event: event,
+		})
+
+		f.cursor = log.Cursor()
+	}
+
+	return events, nil
+}
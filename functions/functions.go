@@ -96,6 +96,11 @@ func WithFunctionsTransport(ctx context.Context, transport Transport) context.Co
 }
 
 func CallPredefinedHook(ctx context.Context, hook config.FunctionHook) error {
+	return CallPredefinedHookWithInputs(ctx, hook, nil)
+}
+
+// CallPredefinedHookWithInputs calls the hook, if it is enabled, with inputs describing the event.
+func CallPredefinedHookWithInputs(ctx context.Context, hook config.FunctionHook, inputs map[string]any) error {
 	cfg, err := runtimectx.GetOAuthConfig(ctx)
 	if err != nil {
 		return err
@@ -108,7 +113,7 @@ func CallPredefinedHook(ctx context.Context, hook config.FunctionHook) error {
 		_, _, err = CallFunction(
 			ctx,
 			string(hook),
-			nil,
+			inputs,
 			permissionState,
 		)
 	}
@@ -143,17 +148,28 @@ func CallFunction(ctx context.Context, actionName string, body any, permissionSt
 		}
 	}
 
+	var client *auth.Client
+	if auth.IsClient(ctx) {
+		client, err = auth.GetClient(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	secrets := runtimectx.GetSecrets(ctx)
 
 	tracingContext := propagation.MapCarrier{}
 	otel.GetTextMapPropagator().Inject(ctx, tracingContext)
 
 	meta := map[string]any{
-		"headers":         requestHeaders,
-		"identity":        identity,
-		"secrets":         secrets,
-		"tracing":         tracingContext,
-		"permissionState": permissionState,
+		"headers":            requestHeaders,
+		"identity":           identity,
+		"client":             client,
+		"isMfaAuthenticated": auth.IsMfaAuthenticated(ctx),
+		"impersonatorId":     auth.GetImpersonatorId(ctx),
+		"secrets":            secrets,
+		"tracing":            tracingContext,
+		"permissionState":    permissionState,
 	}
 
 	req := &FunctionsRuntimeRequest{
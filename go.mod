@@ -124,7 +124,7 @@ require (
 	github.com/sirupsen/logrus v1.9.0
 	github.com/spf13/pflag v1.0.5 // indirect
 	golang.org/x/crypto v0.17.0
-	golang.org/x/net v0.17.0 // indirect
+	golang.org/x/net v0.17.0
 	golang.org/x/sys v0.15.0 // indirect
 	gotest.tools/v3 v3.3.0 // indirect
 )
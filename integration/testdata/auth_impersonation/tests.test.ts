@@ -0,0 +1,294 @@
+import { test, expect, beforeEach } from "vitest";
+import { actions, models, resetDatabase } from "@teamkeel/testing";
+import { useDatabase } from "@teamkeel/sdk";
+import { sql } from "kysely";
+
+beforeEach(resetDatabase);
+
+async function token(body: Record<string, any>) {
+  const response = await fetch(
+    process.env.KEEL_TESTING_AUTH_API_URL + "/token",
+    {
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify(body),
+    }
+  );
+
+  return { status: response.status, body: await response.json() };
+}
+
+async function signIn(email: string, verified: boolean) {
+  const { body } = await token({
+    grant_type: "password",
+    username: email,
+    password: "1234",
+  });
+
+  const identity = await models.identity.findOne({
+    email: email,
+    issuer: "https://keel.so",
+  });
+
+  await models.identity.update({ id: identity!.id }, { emailVerified: verified });
+
+  return { identity: identity!, accessToken: body.access_token as string };
+}
+
+test("impersonation - support role - token issued for target identity", async () => {
+  const support = await signIn("support@keel.xyz", true);
+  const customer = await signIn("customer@keel.xyz", true);
+
+  const { status, body } = await token({
+    grant_type: "impersonation",
+    actor_token: support.accessToken,
+    identity_id: customer.identity.id,
+  });
+
+  expect(status).toEqual(200);
+  expect(body.access_token).toBeTruthy();
+  expect(body.token_type).toEqual("bearer");
+  expect(body.refresh_token).toBeUndefined();
+
+  const claims = JSON.parse(
+    Buffer.from(body.access_token.split(".")[1], "base64url").toString()
+  );
+  expect(claims.sub).toEqual(customer.identity.id);
+  expect(claims.act).toEqual({ sub: support.identity.id });
+});
+
+test("impersonation - ctx.identity is the target identity", async () => {
+  const support = await signIn("support@keel.xyz", true);
+  const customer = await signIn("customer@keel.xyz", true);
+
+  await actions
+    .withIdentity(customer.identity)
+    .createNote({ text: "customer's note" });
+
+  const { body } = await token({
+    grant_type: "impersonation",
+    actor_token: support.accessToken,
+    identity_id: customer.identity.id,
+  });
+
+  const notes = await actions.withAuthToken(body.access_token).listNotes();
+  expect(notes.results).toHaveLength(1);
+  expect(notes.results[0].text).toEqual("customer's note");
+
+  const note = await actions
+    .withAuthToken(body.access_token)
+    .createNote({ text: "created while impersonating" });
+  expect(note.identityId).toEqual(customer.identity.id);
+});
+
+test("impersonation - writes record the impersonator in the audit log", async () => {
+  const support = await signIn("support@keel.xyz", true);
+  const customer = await signIn("customer@keel.xyz", true);
+
+  const { body } = await token({
+    grant_type: "impersonation",
+    actor_token: support.accessToken,
+    identity_id: customer.identity.id,
+  });
+
+  const note = await actions
+    .withAuthToken(body.access_token)
+    .createNote({ text: "created while impersonating" });
+
+  const logs = await sql<{
+    identityId: string | null;
+    impersonatorId: string | null;
+  }>`SELECT * FROM keel_audit WHERE table_name = 'note' AND data->>'id' = ${note.id}`.execute(
+    useDatabase()
+  );
+
+  expect(logs.rows).toHaveLength(1);
+  expect(logs.rows[0].identityId).toEqual(customer.identity.id);
+  expect(logs.rows[0].impersonatorId).toEqual(support.identity.id);
+});
+
+test("impersonation - writes without impersonation have no impersonator", async () => {
+  const customer = await signIn("customer@keel.xyz", true);
+
+  const note = await actions
+    .withAuthToken(customer.accessToken)
+    .createNote({ text: "created by the customer" });
+
+  const logs = await sql<{
+    identityId: string | null;
+    impersonatorId: string | null;
+  }>`SELECT * FROM keel_audit WHERE table_name = 'note' AND data->>'id' = ${note.id}`.execute(
+    useDatabase()
+  );
+
+  expect(logs.rows).toHaveLength(1);
+  expect(logs.rows[0].identityId).toEqual(customer.identity.id);
+  expect(logs.rows[0].impersonatorId).toBeNull();
+});
+
+test("impersonation - without support role - unauthorized client", async () => {
+  const other = await signIn("other@keel.xyz", true);
+  const customer = await signIn("customer@keel.xyz", true);
+
+  const { status, body } = await token({
+    grant_type: "impersonation",
+    actor_token: other.accessToken,
+    identity_id: customer.identity.id,
+  });
+
+  expect(status).toEqual(403);
+  expect(body).toEqual({
+    error: "unauthorized_client",
+    error_description:
+      "the identity does not have a role which is permitted to impersonate",
+  });
+});
+
+test("impersonation - support email not verified - unauthorized client", async () => {
+  const support = await signIn("support@keel.xyz", false);
+  const customer = await signIn("customer@keel.xyz", true);
+
+  const { status, body } = await token({
+    grant_type: "impersonation",
+    actor_token: support.accessToken,
+    identity_id: customer.identity.id,
+  });
+
+  expect(status).toEqual(403);
+  expect(body.error).toEqual("unauthorized_client");
+});
+
+test("impersonation - impersonation token as actor - invalid client", async () => {
+  const support = await signIn("support@keel.xyz", true);
+  const customer = await signIn("customer@keel.xyz", true);
+  const other = await signIn("other@keel.xyz", true);
+
+  const impersonation = await token({
+    grant_type: "impersonation",
+    actor_token: support.accessToken,
+    identity_id: customer.identity.id,
+  });
+
+  const { status, body } = await token({
+    grant_type: "impersonation",
+    actor_token: impersonation.body.access_token,
+    identity_id: other.identity.id,
+  });
+
+  expect(status).toEqual(401);
+  expect(body.error).toEqual("invalid_client");
+});
+
+test("impersonation - admin target - unauthorized client", async () => {
+  const support = await signIn("support@keel.xyz", true);
+  const admin = await signIn("admin@keel.xyz", true);
+
+  const { status, body } = await token({
+    grant_type: "impersonation",
+    actor_token: support.accessToken,
+    identity_id: admin.identity.id,
+  });
+
+  expect(status).toEqual(403);
+  expect(body).toEqual({
+    error: "unauthorized_client",
+    error_description:
+      "identities with an admin or impersonator role cannot be impersonated",
+  });
+});
+
+test("impersonation - impersonator target - unauthorized client", async () => {
+  const support = await signIn("support@keel.xyz", true);
+  const otherSupport = await signIn("support2@keel.xyz", true);
+
+  const { status, body } = await token({
+    grant_type: "impersonation",
+    actor_token: support.accessToken,
+    identity_id: otherSupport.identity.id,
+  });
+
+  expect(status).toEqual(403);
+  expect(body.error).toEqual("unauthorized_client");
+});
+
+test("impersonation - cannot enrol an authenticator for the target", async () => {
+  const support = await signIn("support@keel.xyz", true);
+  const customer = await signIn("customer@keel.xyz", true);
+
+  const { body } = await token({
+    grant_type: "impersonation",
+    actor_token: support.accessToken,
+    identity_id: customer.identity.id,
+  });
+
+  const response = await fetch(
+    process.env.KEEL_TESTING_AUTH_API_URL + "/mfa/enrol",
+    {
+      method: "POST",
+      headers: {
+        Authorization: "Bearer " + body.access_token,
+      },
+    }
+  );
+
+  expect(response.status).toEqual(403);
+  expect(await response.json()).toEqual({
+    error: "unauthorized_client",
+    error_description:
+      "an impersonation access token cannot change how the identity authenticates",
+  });
+});
+
+test("impersonation - cannot sign the target out", async () => {
+  const support = await signIn("support@keel.xyz", true);
+  const customer = await signIn("customer@keel.xyz", true);
+
+  const { body } = await token({
+    grant_type: "impersonation",
+    actor_token: support.accessToken,
+    identity_id: customer.identity.id,
+  });
+
+  await expect(
+    actions.withAuthToken(body.access_token).revokeAllSessions()
+  ).toHaveAuthorizationError();
+
+  const sessions = await actions
+    .withIdentity(customer.identity)
+    .listSessions();
+  expect(sessions.sessions).toHaveLength(1);
+});
+
+test("impersonation - unknown identity - invalid request", async () => {
+  const support = await signIn("support@keel.xyz", true);
+
+  const { status, body } = await token({
+    grant_type: "impersonation",
+    actor_token: support.accessToken,
+    identity_id: "unknown",
+  });
+
+  expect(status).toEqual(400);
+  expect(body).toEqual({
+    error: "invalid_request",
+    error_description: "the identity to impersonate does not exist",
+  });
+});
+
+test("impersonation - missing actor token - invalid request", async () => {
+  const customer = await signIn("customer@keel.xyz", true);
+
+  const { status, body } = await token({
+    grant_type: "impersonation",
+    identity_id: customer.identity.id,
+  });
+
+  expect(status).toEqual(400);
+  expect(body).toEqual({
+    error: "invalid_request",
+    error_description:
+      "the impersonator's access token in the 'actor_token' field is required",
+  });
+});
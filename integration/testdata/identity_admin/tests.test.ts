@@ -0,0 +1,302 @@
+import { test, expect, beforeEach } from "vitest";
+import { actions, models, resetDatabase } from "@teamkeel/testing";
+import { useDatabase } from "@teamkeel/sdk";
+import { sql } from "kysely";
+
+beforeEach(resetDatabase);
+
+async function token(body: Record<string, any>) {
+  const response = await fetch(
+    process.env.KEEL_TESTING_AUTH_API_URL + "/token",
+    {
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify(body),
+    }
+  );
+
+  return { status: response.status, body: await response.json() };
+}
+
+async function signIn(email: string) {
+  const { body } = await token({
+    grant_type: "password",
+    username: email,
+    password: "1234",
+  });
+
+  const identity = await models.identity.findOne({
+    email: email,
+    issuer: "https://keel.so",
+  });
+
+  return {
+    identity: identity!,
+    accessToken: body.access_token as string,
+    refreshToken: body.refresh_token as string,
+  };
+}
+
+async function createAdmin() {
+  const admin = await models.identity.create({
+    email: "admin@keel.xyz",
+    issuer: "https://keel.so",
+  });
+
+  return await models.identity.update(
+    { id: admin.id },
+    { emailVerified: true }
+  );
+}
+
+test("list identities - not an admin - permission denied", async () => {
+  const { identity } = await signIn("user@keel.xyz");
+
+  await expect(
+    actions.withIdentity(identity).listIdentities({})
+  ).toHaveAuthorizationError();
+});
+
+test("list identities - search - matches email address", async () => {
+  const admin = await createAdmin();
+  await signIn("alice@keel.xyz");
+  await signIn("bob@keel.xyz");
+
+  const result = await actions
+    .withIdentity(admin)
+    .listIdentities({ search: "ALICE" });
+
+  expect(result.identities).toHaveLength(1);
+  expect(result.identities[0].email).toEqual("alice@keel.xyz");
+  expect(result.identities[0].disabled).toBeFalsy();
+  expect(result.hasNextPage).toBeFalsy();
+});
+
+test("list identities - paging - has next page", async () => {
+  const admin = await createAdmin();
+  await signIn("alice@keel.xyz");
+  await signIn("bob@keel.xyz");
+
+  const first = await actions
+    .withIdentity(admin)
+    .listIdentities({ first: 2 });
+  expect(first.identities).toHaveLength(2);
+  expect(first.hasNextPage).toBeTruthy();
+
+  const second = await actions
+    .withIdentity(admin)
+    .listIdentities({ first: 2, after: first.identities[1].id });
+  expect(second.identities).toHaveLength(1);
+  expect(second.hasNextPage).toBeFalsy();
+});
+
+test("disable identity - listed as disabled and sessions revoked", async () => {
+  const admin = await createAdmin();
+  const alice = await signIn("alice@keel.xyz");
+  await signIn("bob@keel.xyz");
+
+  const summary = await actions
+    .withIdentity(admin)
+    .disableIdentity({ identityId: alice.identity.id });
+  expect(summary.id).toEqual(alice.identity.id);
+  expect(summary.disabled).toBeTruthy();
+  expect(summary.disabledAt).not.toBeNull();
+
+  const disabled = await actions
+    .withIdentity(admin)
+    .listIdentities({ disabled: true });
+  expect(disabled.identities.map((i) => i.id)).toEqual([alice.identity.id]);
+
+  const enabled = await actions
+    .withIdentity(admin)
+    .listIdentities({ disabled: false });
+  expect(enabled.identities.map((i) => i.id)).not.toContain(alice.identity.id);
+
+  const sessions = await sql<{
+    token: string;
+  }>`SELECT token FROM keel_refresh_token WHERE identity_id = ${alice.identity.id}`.execute(
+    useDatabase()
+  );
+  expect(sessions.rows).toHaveLength(0);
+});
+
+test("disable identity - access token rejected", async () => {
+  const admin = await createAdmin();
+  const alice = await signIn("alice@keel.xyz");
+
+  await actions
+    .withIdentity(admin)
+    .disableIdentity({ identityId: alice.identity.id });
+
+  await expect(
+    actions.withAuthToken(alice.accessToken).listNotes()
+  ).toHaveAuthenticationError();
+});
+
+test("disable identity - cannot sign in or refresh", async () => {
+  const admin = await createAdmin();
+  const alice = await signIn("alice@keel.xyz");
+
+  await actions
+    .withIdentity(admin)
+    .disableIdentity({ identityId: alice.identity.id });
+
+  const password = await token({
+    grant_type: "password",
+    username: "alice@keel.xyz",
+    password: "1234",
+  });
+  expect(password.status).toEqual(401);
+  expect(password.body).toEqual({
+    error: "invalid_client",
+    error_description: "the identity has been disabled",
+  });
+
+  const refresh = await token({
+    grant_type: "refresh_token",
+    refresh_token: alice.refreshToken,
+  });
+  expect(refresh.status).toEqual(401);
+  expect(refresh.body.error).toEqual("invalid_client");
+});
+
+test("disable identity - own identity - invalid input", async () => {
+  const admin = await createAdmin();
+
+  await expect(
+    actions.withIdentity(admin).disableIdentity({ identityId: admin.id })
+  ).toHaveError({
+    code: "ERR_INVALID_INPUT",
+    message: "an identity cannot perform this action on itself",
+  });
+});
+
+test("disable identity - not an admin - permission denied", async () => {
+  const alice = await signIn("alice@keel.xyz");
+  const bob = await signIn("bob@keel.xyz");
+
+  await expect(
+    actions
+      .withIdentity(alice.identity)
+      .disableIdentity({ identityId: bob.identity.id })
+  ).toHaveAuthorizationError();
+});
+
+test("enable identity - can sign in again", async () => {
+  const admin = await createAdmin();
+  const alice = await signIn("alice@keel.xyz");
+
+  await actions
+    .withIdentity(admin)
+    .disableIdentity({ identityId: alice.identity.id });
+
+  const summary = await actions
+    .withIdentity(admin)
+    .enableIdentity({ identityId: alice.identity.id });
+  expect(summary.disabled).toBeFalsy();
+  expect(summary.disabledAt).toBeNull();
+
+  const { status } = await token({
+    grant_type: "password",
+    username: "alice@keel.xyz",
+    password: "1234",
+  });
+  expect(status).toEqual(200);
+});
+
+test("enable identity - unknown identity - not found", async () => {
+  const admin = await createAdmin();
+
+  await expect(
+    actions.withIdentity(admin).enableIdentity({ identityId: "unknown" })
+  ).toHaveError({
+    code: "ERR_RECORD_NOT_FOUND",
+    message: "identity not found",
+  });
+});
+
+test("force password reset - password removed", async () => {
+  const admin = await createAdmin();
+  const alice = await signIn("alice@keel.xyz");
+
+  await actions.withIdentity(admin).forcePasswordReset({
+    identityId: alice.identity.id,
+    redirectUrl: "https://mydomain.com/reset",
+  });
+
+  const identity = await models.identity.findOne({ id: alice.identity.id });
+  expect(identity!.password).toBeNull();
+
+  const { status, body } = await token({
+    grant_type: "password",
+    username: "alice@keel.xyz",
+    password: "1234",
+  });
+  expect(status).toEqual(401);
+  expect(body.error).toEqual("invalid_client");
+});
+
+test("force password reset - invalid redirectUrl - invalid input", async () => {
+  const admin = await createAdmin();
+  const alice = await signIn("alice@keel.xyz");
+
+  await expect(
+    actions.withIdentity(admin).forcePasswordReset({
+      identityId: alice.identity.id,
+      redirectUrl: "mydomain",
+    })
+  ).toHaveError({
+    code: "ERR_INVALID_INPUT",
+    message: "invalid redirect URL",
+  });
+});
+
+test("delete identity - identity and sessions removed", async () => {
+  const admin = await createAdmin();
+  const alice = await signIn("alice@keel.xyz");
+
+  await actions
+    .withIdentity(admin)
+    .deleteIdentity({ identityId: alice.identity.id });
+
+  expect(await models.identity.findOne({ id: alice.identity.id })).toBeNull();
+
+  const sessions = await sql<{
+    token: string;
+  }>`SELECT token FROM keel_refresh_token WHERE identity_id = ${alice.identity.id}`.execute(
+    useDatabase()
+  );
+  expect(sessions.rows).toHaveLength(0);
+});
+
+test("delete identity - referenced by other records - invalid input", async () => {
+  const admin = await createAdmin();
+  const alice = await signIn("alice@keel.xyz");
+
+  await actions
+    .withAuthToken(alice.accessToken)
+    .createNote({ text: "alice's note" });
+
+  await expect(
+    actions
+      .withIdentity(admin)
+      .deleteIdentity({ identityId: alice.identity.id })
+  ).rejects.toHaveProperty("code", "ERR_INVALID_INPUT");
+
+  expect(
+    await models.identity.findOne({ id: alice.identity.id })
+  ).not.toBeNull();
+});
+
+test("delete identity - own identity - invalid input", async () => {
+  const admin = await createAdmin();
+
+  await expect(
+    actions.withIdentity(admin).deleteIdentity({ identityId: admin.id })
+  ).toHaveError({
+    code: "ERR_INVALID_INPUT",
+    message: "an identity cannot perform this action on itself",
+  });
+});
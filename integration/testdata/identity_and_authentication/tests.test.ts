@@ -586,6 +586,60 @@ test("request reset password - unknown email - successful request", async () =>
   ).not.toHaveError({});
 });
 
+test("request email verification - invalid email - respond with invalid email address error", async () => {
+  await expect(
+    actions.requestEmailVerification({
+      email: "user",
+      redirectUrl: "https://mydomain.com",
+    })
+  ).rejects.toEqual({
+    code: "ERR_INVALID_INPUT",
+    message: "invalid email address",
+  });
+});
+
+test("request email verification - invalid redirectUrl - respond with invalid redirectUrl error", async () => {
+  await expect(
+    actions.requestEmailVerification({
+      email: "user@keel.xyz",
+      redirectUrl: "mydomain",
+    })
+  ).rejects.toEqual({
+    code: "ERR_INVALID_INPUT",
+    message: "invalid redirect URL",
+  });
+});
+
+test("request email verification - unknown email - successful request", async () => {
+  await models.identity.create({
+    email: "user@keel.xyz",
+    password: "123",
+  });
+
+  await expect(
+    actions.requestEmailVerification({
+      email: "another-user@keel.xyz",
+      redirectUrl: "https://mydomain.com",
+    })
+  ).not.toHaveError({});
+});
+
+test("verify email - invalid token - cannot be parsed", async () => {
+  await models.identity.create({
+    email: "user@keel.xyz",
+    password: "123",
+  });
+
+  await expect(
+    actions.verifyEmail({
+      token: "invalid",
+    })
+  ).rejects.toEqual({
+    code: "ERR_INVALID_INPUT",
+    message: "cannot be parsed or verified as a valid JWT",
+  });
+});
+
 // This test will break if we use a private key in the test runtime.
 test("reset password - invalid token - cannot be parsed", async () => {
   const identity = await models.identity.create({
@@ -913,3 +967,50 @@ test("identity filter by custom claim in function", async () => {
   const posts2 = await actions.readPostsByTeam({ team: "none" });
   expect(posts2).toHaveLength(0);
 });
+
+test("list sessions - not authenticated - permission denied", async () => {
+  await expect(actions.listSessions()).toHaveAuthorizationError();
+});
+
+test("list sessions - authenticated - no sessions", async () => {
+  const identity = await models.identity.create({
+    email: "user@keel.xyz",
+    password: "123",
+  });
+
+  const response = await actions.withIdentity(identity).listSessions();
+  expect(response.sessions).toHaveLength(0);
+});
+
+test("revoke session - unknown session - not found", async () => {
+  const identity = await models.identity.create({
+    email: "user@keel.xyz",
+    password: "123",
+  });
+
+  await expect(
+    actions.withIdentity(identity).revokeSession({ id: "unknown" })
+  ).toHaveError({
+    code: "ERR_RECORD_NOT_FOUND",
+    message: "session not found",
+  });
+});
+
+test("revoke all sessions - another identity without admin role - permission denied", async () => {
+  const identity = await models.identity.create({
+    email: "user@keel.xyz",
+    password: "123",
+  });
+  const another = await models.identity.create({
+    email: "another-user@keel.xyz",
+    password: "123",
+  });
+
+  await expect(
+    actions.withIdentity(identity).revokeAllSessions({ identityId: another.id })
+  ).toHaveAuthorizationError();
+
+  await expect(
+    actions.withIdentity(identity).revokeAllSessions()
+  ).not.toHaveError({});
+});
@@ -0,0 +1,153 @@
+import { test, expect, beforeEach } from "vitest";
+import { actions, resetDatabase } from "@teamkeel/testing";
+
+beforeEach(resetDatabase);
+
+async function createTree() {
+  const root = await actions.createCategory({ name: "Root" });
+  const clothing = await actions.createCategory({
+    name: "Clothing",
+    parent: { id: root.id },
+  });
+  const shoes = await actions.createCategory({
+    name: "Shoes",
+    parent: { id: clothing.id },
+  });
+  const trainers = await actions.createCategory({
+    name: "Trainers",
+    parent: { id: shoes.id },
+  });
+  const books = await actions.createCategory({
+    name: "Books",
+    parent: { id: root.id },
+  });
+
+  return { root, clothing, shoes, trainers, books };
+}
+
+test("List hierarchy - descendantsOf - returns the whole subtree", async () => {
+  const { root, clothing } = await createTree();
+
+  const { results } = await actions.listCategories({
+    where: { descendantsOf: { id: clothing.id } },
+  });
+  expect(results.map((c) => c.name).sort()).toEqual(["Shoes", "Trainers"]);
+
+  const { results: all } = await actions.listCategories({
+    where: { descendantsOf: { id: root.id } },
+  });
+  expect(all.map((c) => c.name).sort()).toEqual([
+    "Books",
+    "Clothing",
+    "Shoes",
+    "Trainers",
+  ]);
+});
+
+test("List hierarchy - descendantsOf with depth - limits the subtree", async () => {
+  const { root } = await createTree();
+
+  const { results } = await actions.listCategories({
+    where: { descendantsOf: { id: root.id, depth: 1 } },
+  });
+  expect(results.map((c) => c.name).sort()).toEqual(["Books", "Clothing"]);
+
+  const { results: twoLevels } = await actions.listCategories({
+    where: { descendantsOf: { id: root.id, depth: 2 } },
+  });
+  expect(twoLevels.map((c) => c.name).sort()).toEqual([
+    "Books",
+    "Clothing",
+    "Shoes",
+  ]);
+});
+
+test("List hierarchy - ancestorsOf - returns the path to the root", async () => {
+  const { trainers, shoes } = await createTree();
+
+  const { results } = await actions.listCategories({
+    where: { ancestorsOf: { id: trainers.id } },
+  });
+  expect(results.map((c) => c.name).sort()).toEqual([
+    "Clothing",
+    "Root",
+    "Shoes",
+  ]);
+
+  const { results: parent } = await actions.listCategories({
+    where: { ancestorsOf: { id: trainers.id, depth: 1 } },
+  });
+  expect(parent.map((c) => c.id)).toEqual([shoes.id]);
+});
+
+test("List hierarchy - combined with other filters - filters correctly", async () => {
+  const { root } = await createTree();
+
+  const { results } = await actions.listCategories({
+    where: {
+      name: { startsWith: "S" },
+      descendantsOf: { id: root.id },
+    },
+  });
+  expect(results.map((c) => c.name)).toEqual(["Shoes"]);
+});
+
+test("List hierarchy - in an or group - filters correctly", async () => {
+  const { clothing, trainers } = await createTree();
+
+  const { results } = await actions.listCategories({
+    where: {
+      or: [
+        { descendantsOf: { id: clothing.id } },
+        { ancestorsOf: { id: trainers.id, depth: 1 } },
+      ],
+    },
+  });
+  expect(results.map((c) => c.name).sort()).toEqual(["Shoes", "Trainers"]);
+});
+
+test("List hierarchy - leaf node - returns no descendants", async () => {
+  const { trainers } = await createTree();
+
+  const { results } = await actions.listCategories({
+    where: { descendantsOf: { id: trainers.id } },
+  });
+  expect(results).toHaveLength(0);
+});
+
+test("List hierarchy - invalid depth - returns error", async () => {
+  const { root } = await createTree();
+
+  await expect(
+    actions.listCategories({
+      where: { descendantsOf: { id: root.id, depth: 0 } },
+    })
+  ).toHaveError({
+    message: "hierarchy depth must be greater than zero",
+  });
+});
+
+test("List hierarchy - permissions are applied to each returned row", async () => {
+  const root = await actions.createCategory({ name: "Root" });
+  const hidden = await actions.createCategory({
+    name: "Hidden",
+    isPublic: false,
+    parent: { id: root.id },
+  });
+  await actions.createCategory({
+    name: "Visible",
+    parent: { id: hidden.id },
+  });
+
+  await expect(
+    actions.listCategories({
+      where: { descendantsOf: { id: root.id } },
+    })
+  ).toHaveAuthorizationError();
+
+  await expect(
+    actions.listCategories({
+      where: { descendantsOf: { id: hidden.id } },
+    })
+  ).not.toHaveAuthorizationError();
+});
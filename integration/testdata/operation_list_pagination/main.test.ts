@@ -94,3 +94,57 @@ test("hasNextPage", async () => {
 
   expect(hasNextPage).toEqual(true);
 });
+
+test("pagination - offset - limit and offset", async () => {
+  const posts = await setupPosts({ count: 6 });
+
+  const { results, pageInfo } = await actions.listPostsByPage({
+    limit: 2,
+    offset: 2,
+  });
+
+  expect(results.map((r) => r.id)).toEqual(posts.map((p) => p.id).slice(2, 4));
+  expect(pageInfo.count).toEqual(2);
+  expect(pageInfo.totalCount).toEqual(6);
+  expect(pageInfo.hasNextPage).toEqual(true);
+  expect(pageInfo.pageNumber).toEqual(2);
+  expect(pageInfo.totalPages).toEqual(3);
+});
+
+test("pagination - offset - last page", async () => {
+  const posts = await setupPosts({ count: 5 });
+
+  const { results, pageInfo } = await actions.listPostsByPage({
+    limit: 2,
+    offset: 4,
+  });
+
+  expect(results.map((r) => r.id)).toEqual(posts.map((p) => p.id).slice(4));
+  expect(pageInfo.hasNextPage).toEqual(false);
+  expect(pageInfo.pageNumber).toEqual(3);
+  expect(pageInfo.totalPages).toEqual(3);
+});
+
+test("pagination - offset - limit capped at max page size", async () => {
+  await setupPosts({ count: 6 });
+
+  const { results, pageInfo } = await actions.listPostsByPage({
+    limit: 100,
+  });
+
+  expect(results.length).toEqual(4);
+  expect(pageInfo.pageNumber).toEqual(1);
+  expect(pageInfo.totalPages).toEqual(2);
+});
+
+test("pagination - cursor - first capped at max page size", async () => {
+  await setupPosts({ count: 6 });
+
+  const { results, pageInfo } = await actions.listPostsCapped({
+    first: 1000000,
+  });
+
+  expect(results.length).toEqual(3);
+  expect(pageInfo.hasNextPage).toEqual(true);
+  expect(pageInfo.pageNumber).toBeUndefined();
+});
@@ -0,0 +1,79 @@
+import { test, expect, beforeEach } from "vitest";
+import { actions, resetDatabase } from "@teamkeel/testing";
+
+beforeEach(resetDatabase);
+
+beforeEach(async () => {
+  await actions.createContact({ name: "Adam", email: "adam@Keel.xyz" });
+  await actions.createContact({ name: "adaline", email: "adaline@gmail.com" });
+  await actions.createContact({ name: "Agent 47", email: "47@KEEL.XYZ" });
+  await actions.createContact({ name: "Ada_Lovelace", email: "ada@example.com" });
+});
+
+test("List text operators - equalsIgnoreCase - filters correctly", async () => {
+  const { results } = await actions.listContacts({
+    where: { name: { equalsIgnoreCase: "ADAM" } },
+  });
+
+  expect(results.map((c) => c.name)).toEqual(["Adam"]);
+});
+
+test("List text operators - equalsIgnoreCase with wildcard characters - filters correctly", async () => {
+  const { results } = await actions.listContacts({
+    where: { name: { equalsIgnoreCase: "ada_" } },
+  });
+
+  expect(results).toHaveLength(0);
+});
+
+test("List text operators - startsWithIgnoreCase - filters correctly", async () => {
+  const { results } = await actions.listContacts({
+    where: { name: { startsWithIgnoreCase: "ADA" } },
+  });
+
+  expect(results.map((c) => c.name).sort()).toEqual([
+    "Ada_Lovelace",
+    "Adam",
+    "adaline",
+  ]);
+});
+
+test("List text operators - endsWithIgnoreCase - filters correctly", async () => {
+  const { results } = await actions.listContacts({
+    where: { email: { endsWithIgnoreCase: "@keel.xyz" } },
+  });
+
+  expect(results.map((c) => c.name).sort()).toEqual(["Adam", "Agent 47"]);
+});
+
+test("List text operators - containsIgnoreCase - filters correctly", async () => {
+  const { results } = await actions.listContacts({
+    where: { name: { containsIgnoreCase: "LOVE" } },
+  });
+
+  expect(results.map((c) => c.name)).toEqual(["Ada_Lovelace"]);
+});
+
+test("List text operators - matches - filters correctly", async () => {
+  const { results } = await actions.listContacts({
+    where: { email: { matches: "^[a-z]+@" } },
+  });
+
+  expect(results.map((c) => c.name).sort()).toEqual([
+    "Ada_Lovelace",
+    "Adam",
+    "adaline",
+  ]);
+});
+
+test("List text operators - endsWithIgnoreCase in @where - filters correctly", async () => {
+  const { results } = await actions.listCompanyContacts();
+
+  expect(results.map((c) => c.name).sort()).toEqual(["Adam", "Agent 47"]);
+});
+
+test("List text operators - matches in @where - filters correctly", async () => {
+  const { results } = await actions.listNumberedContacts();
+
+  expect(results.map((c) => c.name)).toEqual(["Agent 47"]);
+});
@@ -0,0 +1,84 @@
+import { test, expect, beforeEach } from "vitest";
+import { actions, resetDatabase } from "@teamkeel/testing";
+import { Status } from "@teamkeel/sdk";
+
+beforeEach(resetDatabase);
+
+test("List where groups - or group - filters correctly", async () => {
+  const keelson = await actions.createAuthor({ name: "Keelson" });
+  await actions.createPost({ title: "First", status: Status.Open, views: 1 });
+  await actions.createPost({
+    title: "Second",
+    status: Status.Closed,
+    views: 2,
+    author: { id: keelson.id },
+  });
+  await actions.createPost({ title: "Third", status: Status.Closed, views: 3 });
+
+  const { results } = await actions.listPosts({
+    where: {
+      or: [
+        { status: { equals: Status.Open } },
+        { author: { name: { equals: "Keelson" } } },
+      ],
+    },
+  });
+
+  expect(results.map((p) => p.title).sort()).toEqual(["First", "Second"]);
+});
+
+test("List where groups - or group combined with top level filters - filters correctly", async () => {
+  await actions.createPost({ title: "First", status: Status.Open, views: 1 });
+  await actions.createPost({ title: "Second", status: Status.Closed, views: 2 });
+  await actions.createPost({ title: "Third", status: Status.Closed, views: 3 });
+
+  const { results } = await actions.listPosts({
+    where: {
+      views: { greaterThan: 1 },
+      or: [
+        { status: { equals: Status.Open } },
+        { title: { equals: "Third" } },
+      ],
+    },
+  });
+
+  expect(results.map((p) => p.title)).toEqual(["Third"]);
+});
+
+test("List where groups - not group - filters correctly", async () => {
+  await actions.createPost({ title: "First", status: Status.Open, views: 1 });
+  await actions.createPost({ title: "Second", status: Status.Closed, views: 2 });
+  await actions.createPost({ title: "Third", status: Status.Closed, views: 3 });
+
+  const { results } = await actions.listPosts({
+    where: {
+      not: {
+        or: [{ title: { equals: "First" } }, { views: { equals: 3 } }],
+      },
+    },
+  });
+
+  expect(results.map((p) => p.title)).toEqual(["Second"]);
+});
+
+test("List where groups - nested and group - filters correctly", async () => {
+  await actions.createPost({ title: "First", status: Status.Open, views: 1 });
+  await actions.createPost({ title: "Second", status: Status.Closed, views: 2 });
+  await actions.createPost({ title: "Third", status: Status.Closed, views: 3 });
+
+  const { results } = await actions.listPosts({
+    where: {
+      or: [
+        {
+          and: [
+            { status: { equals: Status.Closed } },
+            { views: { lessThan: 3 } },
+          ],
+        },
+        { title: { startsWith: "Fir" } },
+      ],
+    },
+  });
+
+  expect(results.map((p) => p.title).sort()).toEqual(["First", "Second"]);
+});
@@ -0,0 +1,275 @@
+import { test, expect, beforeEach } from "vitest";
+import { actions, resetDatabase } from "@teamkeel/testing";
+import { models } from "@teamkeel/sdk";
+
+beforeEach(resetDatabase);
+
+async function createIdentity(email: string) {
+  const identity = await models.identity.create({
+    email: email,
+    issuer: "https://keel.so",
+  });
+
+  return await models.identity.update(
+    { id: identity.id },
+    { emailVerified: true }
+  );
+}
+
+test("get - own salary - readable", async () => {
+  const identity = await createIdentity("bob@keel.xyz");
+  const employee = await models.employee.create({
+    name: "Bob",
+    salary: 50000,
+    identityId: identity.id,
+  });
+
+  const result = await actions
+    .withIdentity(identity)
+    .getEmployee({ id: employee.id });
+
+  expect(result!.name).toEqual("Bob");
+  expect(result!.salary).toEqual(50000);
+});
+
+test("get - another employee's salary - null", async () => {
+  const bob = await createIdentity("bob@keel.xyz");
+  const alice = await createIdentity("alice@keel.xyz");
+  const employee = await models.employee.create({
+    name: "Bob",
+    salary: 50000,
+    identityId: bob.id,
+  });
+
+  const result = await actions
+    .withIdentity(alice)
+    .getEmployee({ id: employee.id });
+
+  expect(result!.name).toEqual("Bob");
+  expect(result!.salary).toBeNull();
+});
+
+test("get - human resources role - readable", async () => {
+  const bob = await createIdentity("bob@keel.xyz");
+  const hr = await createIdentity("jane@hr.keel.xyz");
+  const employee = await models.employee.create({
+    name: "Bob",
+    salary: 50000,
+    identityId: bob.id,
+  });
+
+  const result = await actions
+    .withIdentity(hr)
+    .getEmployee({ id: employee.id });
+
+  expect(result!.salary).toEqual(50000);
+});
+
+test("list - only own salary readable", async () => {
+  const bob = await createIdentity("bob@keel.xyz");
+  const alice = await createIdentity("alice@keel.xyz");
+  await models.employee.create({
+    name: "Bob",
+    salary: 50000,
+    identityId: bob.id,
+  });
+  await models.employee.create({
+    name: "Alice",
+    salary: 60000,
+    identityId: alice.id,
+  });
+
+  const result = await actions.withIdentity(alice).listEmployees();
+
+  expect(result.results).toHaveLength(2);
+  const bobResult = result.results.find((e) => e.name === "Bob");
+  const aliceResult = result.results.find((e) => e.name === "Alice");
+  expect(bobResult!.salary).toBeNull();
+  expect(aliceResult!.salary).toEqual(60000);
+});
+
+test("list - filter by salary without human resources role - permission denied", async () => {
+  const bob = await createIdentity("bob@keel.xyz");
+  const alice = await createIdentity("alice@keel.xyz");
+  await models.employee.create({
+    name: "Bob",
+    salary: 50000,
+    identityId: bob.id,
+  });
+
+  await expect(
+    actions
+      .withIdentity(alice)
+      .searchEmployees({ where: { salary: { greaterThan: 40000 } } })
+  ).toHaveError({
+    code: "ERR_PERMISSION_DENIED",
+    message: "not authorized to filter or order by field 'salary'",
+  });
+});
+
+test("list - order by salary without human resources role - permission denied", async () => {
+  const alice = await createIdentity("alice@keel.xyz");
+
+  await expect(
+    actions
+      .withIdentity(alice)
+      .searchEmployees({ orderBy: [{ salary: "desc" }] })
+  ).toHaveError({
+    code: "ERR_PERMISSION_DENIED",
+    message: "not authorized to filter or order by field 'salary'",
+  });
+});
+
+test("list - filter and order by salary with human resources role - permitted", async () => {
+  const bob = await createIdentity("bob@keel.xyz");
+  const alice = await createIdentity("alice@keel.xyz");
+  const hr = await createIdentity("jane@hr.keel.xyz");
+  await models.employee.create({
+    name: "Bob",
+    salary: 50000,
+    identityId: bob.id,
+  });
+  await models.employee.create({
+    name: "Alice",
+    salary: 60000,
+    identityId: alice.id,
+  });
+
+  const result = await actions.withIdentity(hr).searchEmployees({
+    where: { salary: { greaterThan: 40000 } },
+    orderBy: [{ salary: "desc" }],
+  });
+
+  expect(result.results.map((e) => e.name)).toEqual(["Alice", "Bob"]);
+});
+
+test("list - filter and order by readable fields - permitted", async () => {
+  const bob = await createIdentity("bob@keel.xyz");
+  const department = await models.department.create({ name: "Sales" });
+  await models.employee.create({
+    name: "Bob",
+    salary: 50000,
+    identityId: bob.id,
+    departmentId: department.id,
+  });
+
+  const result = await actions.withIdentity(bob).searchEmployees({
+    where: { department: { name: { equals: "Sales" } } },
+    orderBy: [{ name: "asc" }],
+  });
+
+  expect(result.results).toHaveLength(1);
+  expect(result.results[0].salary).toEqual(50000);
+});
+
+test("embedded - another employee's salary - null", async () => {
+  const bob = await createIdentity("bob@keel.xyz");
+  const alice = await createIdentity("alice@keel.xyz");
+  const department = await models.department.create({ name: "Sales" });
+  await models.employee.create({
+    name: "Bob",
+    salary: 50000,
+    identityId: bob.id,
+    departmentId: department.id,
+  });
+  await models.employee.create({
+    name: "Alice",
+    salary: 60000,
+    identityId: alice.id,
+    departmentId: department.id,
+  });
+
+  const result = await actions
+    .withIdentity(alice)
+    .getDepartment({ id: department.id });
+
+  expect(result!.employees).toHaveLength(2);
+  const bobResult = result!.employees.find((e) => e.name === "Bob");
+  const aliceResult = result!.employees.find((e) => e.name === "Alice");
+  expect(bobResult!.salary).toBeNull();
+  expect(aliceResult!.salary).toEqual(60000);
+});
+
+test("update - salary without human resources role - permission denied", async () => {
+  const bob = await createIdentity("bob@keel.xyz");
+  const employee = await models.employee.create({
+    name: "Bob",
+    salary: 50000,
+    identityId: bob.id,
+  });
+
+  await expect(
+    actions
+      .withIdentity(bob)
+      .updateEmployee({ where: { id: employee.id }, values: { salary: 90000 } })
+  ).toHaveError({
+    code: "ERR_PERMISSION_DENIED",
+    message: "not authorized to write to field 'salary'",
+  });
+
+  const unchanged = await models.employee.findOne({ id: employee.id });
+  expect(unchanged!.salary).toEqual(50000);
+});
+
+test("update - other fields without human resources role - permitted", async () => {
+  const bob = await createIdentity("bob@keel.xyz");
+  const employee = await models.employee.create({
+    name: "Bob",
+    salary: 50000,
+    identityId: bob.id,
+  });
+
+  const result = await actions
+    .withIdentity(bob)
+    .updateEmployee({ where: { id: employee.id }, values: { name: "Robert" } });
+
+  expect(result.name).toEqual("Robert");
+  expect(result.salary).toEqual(50000);
+});
+
+test("update - salary with human resources role - permitted", async () => {
+  const bob = await createIdentity("bob@keel.xyz");
+  const hr = await createIdentity("jane@hr.keel.xyz");
+  const employee = await models.employee.create({
+    name: "Bob",
+    salary: 50000,
+    identityId: bob.id,
+  });
+
+  const result = await actions
+    .withIdentity(hr)
+    .updateEmployee({ where: { id: employee.id }, values: { salary: 90000 } });
+
+  expect(result.salary).toEqual(90000);
+});
+
+test("create - salary without human resources role - permission denied", async () => {
+  const bob = await createIdentity("bob@keel.xyz");
+
+  await expect(
+    actions.withIdentity(bob).createEmployee({
+      name: "Bob",
+      salary: 50000,
+      identity: { id: bob.id },
+    })
+  ).toHaveError({
+    code: "ERR_PERMISSION_DENIED",
+    message: "not authorized to write to field 'salary'",
+  });
+
+  const employees = await models.employee.findMany();
+  expect(employees).toHaveLength(0);
+});
+
+test("create - salary with human resources role - permitted", async () => {
+  const bob = await createIdentity("bob@keel.xyz");
+  const hr = await createIdentity("jane@hr.keel.xyz");
+
+  const result = await actions.withIdentity(hr).createEmployee({
+    name: "Bob",
+    salary: 50000,
+    identity: { id: bob.id },
+  });
+
+  expect(result.salary).toEqual(50000);
+});
@@ -0,0 +1,219 @@
+import { test, expect, beforeEach } from "vitest";
+import { actions, resetDatabase } from "@teamkeel/testing";
+import { models } from "@teamkeel/sdk";
+
+beforeEach(resetDatabase);
+
+async function createAdmin() {
+  const admin = await models.identity.create({
+    email: "admin@keel.xyz",
+    issuer: "https://keel.so",
+  });
+
+  return await models.identity.update(
+    { id: admin.id },
+    { emailVerified: true }
+  );
+}
+
+test("assign role - not an admin - permission denied", async () => {
+  const identity = await models.identity.create({
+    email: "user@keel.xyz",
+    issuer: "https://keel.so",
+  });
+
+  await expect(
+    actions
+      .withIdentity(identity)
+      .assignRole({ identityId: identity.id, role: "Editor" })
+  ).toHaveAuthorizationError();
+});
+
+test("assign role - unknown role - invalid input", async () => {
+  const admin = await createAdmin();
+
+  await expect(
+    actions
+      .withIdentity(admin)
+      .assignRole({ identityId: admin.id, role: "Unknown" })
+  ).toHaveError({
+    code: "ERR_INVALID_INPUT",
+    message: "role 'Unknown' does not exist",
+  });
+});
+
+test("assign role - unknown identity - not found", async () => {
+  const admin = await createAdmin();
+
+  await expect(
+    actions
+      .withIdentity(admin)
+      .assignRole({ identityId: "unknown", role: "Editor" })
+  ).toHaveError({
+    code: "ERR_RECORD_NOT_FOUND",
+    message: "identity not found",
+  });
+});
+
+test("assign role - model without record - invalid input", async () => {
+  const admin = await createAdmin();
+
+  await expect(
+    actions
+      .withIdentity(admin)
+      .assignRole({ identityId: admin.id, role: "Editor", model: "Document" })
+  ).toHaveError({
+    code: "ERR_INVALID_INPUT",
+    message: "model and recordId must be provided together",
+  });
+});
+
+test("assign role - all records - is authorized", async () => {
+  const admin = await createAdmin();
+  const identity = await models.identity.create({
+    email: "user@keel.xyz",
+    issuer: "https://keel.so",
+  });
+  const document = await actions.createDocument({ title: "Plan" });
+
+  await expect(
+    actions.withIdentity(identity).getDocument({ id: document.id })
+  ).toHaveAuthorizationError();
+
+  const membership = await actions
+    .withIdentity(admin)
+    .assignRole({ identityId: identity.id, role: "Editor" });
+
+  expect(membership.identityId).toEqual(identity.id);
+  expect(membership.role).toEqual("Editor");
+  expect(membership.model).toBeNull();
+  expect(membership.recordId).toBeNull();
+
+  await expect(
+    actions.withIdentity(identity).getDocument({ id: document.id })
+  ).resolves.toEqual(document);
+
+  await expect(
+    actions
+      .withIdentity(identity)
+      .updateDocument({ where: { id: document.id }, values: { title: "New" } })
+  ).resolves.toMatchObject({ title: "New" });
+});
+
+test("assign role - twice - returns existing membership", async () => {
+  const admin = await createAdmin();
+
+  const first = await actions
+    .withIdentity(admin)
+    .assignRole({ identityId: admin.id, role: "Editor" });
+  const second = await actions
+    .withIdentity(admin)
+    .assignRole({ identityId: admin.id, role: "Editor" });
+
+  expect(second.id).toEqual(first.id);
+
+  const response = await actions.withIdentity(admin).listRoleMemberships();
+  expect(response.memberships).toHaveLength(1);
+});
+
+test("assign role - single record - only authorized for that record", async () => {
+  const admin = await createAdmin();
+  const identity = await models.identity.create({
+    email: "user@keel.xyz",
+    issuer: "https://keel.so",
+  });
+  const document = await actions.createDocument({ title: "Plan" });
+  const another = await actions.createDocument({ title: "Budget" });
+
+  await actions.withIdentity(admin).assignRole({
+    identityId: identity.id,
+    role: "Editor",
+    model: "Document",
+    recordId: document.id,
+  });
+
+  await expect(
+    actions.withIdentity(identity).getDocument({ id: document.id })
+  ).resolves.toEqual(document);
+
+  await expect(
+    actions.withIdentity(identity).getDocument({ id: another.id })
+  ).toHaveAuthorizationError();
+
+  await expect(
+    actions
+      .withIdentity(identity)
+      .listDocuments({ where: { id: { equals: document.id } } })
+  ).resolves.toMatchObject({ results: [document] });
+});
+
+test("revoke role - no longer authorized", async () => {
+  const admin = await createAdmin();
+  const identity = await models.identity.create({
+    email: "user@keel.xyz",
+    issuer: "https://keel.so",
+  });
+  const document = await actions.createDocument({ title: "Plan" });
+
+  await actions
+    .withIdentity(admin)
+    .assignRole({ identityId: identity.id, role: "Editor" });
+
+  await expect(
+    actions.withIdentity(identity).getDocument({ id: document.id })
+  ).resolves.toEqual(document);
+
+  await actions
+    .withIdentity(admin)
+    .revokeRole({ identityId: identity.id, role: "Editor" });
+
+  await expect(
+    actions.withIdentity(identity).getDocument({ id: document.id })
+  ).toHaveAuthorizationError();
+
+  await expect(
+    actions
+      .withIdentity(admin)
+      .revokeRole({ identityId: identity.id, role: "Editor" })
+  ).toHaveError({
+    code: "ERR_RECORD_NOT_FOUND",
+    message: "role membership not found",
+  });
+});
+
+test("list role memberships - own roles - is authorized", async () => {
+  const admin = await createAdmin();
+  const identity = await models.identity.create({
+    email: "user@keel.xyz",
+    issuer: "https://keel.so",
+  });
+
+  await actions
+    .withIdentity(admin)
+    .assignRole({ identityId: identity.id, role: "Editor" });
+
+  const response = await actions
+    .withIdentity(identity)
+    .listRoleMemberships();
+
+  expect(response.memberships).toHaveLength(1);
+  expect(response.memberships[0].role).toEqual("Editor");
+});
+
+test("list role memberships - another identity without admin role - permission denied", async () => {
+  const admin = await createAdmin();
+  const identity = await models.identity.create({
+    email: "user@keel.xyz",
+    issuer: "https://keel.so",
+  });
+
+  await expect(
+    actions
+      .withIdentity(identity)
+      .listRoleMemberships({ identityId: admin.id })
+  ).toHaveAuthorizationError();
+
+  await expect(
+    actions.withIdentity(identity).listRoleMemberships({ role: "Editor" })
+  ).toHaveAuthorizationError();
+});
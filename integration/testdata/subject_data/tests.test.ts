@@ -0,0 +1,193 @@
+import { test, expect, beforeEach } from "vitest";
+import { actions, models, resetDatabase } from "@teamkeel/testing";
+import { useDatabase } from "@teamkeel/sdk";
+import { sql } from "kysely";
+
+beforeEach(resetDatabase);
+
+async function createAdmin() {
+  const admin = await models.identity.create({
+    email: "admin@keel.xyz",
+    issuer: "https://keel.so",
+  });
+
+  return await models.identity.update(
+    { id: admin.id },
+    { emailVerified: true }
+  );
+}
+
+async function createCustomer(email: string) {
+  const identity = await models.identity.create({
+    email: email,
+    issuer: "https://keel.so",
+    password: "1234",
+  });
+
+  const customer = await models.customer.create({
+    name: "Alice",
+    phone: "07700 900000",
+    loyaltyTier: "gold",
+    identityId: identity.id,
+  });
+
+  const address = await models.address.create({
+    line1: "1 High Street",
+    customerId: customer.id,
+  });
+
+  const order = await models.order.create({
+    reference: "ORD-1",
+    customerId: customer.id,
+  });
+
+  const riskAssessment = await models.riskAssessment.create({
+    score: 3,
+    customerId: customer.id,
+  });
+
+  return { identity, customer, address, order, riskAssessment };
+}
+
+test("export identity data - own data - related records exported", async () => {
+  const { identity, customer, address, order } =
+    await createCustomer("alice@keel.xyz");
+  await createCustomer("bob@keel.xyz");
+
+  const exported = await actions
+    .withIdentity(identity)
+    .exportIdentityData({ identityId: identity.id });
+
+  expect(exported.identity.id).toEqual(identity.id);
+  expect(exported.identity.email).toEqual("alice@keel.xyz");
+  expect(exported.identity.password).toBeUndefined();
+  expect(exported.records.Customer.map((c: any) => c.id)).toEqual([
+    customer.id,
+  ]);
+  expect(exported.records.Address.map((a: any) => a.id)).toEqual([address.id]);
+  expect(exported.records.Order.map((o: any) => o.id)).toEqual([order.id]);
+});
+
+test("export identity data - own data - records without get permission not exported", async () => {
+  const { identity } = await createCustomer("alice@keel.xyz");
+
+  const exported = await actions
+    .withIdentity(identity)
+    .exportIdentityData({ identityId: identity.id });
+
+  expect(exported.records.RiskAssessment).toBeUndefined();
+});
+
+test("export identity data - another identity - permission denied", async () => {
+  const alice = await createCustomer("alice@keel.xyz");
+  const bob = await createCustomer("bob@keel.xyz");
+
+  await expect(
+    actions
+      .withIdentity(alice.identity)
+      .exportIdentityData({ identityId: bob.identity.id })
+  ).toHaveAuthorizationError();
+});
+
+test("export identity data - admin - another identity exported", async () => {
+  const admin = await createAdmin();
+  const { identity, riskAssessment } = await createCustomer("alice@keel.xyz");
+
+  const exported = await actions
+    .withIdentity(admin)
+    .exportIdentityData({ identityId: identity.id });
+
+  expect(exported.identity.id).toEqual(identity.id);
+  expect(exported.records.RiskAssessment.map((r: any) => r.id)).toEqual([
+    riskAssessment.id,
+  ]);
+});
+
+test("erase identity data - pii deleted and anonymised", async () => {
+  const admin = await createAdmin();
+  const { identity, customer, address, order } =
+    await createCustomer("alice@keel.xyz");
+  const bob = await createCustomer("bob@keel.xyz");
+
+  const result = await actions
+    .withIdentity(admin)
+    .eraseIdentityData({ identityId: identity.id });
+  expect(result).toEqual({ deleted: 1, anonymised: 1 });
+
+  expect(await models.address.findOne({ id: address.id })).toBeNull();
+  expect(await models.address.findOne({ id: bob.address.id })).not.toBeNull();
+
+  const erasedCustomer = await models.customer.findOne({ id: customer.id });
+  expect(erasedCustomer!.name).toEqual("[redacted]");
+  expect(erasedCustomer!.phone).toBeNull();
+  expect(erasedCustomer!.loyaltyTier).toEqual("gold");
+
+  expect(await models.order.findOne({ id: order.id })).not.toBeNull();
+
+  const erasedIdentity = await models.identity.findOne({ id: identity.id });
+  expect(erasedIdentity!.email).toBeNull();
+  expect(erasedIdentity!.password).toBeNull();
+  expect(erasedIdentity!.emailVerified).toBeFalsy();
+
+  const bobCustomer = await models.customer.findOne({ id: bob.customer.id });
+  expect(bobCustomer!.name).toEqual("Alice");
+});
+
+test("erase identity data - audit log scrubbed", async () => {
+  const admin = await createAdmin();
+  const { identity, customer, address } =
+    await createCustomer("alice@keel.xyz");
+
+  await actions
+    .withIdentity(admin)
+    .eraseIdentityData({ identityId: identity.id });
+
+  const audits = await sql<{
+    tableName: string;
+    data: any;
+  }>`SELECT table_name, data FROM keel_audit WHERE data->>'id' IN (${identity.id}, ${customer.id}, ${address.id})`.execute(
+    useDatabase()
+  );
+  expect(audits.rows.length).toBeGreaterThan(0);
+
+  for (const audit of audits.rows) {
+    const snapshot = JSON.stringify(audit.data);
+    expect(snapshot).not.toContain("alice@keel.xyz");
+    expect(snapshot).not.toContain("07700 900000");
+    expect(snapshot).not.toContain("1 High Street");
+    expect(snapshot).not.toContain('"Alice"');
+  }
+});
+
+test("erase identity data - not an admin - permission denied", async () => {
+  const alice = await createCustomer("alice@keel.xyz");
+  const bob = await createCustomer("bob@keel.xyz");
+
+  await expect(
+    actions
+      .withIdentity(alice.identity)
+      .eraseIdentityData({ identityId: bob.identity.id })
+  ).toHaveAuthorizationError();
+});
+
+test("erase identity data - own identity - invalid input", async () => {
+  const admin = await createAdmin();
+
+  await expect(
+    actions.withIdentity(admin).eraseIdentityData({ identityId: admin.id })
+  ).toHaveError({
+    code: "ERR_INVALID_INPUT",
+    message: "an identity cannot perform this action on itself",
+  });
+});
+
+test("erase identity data - unknown identity - not found", async () => {
+  const admin = await createAdmin();
+
+  await expect(
+    actions.withIdentity(admin).eraseIdentityData({ identityId: "unknown" })
+  ).toHaveError({
+    code: "ERR_RECORD_NOT_FOUND",
+    message: "identity not found",
+  });
+});
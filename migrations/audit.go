@@ -97,6 +97,16 @@ func auditModel() *proto.Model {
 				},
 				Optional: true,
 			},
+			{
+				ModelName: modelName,
+				Name:      strcase.ToLowerCamel(auditing.ColumnImpersonatorId),
+				Type: &proto.TypeInfo{
+					Type:      proto.Type_TYPE_ID,
+					ModelName: wrapperspb.String(modelName),
+					FieldName: wrapperspb.String(strcase.ToLowerCamel(auditing.ColumnImpersonatorId)),
+				},
+				Optional: true,
+			},
 			{
 				ModelName: modelName,
 				Name:      strcase.ToLowerCamel(auditing.ColumnTraceId),
@@ -117,6 +127,16 @@ func auditModel() *proto.Model {
 				},
 				Optional: true,
 			},
+			{
+				ModelName: modelName,
+				Name:      strcase.ToLowerCamel(auditing.ColumnTransactionId),
+				Type: &proto.TypeInfo{
+					Type:      proto.Type_TYPE_INT,
+					ModelName: wrapperspb.String(modelName),
+					FieldName: wrapperspb.String(strcase.ToLowerCamel(auditing.ColumnTransactionId)),
+				},
+				Optional: false,
+			},
 		},
 	}
 	return &mdl
@@ -16,7 +16,7 @@ LEFT JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
 LEFT JOIN pg_catalog.pg_index i on i.indexrelid = a.attrelid
 WHERE
 	n.nspname = 'public'
-	AND c.relname not in ('keel_schema', 'keel_refresh_token', 'keel_storage', 'keel_auth_code', 'pg_stat_statements_info', 'pg_stat_statements')
+	AND c.relname not in ('keel_schema', 'keel_refresh_token', 'keel_storage', 'keel_auth_code', 'keel_service_client', 'keel_auth_attempt', 'keel_identity_link', 'keel_role_membership', 'keel_passwordless_code', 'keel_passwordless_attempt', 'keel_mfa_factor', 'keel_mfa_recovery_code', 'pg_stat_statements_info', 'pg_stat_statements')
 	AND a.attnum > 0
 	AND NOT a.attisdropped
 	AND i.indexrelid is null; -- no indexes
@@ -39,11 +39,20 @@ var (
 	//go:embed set_identity_id.sql
 	setIdentityId string
 
+	//go:embed set_impersonator_id.sql
+	setImpersonatorId string
+
 	//go:embed set_trace_id.sql
 	setTraceId string
 
 	//go:embed set_updated_at.sql
 	setUpdatedAt string
+
+	//go:embed row_level_security_check.sql
+	rowLevelSecurityCheck string
+
+	//go:embed row_level_security_drop.sql
+	dropRowLevelSecurity string
 )
 
 type DatabaseChange struct {
@@ -72,6 +81,18 @@ type Migrations struct {
 
 	// The SQL to run to execute the database schema changes
 	SQL string
+
+	// Whether row-level security policies are generated from the permission rules
+	rowLevelSecurity bool
+}
+
+type Option func(m *Migrations)
+
+// WithRowLevelSecurity enables or disables the row-level security policies generated from the permission rules.
+func WithRowLevelSecurity(enabled bool) Option {
+	return func(m *Migrations) {
+		m.rowLevelSecurity = enabled
+	}
 }
 
 // HasModelFieldChanges returns true if the migrations contain model field changes to be applied
@@ -104,10 +125,14 @@ func (m *Migrations) Apply(ctx context.Context, dryRun bool) error {
 	sql.WriteString("\n")
 	sql.WriteString(setIdentityId)
 	sql.WriteString("\n")
+	sql.WriteString(setImpersonatorId)
+	sql.WriteString("\n")
 	sql.WriteString(setTraceId)
 	sql.WriteString("\n")
 	sql.WriteString(setUpdatedAt)
 	sql.WriteString("\n")
+	sql.WriteString(rowLevelSecurityCheck)
+	sql.WriteString("\n")
 
 	sql.WriteString("CREATE TABLE IF NOT EXISTS keel_schema (schema TEXT NOT NULL);\n")
 	sql.WriteString("DELETE FROM keel_schema;\n")
@@ -122,9 +147,39 @@ func (m *Migrations) Apply(ctx context.Context, dryRun bool) error {
 	sql.WriteString("\n")
 
 	sql.WriteString("CREATE TABLE IF NOT EXISTS keel_refresh_token (token TEXT NOT NULL PRIMARY KEY, identity_id TEXT NOT NULL, created_at TIMESTAMP, expires_at TIMESTAMP);\n")
+	sql.WriteString("ALTER TABLE keel_refresh_token ADD COLUMN IF NOT EXISTS mfa BOOLEAN NOT NULL DEFAULT false;\n")
+	sql.WriteString("ALTER TABLE keel_refresh_token ADD COLUMN IF NOT EXISTS session_id TEXT NOT NULL DEFAULT ksuid(), ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMP, ADD COLUMN IF NOT EXISTS user_agent TEXT, ADD COLUMN IF NOT EXISTS ip_address TEXT;\n")
+	sql.WriteString("CREATE INDEX IF NOT EXISTS idx_keel_refresh_token_identity_id ON keel_refresh_token (identity_id);\n")
 	sql.WriteString("\n")
 
 	sql.WriteString("CREATE TABLE IF NOT EXISTS keel_auth_code (code TEXT NOT NULL PRIMARY KEY, identity_id TEXT NOT NULL, created_at TIMESTAMP, expires_at TIMESTAMP);\n")
+	sql.WriteString("ALTER TABLE keel_auth_code ADD COLUMN IF NOT EXISTS code_challenge TEXT, ADD COLUMN IF NOT EXISTS code_challenge_method TEXT;\n")
+	sql.WriteString("ALTER TABLE keel_auth_code ALTER COLUMN identity_id DROP NOT NULL, ADD COLUMN IF NOT EXISTS link_issuer TEXT, ADD COLUMN IF NOT EXISTS link_external_id TEXT, ADD COLUMN IF NOT EXISTS link_email TEXT;\n")
+	sql.WriteString("\n")
+
+	sql.WriteString("CREATE TABLE IF NOT EXISTS keel_passwordless_code (code TEXT NOT NULL, email TEXT NOT NULL, created_at TIMESTAMP, expires_at TIMESTAMP, PRIMARY KEY (email, code));\n")
+	sql.WriteString("CREATE TABLE IF NOT EXISTS keel_passwordless_attempt (email TEXT NOT NULL PRIMARY KEY, issued INTEGER NOT NULL DEFAULT 0, failures INTEGER NOT NULL DEFAULT 0, window_started_at TIMESTAMP);\n")
+	sql.WriteString("\n")
+
+	sql.WriteString("CREATE TABLE IF NOT EXISTS keel_mfa_factor (identity_id TEXT NOT NULL PRIMARY KEY, secret TEXT NOT NULL, confirmed BOOLEAN NOT NULL DEFAULT false, last_used_step BIGINT NOT NULL DEFAULT 0, failed_attempts INTEGER NOT NULL DEFAULT 0, created_at TIMESTAMP);\n")
+	sql.WriteString("CREATE TABLE IF NOT EXISTS keel_mfa_recovery_code (identity_id TEXT NOT NULL, code TEXT NOT NULL, created_at TIMESTAMP, PRIMARY KEY (identity_id, code));\n")
+	sql.WriteString("\n")
+
+	sql.WriteString("CREATE TABLE IF NOT EXISTS keel_auth_attempt (key TEXT NOT NULL PRIMARY KEY, failures INTEGER NOT NULL DEFAULT 0, last_failed_at TIMESTAMP, locked_until TIMESTAMP);\n")
+	sql.WriteString("\n")
+
+	sql.WriteString("CREATE TABLE IF NOT EXISTS keel_identity_link (issuer TEXT NOT NULL, external_id TEXT NOT NULL, identity_id TEXT NOT NULL, email TEXT, created_at TIMESTAMP, PRIMARY KEY (issuer, external_id));\n")
+	sql.WriteString("CREATE INDEX IF NOT EXISTS idx_keel_identity_link_identity_id ON keel_identity_link (identity_id);\n")
+	sql.WriteString("\n")
+
+	sql.WriteString("CREATE TABLE IF NOT EXISTS keel_role_membership (id TEXT NOT NULL PRIMARY KEY DEFAULT ksuid(), identity_id TEXT NOT NULL, role TEXT NOT NULL, model TEXT, record_id TEXT, created_at TIMESTAMP);\n")
+	sql.WriteString("CREATE UNIQUE INDEX IF NOT EXISTS idx_keel_role_membership_unique ON keel_role_membership (identity_id, role, COALESCE(model, ''), COALESCE(record_id, ''));\n")
+	sql.WriteString("\n")
+
+	sql.WriteString("CREATE TABLE IF NOT EXISTS keel_identity_disabled (identity_id TEXT NOT NULL PRIMARY KEY, disabled_at TIMESTAMP);\n")
+	sql.WriteString("\n")
+
+	sql.WriteString("CREATE TABLE IF NOT EXISTS keel_service_client (client_id TEXT NOT NULL PRIMARY KEY, name TEXT NOT NULL, secret TEXT NOT NULL, roles TEXT[] NOT NULL DEFAULT '{}', created_at TIMESTAMP);\n")
 	sql.WriteString("\n")
 
 	sql.WriteString(fmt.Sprintf("SELECT set_trace_id('%s');\n", span.SpanContext().TraceID().String()))
@@ -135,10 +190,24 @@ func (m *Migrations) Apply(ctx context.Context, dryRun bool) error {
 	// For now, we do this here but this could belong in our proto once we start on the database indexing work.
 	sql.WriteString("CREATE INDEX IF NOT EXISTS idx_keel_audit_trace_id ON keel_audit USING HASH(trace_id);\n")
 	sql.WriteString("CREATE INDEX IF NOT EXISTS idx_keel_audit_table_name_data_id_created_at ON keel_audit (table_name, (data->>'id'), created_at);\n")
+	sql.WriteString("CREATE INDEX IF NOT EXISTS idx_keel_audit_table_name_transaction_id_id ON keel_audit (table_name, transaction_id, id);\n")
 
 	// Data migration when migrating to new authentication methods.
 	sql.WriteString("UPDATE identity SET issuer = 'https://keel.so' WHERE issuer = 'keel';\n")
 
+	// Row-level security policies are recreated each time as they're derived from the permission rules.
+	if m.rowLevelSecurity {
+		policies, err := rowLevelSecurityStmts(m.Schema)
+		if err != nil {
+			return err
+		}
+		sql.WriteString(policies)
+		sql.WriteString("\n")
+	} else {
+		sql.WriteString(dropRowLevelSecurity)
+		sql.WriteString("\n")
+	}
+
 	if dryRun {
 		sql.WriteString("ROLLBACK TRANSACTION;\n")
 	}
@@ -160,7 +229,7 @@ func (m *Migrations) Apply(ctx context.Context, dryRun bool) error {
 // New creates a new Migrations instance for the given schema and database.
 // Introspection is performed on the database to work out what schema changes
 // need to be applied to result in the database schema matching the Keel schema
-func New(ctx context.Context, schema *proto.Schema, database db.Database) (*Migrations, error) {
+func New(ctx context.Context, schema *proto.Schema, database db.Database, opts ...Option) (*Migrations, error) {
 	_, span := tracer.Start(ctx, "Generate Migrations")
 	defer span.End()
 
@@ -364,12 +433,18 @@ func New(ctx context.Context, schema *proto.Schema, database db.Database) (*Migr
 	stringChanges := lo.Map(changes, func(c *DatabaseChange, _ int) string { return c.String() })
 	span.SetAttributes(attribute.StringSlice("migration", stringChanges))
 
-	return &Migrations{
+	m := &Migrations{
 		database: database,
 		Schema:   schema,
 		Changes:  changes,
 		SQL:      strings.TrimSpace(strings.Join(statements, "\n")),
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m, nil
 }
 
 // compositeUniqueConstraintsForModel finds all composite unique constraints in model and
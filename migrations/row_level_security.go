@@ -0,0 +1,155 @@
+package migrations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/teamkeel/keel/casing"
+	"github.com/teamkeel/keel/db"
+	"github.com/teamkeel/keel/permissions"
+	"github.com/teamkeel/keel/proto"
+)
+
+// The session setting containing the identity id, which is also used for auditing.
+const identityIdSetting = "current_setting('audit.identity_id', true)"
+
+type rowLevelSecurityPolicy struct {
+	// Used in the names of the policy and permission function
+	name string
+	// The command the policy applies to
+	command string
+	// The action types whose model-level permission rules make up the policy
+	actionTypes []proto.ActionType
+}
+
+var rowLevelSecurityPolicies = []rowLevelSecurityPolicy{
+	{name: "read", command: "SELECT", actionTypes: []proto.ActionType{proto.ActionType_ACTION_TYPE_GET, proto.ActionType_ACTION_TYPE_LIST}},
+	{name: "create", command: "INSERT", actionTypes: []proto.ActionType{proto.ActionType_ACTION_TYPE_CREATE}},
+	{name: "update", command: "UPDATE", actionTypes: []proto.ActionType{proto.ActionType_ACTION_TYPE_UPDATE}},
+	{name: "delete", command: "DELETE", actionTypes: []proto.ActionType{proto.ActionType_ACTION_TYPE_DELETE}},
+}
+
+// rowLevelSecurityRole is the role which functions switch to when row-level security is enabled, so that the
+// policies apply to them even though they connect as the role which owns the tables.
+const rowLevelSecurityRole = "keel_rls"
+
+// rowLevelSecurityStmts generates the statements which enable row-level security on each model's table with
+// policies compiled from the model's permission rules. For each policy, a security definer function checks
+// whether a row satisfies the permission rules, using the identity id from the session's audit.identity_id setting.
+//
+// Row-level security is enabled but not forced, so the role which owns the tables (i.e. the Keel runtime) is
+// unaffected and continues to apply permissions itself. Functions connect as the same role, so they switch to
+// the keel_rls role, which is not the owner and so is subject to the policies.
+func rowLevelSecurityStmts(schema *proto.Schema) (string, error) {
+	role := db.QuoteIdentifier(rowLevelSecurityRole)
+
+	statements := []string{
+		fmt.Sprintf("DO $$ BEGIN IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = %s) THEN CREATE ROLE %s NOLOGIN; END IF; END $$;", db.QuoteLiteral(rowLevelSecurityRole), role),
+		fmt.Sprintf("GRANT %s TO CURRENT_USER;", role),
+		fmt.Sprintf("GRANT USAGE ON SCHEMA public TO %s;", role),
+		fmt.Sprintf("GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO %s;", role),
+		fmt.Sprintf("GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO %s;", role),
+	}
+
+	for _, model := range schema.Models {
+		table := Identifier(model.Name)
+		pk := Identifier(model.PrimaryKeyFieldName())
+
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ENABLE ROW LEVEL SECURITY;", table))
+
+		for _, policy := range rowLevelSecurityPolicies {
+			sql, values, err := permissions.ToSQLForActionTypes(schema, model, policy.actionTypes...)
+			if err != nil {
+				return "", err
+			}
+
+			check := "false"
+			if sql != "" {
+				sql, err = rowLevelSecuritySQL(sql, values)
+				if err != nil {
+					return "", err
+				}
+				check = fmt.Sprintf("EXISTS (%s)", sql)
+			}
+
+			functionName := fmt.Sprintf("keel_rls_%s_%s", casing.ToSnake(model.Name), policy.name)
+			function := db.QuoteIdentifier(functionName)
+			policyName := db.QuoteIdentifier(fmt.Sprintf("keel_rls_%s", policy.name))
+
+			// The search path is fixed so that the security definer function cannot be made to resolve
+			// its tables and operators from a schema controlled by the caller.
+			statements = append(statements,
+				fmt.Sprintf("CREATE OR REPLACE FUNCTION %s(record_id TEXT) RETURNS BOOLEAN AS $$ SELECT %s $$ LANGUAGE sql STABLE SECURITY DEFINER SET search_path = public, pg_temp;", function, check),
+				fmt.Sprintf("DROP POLICY IF EXISTS %s ON %s;", policyName, table),
+			)
+
+			switch policy.command {
+			case "INSERT":
+				// The new row is not visible to the permission function until it has been inserted,
+				// so instead it is checked by a trigger after the insert.
+				trigger := db.QuoteIdentifier(fmt.Sprintf("%s_rls_create", casing.ToSnake(model.Name)))
+				statements = append(statements,
+					fmt.Sprintf("CREATE POLICY %s ON %s FOR INSERT WITH CHECK (true);", policyName, table),
+					fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s;", trigger, table),
+					fmt.Sprintf("CREATE TRIGGER %s AFTER INSERT ON %s FOR EACH ROW EXECUTE PROCEDURE keel_rls_check(%s, %s);", trigger, table, db.QuoteLiteral(functionName), db.QuoteLiteral(casing.ToSnake(model.PrimaryKeyFieldName()))),
+				)
+			default:
+				statements = append(statements,
+					fmt.Sprintf("CREATE POLICY %s ON %s FOR %s USING (%s(%s));", policyName, table, policy.command, function, pk),
+				)
+			}
+		}
+	}
+
+	return strings.Join(statements, "\n"), nil
+}
+
+// rowLevelSecuritySQL replaces the placeholders in the permissions SQL with the equivalent values available
+// in the database. Values which only exist in the context of a request, such as headers and secrets, are null.
+func rowLevelSecuritySQL(sql string, values []*permissions.Value) (string, error) {
+	b := strings.Builder{}
+	i := 0
+
+	for _, r := range sql {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+
+		if i >= len(values) {
+			return "", fmt.Errorf("permission sql has more placeholders than values")
+		}
+
+		value := values[i]
+		i++
+
+		switch value.Type {
+		case permissions.ValueIdentityID:
+			b.WriteString(fmt.Sprintf("NULLIF(%s, '')", identityIdSetting))
+		case permissions.ValueIdentityEmail:
+			b.WriteString(fmt.Sprintf(`(SELECT "identity"."email" FROM "identity" WHERE "identity"."id" = NULLIF(%s, ''))`, identityIdSetting))
+		case permissions.ValueIsAuthenticated:
+			b.WriteString(fmt.Sprintf("(NULLIF(%s, '') IS NOT NULL)", identityIdSetting))
+		case permissions.ValueNow:
+			b.WriteString("NOW()")
+		case permissions.ValueRecordIDs:
+			b.WriteString("record_id")
+		case permissions.ValueString:
+			// StringValue is wrapped in double quotes, except for enum values
+			str, err := strconv.Unquote(value.StringValue)
+			if err != nil {
+				str = value.StringValue
+			}
+			b.WriteString(db.QuoteLiteral(str))
+		case permissions.ValueNumber:
+			b.WriteString(strconv.Itoa(value.NumberValue))
+		case permissions.ValueIsMfaAuthenticated:
+			b.WriteString("false")
+		default:
+			b.WriteString("NULL")
+		}
+	}
+
+	return b.String(), nil
+}
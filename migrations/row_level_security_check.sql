@@ -0,0 +1,14 @@
+CREATE OR REPLACE FUNCTION keel_rls_check() RETURNS TRIGGER AS $$
+DECLARE
+    authorised BOOLEAN;
+BEGIN
+    -- Only check when row-level security applies to the current role, i.e. not for the table owner.
+    IF row_security_active(TG_RELID) THEN
+        EXECUTE format('SELECT %I($1)', TG_ARGV[0]) INTO authorised USING to_jsonb(NEW) ->> TG_ARGV[1];
+        IF authorised IS NOT TRUE THEN
+            RAISE EXCEPTION 'new row violates row-level security policy for table "%"', TG_TABLE_NAME USING ERRCODE = '42501';
+        END IF;
+    END IF;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,19 @@
+DO $$
+DECLARE
+    p RECORD;
+BEGIN
+    FOR p IN SELECT schemaname, tablename, policyname FROM pg_policies WHERE policyname LIKE 'keel\_rls\_%' LOOP
+        EXECUTE format('DROP POLICY %I ON %I.%I', p.policyname, p.schemaname, p.tablename);
+        EXECUTE format('ALTER TABLE %I.%I DISABLE ROW LEVEL SECURITY', p.schemaname, p.tablename);
+    END LOOP;
+
+    FOR p IN SELECT tgname, tgrelid::regclass AS tablename FROM pg_trigger WHERE NOT tgisinternal AND tgname LIKE '%\_rls\_create' LOOP
+        EXECUTE format('DROP TRIGGER %I ON %s', p.tgname, p.tablename);
+    END LOOP;
+
+    FOR p IN SELECT pg_proc.oid::regprocedure AS function FROM pg_proc JOIN pg_namespace ON pg_namespace.oid = pg_proc.pronamespace
+        WHERE pg_namespace.nspname = 'public' AND pg_proc.proname LIKE 'keel\_rls\_%' AND pg_proc.proname <> 'keel_rls_check' LOOP
+        EXECUTE format('DROP FUNCTION %s', p.function);
+    END LOOP;
+END
+$$;
@@ -0,0 +1,148 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/config"
+	"github.com/teamkeel/keel/db"
+	"github.com/teamkeel/keel/schema"
+)
+
+func TestRowLevelSecurityStmts(t *testing.T) {
+	t.Parallel()
+
+	builder := &schema.Builder{}
+	s, err := builder.MakeFromString(`
+		model Post {
+			fields {
+				title Text
+				author Identity
+			}
+			@permission(expression: post.author == ctx.identity, actions: [get, list, update])
+			@permission(roles: [Admin], actions: [delete])
+		}
+		role Admin {
+			domains {
+				"keel.xyz"
+			}
+		}`, config.Empty)
+	require.NoError(t, err)
+
+	sql, err := rowLevelSecurityStmts(s)
+	require.NoError(t, err)
+
+	require.Contains(t, sql, `ALTER TABLE "post" ENABLE ROW LEVEL SECURITY;`)
+
+	// Functions run as the keel_rls role as they connect as the table owner which isn't subject to the policies
+	require.Contains(t, sql, `DO $$ BEGIN IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'keel_rls') THEN CREATE ROLE "keel_rls" NOLOGIN; END IF; END $$;`)
+	require.Contains(t, sql, `GRANT "keel_rls" TO CURRENT_USER;`)
+	require.Contains(t, sql, `GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO "keel_rls";`)
+
+	require.Contains(t, sql, `CREATE OR REPLACE FUNCTION "keel_rls_post_read"(record_id TEXT) RETURNS BOOLEAN AS $$ SELECT EXISTS (SELECT DISTINCT "post"."id" FROM "post" WHERE ("post"."author_id" IS NOT DISTINCT FROM NULLIF(current_setting('audit.identity_id', true), '')) AND "post"."id" IN (record_id)) $$ LANGUAGE sql STABLE SECURITY DEFINER SET search_path = public, pg_temp;`)
+	require.Contains(t, sql, `CREATE POLICY "keel_rls_read" ON "post" FOR SELECT USING ("keel_rls_post_read"("id"));`)
+	require.Contains(t, sql, `CREATE POLICY "keel_rls_update" ON "post" FOR UPDATE USING ("keel_rls_post_update"("id"));`)
+
+	// No create permission rules, so no rows can be inserted
+	require.Contains(t, sql, `CREATE OR REPLACE FUNCTION "keel_rls_post_create"(record_id TEXT) RETURNS BOOLEAN AS $$ SELECT false $$ LANGUAGE sql STABLE SECURITY DEFINER SET search_path = public, pg_temp;`)
+	require.Contains(t, sql, `CREATE TRIGGER "post_rls_create" AFTER INSERT ON "post" FOR EACH ROW EXECUTE PROCEDURE keel_rls_check('keel_rls_post_create', 'id');`)
+
+	// Role domains are checked against the identity's verified email
+	require.Contains(t, sql, `split_part("identity"."email", '@', 2) IN ('keel.xyz')`)
+	require.Contains(t, sql, `CREATE POLICY "keel_rls_delete" ON "post" FOR DELETE USING ("keel_rls_post_delete"("id"));`)
+}
+
+func TestRowLevelSecurity_AppliesToFunctionsRoleAndIsDropped(t *testing.T) {
+	dbConnInfo := &db.ConnectionInfo{
+		Host:     "localhost",
+		Port:     "8001",
+		Username: "postgres",
+		Password: "postgres",
+		Database: "keel",
+	}
+
+	mainDB, err := sql.Open("pgx/v5", dbConnInfo.String())
+	require.NoError(t, err)
+	defer mainDB.Close()
+
+	dbName := "keel_test_row_level_security"
+	_, err = mainDB.Exec("DROP DATABASE if exists " + dbName)
+	require.NoError(t, err)
+	_, err = mainDB.Exec("CREATE DATABASE " + dbName)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	database, err := db.New(ctx, dbConnInfo.WithDatabase(dbName).String())
+	require.NoError(t, err)
+	defer database.Close()
+
+	builder := &schema.Builder{}
+	s, err := builder.MakeFromString(`
+		model Post {
+			fields {
+				title Text
+				author Identity
+			}
+			@permission(expression: post.author == ctx.identity, actions: [get, list, update])
+		}`, config.Empty)
+	require.NoError(t, err)
+
+	m, err := New(ctx, s, database, WithRowLevelSecurity(true))
+	require.NoError(t, err)
+	require.NoError(t, m.Apply(ctx, false))
+
+	_, err = database.ExecuteStatement(ctx, `INSERT INTO "identity" (id, email) VALUES ('author', 'author@keel.xyz'), ('other', 'other@keel.xyz')`)
+	require.NoError(t, err)
+	_, err = database.ExecuteStatement(ctx, `INSERT INTO "post" (id, title, author_id) VALUES ('mine', 'Mine', 'author'), ('theirs', 'Theirs', 'other')`)
+	require.NoError(t, err)
+
+	// Functions connect as the table owner and switch to the keel_rls role, so the policies apply to them
+	conn, err := database.GetDB().DB()
+	require.NoError(t, err)
+	c, err := conn.Conn(ctx)
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, err = c.ExecContext(ctx, "SET ROLE keel_rls")
+	require.NoError(t, err)
+	_, err = c.ExecContext(ctx, "SELECT set_config('audit.identity_id', 'author', false)")
+	require.NoError(t, err)
+
+	var ids []string
+	rows, err := c.QueryContext(ctx, `SELECT id FROM "post" ORDER BY id`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var id string
+		require.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	require.NoError(t, rows.Close())
+	require.Equal(t, []string{"mine"}, ids)
+
+	result, err := c.ExecContext(ctx, `UPDATE "post" SET title = 'Changed' WHERE id = 'theirs'`)
+	require.NoError(t, err)
+	affected, err := result.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(0), affected)
+
+	_, err = c.ExecContext(ctx, `INSERT INTO "post" (id, title, author_id) VALUES ('new', 'New', 'author')`)
+	require.ErrorContains(t, err, "violates row-level security policy")
+
+	_, err = c.ExecContext(ctx, "RESET ROLE")
+	require.NoError(t, err)
+
+	// Disabling row-level security drops the policies, triggers and permission functions
+	m, err = New(ctx, s, database)
+	require.NoError(t, err)
+	require.NoError(t, m.Apply(ctx, false))
+
+	remaining, err := database.ExecuteQuery(ctx, `
+		SELECT policyname AS name FROM pg_policies WHERE policyname LIKE 'keel\_rls\_%'
+		UNION ALL SELECT tgname AS name FROM pg_trigger WHERE NOT tgisinternal AND tgname LIKE '%\_rls\_create'
+		UNION ALL SELECT proname AS name FROM pg_proc WHERE proname LIKE 'keel\_rls\_%' AND proname <> 'keel_rls_check'`)
+	require.NoError(t, err)
+	require.Empty(t, remaining.Rows)
+}
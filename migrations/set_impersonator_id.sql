@@ -0,0 +1,6 @@
+CREATE OR REPLACE FUNCTION set_impersonator_id(id VARCHAR)
+RETURNS TEXT AS $$
+BEGIN
+    RETURN set_config('audit.impersonator_id', id, true);
+END
+$$ LANGUAGE plpgsql;
\ No newline at end of file
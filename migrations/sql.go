@@ -236,11 +236,19 @@ func fieldDefinition(field *proto.Field) (string, error) {
 
 	isAuditDataColumn := (field.ModelName == strcase.ToCamel(auditing.TableName)) && (field.Name == auditing.ColumnData)
 
+	// Similarly, the keel_audit table records the id of the transaction which wrote each log, which is
+	// a 64-bit integer set by the database, so that the audit trail can be read in transaction order.
+	isAuditTransactionIdColumn := (field.ModelName == strcase.ToCamel(auditing.TableName)) && (field.Name == strcase.ToLowerCamel(auditing.ColumnTransactionId))
+
 	fieldType := lo.Ternary(
 		isAuditDataColumn,
 		"jsonb",
 		PostgresFieldTypes[field.Type.Type])
 
+	if isAuditTransactionIdColumn {
+		return fmt.Sprintf("%s BIGINT NOT NULL DEFAULT txid_current()", columnName), nil
+	}
+
 	if field.Type.Repeated {
 		fieldType = fmt.Sprintf("%s[]", fieldType)
 	}
@@ -419,6 +419,10 @@ queries: {
 	getPerson: this.actions.getPerson,
 	listPeople: this.actions.listPeople,
 	readPeople: this.actions.readPeople,
+	listSessions: this.actions.listSessions,
+	listRoleMemberships: this.actions.listRoleMemberships,
+	listIdentities: this.actions.listIdentities,
+	exportIdentityData: this.actions.exportIdentityData,
 },
 mutations: {
 	createPerson: this.actions.createPerson,
@@ -426,6 +430,17 @@ mutations: {
 	deletePerson: this.actions.deletePerson,
 	requestPasswordReset: this.actions.requestPasswordReset,
 	resetPassword: this.actions.resetPassword,
+	requestEmailVerification: this.actions.requestEmailVerification,
+	verifyEmail: this.actions.verifyEmail,
+	revokeSession: this.actions.revokeSession,
+	revokeAllSessions: this.actions.revokeAllSessions,
+	assignRole: this.actions.assignRole,
+	revokeRole: this.actions.revokeRole,
+	disableIdentity: this.actions.disableIdentity,
+	enableIdentity: this.actions.enableIdentity,
+	forcePasswordReset: this.actions.forcePasswordReset,
+	deleteIdentity: this.actions.deleteIdentity,
+	eraseIdentityData: this.actions.eraseIdentityData,
 }`
 
 	runWriterTest(t, schema, expected, func(s *proto.Schema, w *codegen.Writer) {
@@ -462,6 +477,120 @@ export interface ResetPasswordInput {
 }
 export interface ResetPasswordResponse {
 }
+export interface RequestEmailVerificationInput {
+	email: string;
+	redirectUrl: string;
+}
+export interface RequestEmailVerificationResponse {
+}
+export interface VerifyEmailInput {
+	token: string;
+}
+export interface VerifyEmailResponse {
+}
+export interface IdentitySession {
+	id: string;
+	createdAt: Date;
+	lastUsedAt?: Date;
+	expiresAt: Date;
+	userAgent?: string;
+	ipAddress?: string;
+	mfa: boolean;
+}
+export interface ListSessionsInput {
+	identityId?: string;
+}
+export interface ListSessionsResponse {
+	sessions: IdentitySession[];
+}
+export interface RevokeSessionInput {
+	id: string;
+	identityId?: string;
+}
+export interface RevokeSessionResponse {
+}
+export interface RevokeAllSessionsInput {
+	identityId?: string;
+}
+export interface RevokeAllSessionsResponse {
+}
+export interface IdentityRoleMembership {
+	id: string;
+	identityId: string;
+	role: string;
+	model?: string;
+	recordId?: string;
+	createdAt: Date;
+}
+export interface ListRoleMembershipsInput {
+	identityId?: string;
+	role?: string;
+}
+export interface ListRoleMembershipsResponse {
+	memberships: IdentityRoleMembership[];
+}
+export interface AssignRoleInput {
+	identityId: string;
+	role: string;
+	model?: string;
+	recordId?: string;
+}
+export interface RevokeRoleInput {
+	identityId: string;
+	role: string;
+	model?: string;
+	recordId?: string;
+}
+export interface RevokeRoleResponse {
+}
+export interface IdentitySummary {
+	id: string;
+	email?: string;
+	emailVerified: boolean;
+	externalId?: string;
+	issuer?: string;
+	name?: string;
+	disabled: boolean;
+	disabledAt?: Date;
+	createdAt: Date;
+}
+export interface ListIdentitiesInput {
+	search?: string;
+	disabled?: boolean;
+	first?: number;
+	after?: string;
+}
+export interface ListIdentitiesResponse {
+	identities: IdentitySummary[];
+	hasNextPage: boolean;
+}
+export interface DisableIdentityInput {
+	identityId: string;
+}
+export interface EnableIdentityInput {
+	identityId: string;
+}
+export interface ForcePasswordResetInput {
+	identityId: string;
+	redirectUrl: string;
+}
+export interface ForcePasswordResetResponse {
+}
+export interface DeleteIdentityInput {
+	identityId: string;
+}
+export interface DeleteIdentityResponse {
+}
+export interface ExportIdentityDataInput {
+	identityId: string;
+}
+export interface EraseIdentityDataInput {
+	identityId: string;
+}
+export interface EraseIdentityDataResponse {
+	deleted: number;
+	anonymised: number;
+}
 export interface GetPersonInput {
 	id: string;
 }
@@ -472,9 +601,23 @@ export interface StringQueryInput {
 	endsWith?: string;
 	contains?: string;
 	oneOf?: string[];
+	equalsIgnoreCase?: string;
+	startsWithIgnoreCase?: string;
+	endsWithIgnoreCase?: string;
+	containsIgnoreCase?: string;
+	matches?: string;
+}
+export interface ListPeopleWhereGroupInput {
+	name?: StringQueryInput;
+	and?: ListPeopleWhereGroupInput[];
+	or?: ListPeopleWhereGroupInput[];
+	not?: ListPeopleWhereGroupInput;
 }
 export interface ListPeopleWhere {
 	name: StringQueryInput;
+	and?: ListPeopleWhereGroupInput[];
+	or?: ListPeopleWhereGroupInput[];
+	not?: ListPeopleWhereGroupInput;
 }
 export interface ListPeopleInput {
 	where: ListPeopleWhere;
@@ -547,15 +690,75 @@ export class APIClient extends Core {
 		resetPassword: (i: ResetPasswordInput) => {
 			return this.client.rawRequest<ResetPasswordResponse>("resetPassword", i);
 		},
+		requestEmailVerification: (i: RequestEmailVerificationInput) => {
+			return this.client.rawRequest<RequestEmailVerificationResponse>("requestEmailVerification", i);
+		},
+		verifyEmail: (i: VerifyEmailInput) => {
+			return this.client.rawRequest<VerifyEmailResponse>("verifyEmail", i);
+		},
+		listSessions: (i?: ListSessionsInput) => {
+			return this.client.rawRequest<ListSessionsResponse>("listSessions", i);
+		},
+		revokeSession: (i: RevokeSessionInput) => {
+			return this.client.rawRequest<RevokeSessionResponse>("revokeSession", i);
+		},
+		revokeAllSessions: (i?: RevokeAllSessionsInput) => {
+			return this.client.rawRequest<RevokeAllSessionsResponse>("revokeAllSessions", i);
+		},
+		listRoleMemberships: (i?: ListRoleMembershipsInput) => {
+			return this.client.rawRequest<ListRoleMembershipsResponse>("listRoleMemberships", i);
+		},
+		assignRole: (i: AssignRoleInput) => {
+			return this.client.rawRequest<IdentityRoleMembership>("assignRole", i);
+		},
+		revokeRole: (i: RevokeRoleInput) => {
+			return this.client.rawRequest<RevokeRoleResponse>("revokeRole", i);
+		},
+		listIdentities: (i?: ListIdentitiesInput) => {
+			return this.client.rawRequest<ListIdentitiesResponse>("listIdentities", i);
+		},
+		disableIdentity: (i: DisableIdentityInput) => {
+			return this.client.rawRequest<IdentitySummary>("disableIdentity", i);
+		},
+		enableIdentity: (i: EnableIdentityInput) => {
+			return this.client.rawRequest<IdentitySummary>("enableIdentity", i);
+		},
+		forcePasswordReset: (i: ForcePasswordResetInput) => {
+			return this.client.rawRequest<ForcePasswordResetResponse>("forcePasswordReset", i);
+		},
+		deleteIdentity: (i: DeleteIdentityInput) => {
+			return this.client.rawRequest<DeleteIdentityResponse>("deleteIdentity", i);
+		},
+		exportIdentityData: (i: ExportIdentityDataInput) => {
+			return this.client.rawRequest<any>("exportIdentityData", i);
+		},
+		eraseIdentityData: (i: EraseIdentityDataInput) => {
+			return this.client.rawRequest<EraseIdentityDataResponse>("eraseIdentityData", i);
+		},
 	};
 
 	api = {
 		queries: {
 			getPerson: this.actions.getPerson,
+			listSessions: this.actions.listSessions,
+			listRoleMemberships: this.actions.listRoleMemberships,
+			listIdentities: this.actions.listIdentities,
+			exportIdentityData: this.actions.exportIdentityData,
 		},
 		mutations: {
 			requestPasswordReset: this.actions.requestPasswordReset,
 			resetPassword: this.actions.resetPassword,
+			requestEmailVerification: this.actions.requestEmailVerification,
+			verifyEmail: this.actions.verifyEmail,
+			revokeSession: this.actions.revokeSession,
+			revokeAllSessions: this.actions.revokeAllSessions,
+			assignRole: this.actions.assignRole,
+			revokeRole: this.actions.revokeRole,
+			disableIdentity: this.actions.disableIdentity,
+			enableIdentity: this.actions.enableIdentity,
+			forcePasswordReset: this.actions.forcePasswordReset,
+			deleteIdentity: this.actions.deleteIdentity,
+			eraseIdentityData: this.actions.eraseIdentityData,
 		}
 	};
 }`
@@ -127,6 +127,8 @@ func generateSdkPackage(schema *proto.Schema, cfg *config.ProjectConfig) codegen
 
 	sdkTypes.Writeln("export declare function AfterAuthentication(fn: (ctx: ContextAPI) => Promise<void>): Promise<void>;")
 	sdkTypes.Writeln("export declare function AfterIdentityCreated(fn: (ctx: ContextAPI) => Promise<void>): Promise<void>;")
+	sdkTypes.Writeln("export interface LockoutEvent { email: string; ipAddress: string | null; emailLocked: boolean; ipAddressLocked: boolean; lockedUntil: string; }")
+	sdkTypes.Writeln("export declare function AfterLockout(fn: (ctx: ContextAPI, event: LockoutEvent) => Promise<void>): Promise<void>;")
 
 	for _, job := range schema.Jobs {
 		writeJobFunctionWrapperType(sdkTypes, job)
@@ -966,8 +968,9 @@ func writeAPIFactory(w *codegen.Writer, schema *proto.Schema) {
 	w.Writeln("const headers = new Headers(meta.headers);")
 	w.Writeln("const response = { headers: responseHeaders }")
 	w.Writeln("const now = () => { return new Date(); };")
-	w.Writeln("const { identity } = meta;")
+	w.Writeln("const { identity, client } = meta;")
 	w.Writeln("const isAuthenticated = identity != null;")
+	w.Writeln("const isMfaAuthenticated = isAuthenticated && meta.isMfaAuthenticated == true;")
 	w.Writeln("const env = {")
 	w.Indent()
 
@@ -988,7 +991,7 @@ func writeAPIFactory(w *codegen.Writer, schema *proto.Schema) {
 
 	w.Dedent()
 	w.Writeln("};")
-	w.Writeln("return { headers, response, identity, env, now, secrets, isAuthenticated };")
+	w.Writeln("return { headers, response, identity, client, env, now, secrets, isAuthenticated, isMfaAuthenticated };")
 	w.Dedent()
 	w.Writeln("};")
 
@@ -1365,7 +1368,11 @@ func toActionReturnType(model *proto.Model, op *proto.Action) string {
 		// todo: create ID type
 		returnType += "string"
 	case proto.ActionType_ACTION_TYPE_READ, proto.ActionType_ACTION_TYPE_WRITE:
-		returnType += op.ResponseMessageName
+		if op.ResponseMessageName == parser.MessageFieldTypeAny {
+			returnType += "any"
+		} else {
+			returnType += op.ResponseMessageName
+		}
 	}
 
 	returnType += ">"
@@ -429,15 +429,16 @@ function createContextAPI({ responseHeaders, meta }) {
 	const headers = new Headers(meta.headers);
 	const response = { headers: responseHeaders }
 	const now = () => { return new Date(); };
-	const { identity } = meta;
+	const { identity, client } = meta;
 	const isAuthenticated = identity != null;
+	const isMfaAuthenticated = isAuthenticated && meta.isMfaAuthenticated == true;
 	const env = {
 		TEST: process.env["TEST"] || "",
 	};
 	const secrets = {
 		SECRET_KEY: meta.secrets.SECRET_KEY || "",
 	};
-	return { headers, response, identity, env, now, secrets, isAuthenticated };
+	return { headers, response, identity, client, env, now, secrets, isAuthenticated, isMfaAuthenticated };
 };
 function createJobContextAPI({ meta }) {
 	const now = () => { return new Date(); };
@@ -900,6 +901,11 @@ export interface StringQueryInput {
 	endsWith?: string;
 	contains?: string;
 	oneOf?: string[];
+	equalsIgnoreCase?: string;
+	startsWithIgnoreCase?: string;
+	endsWithIgnoreCase?: string;
+	containsIgnoreCase?: string;
+	matches?: string;
 }
 export interface ListPeopleWhere {
 	name: StringQueryInput;
@@ -943,15 +949,30 @@ export interface StringQueryInput {
 	endsWith?: string;
 	contains?: string;
 	oneOf?: string[];
+	equalsIgnoreCase?: string;
+	startsWithIgnoreCase?: string;
+	endsWithIgnoreCase?: string;
+	containsIgnoreCase?: string;
+	matches?: string;
 }
 export interface SportQueryInput {
 	equals?: Sport | null;
 	notEquals?: Sport | null;
 	oneOf?: Sport[];
 }
+export interface ListPeopleWhereGroupInput {
+	name?: StringQueryInput;
+	favouriteSport?: SportQueryInput;
+	and?: ListPeopleWhereGroupInput[];
+	or?: ListPeopleWhereGroupInput[];
+	not?: ListPeopleWhereGroupInput;
+}
 export interface ListPeopleWhere {
 	name: StringQueryInput;
 	favouriteSport: SportQueryInput;
+	and?: ListPeopleWhereGroupInput[];
+	or?: ListPeopleWhereGroupInput[];
+	not?: ListPeopleWhereGroupInput;
 }
 export interface ListPeopleInput {
 	where: ListPeopleWhere;
@@ -1086,6 +1107,11 @@ export interface StringQueryInput {
 	endsWith?: string;
 	contains?: string;
 	oneOf?: string[];
+	equalsIgnoreCase?: string;
+	startsWithIgnoreCase?: string;
+	endsWithIgnoreCase?: string;
+	containsIgnoreCase?: string;
+	matches?: string;
 }
 export interface ListBooksWhere {
 	author: ListBooksAuthorInput;
@@ -1145,6 +1171,11 @@ export interface StringQueryInput {
 	endsWith?: string;
 	contains?: string;
 	oneOf?: string[];
+	equalsIgnoreCase?: string;
+	startsWithIgnoreCase?: string;
+	endsWithIgnoreCase?: string;
+	containsIgnoreCase?: string;
+	matches?: string;
 }
 export interface ListBooksWhere {
 	author?: ListBooksAuthorInput;
@@ -1216,9 +1247,19 @@ export interface SportArrayQueryInput {
 	any?: SportArrayAnyQueryInput;
 	all?: SportArrayAllQueryInput;
 }
+export interface ListPeopleWhereGroupInput {
+	favouriteNumbers?: IntArrayQueryInput;
+	favouriteSports?: SportArrayQueryInput;
+	and?: ListPeopleWhereGroupInput[];
+	or?: ListPeopleWhereGroupInput[];
+	not?: ListPeopleWhereGroupInput;
+}
 export interface ListPeopleWhere {
 	favouriteNumbers: IntArrayQueryInput;
 	favouriteSports: SportArrayQueryInput;
+	and?: ListPeopleWhereGroupInput[];
+	or?: ListPeopleWhereGroupInput[];
+	not?: ListPeopleWhereGroupInput;
 }
 export interface ListPeopleInput {
 	where: ListPeopleWhere;
@@ -1260,15 +1301,30 @@ export interface StringQueryInput {
 	endsWith?: string;
 	contains?: string;
 	oneOf?: string[];
+	equalsIgnoreCase?: string;
+	startsWithIgnoreCase?: string;
+	endsWithIgnoreCase?: string;
+	containsIgnoreCase?: string;
+	matches?: string;
 }
 export interface SportQueryInput {
 	equals?: Sport | null;
 	notEquals?: Sport | null;
 	oneOf?: Sport[];
 }
+export interface ListPeopleWhereGroupInput {
+	name?: StringQueryInput;
+	favouriteSport?: SportQueryInput;
+	and?: ListPeopleWhereGroupInput[];
+	or?: ListPeopleWhereGroupInput[];
+	not?: ListPeopleWhereGroupInput;
+}
 export interface ListPeopleWhere {
 	name: StringQueryInput;
 	favouriteSport: SportQueryInput;
+	and?: ListPeopleWhereGroupInput[];
+	or?: ListPeopleWhereGroupInput[];
+	not?: ListPeopleWhereGroupInput;
 }
 export interface ListPeopleOrderByName {
 	name: runtime.SortDirection;
@@ -1896,6 +1952,120 @@ export interface ResetPasswordInput {
 }
 export interface ResetPasswordResponse {
 }
+export interface RequestEmailVerificationInput {
+	email: string;
+	redirectUrl: string;
+}
+export interface RequestEmailVerificationResponse {
+}
+export interface VerifyEmailInput {
+	token: string;
+}
+export interface VerifyEmailResponse {
+}
+export interface IdentitySession {
+	id: string;
+	createdAt: Date;
+	lastUsedAt?: Date;
+	expiresAt: Date;
+	userAgent?: string;
+	ipAddress?: string;
+	mfa: boolean;
+}
+export interface ListSessionsInput {
+	identityId?: string;
+}
+export interface ListSessionsResponse {
+	sessions: IdentitySession[];
+}
+export interface RevokeSessionInput {
+	id: string;
+	identityId?: string;
+}
+export interface RevokeSessionResponse {
+}
+export interface RevokeAllSessionsInput {
+	identityId?: string;
+}
+export interface RevokeAllSessionsResponse {
+}
+export interface IdentityRoleMembership {
+	id: string;
+	identityId: string;
+	role: string;
+	model?: string;
+	recordId?: string;
+	createdAt: Date;
+}
+export interface ListRoleMembershipsInput {
+	identityId?: string;
+	role?: string;
+}
+export interface ListRoleMembershipsResponse {
+	memberships: IdentityRoleMembership[];
+}
+export interface AssignRoleInput {
+	identityId: string;
+	role: string;
+	model?: string;
+	recordId?: string;
+}
+export interface RevokeRoleInput {
+	identityId: string;
+	role: string;
+	model?: string;
+	recordId?: string;
+}
+export interface RevokeRoleResponse {
+}
+export interface IdentitySummary {
+	id: string;
+	email?: string;
+	emailVerified: boolean;
+	externalId?: string;
+	issuer?: string;
+	name?: string;
+	disabled: boolean;
+	disabledAt?: Date;
+	createdAt: Date;
+}
+export interface ListIdentitiesInput {
+	search?: string;
+	disabled?: boolean;
+	first?: number;
+	after?: string;
+}
+export interface ListIdentitiesResponse {
+	identities: IdentitySummary[];
+	hasNextPage: boolean;
+}
+export interface DisableIdentityInput {
+	identityId: string;
+}
+export interface EnableIdentityInput {
+	identityId: string;
+}
+export interface ForcePasswordResetInput {
+	identityId: string;
+	redirectUrl: string;
+}
+export interface ForcePasswordResetResponse {
+}
+export interface DeleteIdentityInput {
+	identityId: string;
+}
+export interface DeleteIdentityResponse {
+}
+export interface ExportIdentityDataInput {
+	identityId: string;
+}
+export interface EraseIdentityDataInput {
+	identityId: string;
+}
+export interface EraseIdentityDataResponse {
+	deleted: number;
+	anonymised: number;
+}
 export interface GetPersonInput {
 	id: string;
 }
@@ -1930,6 +2100,21 @@ declare class ActionExecutor {
 	listPeople(i?: ListPeopleInput): Promise<{results: sdk.Person[], pageInfo: runtime.PageInfo}>;
 	requestPasswordReset(i: RequestPasswordResetInput): Promise<RequestPasswordResetResponse>;
 	resetPassword(i: ResetPasswordInput): Promise<ResetPasswordResponse>;
+	requestEmailVerification(i: RequestEmailVerificationInput): Promise<RequestEmailVerificationResponse>;
+	verifyEmail(i: VerifyEmailInput): Promise<VerifyEmailResponse>;
+	listSessions(i?: ListSessionsInput): Promise<ListSessionsResponse>;
+	revokeSession(i: RevokeSessionInput): Promise<RevokeSessionResponse>;
+	revokeAllSessions(i?: RevokeAllSessionsInput): Promise<RevokeAllSessionsResponse>;
+	listRoleMemberships(i?: ListRoleMembershipsInput): Promise<ListRoleMembershipsResponse>;
+	assignRole(i: AssignRoleInput): Promise<IdentityRoleMembership>;
+	revokeRole(i: RevokeRoleInput): Promise<RevokeRoleResponse>;
+	listIdentities(i?: ListIdentitiesInput): Promise<ListIdentitiesResponse>;
+	disableIdentity(i: DisableIdentityInput): Promise<IdentitySummary>;
+	enableIdentity(i: EnableIdentityInput): Promise<IdentitySummary>;
+	forcePasswordReset(i: ForcePasswordResetInput): Promise<ForcePasswordResetResponse>;
+	deleteIdentity(i: DeleteIdentityInput): Promise<DeleteIdentityResponse>;
+	exportIdentityData(i: ExportIdentityDataInput): Promise<any>;
+	eraseIdentityData(i: EraseIdentityDataInput): Promise<EraseIdentityDataResponse>;
 }
 export declare const actions: ActionExecutor;
 export declare const models: sdk.ModelsAPI;
@@ -1975,6 +2160,120 @@ export interface ResetPasswordInput {
 }
 export interface ResetPasswordResponse {
 }
+export interface RequestEmailVerificationInput {
+	email: string;
+	redirectUrl: string;
+}
+export interface RequestEmailVerificationResponse {
+}
+export interface VerifyEmailInput {
+	token: string;
+}
+export interface VerifyEmailResponse {
+}
+export interface IdentitySession {
+	id: string;
+	createdAt: Date;
+	lastUsedAt?: Date;
+	expiresAt: Date;
+	userAgent?: string;
+	ipAddress?: string;
+	mfa: boolean;
+}
+export interface ListSessionsInput {
+	identityId?: string;
+}
+export interface ListSessionsResponse {
+	sessions: IdentitySession[];
+}
+export interface RevokeSessionInput {
+	id: string;
+	identityId?: string;
+}
+export interface RevokeSessionResponse {
+}
+export interface RevokeAllSessionsInput {
+	identityId?: string;
+}
+export interface RevokeAllSessionsResponse {
+}
+export interface IdentityRoleMembership {
+	id: string;
+	identityId: string;
+	role: string;
+	model?: string;
+	recordId?: string;
+	createdAt: Date;
+}
+export interface ListRoleMembershipsInput {
+	identityId?: string;
+	role?: string;
+}
+export interface ListRoleMembershipsResponse {
+	memberships: IdentityRoleMembership[];
+}
+export interface AssignRoleInput {
+	identityId: string;
+	role: string;
+	model?: string;
+	recordId?: string;
+}
+export interface RevokeRoleInput {
+	identityId: string;
+	role: string;
+	model?: string;
+	recordId?: string;
+}
+export interface RevokeRoleResponse {
+}
+export interface IdentitySummary {
+	id: string;
+	email?: string;
+	emailVerified: boolean;
+	externalId?: string;
+	issuer?: string;
+	name?: string;
+	disabled: boolean;
+	disabledAt?: Date;
+	createdAt: Date;
+}
+export interface ListIdentitiesInput {
+	search?: string;
+	disabled?: boolean;
+	first?: number;
+	after?: string;
+}
+export interface ListIdentitiesResponse {
+	identities: IdentitySummary[];
+	hasNextPage: boolean;
+}
+export interface DisableIdentityInput {
+	identityId: string;
+}
+export interface EnableIdentityInput {
+	identityId: string;
+}
+export interface ForcePasswordResetInput {
+	identityId: string;
+	redirectUrl: string;
+}
+export interface ForcePasswordResetResponse {
+}
+export interface DeleteIdentityInput {
+	identityId: string;
+}
+export interface DeleteIdentityResponse {
+}
+export interface ExportIdentityDataInput {
+	identityId: string;
+}
+export interface EraseIdentityDataInput {
+	identityId: string;
+}
+export interface EraseIdentityDataResponse {
+	deleted: number;
+	anonymised: number;
+}
 export interface AdHocJobWithInputsMessage {
 	nameField: string;
 	someBool?: boolean;
@@ -1984,6 +2283,21 @@ declare class ActionExecutor {
 	withAuthToken(token: string): ActionExecutor;
 	requestPasswordReset(i: RequestPasswordResetInput): Promise<RequestPasswordResetResponse>;
 	resetPassword(i: ResetPasswordInput): Promise<ResetPasswordResponse>;
+	requestEmailVerification(i: RequestEmailVerificationInput): Promise<RequestEmailVerificationResponse>;
+	verifyEmail(i: VerifyEmailInput): Promise<VerifyEmailResponse>;
+	listSessions(i?: ListSessionsInput): Promise<ListSessionsResponse>;
+	revokeSession(i: RevokeSessionInput): Promise<RevokeSessionResponse>;
+	revokeAllSessions(i?: RevokeAllSessionsInput): Promise<RevokeAllSessionsResponse>;
+	listRoleMemberships(i?: ListRoleMembershipsInput): Promise<ListRoleMembershipsResponse>;
+	assignRole(i: AssignRoleInput): Promise<IdentityRoleMembership>;
+	revokeRole(i: RevokeRoleInput): Promise<RevokeRoleResponse>;
+	listIdentities(i?: ListIdentitiesInput): Promise<ListIdentitiesResponse>;
+	disableIdentity(i: DisableIdentityInput): Promise<IdentitySummary>;
+	enableIdentity(i: EnableIdentityInput): Promise<IdentitySummary>;
+	forcePasswordReset(i: ForcePasswordResetInput): Promise<ForcePasswordResetResponse>;
+	deleteIdentity(i: DeleteIdentityInput): Promise<DeleteIdentityResponse>;
+	exportIdentityData(i: ExportIdentityDataInput): Promise<any>;
+	eraseIdentityData(i: EraseIdentityDataInput): Promise<EraseIdentityDataResponse>;
 }
 type JobOptions = { scheduled?: boolean } | null
 declare class JobExecutor {
@@ -2040,6 +2354,21 @@ declare class ActionExecutor {
 	withAuthToken(token: string): ActionExecutor;
 	requestPasswordReset(i: RequestPasswordResetInput): Promise<RequestPasswordResetResponse>;
 	resetPassword(i: ResetPasswordInput): Promise<ResetPasswordResponse>;
+	requestEmailVerification(i: RequestEmailVerificationInput): Promise<RequestEmailVerificationResponse>;
+	verifyEmail(i: VerifyEmailInput): Promise<VerifyEmailResponse>;
+	listSessions(i?: ListSessionsInput): Promise<ListSessionsResponse>;
+	revokeSession(i: RevokeSessionInput): Promise<RevokeSessionResponse>;
+	revokeAllSessions(i?: RevokeAllSessionsInput): Promise<RevokeAllSessionsResponse>;
+	listRoleMemberships(i?: ListRoleMembershipsInput): Promise<ListRoleMembershipsResponse>;
+	assignRole(i: AssignRoleInput): Promise<IdentityRoleMembership>;
+	revokeRole(i: RevokeRoleInput): Promise<RevokeRoleResponse>;
+	listIdentities(i?: ListIdentitiesInput): Promise<ListIdentitiesResponse>;
+	disableIdentity(i: DisableIdentityInput): Promise<IdentitySummary>;
+	enableIdentity(i: EnableIdentityInput): Promise<IdentitySummary>;
+	forcePasswordReset(i: ForcePasswordResetInput): Promise<ForcePasswordResetResponse>;
+	deleteIdentity(i: DeleteIdentityInput): Promise<DeleteIdentityResponse>;
+	exportIdentityData(i: ExportIdentityDataInput): Promise<any>;
+	eraseIdentityData(i: EraseIdentityDataInput): Promise<EraseIdentityDataResponse>;
 }
 declare class SubscriberExecutor {
 	verifyEmail(e: VerifyEmailEvent): Promise<void>;
@@ -2141,13 +2470,136 @@ export interface ResetPasswordInput {
 }
 export interface ResetPasswordResponse {
 }
+export interface RequestEmailVerificationInput {
+	email: string;
+	redirectUrl: string;
+}
+export interface RequestEmailVerificationResponse {
+}
+export interface VerifyEmailInput {
+	token: string;
+}
+export interface VerifyEmailResponse {
+}
+export interface IdentitySession {
+	id: string;
+	createdAt: Date;
+	lastUsedAt?: Date;
+	expiresAt: Date;
+	userAgent?: string;
+	ipAddress?: string;
+	mfa: boolean;
+}
+export interface ListSessionsInput {
+	identityId?: string;
+}
+export interface ListSessionsResponse {
+	sessions: IdentitySession[];
+}
+export interface RevokeSessionInput {
+	id: string;
+	identityId?: string;
+}
+export interface RevokeSessionResponse {
+}
+export interface RevokeAllSessionsInput {
+	identityId?: string;
+}
+export interface RevokeAllSessionsResponse {
+}
+export interface IdentityRoleMembership {
+	id: string;
+	identityId: string;
+	role: string;
+	model?: string;
+	recordId?: string;
+	createdAt: Date;
+}
+export interface ListRoleMembershipsInput {
+	identityId?: string;
+	role?: string;
+}
+export interface ListRoleMembershipsResponse {
+	memberships: IdentityRoleMembership[];
+}
+export interface AssignRoleInput {
+	identityId: string;
+	role: string;
+	model?: string;
+	recordId?: string;
+}
+export interface RevokeRoleInput {
+	identityId: string;
+	role: string;
+	model?: string;
+	recordId?: string;
+}
+export interface RevokeRoleResponse {
+}
+export interface IdentitySummary {
+	id: string;
+	email?: string;
+	emailVerified: boolean;
+	externalId?: string;
+	issuer?: string;
+	name?: string;
+	disabled: boolean;
+	disabledAt?: Date;
+	createdAt: Date;
+}
+export interface ListIdentitiesInput {
+	search?: string;
+	disabled?: boolean;
+	first?: number;
+	after?: string;
+}
+export interface ListIdentitiesResponse {
+	identities: IdentitySummary[];
+	hasNextPage: boolean;
+}
+export interface DisableIdentityInput {
+	identityId: string;
+}
+export interface EnableIdentityInput {
+	identityId: string;
+}
+export interface ForcePasswordResetInput {
+	identityId: string;
+	redirectUrl: string;
+}
+export interface ForcePasswordResetResponse {
+}
+export interface DeleteIdentityInput {
+	identityId: string;
+}
+export interface DeleteIdentityResponse {
+}
+export interface ExportIdentityDataInput {
+	identityId: string;
+}
+export interface EraseIdentityDataInput {
+	identityId: string;
+}
+export interface EraseIdentityDataResponse {
+	deleted: number;
+	anonymised: number;
+}
 export interface HobbyQueryInput {
 	equals?: Hobby | null;
 	notEquals?: Hobby | null;
 	oneOf?: Hobby[];
 }
+export interface PeopleByHobbyWhereGroupInput {
+	hobby?: HobbyQueryInput;
+	and?: PeopleByHobbyWhereGroupInput[];
+	or?: PeopleByHobbyWhereGroupInput[];
+	not?: PeopleByHobbyWhereGroupInput;
+}
 export interface PeopleByHobbyWhere {
 	hobby: HobbyQueryInput;
+	and?: PeopleByHobbyWhereGroupInput[];
+	or?: PeopleByHobbyWhereGroupInput[];
+	not?: PeopleByHobbyWhereGroupInput;
 }
 export interface PeopleByHobbyInput {
 	where: PeopleByHobbyWhere;
@@ -2162,6 +2614,21 @@ declare class ActionExecutor {
 	peopleByHobby(i: PeopleByHobbyInput): Promise<{results: sdk.Person[], pageInfo: runtime.PageInfo}>;
 	requestPasswordReset(i: RequestPasswordResetInput): Promise<RequestPasswordResetResponse>;
 	resetPassword(i: ResetPasswordInput): Promise<ResetPasswordResponse>;
+	requestEmailVerification(i: RequestEmailVerificationInput): Promise<RequestEmailVerificationResponse>;
+	verifyEmail(i: VerifyEmailInput): Promise<VerifyEmailResponse>;
+	listSessions(i?: ListSessionsInput): Promise<ListSessionsResponse>;
+	revokeSession(i: RevokeSessionInput): Promise<RevokeSessionResponse>;
+	revokeAllSessions(i?: RevokeAllSessionsInput): Promise<RevokeAllSessionsResponse>;
+	listRoleMemberships(i?: ListRoleMembershipsInput): Promise<ListRoleMembershipsResponse>;
+	assignRole(i: AssignRoleInput): Promise<IdentityRoleMembership>;
+	revokeRole(i: RevokeRoleInput): Promise<RevokeRoleResponse>;
+	listIdentities(i?: ListIdentitiesInput): Promise<ListIdentitiesResponse>;
+	disableIdentity(i: DisableIdentityInput): Promise<IdentitySummary>;
+	enableIdentity(i: EnableIdentityInput): Promise<IdentitySummary>;
+	forcePasswordReset(i: ForcePasswordResetInput): Promise<ForcePasswordResetResponse>;
+	deleteIdentity(i: DeleteIdentityInput): Promise<DeleteIdentityResponse>;
+	exportIdentityData(i: ExportIdentityDataInput): Promise<any>;
+	eraseIdentityData(i: EraseIdentityDataInput): Promise<EraseIdentityDataResponse>;
 }
 export declare const actions: ActionExecutor;
 export declare const models: sdk.ModelsAPI;
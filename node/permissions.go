@@ -55,8 +55,14 @@ func writePermissions(w *codegen.Writer, schema *proto.Schema) {
 					return "${ctx.identity ? ctx.identity.email : ''}"
 				case permissions.ValueNow:
 					return "${ctx.now()}"
+				case permissions.ValueClientID:
+					return "${ctx.client ? ctx.client.id : ''}"
+				case permissions.ValueClientName:
+					return "${ctx.client ? ctx.client.name : ''}"
 				case permissions.ValueIsAuthenticated:
 					return "${ctx.isAuthenticated}"
+				case permissions.ValueIsMfaAuthenticated:
+					return "${ctx.isMfaAuthenticated}"
 				case permissions.ValueRecordIDs:
 					// Need to use sql.join() here:
 					// Docs: https://kysely-org.github.io/kysely/interfaces/Sql.html#join
@@ -271,6 +271,39 @@ module.exports.permissionFns = permissionFns;
 				WHERE (${ctx.isAuthenticated}::boolean) AND "post"."id" IN (${(records.length > 0) ? sql.join(records.map(x => x.id)) : []})
 			`,
 		},
+		{
+			name: "ValueIsMfaAuthenticated",
+			schema: `
+				model Post {
+					fields {
+						title Text
+						identity Identity
+					}
+					actions {
+						update createPost() with(title) {
+							@permission(expression: ctx.isMfaAuthenticated)
+							@function
+						}
+					}
+				}
+			`,
+			expected: `
+const permissionFns = {
+	createPost: [
+		async (records, ctx, db) => {
+			const { rows } = await sql%s.execute(db);
+			return rows.length === records.length;
+		},
+	],
+}
+module.exports.permissionFns = permissionFns;
+			`,
+			sql: `
+				SELECT DISTINCT "post"."id" 
+				FROM "post" 
+				WHERE (${ctx.isMfaAuthenticated}::boolean) AND "post"."id" IN (${(records.length > 0) ? sql.join(records.map(x => x.id)) : []})
+			`,
+		},
 		{
 			name: "ValueHeader",
 			schema: `
@@ -73,6 +73,13 @@ export default AfterAuthentication(async (ctx) => {
 // This synchronous hook will execute after a new identity record is created during an authentication flow
 export default AfterIdentityCreated(async (ctx) => {
 
+});`
+		case config.HookAfterLockout:
+			contents = `import { AfterLockout } from '@teamkeel/sdk';
+
+// This synchronous hook will execute after an email or IP address is locked out following too many failed sign in attempts
+export default AfterLockout(async (ctx, event) => {
+
 });`
 		}
 
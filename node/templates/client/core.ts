@@ -267,6 +267,80 @@ export class Core {
       return await this.auth.requestToken(req);
     },
 
+    /**
+     * Sends a magic link or one-time code to the email address, which can then be used with authenticateWithPasswordlessCode.
+     * Returns error field if an error occurred.
+     */
+    requestPasswordlessCode: async (
+      input: PasswordlessCodeRequestInput
+    ): Promise<APIResult<boolean>> => {
+      const url = new URL(this.config.baseUrl);
+      const result = await globalThis.fetch(url.origin + "/auth/passwordless", {
+        method: "POST",
+        cache: "no-cache",
+        headers: {
+          accept: "application/json",
+          "content-type": "application/json",
+        },
+        body: JSON.stringify({
+          email: input.email,
+          code_type: input.codeType,
+          create_if_not_exists: input.createIfNotExists,
+        }),
+      });
+
+      if (result.ok) {
+        return { data: true };
+      }
+
+      let errorMessage = "unknown error";
+      try {
+        const resp = await result.json();
+        errorMessage = resp.error_description;
+      } catch (error) {}
+
+      return {
+        error: {
+          message: errorMessage,
+          type: result.status == 400 ? "bad_request" : "unknown",
+        },
+      };
+    },
+
+    /**
+     * Authenticates with the code from a magic link or one-time code email and, if successful, returns data field with result of the authentication.
+     * Returns error field if an error occurred.
+     */
+    authenticateWithPasswordlessCode: async (
+      input: PasswordlessFlowInput
+    ): Promise<APIResult<AuthenticationResponse>> => {
+      const req: PasswordlessGrant = {
+        grant_type: "passwordless",
+        username: input.email,
+        code: input.code,
+        create_if_not_exists: input.createIfNotExists,
+      };
+
+      return await this.auth.requestToken(req);
+    },
+
+    /**
+     * Completes authentication with a code from the identity's authenticator, or a recovery code, after an mfa_required error.
+     * Returns error field if an error occurred.
+     */
+    authenticateWithMfaCode: async (
+      input: MfaFlowInput
+    ): Promise<APIResult<AuthenticationResponse>> => {
+      const req: MfaOtpGrant = {
+        grant_type: "mfa_otp",
+        mfa_token: input.mfaToken,
+        otp: input.otp,
+        recovery_code: input.recoveryCode,
+      };
+
+      return await this.auth.requestToken(req);
+    },
+
     /**
      * Authenticates with the ID Token flow and, if successful, returns data field with result of the authentication.
      * Returns error field if an error occurred.
@@ -386,10 +460,12 @@ export class Core {
           const requestId = result.headers.get("X-Amzn-Requestid") || undefined;
 
           let errorMessage = "unknown error";
+          let mfaToken: string | undefined;
 
           try {
             const resp = await result.json();
             errorMessage = resp.error_description;
+            mfaToken = resp.mfa_token;
           } catch (error) {}
 
           const errorCommon = {
@@ -397,6 +473,16 @@ export class Core {
             requestId,
           };
 
+          if (result.status == 403 && mfaToken) {
+            return {
+              error: {
+                ...errorCommon,
+                type: "mfa_required",
+                mfaToken,
+              },
+            };
+          }
+
           switch (result.status) {
             case 400:
               return {
@@ -49,6 +49,14 @@ type ForbiddenError = {
   requestId?: string;
 };
 
+/* 403 when a code from the identity's authenticator is required to complete authentication */
+type MfaRequiredError = {
+  type: "mfa_required";
+  message: string;
+  mfaToken: string;
+  requestId?: string;
+};
+
 /* 404 */
 type NotFoundError = {
   type: "not_found";
@@ -74,6 +82,7 @@ type UnknownError = {
 export type APIError =
   | UnauthorizedError
   | ForbiddenError
+  | MfaRequiredError
   | NotFoundError
   | BadRequestError
   | InternalServerError
@@ -111,6 +120,24 @@ export interface SingleSignOnFlowInput {
   code: string;
 }
 
+export interface PasswordlessCodeRequestInput {
+  email: string;
+  codeType: "magic_link" | "otp";
+  createIfNotExists?: boolean;
+}
+
+export interface PasswordlessFlowInput {
+  email: string;
+  code: string;
+  createIfNotExists?: boolean;
+}
+
+export interface MfaFlowInput {
+  mfaToken: string;
+  otp?: string;
+  recoveryCode?: string;
+}
+
 type PasswordGrant = {
   grant_type: "password";
   username: string;
@@ -118,6 +145,13 @@ type PasswordGrant = {
   create_if_not_exists?: boolean;
 };
 
+type PasswordlessGrant = {
+  grant_type: "passwordless";
+  username: string;
+  code: string;
+  create_if_not_exists?: boolean;
+};
+
 type TokenExchangeGrant = {
   grant_type: "token_exchange";
   subject_token: string;
@@ -129,6 +163,13 @@ type AuthorizationCodeGrant = {
   code: string;
 };
 
+type MfaOtpGrant = {
+  grant_type: "mfa_otp";
+  mfa_token: string;
+  otp?: string;
+  recovery_code?: string;
+};
+
 type RefreshGrant = {
   grant_type: "refresh_token";
   refresh_token: string;
@@ -136,8 +177,10 @@ type RefreshGrant = {
 
 export type TokenRequest =
   | PasswordGrant
+  | PasswordlessGrant
   | TokenExchangeGrant
   | AuthorizationCodeGrant
+  | MfaOtpGrant
   | RefreshGrant;
 
 export type SortDirection = "asc" | "desc" | "ASC" | "DESC";
@@ -148,6 +191,8 @@ type PageInfo = {
   hasNextPage: boolean;
   startCursor: string;
   totalCount: number;
+  pageNumber?: number;
+  totalPages?: number;
 };
 
 type FileResponseObject = {
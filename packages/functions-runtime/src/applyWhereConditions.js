@@ -5,6 +5,15 @@ const opMapping = {
   startsWith: { op: "like", value: (v) => `${v}%` },
   endsWith: { op: "like", value: (v) => `%${v}` },
   contains: { op: "like", value: (v) => `%${v}%` },
+  // Wildcards are escaped so that only the case of the value is ignored
+  equalsIgnoreCase: {
+    op: "ilike",
+    value: (v) => v.replace(/[\\%_]/g, "\\$&"),
+  },
+  startsWithIgnoreCase: { op: "ilike", value: (v) => `${v}%` },
+  endsWithIgnoreCase: { op: "ilike", value: (v) => `%${v}` },
+  containsIgnoreCase: { op: "ilike", value: (v) => `%${v}%` },
+  matches: { op: "~" },
   oneOf: { op: "=", value: (v) => sql`ANY(${v})` },
   greaterThan: { op: ">" },
   greaterThanOrEquals: { op: ">=" },
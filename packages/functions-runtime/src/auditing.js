@@ -13,6 +13,9 @@ async function withAuditContext(request, cb) {
   if (request.meta?.identity) {
     audit.identityId = request.meta.identity.id;
   }
+  if (request.meta?.impersonatorId) {
+    audit.impersonatorId = request.meta.impersonatorId;
+  }
   if (request.meta?.tracing?.traceparent) {
     audit.traceId = TraceParent.fromString(
       request.meta.tracing.traceparent
@@ -29,6 +32,7 @@ function getAuditContext() {
   let auditStore = auditContextStorage.getStore();
   return {
     identityId: auditStore?.identityId,
+    impersonatorId: auditStore?.impersonatorId,
     traceId: auditStore?.traceId,
   };
 }
@@ -41,6 +45,7 @@ function getAuditContext() {
 class AuditContextPlugin {
   constructor() {
     this.identityIdAlias = "__keel_identity_id";
+    this.impersonatorIdAlias = "__keel_impersonator_id";
     this.traceIdAlias = "__keel_trace_id";
   }
 
@@ -73,6 +78,14 @@ class AuditContextPlugin {
           returning.selections.push(SelectionNode.create(rawNode));
         }
 
+        if (audit.impersonatorId) {
+          const rawNode = sql`set_impersonator_id(${audit.impersonatorId})`
+            .as(this.impersonatorIdAlias)
+            .toOperationNode();
+
+          returning.selections.push(SelectionNode.create(rawNode));
+        }
+
         if (audit.traceId) {
           const rawNode = sql`set_trace_id(${audit.traceId})`
             .as(this.traceIdAlias)
@@ -97,6 +110,7 @@ class AuditContextPlugin {
     if (args.result?.rows) {
       for (let i = 0; i < args.result.rows.length; i++) {
         delete args.result.rows[i][this.identityIdAlias];
+        delete args.result.rows[i][this.impersonatorIdAlias];
         delete args.result.rows[i][this.traceIdAlias];
       }
     }
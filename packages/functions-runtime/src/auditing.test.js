@@ -29,6 +29,13 @@ beforeEach(async () => {
   END
   $$ LANGUAGE plpgsql;
 
+  CREATE OR REPLACE FUNCTION set_impersonator_id(id VARCHAR)
+  RETURNS TEXT AS $$
+  BEGIN
+      RETURN set_config('audit.impersonator_id', id, true);
+  END
+  $$ LANGUAGE plpgsql;
+
   CREATE OR REPLACE FUNCTION set_trace_id(id VARCHAR)
   RETURNS TEXT AS $$
   BEGIN
@@ -48,6 +55,14 @@ async function identityIdFromConfigParam(database, nonLocal = true) {
   return result.rows[0].id;
 }
 
+async function impersonatorIdFromConfigParam(database, nonLocal = true) {
+  const result =
+    await sql`SELECT NULLIF(current_setting('audit.impersonator_id', ${sql.literal(
+      nonLocal
+    )}), '') AS id`.execute(database);
+  return result.rows[0].id;
+}
+
 async function traceIdFromConfigParam(database, nonLocal = true) {
   const result =
     await sql`SELECT NULLIF(current_setting('audit.trace_id', ${sql.literal(
@@ -88,6 +103,39 @@ test("auditing - capturing identity id in transaction", async () => {
   expect(await identityIdFromConfigParam(db, false)).toBeNull();
 });
 
+test("auditing - capturing impersonator id in transaction", async () => {
+  const request = {
+    meta: {
+      identity: { id: KSUID.randomSync().string },
+      impersonatorId: KSUID.randomSync().string,
+    },
+  };
+
+  const row = await withDatabase(
+    db,
+    PROTO_ACTION_TYPES.CREATE, // CREATE will ensure a transaction is opened
+    async ({ transaction }) => {
+      const row = withAuditContext(request, async () => {
+        return await personAPI.create({
+          id: KSUID.randomSync().string,
+          name: "James",
+        });
+      });
+
+      expect(await impersonatorIdFromConfigParam(transaction)).toEqual(
+        request.meta.impersonatorId
+      );
+      expect(await impersonatorIdFromConfigParam(db)).toBeNull();
+
+      return row;
+    }
+  );
+
+  expect(row.name).toEqual("James");
+  expect(row.__keel_impersonator_id).toBeUndefined();
+  expect(await impersonatorIdFromConfigParam(db)).toBeNull();
+});
+
 test("auditing - capturing tracing in transaction", async () => {
   const request = {
     meta: {
@@ -1,4 +1,4 @@
-const { Kysely, PostgresDialect, CamelCasePlugin } = require("kysely");
+const { Kysely, PostgresDialect, CamelCasePlugin, sql } = require("kysely");
 const neonserverless = require("@neondatabase/serverless");
 const { AsyncLocalStorage } = require("async_hooks");
 const { AuditContextPlugin } = require("./auditing");
@@ -45,6 +45,31 @@ async function withDatabase(db, actionType, cb) {
 
 const dbInstance = new AsyncLocalStorage();
 
+// withRowLevelSecurity runs the callback as the keel_rls database role when row-level security is enabled.
+// Functions connect as the role which owns the tables, which isn't subject to the row-level security policies,
+// whereas keel_rls is. The identity is also set for the connection so that the policies can check against it.
+// Both are reset before the connection or transaction is released.
+async function withRowLevelSecurity(identityId, cb) {
+  const db = dbInstance.getStore();
+  if (!db || process.env["KEEL_DB_ROW_LEVEL_SECURITY"] !== "true") {
+    return cb();
+  }
+
+  await sql`SET ROLE keel_rls`.execute(db);
+  await sql`SELECT set_config('audit.identity_id', ${identityId || ""}, false)`.execute(db);
+
+  try {
+    return await cb();
+  } finally {
+    try {
+      await sql`RESET ROLE`.execute(db);
+      await sql`SELECT set_config('audit.identity_id', '', false)`.execute(db);
+    } catch {
+      // The transaction has been aborted, and its rollback will undo both
+    }
+  }
+}
+
 // used to establish a singleton for our vitest environment
 let vitestDb = null;
 
@@ -229,4 +254,5 @@ module.exports = {
   createDatabaseClient,
   useDatabase,
   withDatabase,
+  withRowLevelSecurity,
 };
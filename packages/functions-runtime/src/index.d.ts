@@ -11,6 +11,11 @@ export type StringWhereCondition = {
   contains?: string | null;
   equals?: string | null;
   notEquals?: string | null;
+  equalsIgnoreCase?: string;
+  startsWithIgnoreCase?: string;
+  endsWithIgnoreCase?: string;
+  containsIgnoreCase?: string;
+  matches?: string;
 };
 
 export type BooleanWhereCondition = {
@@ -108,9 +113,17 @@ export type ContextAPI = {
   headers: RequestHeaders;
   response: Response;
   isAuthenticated: boolean;
+  isMfaAuthenticated: boolean;
+  client?: Client;
   now(): Date;
 };
 
+export type Client = {
+  id: string;
+  name: string;
+  roles: string[];
+};
+
 export type Response = {
   headers: Headers;
   status?: number;
@@ -122,6 +135,8 @@ export type PageInfo = {
   totalCount: number;
   hasNextPage: boolean;
   count: number;
+  pageNumber?: number;
+  totalPages?: number;
 };
 
 type MimeType =
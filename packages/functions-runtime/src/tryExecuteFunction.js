@@ -1,4 +1,4 @@
-const { withDatabase } = require("./database");
+const { withDatabase, withRowLevelSecurity } = require("./database");
 const { withAuditContext } = require("./auditing");
 const {
   withPermissions,
@@ -17,7 +17,7 @@ function tryExecuteFunction(
   return withPermissions(permitted, async ({ getPermissionState }) => {
     return withDatabase(db, actionType, async ({ transaction }) => {
       const fnResult = await withAuditContext(request, async () => {
-        return cb();
+        return withRowLevelSecurity(request.meta?.identity?.id, cb);
       });
 
       // api.permissions maintains an internal state of whether the current function has been *explicitly* permitted/denied by the user in the course of their custom function, or if execution has already been permitted by a role based permission (evaluated in the main runtime).
@@ -1,11 +1,11 @@
-const { withDatabase } = require("./database");
+const { withDatabase, withRowLevelSecurity } = require("./database");
 const { withAuditContext } = require("./auditing");
 
 // tryExecuteSubscriber will create a new database connection and execute the function call.
 function tryExecuteSubscriber({ request, db, actionType }, cb) {
   return withDatabase(db, actionType, async () => {
     await withAuditContext(request, async () => {
-      return cb();
+      return withRowLevelSecurity(request.meta?.identity?.id, cb);
     });
   });
 }
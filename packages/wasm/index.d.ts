@@ -34,6 +34,7 @@ export interface ValidateRequest {
   schemaFiles: SchemaFile[];
   config?: string;
   includeWarnings?: boolean;
+  includePermissions?: boolean;
 }
 
 export interface SchemaFile {
@@ -69,4 +70,26 @@ export interface ValidationError {
 export interface ValidationResult {
   errors: ValidationError[];
   warnings?: ValidationError[];
+  permissions?: PermissionReport;
+}
+
+export interface PermissionReport {
+  actions: PermissionReportAction[];
+}
+
+export interface PermissionReportAction {
+  api: string;
+  model: string;
+  action: string;
+  actionType: string;
+  rules: PermissionReportRule[];
+  roles: string[];
+  builtIn: boolean;
+  warnings: string[];
+}
+
+export interface PermissionReportRule {
+  level: "model" | "action";
+  expression?: string;
+  roles?: string[];
 }
@@ -7,6 +7,7 @@ import (
 	"syscall/js"
 
 	"github.com/teamkeel/keel/config"
+	"github.com/teamkeel/keel/permissions/coverage"
 	"github.com/teamkeel/keel/schema"
 	"github.com/teamkeel/keel/schema/completions"
 	"github.com/teamkeel/keel/schema/definitions"
@@ -200,10 +201,12 @@ func formatSchema(this js.Value, args []js.Value) any {
 //			},
 //		],
 //		config: "<YAML config file>",
-//		includeWarnings: true
+//		includeWarnings: true,
+//		includePermissions: true
 //	}
 //
-// The config file source is optional.
+// The config file source is optional. If includePermissions is set and the schema
+// is valid then the permission coverage of every action is also returned.
 func validate(this js.Value, args []js.Value) any {
 	return newPromise(func() (any, error) {
 
@@ -229,6 +232,7 @@ func validate(this js.Value, args []js.Value) any {
 			}
 		}
 		includeWarnings := args[0].Get("includeWarnings").Truthy()
+		includePermissions := args[0].Get("includePermissions").Truthy()
 
 		err := builder.ValidateFromInputs(&reader.Inputs{
 			SchemaFiles: schemaFiles,
@@ -242,6 +246,22 @@ func validate(this js.Value, args []js.Value) any {
 		}
 
 		errs, ok := err.(*errorhandling.ValidationErrors)
+
+		// The permission coverage can only be reported for a valid schema
+		if includePermissions && (err == nil || (ok && errs != nil && len(errs.Errors) == 0)) {
+			permissionsBuilder := schema.Builder{Config: builder.Config}
+			protoSchema, err := permissionsBuilder.MakeFromInputs(&reader.Inputs{
+				SchemaFiles: schemaFiles,
+			})
+			if err == nil {
+				report, mapErr := toMap(coverage.NewReport(protoSchema))
+				if mapErr != nil {
+					return nil, mapErr
+				}
+				resp["permissions"] = report
+			}
+		}
+
 		if !ok || errs == nil {
 			return resp, nil
 		}
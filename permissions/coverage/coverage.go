@@ -0,0 +1,186 @@
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/samber/lo"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/schema/parser"
+)
+
+const (
+	WarningNoPermissions = "no permission rules, so access will always be denied"
+	WarningPublic        = "permission expression is always true, so the action is publicly accessible"
+	WarningNotInApi      = "not included in any API, so the action is unreachable"
+)
+
+const (
+	LevelModel  = "model"
+	LevelAction = "action"
+)
+
+// Report is the permission coverage of every action in a schema.
+type Report struct {
+	Actions []*Action `json:"actions"`
+}
+
+// Action is the effective permission rules of an action within an API.
+type Action struct {
+	Api        string   `json:"api"`
+	Model      string   `json:"model"`
+	Action     string   `json:"action"`
+	ActionType string   `json:"actionType"`
+	Rules      []*Rule  `json:"rules"`
+	Roles      []string `json:"roles"`
+	BuiltIn    bool     `json:"builtIn"`
+	Warnings   []string `json:"warnings"`
+}
+
+// Rule is a single permission rule, which could be defined either at the model or action level.
+type Rule struct {
+	Level      string   `json:"level"`
+	Expression string   `json:"expression,omitempty"`
+	Roles      []string `json:"roles,omitempty"`
+}
+
+// NewReport generates the permission coverage report for every action of every API in the schema.
+// Actions which aren't included in any API are also reported.
+func NewReport(schema *proto.Schema) *Report {
+	report := &Report{
+		Actions: []*Action{},
+	}
+
+	inApi := map[string]bool{}
+	for _, api := range schema.Apis {
+		for _, name := range proto.GetActionNamesForApi(schema, api) {
+			action := schema.FindAction(name)
+			if action == nil {
+				continue
+			}
+
+			inApi[name] = true
+			report.Actions = append(report.Actions, newAction(schema, api.Name, action))
+		}
+	}
+
+	for _, model := range schema.Models {
+		for _, action := range model.Actions {
+			if inApi[action.Name] {
+				continue
+			}
+
+			coverage := newAction(schema, "", action)
+			if !coverage.BuiltIn {
+				coverage.Warnings = append(coverage.Warnings, WarningNotInApi)
+			}
+			report.Actions = append(report.Actions, coverage)
+		}
+	}
+
+	return report
+}
+
+func newAction(schema *proto.Schema, apiName string, action *proto.Action) *Action {
+	coverage := &Action{
+		Api:        apiName,
+		Model:      action.ModelName,
+		Action:     action.Name,
+		ActionType: strings.ToLower(strings.TrimPrefix(action.Type.String(), "ACTION_TYPE_")),
+		Rules:      []*Rule{},
+		Roles:      []string{},
+		BuiltIn:    action.ModelName == parser.IdentityModelName && lo.Contains(parser.BuiltInIdentityActionNames, action.Name),
+		Warnings:   []string{},
+	}
+
+	// Built-in actions perform their own authorisation
+	if coverage.BuiltIn {
+		return coverage
+	}
+
+	level := lo.Ternary(len(action.Permissions) > 0, LevelAction, LevelModel)
+
+	for _, permission := range proto.PermissionsForAction(schema, action) {
+		rule := &Rule{
+			Level: level,
+			Roles: permission.RoleNames,
+		}
+
+		if permission.Expression != nil {
+			rule.Expression = permission.Expression.Source
+
+			if expression, err := parser.ParseExpression(permission.Expression.Source); err == nil && isAlwaysTrue(expression) {
+				coverage.Warnings = append(coverage.Warnings, WarningPublic)
+			}
+		}
+
+		coverage.Roles = append(coverage.Roles, permission.RoleNames...)
+		coverage.Rules = append(coverage.Rules, rule)
+	}
+
+	coverage.Roles = lo.Uniq(coverage.Roles)
+
+	if len(coverage.Rules) == 0 {
+		coverage.Warnings = append(coverage.Warnings, WarningNoPermissions)
+	}
+
+	coverage.Warnings = lo.Uniq(coverage.Warnings)
+
+	return coverage
+}
+
+// isAlwaysTrue returns true if any of the ORed conditions of the expression is the literal true.
+func isAlwaysTrue(expression *parser.Expression) bool {
+	for _, or := range expression.Or {
+		if len(or.And) != 1 {
+			continue
+		}
+
+		and := or.And[0]
+		switch {
+		case and.Expression != nil:
+			if isAlwaysTrue(and.Expression) {
+				return true
+			}
+		case and.Condition != nil:
+			if and.Condition.Operator == nil && and.Condition.LHS != nil && and.Condition.LHS.True {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// WriteTable writes the report as a table.
+func (r *Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "API\tMODEL\tACTION\tTYPE\tRULES\tWARNINGS")
+
+	for _, action := range r.Actions {
+		rules := lo.Map(action.Rules, func(rule *Rule, _ int) string {
+			if rule.Expression != "" {
+				return rule.Expression
+			}
+			return fmt.Sprintf("roles: %s", strings.Join(rule.Roles, ", "))
+		})
+
+		if action.BuiltIn {
+			rules = []string{"(built-in)"}
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			lo.Ternary(action.Api != "", action.Api, "-"),
+			action.Model,
+			action.Action,
+			action.ActionType,
+			lo.Ternary(len(rules) > 0, strings.Join(rules, " | "), "-"),
+			lo.Ternary(len(action.Warnings) > 0, strings.Join(action.Warnings, "; "), "-"),
+		)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,124 @@
+package coverage_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/config"
+	"github.com/teamkeel/keel/permissions/coverage"
+	"github.com/teamkeel/keel/schema"
+)
+
+const coverageSchema = `
+model Post {
+	fields {
+		title Text
+		identity Identity
+	}
+	actions {
+		get getPost(id)
+		list listPosts() {
+			@permission(expression: true)
+		}
+		create createPost() with (title, identity.id) {
+			@permission(roles: [Admin])
+		}
+		delete deletePost(id)
+		update updatePost(id) with (title)
+	}
+	@permission(expression: post.identity == ctx.identity, actions: [get, update])
+}
+
+role Admin {
+	emails {
+		"admin@keel.xyz"
+	}
+}
+
+api Api {
+	models {
+		Post {
+			actions {
+				getPost
+				listPosts
+				createPost
+				deletePost
+			}
+		}
+		Identity {
+			actions {
+				requestPasswordReset
+			}
+		}
+	}
+}`
+
+func TestNewReport(t *testing.T) {
+	t.Parallel()
+
+	builder := schema.Builder{}
+	s, err := builder.MakeFromString(coverageSchema, config.Empty)
+	require.NoError(t, err)
+
+	report := coverage.NewReport(s)
+
+	find := func(name string) *coverage.Action {
+		action, ok := lo.Find(report.Actions, func(a *coverage.Action) bool { return a.Action == name })
+		require.True(t, ok, "action %s not found", name)
+		return action
+	}
+
+	getPost := find("getPost")
+	require.Equal(t, "Api", getPost.Api)
+	require.Equal(t, "get", getPost.ActionType)
+	require.Len(t, getPost.Rules, 1)
+	require.Equal(t, coverage.LevelModel, getPost.Rules[0].Level)
+	require.Equal(t, "post.identity == ctx.identity", getPost.Rules[0].Expression)
+	require.Empty(t, getPost.Warnings)
+
+	listPosts := find("listPosts")
+	require.Len(t, listPosts.Rules, 1)
+	require.Equal(t, coverage.LevelAction, listPosts.Rules[0].Level)
+	require.Equal(t, []string{coverage.WarningPublic}, listPosts.Warnings)
+
+	createPost := find("createPost")
+	require.Equal(t, []string{"Admin"}, createPost.Roles)
+	require.Empty(t, createPost.Warnings)
+
+	deletePost := find("deletePost")
+	require.Empty(t, deletePost.Rules)
+	require.Equal(t, []string{coverage.WarningNoPermissions}, deletePost.Warnings)
+
+	updatePost := find("updatePost")
+	require.Equal(t, "", updatePost.Api)
+	require.Equal(t, []string{coverage.WarningNotInApi}, updatePost.Warnings)
+
+	requestPasswordReset := find("requestPasswordReset")
+	require.True(t, requestPasswordReset.BuiltIn)
+	require.Equal(t, "Api", requestPasswordReset.Api)
+	require.Empty(t, requestPasswordReset.Warnings)
+
+	resetPassword := find("resetPassword")
+	require.True(t, resetPassword.BuiltIn)
+	require.Equal(t, "", resetPassword.Api)
+	require.Empty(t, resetPassword.Warnings)
+}
+
+func TestWriteTable(t *testing.T) {
+	t.Parallel()
+
+	builder := schema.Builder{}
+	s, err := builder.MakeFromString(coverageSchema, config.Empty)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, coverage.NewReport(s).WriteTable(&b))
+
+	table := b.String()
+	require.Contains(t, table, "API")
+	require.Contains(t, table, "roles: Admin")
+	require.Contains(t, table, "(built-in)")
+	require.Contains(t, table, coverage.WarningNotInApi)
+}
@@ -3,6 +3,7 @@ package permissions
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/samber/lo"
@@ -12,18 +13,24 @@ import (
 	"github.com/teamkeel/keel/schema/parser"
 )
 
+// The table of roles which have been assigned to identities at runtime
+const roleMembershipTable = "keel_role_membership"
+
 type ValueType int
 
 const (
-	ValueIdentityID      ValueType = iota // Identity ID of caller
-	ValueIdentityEmail                    // Identity email of caller
-	ValueIsAuthenticated                  // Is authenticated flag
-	ValueNow                              // Current timestamp
-	ValueHeader                           // Header value
-	ValueSecret                           // Secret value
-	ValueString                           // A string literal
-	ValueNumber                           // A number literal
-	ValueRecordIDs                        // The ID's of the records to check permission for
+	ValueIdentityID         ValueType = iota // Identity ID of caller
+	ValueIdentityEmail                       // Identity email of caller
+	ValueIsAuthenticated                     // Is authenticated flag
+	ValueNow                                 // Current timestamp
+	ValueHeader                              // Header value
+	ValueSecret                              // Secret value
+	ValueString                              // A string literal
+	ValueNumber                              // A number literal
+	ValueRecordIDs                           // The ID's of the records to check permission for
+	ValueClientID                            // Service client ID of caller
+	ValueClientName                          // Service client name of caller
+	ValueIsMfaAuthenticated                  // Is authenticated with a second factor flag
 )
 
 type Value struct {
@@ -40,14 +47,31 @@ type Value struct {
 // The returned SQL uses "?" placeholders for values and the returned list of values indicates
 // what values should be provided to the query at runtime.
 func ToSQL(s *proto.Schema, m *proto.Model, action *proto.Action) (sql string, values []*Value, err error) {
+	// Roles matched by the emails and domains in the schema are resolved before this query is run,
+	// so only the roles assigned to the identity at runtime need to be checked
+	return toSQL(s, m, proto.PermissionsForAction(s, action), false)
+}
+
+// ToSQLForActionTypes creates a single SQL query, in the same form as ToSQL, for the model-level
+// permission rules of any of the given action types. As this is not tied to a particular request,
+// roles matched by the emails and domains in the schema are also checked in the query.
+func ToSQLForActionTypes(s *proto.Schema, m *proto.Model, actionTypes ...proto.ActionType) (sql string, values []*Value, err error) {
+	permissions := []*proto.PermissionRule{}
+	for _, actionType := range actionTypes {
+		permissions = append(permissions, proto.PermissionsForActionType(s, m.Name, actionType)...)
+	}
+
+	return toSQL(s, m, lo.Uniq(permissions), true)
+}
+
+func toSQL(s *proto.Schema, m *proto.Model, permissions []*proto.PermissionRule, withRoleEmailsAndDomains bool) (sql string, values []*Value, err error) {
 	tableName := identifier(m.Name)
 	pkField := identifier(m.PrimaryKeyFieldName())
 
 	stmt := &statement{}
-	permissions := proto.PermissionsForAction(s, action)
 
 	for _, p := range permissions {
-		if p.Expression == nil {
+		if p.Expression == nil && len(p.RoleNames) == 0 {
 			continue
 		}
 
@@ -56,6 +80,20 @@ func ToSQL(s *proto.Schema, m *proto.Model, action *proto.Action) (sql string, v
 			stmt.expression += " or "
 		}
 
+		if len(p.RoleNames) > 0 {
+			if withRoleEmailsAndDomains {
+				stmt.expression += "("
+				handleRoleEmailsAndDomains(s, p.RoleNames, stmt)
+				stmt.expression += " or "
+				handleRoleMembership(m, p.RoleNames, stmt)
+				stmt.expression += ")"
+				continue
+			}
+
+			handleRoleMembership(m, p.RoleNames, stmt)
+			continue
+		}
+
 		expr, err := parser.ParseExpression(p.Expression.Source)
 		if err != nil {
 			return sql, values, err
@@ -239,6 +277,10 @@ func handleContext(s *proto.Schema, o *parser.Operand, stmt *statement) error {
 		stmt.expression += "?::boolean"
 		stmt.values = append(stmt.values, &Value{Type: ValueIsAuthenticated})
 		return nil
+	case "isMfaAuthenticated":
+		stmt.expression += "?::boolean"
+		stmt.values = append(stmt.values, &Value{Type: ValueIsMfaAuthenticated})
+		return nil
 	case "now":
 		stmt.expression += "?"
 		stmt.values = append(stmt.values, &Value{Type: ValueNow})
@@ -253,6 +295,22 @@ func handleContext(s *proto.Schema, o *parser.Operand, stmt *statement) error {
 		key := o.Ident.Fragments[2].Fragment
 		stmt.values = append(stmt.values, &Value{Type: ValueSecret, SecretKey: key})
 		return nil
+	case "client":
+		if len(o.Ident.Fragments) != 3 {
+			return errors.New("ctx.client used in expression with no properties")
+		}
+		switch o.Ident.Fragments[2].Fragment {
+		case "id":
+			stmt.expression += "?"
+			stmt.values = append(stmt.values, &Value{Type: ValueClientID})
+			return nil
+		case "name":
+			stmt.expression += "?"
+			stmt.values = append(stmt.values, &Value{Type: ValueClientName})
+			return nil
+		default:
+			return fmt.Errorf("unknown property %s of ctx.client", o.Ident.Fragments[2].Fragment)
+		}
 	default:
 		return fmt.Errorf("unknown property %s of ctx", o.Ident.Fragments[1].Fragment)
 	}
@@ -342,6 +400,75 @@ func handleModel(s *proto.Schema, model *proto.Model, ident *parser.Ident, stmt
 	return nil
 }
 
+// handleRoleMembership checks whether the identity has been assigned any of the roles, either for
+// all records or for the specific record being checked.
+func handleRoleMembership(m *proto.Model, roleNames []string, stmt *statement) {
+	table := identifier(roleMembershipTable)
+
+	stmt.expression += fmt.Sprintf(
+		"(EXISTS (SELECT 1 FROM %s WHERE %s.%s = ? AND %s.%s IN (%s) AND (%s.%s IS NULL OR (%s.%s = ? AND %s.%s = %s.%s))))",
+		table,
+		table, identifier("identity_id"),
+		table, identifier("role"), strings.TrimSuffix(strings.Repeat("?, ", len(roleNames)), ", "),
+		table, identifier("model"),
+		table, identifier("model"),
+		table, identifier("record_id"), identifier(m.Name), identifier(m.PrimaryKeyFieldName()),
+	)
+
+	stmt.values = append(stmt.values, &Value{Type: ValueIdentityID})
+	for _, roleName := range roleNames {
+		stmt.values = append(stmt.values, &Value{Type: ValueString, StringValue: strconv.Quote(roleName)})
+	}
+	stmt.values = append(stmt.values, &Value{Type: ValueString, StringValue: strconv.Quote(m.Name)})
+}
+
+// handleRoleEmailsAndDomains checks whether the identity has a verified email matching any of the
+// emails or domains of the roles.
+func handleRoleEmailsAndDomains(s *proto.Schema, roleNames []string, stmt *statement) {
+	emails := []string{}
+	domains := []string{}
+	for _, roleName := range roleNames {
+		role := proto.FindRole(roleName, s)
+		if role == nil {
+			continue
+		}
+		emails = append(emails, role.Emails...)
+		domains = append(domains, role.Domains...)
+	}
+
+	if len(emails) == 0 && len(domains) == 0 {
+		stmt.expression += "false"
+		return
+	}
+
+	table := identifier("identity")
+	conditions := []string{}
+
+	stmt.values = append(stmt.values, &Value{Type: ValueIdentityID})
+
+	if len(emails) > 0 {
+		conditions = append(conditions, fmt.Sprintf("%s.%s IN (%s)", table, identifier("email"), strings.TrimSuffix(strings.Repeat("?, ", len(emails)), ", ")))
+		for _, email := range emails {
+			stmt.values = append(stmt.values, &Value{Type: ValueString, StringValue: strconv.Quote(email)})
+		}
+	}
+
+	if len(domains) > 0 {
+		conditions = append(conditions, fmt.Sprintf("split_part(%s.%s, '@', 2) IN (%s)", table, identifier("email"), strings.TrimSuffix(strings.Repeat("?, ", len(domains)), ", ")))
+		for _, domain := range domains {
+			stmt.values = append(stmt.values, &Value{Type: ValueString, StringValue: strconv.Quote(domain)})
+		}
+	}
+
+	stmt.expression += fmt.Sprintf(
+		"(EXISTS (SELECT 1 FROM %s WHERE %s.%s = ? AND %s.%s AND (%s)))",
+		table,
+		table, identifier("id"),
+		table, identifier("emailVerified"),
+		strings.Join(conditions, " OR "),
+	)
+}
+
 // identifier converts s to snake cases and wraps it in double quotes
 func identifier(s string) string {
 	return db.QuoteIdentifier(casing.ToSnake(s))
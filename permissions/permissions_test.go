@@ -329,6 +329,37 @@ func TestToSQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "equals_client",
+			schema: `
+				model Post {
+					fields {
+						clientId Text
+					}
+					actions {
+						get getPost(id)
+					}
+					@permission(
+						expression: ctx.client.id == post.clientId,
+						actions: [get]
+					)
+				}
+			`,
+			action: "getPost",
+			sql: `
+				SELECT DISTINCT "post"."id" 
+				FROM "post" 
+				WHERE (? IS NOT DISTINCT FROM "post"."client_id") AND "post"."id" IN (?)
+			`,
+			values: []permissions.Value{
+				{
+					Type: permissions.ValueClientID,
+				},
+				{
+					Type: permissions.ValueRecordIDs,
+				},
+			},
+		},
 		{
 			name: "belongs_to_join",
 			schema: `
@@ -481,6 +512,39 @@ func TestToSQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "mfa_authenticated",
+			schema: `
+				model Payment {
+					fields {
+						identity Identity
+					}
+					actions {
+						get getPayment(id)
+					}
+					@permission(
+						expression: payment.identity == ctx.identity and ctx.isMfaAuthenticated,
+						actions: [get]
+					)
+				}
+			`,
+			action: "getPayment",
+			sql: `
+				SELECT DISTINCT "payment"."id" FROM "payment" 
+				WHERE ("payment"."identity_id" IS NOT DISTINCT FROM ? and ?::boolean) AND "payment"."id" IN (?)
+			`,
+			values: []permissions.Value{
+				{
+					Type: permissions.ValueIdentityID,
+				},
+				{
+					Type: permissions.ValueIsMfaAuthenticated,
+				},
+				{
+					Type: permissions.ValueRecordIDs,
+				},
+			},
+		},
 		{
 			name: "ctx_now",
 			schema: `
@@ -808,6 +872,112 @@ func TestToSQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "role",
+			schema: `
+				model Post {
+					actions {
+						get getPost(id)
+					}
+					@permission(
+						roles: [Editor, Admin],
+						actions: [get]
+					)
+				}
+				role Editor {
+					domains {
+						"keel.xyz"
+					}
+				}
+				role Admin {
+					emails {
+						"admin@keel.xyz"
+					}
+				}
+			`,
+			action: "getPost",
+			sql: `
+				SELECT DISTINCT "post"."id" 
+				FROM "post" 
+				WHERE (EXISTS (SELECT 1 FROM "keel_role_membership" 
+					WHERE "keel_role_membership"."identity_id" = ? 
+					AND "keel_role_membership"."role" IN (?, ?) 
+					AND ("keel_role_membership"."model" IS NULL OR ("keel_role_membership"."model" = ? AND "keel_role_membership"."record_id" = "post"."id")))) 
+				AND "post"."id" IN (?)
+			`,
+			values: []permissions.Value{
+				{
+					Type: permissions.ValueIdentityID,
+				},
+				{
+					Type:        permissions.ValueString,
+					StringValue: `"Editor"`,
+				},
+				{
+					Type:        permissions.ValueString,
+					StringValue: `"Admin"`,
+				},
+				{
+					Type:        permissions.ValueString,
+					StringValue: `"Post"`,
+				},
+				{
+					Type: permissions.ValueRecordIDs,
+				},
+			},
+		},
+		{
+			name: "role_and_expression",
+			schema: `
+				model Post {
+					fields {
+						public Boolean
+					}
+					actions {
+						get getPost(id)
+					}
+					@permission(
+						expression: post.public,
+						actions: [get]
+					)
+					@permission(
+						roles: [Editor],
+						actions: [get]
+					)
+				}
+				role Editor {
+					domains {
+						"keel.xyz"
+					}
+				}
+			`,
+			action: "getPost",
+			sql: `
+				SELECT DISTINCT "post"."id" 
+				FROM "post" 
+				WHERE (("post"."public") or (EXISTS (SELECT 1 FROM "keel_role_membership" 
+					WHERE "keel_role_membership"."identity_id" = ? 
+					AND "keel_role_membership"."role" IN (?) 
+					AND ("keel_role_membership"."model" IS NULL OR ("keel_role_membership"."model" = ? AND "keel_role_membership"."record_id" = "post"."id"))))) 
+				AND "post"."id" IN (?)
+			`,
+			values: []permissions.Value{
+				{
+					Type: permissions.ValueIdentityID,
+				},
+				{
+					Type:        permissions.ValueString,
+					StringValue: `"Editor"`,
+				},
+				{
+					Type:        permissions.ValueString,
+					StringValue: `"Post"`,
+				},
+				{
+					Type: permissions.ValueRecordIDs,
+				},
+			},
+		},
 	}
 
 	for _, fixture := range fixtures {
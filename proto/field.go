@@ -1,5 +1,10 @@
 package proto
 
+import (
+	"fmt"
+	"strings"
+)
+
 // IsFile tells us if the field is a file
 func (f *Field) IsFile() bool {
 	if f.Type == nil {
@@ -35,3 +40,57 @@ func (f *Field) IsBelongsTo() bool {
 func (f *Field) IsForeignKey() bool {
 	return f.ForeignKeyInfo != nil
 }
+
+// ReadPermissions returns the permission rules which restrict who can read the field.
+func (f *Field) ReadPermissions() []*PermissionRule {
+	return f.permissionsForActionType(ActionType_ACTION_TYPE_READ)
+}
+
+// WritePermissions returns the permission rules which restrict who can write to the field.
+func (f *Field) WritePermissions() []*PermissionRule {
+	return f.permissionsForActionType(ActionType_ACTION_TYPE_WRITE)
+}
+
+func (f *Field) permissionsForActionType(actionType ActionType) []*PermissionRule {
+	permissions := []*PermissionRule{}
+	for _, perm := range f.Permissions {
+		for _, t := range perm.ActionTypes {
+			if t == actionType {
+				permissions = append(permissions, perm)
+				break
+			}
+		}
+	}
+	return permissions
+}
+
+// PermissionsDescription describes who can read and write the field according to its permission rules,
+// for use in generated API documentation. Returns an empty string if the field has no permission rules.
+func (f *Field) PermissionsDescription() string {
+	descriptions := []string{}
+
+	if rules := f.ReadPermissions(); len(rules) > 0 {
+		descriptions = append(descriptions, fmt.Sprintf("Only readable when %s, otherwise null.", describePermissionRules(rules)))
+	}
+
+	if rules := f.WritePermissions(); len(rules) > 0 {
+		descriptions = append(descriptions, fmt.Sprintf("Only writable when %s.", describePermissionRules(rules)))
+	}
+
+	return strings.Join(descriptions, " ")
+}
+
+func describePermissionRules(rules []*PermissionRule) string {
+	conditions := []string{}
+	for _, rule := range rules {
+		switch {
+		case rule.Expression != nil:
+			conditions = append(conditions, rule.Expression.Source)
+		case len(rule.RoleNames) == 1:
+			conditions = append(conditions, fmt.Sprintf("the identity has the %s role", rule.RoleNames[0]))
+		case len(rule.RoleNames) > 1:
+			conditions = append(conditions, fmt.Sprintf("the identity has one of the %s roles", strings.Join(rule.RoleNames, ", ")))
+		}
+	}
+	return strings.Join(conditions, " or ")
+}
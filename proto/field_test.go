@@ -0,0 +1,34 @@
+package proto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldPermissionsDescription(t *testing.T) {
+	t.Parallel()
+
+	field := &Field{
+		Name: "salary",
+		Permissions: []*PermissionRule{
+			{
+				Expression:  &Expression{Source: "employee.identity == ctx.identity"},
+				ActionTypes: []ActionType{ActionType_ACTION_TYPE_READ},
+			},
+			{
+				RoleNames:   []string{"Finance", "HumanResources"},
+				ActionTypes: []ActionType{ActionType_ACTION_TYPE_READ, ActionType_ACTION_TYPE_WRITE},
+			},
+		},
+	}
+
+	require.Len(t, field.ReadPermissions(), 2)
+	require.Len(t, field.WritePermissions(), 1)
+	require.Equal(t,
+		"Only readable when employee.identity == ctx.identity or the identity has one of the Finance, HumanResources roles, otherwise null. "+
+			"Only writable when the identity has one of the Finance, HumanResources roles.",
+		field.PermissionsDescription())
+
+	require.Equal(t, "", (&Field{Name: "name"}).PermissionsDescription())
+}
@@ -1,5 +1,24 @@
 package proto
 
+import "strings"
+
+const (
+	FilterGroupAnd = "and"
+	FilterGroupOr  = "or"
+	FilterGroupNot = "not"
+
+	// FilterGroupMessageSuffix is appended to the action name to form
+	// the name of a list action's filter group message.
+	FilterGroupMessageSuffix = "WhereGroup"
+
+	HierarchyDescendantsOf = "descendantsOf"
+	HierarchyAncestorsOf   = "ancestorsOf"
+
+	// HierarchyQueryInputMessageName is the name of the message used to
+	// filter a self-referencing model's records by their position in the tree.
+	HierarchyQueryInputMessageName = "HierarchyQueryInput"
+)
+
 // IsModelField returns true if the input targets a model field
 // and is handled automatically by the runtime.
 // This will only be true for inputs that are built-in actions,
@@ -22,6 +41,36 @@ func (f *MessageField) IsMessage() bool {
 	return f.Type.Type == Type_TYPE_MESSAGE
 }
 
+// IsFilterGroup returns true if the field is a nested "and", "or" or "not"
+// group of filters on the where input of a built-in list action.
+func (f *MessageField) IsFilterGroup() bool {
+	if f.IsModelField() || !f.IsMessage() || f.Type.MessageName == nil {
+		return false
+	}
+
+	switch f.Name {
+	case FilterGroupAnd, FilterGroupOr, FilterGroupNot:
+		return strings.HasSuffix(f.Type.MessageName.Value, FilterGroupMessageSuffix+"Input")
+	default:
+		return false
+	}
+}
+
+// IsHierarchyFilter returns true if the field is a "descendantsOf" or "ancestorsOf"
+// filter on the where input of a built-in list action.
+func (f *MessageField) IsHierarchyFilter() bool {
+	if f.IsModelField() || !f.IsMessage() || f.Type.MessageName == nil {
+		return false
+	}
+
+	switch f.Name {
+	case HierarchyDescendantsOf, HierarchyAncestorsOf:
+		return f.Type.MessageName.Value == HierarchyQueryInputMessageName
+	default:
+		return false
+	}
+}
+
 func (m *Message) FindField(fieldName string) *MessageField {
 	for _, field := range m.Fields {
 		if field.Name == fieldName {
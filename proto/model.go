@@ -27,6 +27,13 @@ func (m *Model) FieldNames() []string {
 	return names
 }
 
+// ReadRestrictedFields returns the fields of the model which have permission rules restricting who can read them.
+func (m *Model) ReadRestrictedFields() []*Field {
+	return lo.Filter(m.Fields, func(f *Field, _ int) bool {
+		return len(f.ReadPermissions()) > 0
+	})
+}
+
 // ForeignKeyFields returns all the fields in the given model which have their ForeignKeyInfo
 // populated.
 func (m *Model) ForeignKeyFields() []*Field {
@@ -46,3 +53,22 @@ func (m *Model) PrimaryKeyFieldName() string {
 	}
 	return ""
 }
+
+// HierarchyField returns the model's self-referencing belongs-to relationship field (such as "parent"),
+// which arranges the model's records into a tree. Nil is returned unless there is exactly one such field.
+func (m *Model) HierarchyField() *Field {
+	fields := lo.Filter(m.Fields, func(f *Field, _ int) bool {
+		return f.IsBelongsTo() && f.Type.ModelName.GetValue() == m.Name
+	})
+	if len(fields) != 1 {
+		return nil
+	}
+	return fields[0]
+}
+
+// PiiFields returns the fields of the model which hold personal data, as marked with @pii.
+func (m *Model) PiiFields() []*Field {
+	return lo.Filter(m.Fields, func(f *Field, _ int) bool {
+		return f.Pii
+	})
+}
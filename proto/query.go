@@ -318,6 +318,24 @@ func GetActionNamesForApi(p *Schema, api *Api) []string {
 	return actions
 }
 
+// GetModelsWithActionTypeForApi returns the models which have an action of the given type available on an API.
+func GetModelsWithActionTypeForApi(p *Schema, api *Api, actionType ActionType) []*Model {
+	models := []*Model{}
+	for _, actionName := range GetActionNamesForApi(p, api) {
+		action := p.FindAction(actionName)
+		if action == nil || action.Type != actionType {
+			continue
+		}
+
+		model := p.FindModel(action.ModelName)
+		if !lo.Contains(models, model) {
+			models = append(models, model)
+		}
+	}
+
+	return models
+}
+
 // PermissionsWithRole returns a list of those permission present in the given permissions
 // list, which have at least one Role-based permission rule. This does not imply that the
 // returned Permissions might not also have some expression-based rules.
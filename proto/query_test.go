@@ -32,6 +32,46 @@ func TestModelExists(t *testing.T) {
 	require.False(t, ModelExists(referenceSchema.Models, "ModelZ"))
 }
 
+func TestGetModelsWithActionTypeForApi(t *testing.T) {
+	t.Parallel()
+	schema := &Schema{
+		Models: []*Model{
+			{
+				Name: "ModelA",
+				Actions: []*Action{
+					{Name: "getA", ModelName: "ModelA", Type: ActionType_ACTION_TYPE_GET},
+					{Name: "listA", ModelName: "ModelA", Type: ActionType_ACTION_TYPE_LIST},
+				},
+			},
+			{
+				Name: "ModelB",
+				Actions: []*Action{
+					{Name: "getB", ModelName: "ModelB", Type: ActionType_ACTION_TYPE_GET},
+				},
+			},
+			{
+				Name: "ModelC",
+				Actions: []*Action{
+					{Name: "getC", ModelName: "ModelC", Type: ActionType_ACTION_TYPE_GET},
+				},
+			},
+		},
+	}
+	api := &Api{
+		ApiModels: []*ApiModel{
+			{ModelName: "ModelA", ModelActions: []*ApiModelAction{{ActionName: "getA"}, {ActionName: "listA"}}},
+			{ModelName: "ModelB", ModelActions: []*ApiModelAction{}},
+			{ModelName: "ModelC", ModelActions: []*ApiModelAction{{ActionName: "getC"}}},
+		},
+	}
+
+	models := GetModelsWithActionTypeForApi(schema, api, ActionType_ACTION_TYPE_GET)
+	names := lo.Map(models, func(m *Model, _ int) string {
+		return m.Name
+	})
+	require.Equal(t, []string{"ModelA", "ModelC"}, names)
+}
+
 var referenceSchema *Schema = &Schema{
 	Models: []*Model{
 		{
@@ -801,6 +801,496 @@ func (x *ListToolsResponse) GetTools() []*proto1.ActionConfig {
 	return nil
 }
 
+type SetExplainPermissionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+}
+
+func (x *SetExplainPermissionsRequest) Reset() {
+	*x = SetExplainPermissionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetExplainPermissionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetExplainPermissionsRequest) ProtoMessage() {}
+
+func (x *SetExplainPermissionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetExplainPermissionsRequest.ProtoReflect.Descriptor instead.
+func (*SetExplainPermissionsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SetExplainPermissionsRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type SetExplainPermissionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+}
+
+func (x *SetExplainPermissionsResponse) Reset() {
+	*x = SetExplainPermissionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetExplainPermissionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetExplainPermissionsResponse) ProtoMessage() {}
+
+func (x *SetExplainPermissionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetExplainPermissionsResponse.ProtoReflect.Descriptor instead.
+func (*SetExplainPermissionsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SetExplainPermissionsResponse) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type ListPermissionExplanationsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Limit int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *ListPermissionExplanationsRequest) Reset() {
+	*x = ListPermissionExplanationsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPermissionExplanationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPermissionExplanationsRequest) ProtoMessage() {}
+
+func (x *ListPermissionExplanationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPermissionExplanationsRequest.ProtoReflect.Descriptor instead.
+func (*ListPermissionExplanationsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListPermissionExplanationsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListPermissionExplanationsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Requests []*RequestPermissionExplanations `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+}
+
+func (x *ListPermissionExplanationsResponse) Reset() {
+	*x = ListPermissionExplanationsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPermissionExplanationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPermissionExplanationsResponse) ProtoMessage() {}
+
+func (x *ListPermissionExplanationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPermissionExplanationsResponse.ProtoReflect.Descriptor instead.
+func (*ListPermissionExplanationsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListPermissionExplanationsResponse) GetRequests() []*RequestPermissionExplanations {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type RequestPermissionExplanations struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Method       string                   `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Path         string                   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Time         *timestamppb.Timestamp   `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	Explanations []*PermissionExplanation `protobuf:"bytes,4,rep,name=explanations,proto3" json:"explanations,omitempty"`
+}
+
+func (x *RequestPermissionExplanations) Reset() {
+	*x = RequestPermissionExplanations{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestPermissionExplanations) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPermissionExplanations) ProtoMessage() {}
+
+func (x *RequestPermissionExplanations) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPermissionExplanations.ProtoReflect.Descriptor instead.
+func (*RequestPermissionExplanations) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RequestPermissionExplanations) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *RequestPermissionExplanations) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *RequestPermissionExplanations) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *RequestPermissionExplanations) GetExplanations() []*PermissionExplanation {
+	if x != nil {
+		return x.Explanations
+	}
+	return nil
+}
+
+type PermissionExplanation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Model      string                       `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	Action     string                       `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Authorised bool                         `protobuf:"varint,3,opt,name=authorised,proto3" json:"authorised,omitempty"`
+	Reason     string                       `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Rules      []*PermissionRuleExplanation `protobuf:"bytes,5,rep,name=rules,proto3" json:"rules,omitempty"`
+	Sql        string                       `protobuf:"bytes,6,opt,name=sql,proto3" json:"sql,omitempty"`
+	SqlArgs    []string                     `protobuf:"bytes,7,rep,name=sql_args,json=sqlArgs,proto3" json:"sql_args,omitempty"`
+	Rows       []*PermissionRowExplanation  `protobuf:"bytes,8,rep,name=rows,proto3" json:"rows,omitempty"`
+}
+
+func (x *PermissionExplanation) Reset() {
+	*x = PermissionExplanation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PermissionExplanation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PermissionExplanation) ProtoMessage() {}
+
+func (x *PermissionExplanation) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PermissionExplanation.ProtoReflect.Descriptor instead.
+func (*PermissionExplanation) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PermissionExplanation) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *PermissionExplanation) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *PermissionExplanation) GetAuthorised() bool {
+	if x != nil {
+		return x.Authorised
+	}
+	return false
+}
+
+func (x *PermissionExplanation) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PermissionExplanation) GetRules() []*PermissionRuleExplanation {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *PermissionExplanation) GetSql() string {
+	if x != nil {
+		return x.Sql
+	}
+	return ""
+}
+
+func (x *PermissionExplanation) GetSqlArgs() []string {
+	if x != nil {
+		return x.SqlArgs
+	}
+	return nil
+}
+
+func (x *PermissionExplanation) GetRows() []*PermissionRowExplanation {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+type PermissionRuleExplanation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Expression string   `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	Roles      []string `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	// Either "early" if the rule was resolved without querying the database, or "database" if resolved in SQL
+	ResolvedIn string `protobuf:"bytes,3,opt,name=resolved_in,json=resolvedIn,proto3" json:"resolved_in,omitempty"`
+	// Only set for rules resolved early
+	Authorised *bool `protobuf:"varint,4,opt,name=authorised,proto3,oneof" json:"authorised,omitempty"`
+}
+
+func (x *PermissionRuleExplanation) Reset() {
+	*x = PermissionRuleExplanation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PermissionRuleExplanation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PermissionRuleExplanation) ProtoMessage() {}
+
+func (x *PermissionRuleExplanation) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PermissionRuleExplanation.ProtoReflect.Descriptor instead.
+func (*PermissionRuleExplanation) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PermissionRuleExplanation) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *PermissionRuleExplanation) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *PermissionRuleExplanation) GetResolvedIn() string {
+	if x != nil {
+		return x.ResolvedIn
+	}
+	return ""
+}
+
+func (x *PermissionRuleExplanation) GetAuthorised() bool {
+	if x != nil && x.Authorised != nil {
+		return *x.Authorised
+	}
+	return false
+}
+
+type PermissionRowExplanation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Authorised bool   `protobuf:"varint,2,opt,name=authorised,proto3" json:"authorised,omitempty"`
+}
+
+func (x *PermissionRowExplanation) Reset() {
+	*x = PermissionRowExplanation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PermissionRowExplanation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PermissionRowExplanation) ProtoMessage() {}
+
+func (x *PermissionRowExplanation) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PermissionRowExplanation.ProtoReflect.Descriptor instead.
+func (*PermissionRowExplanation) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *PermissionRowExplanation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PermissionRowExplanation) GetAuthorised() bool {
+	if x != nil {
+		return x.Authorised
+	}
+	return false
+}
+
 var File_rpc_proto protoreflect.FileDescriptor
 
 var file_rpc_proto_rawDesc = []byte{
@@ -902,30 +1392,104 @@ var file_rpc_proto_rawDesc = []byte{
 	0x6f, 0x6f, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x05,
 	0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x6f,
 	0x6f, 0x6c, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x52, 0x05, 0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x2a, 0x29, 0x0a, 0x0e, 0x53, 0x51, 0x4c, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x73, 0x75, 0x63,
-	0x63, 0x65, 0x73, 0x73, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64,
-	0x10, 0x01, 0x32, 0xb5, 0x02, 0x0a, 0x03, 0x41, 0x50, 0x49, 0x12, 0x40, 0x0a, 0x0f, 0x47, 0x65,
-	0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x15, 0x2e,
-	0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x63,
-	0x68, 0x65, 0x6d, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x0b,
-	0x52, 0x75, 0x6e, 0x53, 0x51, 0x4c, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x12, 0x2e, 0x72, 0x70,
-	0x63, 0x2e, 0x53, 0x51, 0x4c, 0x51, 0x75, 0x65, 0x72, 0x79, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x1a,
-	0x15, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x51, 0x4c, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61,
-	0x63, 0x65, 0x12, 0x14, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x63,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47,
-	0x65, 0x74, 0x54, 0x72, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x3d, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x63, 0x65, 0x73, 0x12, 0x16, 0x2e,
-	0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74,
-	0x54, 0x72, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a,
-	0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x6f, 0x6c, 0x73, 0x12, 0x15, 0x2e, 0x72, 0x70,
-	0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x6f, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x6f,
-	0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x52, 0x05, 0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x22, 0x38, 0x0a, 0x1c, 0x53, 0x65, 0x74, 0x45, 0x78,
+	0x70, 0x6c, 0x61, 0x69, 0x6e, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c,
+	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65,
+	0x64, 0x22, 0x39, 0x0a, 0x1d, 0x53, 0x65, 0x74, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x50,
+	0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x22, 0x39, 0x0a, 0x21,
+	0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x78,
+	0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x64, 0x0a, 0x22, 0x4c, 0x69, 0x73, 0x74, 0x50,
+	0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a,
+	0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x22, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x50, 0x65, 0x72,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0xbb, 0x01,
+	0x0a, 0x1d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x2e, 0x0a, 0x04, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x65,
+	0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x65,
+	0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x93, 0x02, 0x0a, 0x15,
+	0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x6e,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x73, 0x65,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69,
+	0x73, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x34, 0x0a, 0x05, 0x72,
+	0x75, 0x6c, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x75, 0x6c, 0x65, 0x45,
+	0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65,
+	0x73, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x71, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x73, 0x71, 0x6c, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x71, 0x6c, 0x5f, 0x61, 0x72, 0x67, 0x73, 0x18,
+	0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x73, 0x71, 0x6c, 0x41, 0x72, 0x67, 0x73, 0x12, 0x31,
+	0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x6f, 0x77,
+	0x45, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x72, 0x6f, 0x77,
+	0x73, 0x22, 0xa6, 0x01, 0x0a, 0x19, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x52, 0x75, 0x6c, 0x65, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x14, 0x0a, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05,
+	0x72, 0x6f, 0x6c, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65,
+	0x64, 0x5f, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x73, 0x6f,
+	0x6c, 0x76, 0x65, 0x64, 0x49, 0x6e, 0x12, 0x23, 0x0a, 0x0a, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72,
+	0x69, 0x73, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x0a, 0x61, 0x75,
+	0x74, 0x68, 0x6f, 0x72, 0x69, 0x73, 0x65, 0x64, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f,
+	0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x73, 0x65, 0x64, 0x22, 0x4a, 0x0a, 0x18, 0x50, 0x65,
+	0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x6f, 0x77, 0x45, 0x78, 0x70, 0x6c, 0x61,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72,
+	0x69, 0x73, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x61, 0x75, 0x74, 0x68,
+	0x6f, 0x72, 0x69, 0x73, 0x65, 0x64, 0x2a, 0x29, 0x0a, 0x0e, 0x53, 0x51, 0x4c, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x10,
+	0x01, 0x32, 0x84, 0x04, 0x0a, 0x03, 0x41, 0x50, 0x49, 0x12, 0x40, 0x0a, 0x0f, 0x47, 0x65, 0x74,
+	0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x15, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x63, 0x68,
+	0x65, 0x6d, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x0b, 0x52,
+	0x75, 0x6e, 0x53, 0x51, 0x4c, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x12, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x51, 0x4c, 0x51, 0x75, 0x65, 0x72, 0x79, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x1a, 0x15,
+	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x51, 0x4c, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x63,
+	0x65, 0x12, 0x14, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x63, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65,
+	0x74, 0x54, 0x72, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d,
+	0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x63, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54,
+	0x72, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a,
+	0x09, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x6f, 0x6c, 0x73, 0x12, 0x15, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x6f, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x16, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x6f, 0x6c,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5e, 0x0a, 0x15, 0x53, 0x65, 0x74,
+	0x45, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x21, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x45, 0x78, 0x70, 0x6c,
+	0x61, 0x69, 0x6e, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x45,
+	0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6d, 0x0a, 0x1a, 0x4c, 0x69, 0x73,
+	0x74, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x78, 0x70, 0x6c, 0x61,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x26, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x78, 0x70, 0x6c,
+	0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x27, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -941,52 +1505,69 @@ func file_rpc_proto_rawDescGZIP() []byte {
 }
 
 var file_rpc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_rpc_proto_goTypes = []any{
-	(SQLQueryStatus)(0),           // 0: rpc.SQLQueryStatus
-	(*GetSchemaRequest)(nil),      // 1: rpc.GetSchemaRequest
-	(*GetSchemaResponse)(nil),     // 2: rpc.GetSchemaResponse
-	(*SQLQueryInput)(nil),         // 3: rpc.SQLQueryInput
-	(*SQLQueryResponse)(nil),      // 4: rpc.SQLQueryResponse
-	(*GetTraceRequest)(nil),       // 5: rpc.GetTraceRequest
-	(*GetTraceResponse)(nil),      // 6: rpc.GetTraceResponse
-	(*ListTracesRequest)(nil),     // 7: rpc.ListTracesRequest
-	(*ListTraceFilter)(nil),       // 8: rpc.ListTraceFilter
-	(*ListTracesResponse)(nil),    // 9: rpc.ListTracesResponse
-	(*TraceItem)(nil),             // 10: rpc.TraceItem
-	(*ListToolsRequest)(nil),      // 11: rpc.ListToolsRequest
-	(*ListToolsResponse)(nil),     // 12: rpc.ListToolsResponse
-	(*proto.Schema)(nil),          // 13: proto.Schema
-	(*v1.TracesData)(nil),         // 14: opentelemetry.proto.trace.v1.TracesData
-	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
-	(*proto1.ActionConfig)(nil),   // 16: tools.ActionConfig
+	(SQLQueryStatus)(0),                        // 0: rpc.SQLQueryStatus
+	(*GetSchemaRequest)(nil),                   // 1: rpc.GetSchemaRequest
+	(*GetSchemaResponse)(nil),                  // 2: rpc.GetSchemaResponse
+	(*SQLQueryInput)(nil),                      // 3: rpc.SQLQueryInput
+	(*SQLQueryResponse)(nil),                   // 4: rpc.SQLQueryResponse
+	(*GetTraceRequest)(nil),                    // 5: rpc.GetTraceRequest
+	(*GetTraceResponse)(nil),                   // 6: rpc.GetTraceResponse
+	(*ListTracesRequest)(nil),                  // 7: rpc.ListTracesRequest
+	(*ListTraceFilter)(nil),                    // 8: rpc.ListTraceFilter
+	(*ListTracesResponse)(nil),                 // 9: rpc.ListTracesResponse
+	(*TraceItem)(nil),                          // 10: rpc.TraceItem
+	(*ListToolsRequest)(nil),                   // 11: rpc.ListToolsRequest
+	(*ListToolsResponse)(nil),                  // 12: rpc.ListToolsResponse
+	(*SetExplainPermissionsRequest)(nil),       // 13: rpc.SetExplainPermissionsRequest
+	(*SetExplainPermissionsResponse)(nil),      // 14: rpc.SetExplainPermissionsResponse
+	(*ListPermissionExplanationsRequest)(nil),  // 15: rpc.ListPermissionExplanationsRequest
+	(*ListPermissionExplanationsResponse)(nil), // 16: rpc.ListPermissionExplanationsResponse
+	(*RequestPermissionExplanations)(nil),      // 17: rpc.RequestPermissionExplanations
+	(*PermissionExplanation)(nil),              // 18: rpc.PermissionExplanation
+	(*PermissionRuleExplanation)(nil),          // 19: rpc.PermissionRuleExplanation
+	(*PermissionRowExplanation)(nil),           // 20: rpc.PermissionRowExplanation
+	(*proto.Schema)(nil),                       // 21: proto.Schema
+	(*v1.TracesData)(nil),                      // 22: opentelemetry.proto.trace.v1.TracesData
+	(*timestamppb.Timestamp)(nil),              // 23: google.protobuf.Timestamp
+	(*proto1.ActionConfig)(nil),                // 24: tools.ActionConfig
 }
 var file_rpc_proto_depIdxs = []int32{
-	13, // 0: rpc.GetSchemaResponse.schema:type_name -> proto.Schema
+	21, // 0: rpc.GetSchemaResponse.schema:type_name -> proto.Schema
 	0,  // 1: rpc.SQLQueryResponse.status:type_name -> rpc.SQLQueryStatus
-	14, // 2: rpc.GetTraceResponse.trace:type_name -> opentelemetry.proto.trace.v1.TracesData
-	15, // 3: rpc.ListTracesRequest.before:type_name -> google.protobuf.Timestamp
-	15, // 4: rpc.ListTracesRequest.after:type_name -> google.protobuf.Timestamp
+	22, // 2: rpc.GetTraceResponse.trace:type_name -> opentelemetry.proto.trace.v1.TracesData
+	23, // 3: rpc.ListTracesRequest.before:type_name -> google.protobuf.Timestamp
+	23, // 4: rpc.ListTracesRequest.after:type_name -> google.protobuf.Timestamp
 	8,  // 5: rpc.ListTracesRequest.filters:type_name -> rpc.ListTraceFilter
 	10, // 6: rpc.ListTracesResponse.traces:type_name -> rpc.TraceItem
-	15, // 7: rpc.TraceItem.start_time:type_name -> google.protobuf.Timestamp
-	15, // 8: rpc.TraceItem.end_time:type_name -> google.protobuf.Timestamp
-	16, // 9: rpc.ListToolsResponse.tools:type_name -> tools.ActionConfig
-	1,  // 10: rpc.API.GetActiveSchema:input_type -> rpc.GetSchemaRequest
-	3,  // 11: rpc.API.RunSQLQuery:input_type -> rpc.SQLQueryInput
-	5,  // 12: rpc.API.GetTrace:input_type -> rpc.GetTraceRequest
-	7,  // 13: rpc.API.ListTraces:input_type -> rpc.ListTracesRequest
-	11, // 14: rpc.API.ListTools:input_type -> rpc.ListToolsRequest
-	2,  // 15: rpc.API.GetActiveSchema:output_type -> rpc.GetSchemaResponse
-	4,  // 16: rpc.API.RunSQLQuery:output_type -> rpc.SQLQueryResponse
-	6,  // 17: rpc.API.GetTrace:output_type -> rpc.GetTraceResponse
-	9,  // 18: rpc.API.ListTraces:output_type -> rpc.ListTracesResponse
-	12, // 19: rpc.API.ListTools:output_type -> rpc.ListToolsResponse
-	15, // [15:20] is the sub-list for method output_type
-	10, // [10:15] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	23, // 7: rpc.TraceItem.start_time:type_name -> google.protobuf.Timestamp
+	23, // 8: rpc.TraceItem.end_time:type_name -> google.protobuf.Timestamp
+	24, // 9: rpc.ListToolsResponse.tools:type_name -> tools.ActionConfig
+	17, // 10: rpc.ListPermissionExplanationsResponse.requests:type_name -> rpc.RequestPermissionExplanations
+	23, // 11: rpc.RequestPermissionExplanations.time:type_name -> google.protobuf.Timestamp
+	18, // 12: rpc.RequestPermissionExplanations.explanations:type_name -> rpc.PermissionExplanation
+	19, // 13: rpc.PermissionExplanation.rules:type_name -> rpc.PermissionRuleExplanation
+	20, // 14: rpc.PermissionExplanation.rows:type_name -> rpc.PermissionRowExplanation
+	1,  // 15: rpc.API.GetActiveSchema:input_type -> rpc.GetSchemaRequest
+	3,  // 16: rpc.API.RunSQLQuery:input_type -> rpc.SQLQueryInput
+	5,  // 17: rpc.API.GetTrace:input_type -> rpc.GetTraceRequest
+	7,  // 18: rpc.API.ListTraces:input_type -> rpc.ListTracesRequest
+	11, // 19: rpc.API.ListTools:input_type -> rpc.ListToolsRequest
+	13, // 20: rpc.API.SetExplainPermissions:input_type -> rpc.SetExplainPermissionsRequest
+	15, // 21: rpc.API.ListPermissionExplanations:input_type -> rpc.ListPermissionExplanationsRequest
+	2,  // 22: rpc.API.GetActiveSchema:output_type -> rpc.GetSchemaResponse
+	4,  // 23: rpc.API.RunSQLQuery:output_type -> rpc.SQLQueryResponse
+	6,  // 24: rpc.API.GetTrace:output_type -> rpc.GetTraceResponse
+	9,  // 25: rpc.API.ListTraces:output_type -> rpc.ListTracesResponse
+	12, // 26: rpc.API.ListTools:output_type -> rpc.ListToolsResponse
+	14, // 27: rpc.API.SetExplainPermissions:output_type -> rpc.SetExplainPermissionsResponse
+	16, // 28: rpc.API.ListPermissionExplanations:output_type -> rpc.ListPermissionExplanationsResponse
+	22, // [22:29] is the sub-list for method output_type
+	15, // [15:22] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
 }
 
 func init() { file_rpc_proto_init() }
@@ -1139,15 +1720,112 @@ func file_rpc_proto_init() {
 				return nil
 			}
 		}
+		file_rpc_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*SetExplainPermissionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*SetExplainPermissionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*ListPermissionExplanationsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*ListPermissionExplanationsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*RequestPermissionExplanations); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*PermissionExplanation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*PermissionRuleExplanation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*PermissionRowExplanation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_rpc_proto_msgTypes[2].OneofWrappers = []any{}
+	file_rpc_proto_msgTypes[18].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_rpc_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   12,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -42,6 +42,12 @@ type API interface {
 
 	// Return a list of default generated tools config for interacting with the API
 	ListTools(context.Context, *ListToolsRequest) (*ListToolsResponse, error)
+
+	// Enable or disable the explaining of permission decisions for all requests
+	SetExplainPermissions(context.Context, *SetExplainPermissionsRequest) (*SetExplainPermissionsResponse, error)
+
+	// Return the permission explanations of the most recent requests which were explained
+	ListPermissionExplanations(context.Context, *ListPermissionExplanationsRequest) (*ListPermissionExplanationsResponse, error)
 }
 
 // ===================
@@ -50,7 +56,7 @@ type API interface {
 
 type aPIProtobufClient struct {
 	client      HTTPClient
-	urls        [5]string
+	urls        [7]string
 	interceptor twirp.Interceptor
 	opts        twirp.ClientOptions
 }
@@ -78,12 +84,14 @@ func NewAPIProtobufClient(baseURL string, client HTTPClient, opts ...twirp.Clien
 	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
 	serviceURL := sanitizeBaseURL(baseURL)
 	serviceURL += baseServicePath(pathPrefix, "rpc", "API")
-	urls := [5]string{
+	urls := [7]string{
 		serviceURL + "GetActiveSchema",
 		serviceURL + "RunSQLQuery",
 		serviceURL + "GetTrace",
 		serviceURL + "ListTraces",
 		serviceURL + "ListTools",
+		serviceURL + "SetExplainPermissions",
+		serviceURL + "ListPermissionExplanations",
 	}
 
 	return &aPIProtobufClient{
@@ -324,13 +332,105 @@ func (c *aPIProtobufClient) callListTools(ctx context.Context, in *ListToolsRequ
 	return out, nil
 }
 
+func (c *aPIProtobufClient) SetExplainPermissions(ctx context.Context, in *SetExplainPermissionsRequest) (*SetExplainPermissionsResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "rpc")
+	ctx = ctxsetters.WithServiceName(ctx, "API")
+	ctx = ctxsetters.WithMethodName(ctx, "SetExplainPermissions")
+	caller := c.callSetExplainPermissions
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *SetExplainPermissionsRequest) (*SetExplainPermissionsResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*SetExplainPermissionsRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*SetExplainPermissionsRequest) when calling interceptor")
+					}
+					return c.callSetExplainPermissions(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*SetExplainPermissionsResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*SetExplainPermissionsResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *aPIProtobufClient) callSetExplainPermissions(ctx context.Context, in *SetExplainPermissionsRequest) (*SetExplainPermissionsResponse, error) {
+	out := new(SetExplainPermissionsResponse)
+	ctx, err := doProtobufRequest(ctx, c.client, c.opts.Hooks, c.urls[5], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *aPIProtobufClient) ListPermissionExplanations(ctx context.Context, in *ListPermissionExplanationsRequest) (*ListPermissionExplanationsResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "rpc")
+	ctx = ctxsetters.WithServiceName(ctx, "API")
+	ctx = ctxsetters.WithMethodName(ctx, "ListPermissionExplanations")
+	caller := c.callListPermissionExplanations
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *ListPermissionExplanationsRequest) (*ListPermissionExplanationsResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ListPermissionExplanationsRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ListPermissionExplanationsRequest) when calling interceptor")
+					}
+					return c.callListPermissionExplanations(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ListPermissionExplanationsResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ListPermissionExplanationsResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *aPIProtobufClient) callListPermissionExplanations(ctx context.Context, in *ListPermissionExplanationsRequest) (*ListPermissionExplanationsResponse, error) {
+	out := new(ListPermissionExplanationsResponse)
+	ctx, err := doProtobufRequest(ctx, c.client, c.opts.Hooks, c.urls[6], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
 // ===============
 // API JSON Client
 // ===============
 
 type aPIJSONClient struct {
 	client      HTTPClient
-	urls        [5]string
+	urls        [7]string
 	interceptor twirp.Interceptor
 	opts        twirp.ClientOptions
 }
@@ -358,12 +458,14 @@ func NewAPIJSONClient(baseURL string, client HTTPClient, opts ...twirp.ClientOpt
 	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
 	serviceURL := sanitizeBaseURL(baseURL)
 	serviceURL += baseServicePath(pathPrefix, "rpc", "API")
-	urls := [5]string{
+	urls := [7]string{
 		serviceURL + "GetActiveSchema",
 		serviceURL + "RunSQLQuery",
 		serviceURL + "GetTrace",
 		serviceURL + "ListTraces",
 		serviceURL + "ListTools",
+		serviceURL + "SetExplainPermissions",
+		serviceURL + "ListPermissionExplanations",
 	}
 
 	return &aPIJSONClient{
@@ -604,6 +706,98 @@ func (c *aPIJSONClient) callListTools(ctx context.Context, in *ListToolsRequest)
 	return out, nil
 }
 
+func (c *aPIJSONClient) SetExplainPermissions(ctx context.Context, in *SetExplainPermissionsRequest) (*SetExplainPermissionsResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "rpc")
+	ctx = ctxsetters.WithServiceName(ctx, "API")
+	ctx = ctxsetters.WithMethodName(ctx, "SetExplainPermissions")
+	caller := c.callSetExplainPermissions
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *SetExplainPermissionsRequest) (*SetExplainPermissionsResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*SetExplainPermissionsRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*SetExplainPermissionsRequest) when calling interceptor")
+					}
+					return c.callSetExplainPermissions(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*SetExplainPermissionsResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*SetExplainPermissionsResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *aPIJSONClient) callSetExplainPermissions(ctx context.Context, in *SetExplainPermissionsRequest) (*SetExplainPermissionsResponse, error) {
+	out := new(SetExplainPermissionsResponse)
+	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[5], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *aPIJSONClient) ListPermissionExplanations(ctx context.Context, in *ListPermissionExplanationsRequest) (*ListPermissionExplanationsResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "rpc")
+	ctx = ctxsetters.WithServiceName(ctx, "API")
+	ctx = ctxsetters.WithMethodName(ctx, "ListPermissionExplanations")
+	caller := c.callListPermissionExplanations
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *ListPermissionExplanationsRequest) (*ListPermissionExplanationsResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ListPermissionExplanationsRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ListPermissionExplanationsRequest) when calling interceptor")
+					}
+					return c.callListPermissionExplanations(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ListPermissionExplanationsResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ListPermissionExplanationsResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *aPIJSONClient) callListPermissionExplanations(ctx context.Context, in *ListPermissionExplanationsRequest) (*ListPermissionExplanationsResponse, error) {
+	out := new(ListPermissionExplanationsResponse)
+	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[6], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
 // ==================
 // API Server Handler
 // ==================
@@ -716,6 +910,12 @@ func (s *aPIServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	case "ListTools":
 		s.serveListTools(ctx, resp, req)
 		return
+	case "SetExplainPermissions":
+		s.serveSetExplainPermissions(ctx, resp, req)
+		return
+	case "ListPermissionExplanations":
+		s.serveListPermissionExplanations(ctx, resp, req)
+		return
 	default:
 		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
 		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
@@ -1623,6 +1823,366 @@ func (s *aPIServer) serveListToolsProtobuf(ctx context.Context, resp http.Respon
 	callResponseSent(ctx, s.hooks)
 }
 
+func (s *aPIServer) serveSetExplainPermissions(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveSetExplainPermissionsJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveSetExplainPermissionsProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *aPIServer) serveSetExplainPermissionsJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "SetExplainPermissions")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	d := json.NewDecoder(req.Body)
+	rawReqBody := json.RawMessage{}
+	if err := d.Decode(&rawReqBody); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+	reqContent := new(SetExplainPermissionsRequest)
+	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
+	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+
+	handler := s.API.SetExplainPermissions
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *SetExplainPermissionsRequest) (*SetExplainPermissionsResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*SetExplainPermissionsRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*SetExplainPermissionsRequest) when calling interceptor")
+					}
+					return s.API.SetExplainPermissions(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*SetExplainPermissionsResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*SetExplainPermissionsResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *SetExplainPermissionsResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *SetExplainPermissionsResponse and nil error while calling SetExplainPermissions. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	marshaler := &protojson.MarshalOptions{UseProtoNames: !s.jsonCamelCase, EmitUnpopulated: !s.jsonSkipDefaults}
+	respBytes, err := marshaler.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal json response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *aPIServer) serveSetExplainPermissionsProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "SetExplainPermissions")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := io.ReadAll(req.Body)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
+		return
+	}
+	reqContent := new(SetExplainPermissionsRequest)
+	if err = proto1.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
+		return
+	}
+
+	handler := s.API.SetExplainPermissions
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *SetExplainPermissionsRequest) (*SetExplainPermissionsResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*SetExplainPermissionsRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*SetExplainPermissionsRequest) when calling interceptor")
+					}
+					return s.API.SetExplainPermissions(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*SetExplainPermissionsResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*SetExplainPermissionsResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *SetExplainPermissionsResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *SetExplainPermissionsResponse and nil error while calling SetExplainPermissions. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto1.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal proto response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *aPIServer) serveListPermissionExplanations(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveListPermissionExplanationsJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveListPermissionExplanationsProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *aPIServer) serveListPermissionExplanationsJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListPermissionExplanations")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	d := json.NewDecoder(req.Body)
+	rawReqBody := json.RawMessage{}
+	if err := d.Decode(&rawReqBody); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+	reqContent := new(ListPermissionExplanationsRequest)
+	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
+	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+
+	handler := s.API.ListPermissionExplanations
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *ListPermissionExplanationsRequest) (*ListPermissionExplanationsResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ListPermissionExplanationsRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ListPermissionExplanationsRequest) when calling interceptor")
+					}
+					return s.API.ListPermissionExplanations(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ListPermissionExplanationsResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ListPermissionExplanationsResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *ListPermissionExplanationsResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListPermissionExplanationsResponse and nil error while calling ListPermissionExplanations. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	marshaler := &protojson.MarshalOptions{UseProtoNames: !s.jsonCamelCase, EmitUnpopulated: !s.jsonSkipDefaults}
+	respBytes, err := marshaler.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal json response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *aPIServer) serveListPermissionExplanationsProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListPermissionExplanations")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := io.ReadAll(req.Body)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
+		return
+	}
+	reqContent := new(ListPermissionExplanationsRequest)
+	if err = proto1.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
+		return
+	}
+
+	handler := s.API.ListPermissionExplanations
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *ListPermissionExplanationsRequest) (*ListPermissionExplanationsResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ListPermissionExplanationsRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ListPermissionExplanationsRequest) when calling interceptor")
+					}
+					return s.API.ListPermissionExplanations(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ListPermissionExplanationsResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ListPermissionExplanationsResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *ListPermissionExplanationsResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListPermissionExplanationsResponse and nil error while calling ListPermissionExplanations. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto1.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal proto response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
 func (s *aPIServer) ServiceDescriptor() ([]byte, int) {
 	return twirpFileDescriptor0, 0
 }
@@ -2204,60 +2764,83 @@ func callClientError(ctx context.Context, h *twirp.ClientHooks, err twirp.Error)
 }
 
 var twirpFileDescriptor0 = []byte{
-	// 875 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xdd, 0x72, 0xdb, 0x44,
-	0x14, 0xae, 0xe2, 0xfa, 0x47, 0xc7, 0x89, 0xe3, 0x6c, 0x93, 0x60, 0x0c, 0x4c, 0x83, 0xa0, 0xe0,
-	0x42, 0x47, 0xa1, 0x66, 0x18, 0x68, 0x87, 0x76, 0x28, 0x78, 0x28, 0x66, 0xda, 0x42, 0xe5, 0x0e,
-	0xb7, 0x1e, 0x45, 0x3a, 0x0a, 0x62, 0x24, 0xad, 0xba, 0xbb, 0x72, 0xc9, 0x1d, 0x97, 0x3c, 0x03,
-	0xef, 0xc1, 0x13, 0xf0, 0x5e, 0x0c, 0xb3, 0x67, 0x57, 0xfe, 0x4b, 0x27, 0xc0, 0x95, 0x7d, 0xbe,
-	0xfd, 0xce, 0xee, 0xf9, 0xf9, 0xf4, 0x81, 0x2b, 0xca, 0xc8, 0x2f, 0x05, 0x57, 0x9c, 0x35, 0x44,
-	0x19, 0x0d, 0x77, 0x65, 0xf4, 0x33, 0xe6, 0xa1, 0x81, 0x86, 0x5d, 0xc5, 0x79, 0x26, 0x6d, 0x30,
-	0xe2, 0x25, 0x16, 0x0a, 0x33, 0xcc, 0x51, 0x89, 0x8b, 0x53, 0x02, 0x4f, 0x95, 0x08, 0x23, 0x3c,
-	0x5d, 0xdc, 0x35, 0x7f, 0x2c, 0xf3, 0xe6, 0x39, 0xe7, 0xe7, 0x19, 0x1a, 0xca, 0x59, 0x95, 0x9c,
-	0xaa, 0x34, 0x47, 0xa9, 0xc2, 0xbc, 0x34, 0x04, 0xef, 0x1e, 0xf4, 0x1f, 0xa3, 0x9a, 0xd1, 0x53,
-	0x01, 0xbe, 0xac, 0x50, 0x2a, 0x76, 0x0b, 0x7a, 0x58, 0x2c, 0x52, 0xc1, 0x8b, 0x1c, 0x0b, 0x35,
-	0x4f, 0xe3, 0x81, 0x73, 0xe2, 0x8c, 0xdc, 0x60, 0x6f, 0x0d, 0x9d, 0xc6, 0xde, 0x7d, 0x38, 0x58,
-	0x4b, 0x95, 0x25, 0x2f, 0x24, 0xb2, 0x5b, 0xd0, 0x32, 0x75, 0x53, 0x4e, 0x77, 0xbc, 0x67, 0xde,
-	0xf1, 0x2d, 0xcd, 0x1e, 0x7a, 0x7f, 0x38, 0xb0, 0x37, 0x7b, 0xfe, 0xe4, 0x79, 0x85, 0xe2, 0x62,
-	0x5a, 0x94, 0x95, 0x62, 0x6f, 0x83, 0x5b, 0x0a, 0xfe, 0x0b, 0x46, 0x6a, 0x3a, 0xb1, 0xef, 0xad,
-	0x00, 0xf6, 0x3e, 0x6c, 0x3c, 0x3e, 0x19, 0xec, 0x5c, 0xae, 0x68, 0xc2, 0x0e, 0xa1, 0xf9, 0x52,
-	0xdf, 0x38, 0x68, 0xd0, 0xa9, 0x09, 0xd8, 0xbb, 0xe0, 0xbe, 0x12, 0xa9, 0xc2, 0xa7, 0x3c, 0xc6,
-	0xc1, 0xf5, 0x13, 0x67, 0xd4, 0xf9, 0xee, 0x5a, 0xb0, 0x82, 0x7e, 0x77, 0x9c, 0xaf, 0x77, 0x01,
-	0xe6, 0x4b, 0xc0, 0xfb, 0xcb, 0x81, 0x7e, 0x5d, 0xdc, 0xb2, 0xb1, 0x8f, 0xa1, 0x25, 0x55, 0xa8,
-	0x2a, 0x49, 0xc5, 0xf5, 0xc6, 0x37, 0x7c, 0xbd, 0xaf, 0x9a, 0x36, 0xa3, 0xa3, 0xc0, 0x52, 0xd8,
-	0x1d, 0x38, 0xc0, 0x5f, 0x31, 0xaa, 0x54, 0xca, 0x8b, 0x49, 0x25, 0x42, 0xfd, 0x4b, 0x25, 0x37,
-	0x83, 0xcb, 0x07, 0xec, 0x04, 0xba, 0x02, 0x65, 0x95, 0x29, 0xf9, 0xfd, 0xec, 0x87, 0x67, 0xb6,
-	0xf8, 0x75, 0x48, 0x0f, 0x47, 0x71, 0x15, 0x66, 0x01, 0x7f, 0x25, 0xa9, 0x85, 0x66, 0xb0, 0x02,
-	0x74, 0xdb, 0x28, 0x04, 0x17, 0x83, 0xa6, 0x69, 0x9b, 0x02, 0xef, 0x0e, 0xec, 0x3f, 0x46, 0xf5,
-	0x42, 0x8b, 0xa1, 0x5e, 0xec, 0x9b, 0xd0, 0x21, 0x71, 0xac, 0x56, 0xda, 0xa6, 0x78, 0x1a, 0x7b,
-	0x01, 0xe9, 0xc0, 0xb2, 0x6d, 0xcb, 0x0f, 0xa1, 0x49, 0xc7, 0x76, 0x95, 0x23, 0x7f, 0x43, 0x76,
-	0x76, 0xb1, 0x46, 0x6d, 0x8b, 0xbb, 0x3e, 0xe5, 0xca, 0x49, 0xa8, 0xc2, 0xc0, 0xa4, 0x79, 0x7f,
-	0x3b, 0x70, 0xf0, 0x24, 0x95, 0xe6, 0x56, 0xf9, 0xff, 0xd4, 0xc5, 0xc6, 0xd0, 0x3a, 0xc3, 0x84,
-	0x0b, 0xa4, 0xb9, 0x75, 0xc7, 0x43, 0xdf, 0x48, 0xd9, 0xaf, 0xa5, 0xec, 0xbf, 0xa8, 0xa5, 0x1c,
-	0x58, 0x26, 0xfb, 0x04, 0x9a, 0x61, 0xa2, 0x50, 0xd0, 0x08, 0xaf, 0x4e, 0x31, 0x44, 0xe6, 0x43,
-	0x3b, 0x49, 0x33, 0x85, 0x42, 0x8f, 0xb5, 0x31, 0xea, 0x8e, 0x0f, 0x69, 0xad, 0xcb, 0xaa, 0xbf,
-	0xa5, 0xc3, 0xa0, 0x26, 0xe9, 0x51, 0x67, 0x69, 0x9e, 0x2a, 0x1a, 0x75, 0x33, 0x30, 0x01, 0x3b,
-	0x86, 0x16, 0x4f, 0x12, 0x89, 0x6a, 0xd0, 0x22, 0xd8, 0x46, 0xde, 0x03, 0xd8, 0xdf, 0xba, 0x49,
-	0x5f, 0x90, 0xa4, 0x98, 0xd5, 0x4d, 0x9b, 0x40, 0xa3, 0x8b, 0x30, 0xab, 0xd0, 0xca, 0xda, 0x04,
-	0xde, 0x97, 0xc0, 0xd6, 0xc7, 0x67, 0xb7, 0xf2, 0x01, 0xb4, 0x68, 0xbc, 0x5a, 0x88, 0xba, 0xe2,
-	0x1e, 0x55, 0x4c, 0xa4, 0xa9, 0xc2, 0x3c, 0xb0, 0xa7, 0xde, 0x6f, 0x0d, 0x70, 0x97, 0xe8, 0x15,
-	0xab, 0x7f, 0xcd, 0x42, 0x76, 0x5e, 0xb7, 0x90, 0x7b, 0x00, 0x52, 0x85, 0x42, 0xcd, 0xb5, 0x85,
-	0xfc, 0x87, 0x09, 0xbb, 0xc4, 0xd6, 0x31, 0xfb, 0x0c, 0x3a, 0x58, 0xc4, 0x26, 0xf1, 0xfa, 0xbf,
-	0x26, 0xb6, 0xb1, 0x88, 0x29, 0x6d, 0x43, 0xd7, 0x1d, 0xab, 0x6b, 0x76, 0x13, 0xba, 0xb1, 0xfd,
-	0x72, 0xe6, 0xb9, 0xa4, 0x89, 0xef, 0x04, 0x50, 0x43, 0x4f, 0x25, 0x7b, 0x0b, 0x5c, 0xc1, 0xb9,
-	0x9a, 0x17, 0x61, 0x8e, 0x83, 0x36, 0xb5, 0xd2, 0xd1, 0xc0, 0xb3, 0x30, 0x47, 0xf6, 0x0e, 0x80,
-	0x75, 0x15, 0xdd, 0x68, 0x67, 0xd3, 0x67, 0x62, 0xf6, 0x1e, 0xec, 0xc5, 0x58, 0x66, 0xfc, 0xa2,
-	0x1e, 0x85, 0x4b, 0x8c, 0xdd, 0x15, 0x38, 0x8d, 0xd9, 0x87, 0xb0, 0x2f, 0xaa, 0x42, 0x77, 0x33,
-	0x5f, 0xa0, 0x90, 0xfa, 0xdb, 0x06, 0xa2, 0xf5, 0x2c, 0xfc, 0x93, 0x41, 0x3d, 0x06, 0x7d, 0x5a,
-	0xa0, 0xb6, 0x6e, 0x2b, 0x7f, 0xef, 0xa1, 0xfd, 0x26, 0x0c, 0x66, 0x77, 0x7a, 0x1b, 0x9a, 0xe4,
-	0xef, 0x76, 0xa5, 0x37, 0x7c, 0xe3, 0xf6, 0x8f, 0x22, 0xdd, 0xd2, 0x37, 0xbc, 0x48, 0xd2, 0xf3,
-	0xc0, 0x30, 0x3e, 0xba, 0x0d, 0xbd, 0x4d, 0xd3, 0x61, 0x5d, 0x68, 0xcb, 0x2a, 0x8a, 0x50, 0xca,
-	0xfe, 0x35, 0x06, 0xd0, 0x4a, 0xc2, 0x34, 0xc3, 0xb8, 0xef, 0x8c, 0xff, 0xdc, 0x81, 0xc6, 0xa3,
-	0x1f, 0xa7, 0xec, 0x2b, 0x72, 0x02, 0x7d, 0xd9, 0x02, 0x8d, 0x0f, 0xb3, 0x23, 0x12, 0xcd, 0xb6,
-	0xf3, 0x0f, 0x8f, 0xb7, 0x61, 0x5b, 0xdf, 0x17, 0xd0, 0x0d, 0xaa, 0xa2, 0x7e, 0x97, 0xb1, 0x0d,
-	0xef, 0x23, 0xff, 0x1e, 0x1e, 0x6d, 0x60, 0xcb, 0xcc, 0xcf, 0xa1, 0x53, 0xfb, 0x0a, 0x3b, 0xac,
-	0x6f, 0x5f, 0x37, 0xa5, 0xe1, 0xd1, 0x16, 0x6a, 0x13, 0x1f, 0x00, 0xac, 0xc4, 0xcf, 0x8e, 0x37,
-	0x3f, 0xcb, 0x7a, 0x9a, 0xc3, 0x37, 0x2e, 0xe1, 0x36, 0xfd, 0x3e, 0xb8, 0xcb, 0x31, 0xdb, 0x6e,
-	0xb7, 0x57, 0x31, 0x3c, 0xde, 0x86, 0x4d, 0xee, 0x59, 0x8b, 0x44, 0xf9, 0xe9, 0x3f, 0x01, 0x00,
-	0x00, 0xff, 0xff, 0x39, 0xb4, 0xfe, 0x52, 0x92, 0x07, 0x00, 0x00,
+	// 1242 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0x4f, 0x73, 0xd3, 0x46,
+	0x14, 0xc7, 0x76, 0xfc, 0xef, 0x39, 0x09, 0xce, 0x42, 0x52, 0xe3, 0x12, 0x08, 0xa2, 0x40, 0x68,
+	0x19, 0xa5, 0xa4, 0xed, 0x14, 0x98, 0xc2, 0x94, 0x36, 0x2d, 0x35, 0x03, 0x14, 0x14, 0xa6, 0xc7,
+	0x7a, 0x14, 0xeb, 0x39, 0x51, 0x47, 0xd2, 0x2a, 0xbb, 0x2b, 0x03, 0xb7, 0x1e, 0x7a, 0xe8, 0xbd,
+	0xb7, 0x7e, 0x80, 0x7e, 0x89, 0x1e, 0xfb, 0xbd, 0x3a, 0x9d, 0x7d, 0xbb, 0x92, 0x65, 0x93, 0x18,
+	0x7a, 0x92, 0xde, 0x6f, 0x7f, 0x6f, 0xdf, 0xdf, 0x7d, 0xbb, 0xd0, 0x16, 0xe9, 0xc8, 0x4d, 0x05,
+	0x57, 0x9c, 0xd5, 0x44, 0x3a, 0xea, 0x2f, 0xcb, 0xd1, 0x11, 0xc6, 0xbe, 0x81, 0xfa, 0x1d, 0xc5,
+	0x79, 0x24, 0xad, 0xb0, 0xcd, 0x53, 0x4c, 0x14, 0x46, 0x18, 0xa3, 0x12, 0x6f, 0x76, 0x08, 0xdc,
+	0x51, 0xc2, 0x1f, 0xe1, 0xce, 0xe4, 0xb6, 0xf9, 0xb1, 0xcc, 0xcb, 0x87, 0x9c, 0x1f, 0x46, 0x68,
+	0x28, 0x07, 0xd9, 0x78, 0x47, 0x85, 0x31, 0x4a, 0xe5, 0xc7, 0xa9, 0x21, 0x38, 0x77, 0xa1, 0xfb,
+	0x08, 0xd5, 0x3e, 0x99, 0xf2, 0xf0, 0x38, 0x43, 0xa9, 0xd8, 0x35, 0x58, 0xc5, 0x64, 0x12, 0x0a,
+	0x9e, 0xc4, 0x98, 0xa8, 0x61, 0x18, 0xf4, 0x2a, 0x5b, 0x95, 0xed, 0xb6, 0xb7, 0x52, 0x42, 0x07,
+	0x81, 0x73, 0x0f, 0xd6, 0x4a, 0xaa, 0x32, 0xe5, 0x89, 0x44, 0x76, 0x0d, 0x1a, 0xc6, 0x6f, 0xd2,
+	0xe9, 0xec, 0xae, 0x18, 0x3b, 0xae, 0xa5, 0xd9, 0x45, 0xe7, 0xcf, 0x0a, 0xac, 0xec, 0xbf, 0x78,
+	0xf2, 0x22, 0x43, 0xf1, 0x66, 0x90, 0xa4, 0x99, 0x62, 0x17, 0xa1, 0x9d, 0x0a, 0xfe, 0x0b, 0x8e,
+	0xd4, 0x60, 0xcf, 0xda, 0x9b, 0x02, 0xec, 0x23, 0x98, 0x31, 0xbe, 0xd7, 0xab, 0xbe, 0xed, 0xd1,
+	0x1e, 0x3b, 0x0f, 0xf5, 0x63, 0xbd, 0x63, 0xaf, 0x46, 0xab, 0x46, 0x60, 0x57, 0xa0, 0xfd, 0x4a,
+	0x84, 0x0a, 0x9f, 0xf2, 0x00, 0x7b, 0x4b, 0x5b, 0x95, 0xed, 0xd6, 0x0f, 0x67, 0xbc, 0x29, 0xf4,
+	0x7b, 0xa5, 0xf2, 0xcd, 0x32, 0xc0, 0xb0, 0x00, 0x9c, 0x7f, 0x2a, 0xd0, 0xcd, 0x9d, 0x2b, 0x02,
+	0xfb, 0x04, 0x1a, 0x52, 0xf9, 0x2a, 0x93, 0xe4, 0xdc, 0xea, 0xee, 0x39, 0x57, 0xd7, 0x2b, 0xa7,
+	0xed, 0xd3, 0x92, 0x67, 0x29, 0xec, 0x16, 0xac, 0xe1, 0x6b, 0x1c, 0x65, 0x2a, 0xe4, 0xc9, 0x5e,
+	0x26, 0x7c, 0xfd, 0x25, 0x97, 0xeb, 0xde, 0xdb, 0x0b, 0x6c, 0x0b, 0x3a, 0x02, 0x65, 0x16, 0x29,
+	0xf9, 0x78, 0xff, 0xc7, 0x67, 0xd6, 0xf9, 0x32, 0xa4, 0x93, 0xa3, 0xb8, 0xf2, 0x23, 0x8f, 0xbf,
+	0x92, 0x14, 0x42, 0xdd, 0x9b, 0x02, 0x3a, 0x6c, 0x14, 0x82, 0x8b, 0x5e, 0xdd, 0x84, 0x4d, 0x82,
+	0x73, 0x0b, 0xce, 0x3e, 0x42, 0xf5, 0x52, 0x37, 0x43, 0x5e, 0xd8, 0x0b, 0xd0, 0xa2, 0xe6, 0x98,
+	0x96, 0xb4, 0x49, 0xf2, 0x20, 0x70, 0x3c, 0xe8, 0x4e, 0xd9, 0x36, 0xe4, 0x07, 0x50, 0xa7, 0x65,
+	0x5b, 0xca, 0x6d, 0x77, 0xa6, 0xed, 0x6c, 0x61, 0x4d, 0xb7, 0x4d, 0x6e, 0xbb, 0xa4, 0x2b, 0xf7,
+	0x7c, 0xe5, 0x7b, 0x46, 0xcd, 0xf9, 0xb7, 0x02, 0x6b, 0x4f, 0x42, 0x69, 0x76, 0x95, 0xff, 0xaf,
+	0xbb, 0xd8, 0x2e, 0x34, 0x0e, 0x70, 0xcc, 0x05, 0x52, 0xde, 0x3a, 0xbb, 0x7d, 0xd7, 0xb4, 0xb2,
+	0x9b, 0xb7, 0xb2, 0xfb, 0x32, 0x6f, 0x65, 0xcf, 0x32, 0xd9, 0xa7, 0x50, 0xf7, 0xc7, 0x0a, 0x45,
+	0xaf, 0xf6, 0x4e, 0x15, 0x43, 0x64, 0x2e, 0x34, 0xc7, 0x61, 0xa4, 0x50, 0xe8, 0xb4, 0xd6, 0xb6,
+	0x3b, 0xbb, 0xe7, 0xa9, 0xac, 0x85, 0xd7, 0xdf, 0xd3, 0xa2, 0x97, 0x93, 0x74, 0xaa, 0xa3, 0x30,
+	0x0e, 0x15, 0xa5, 0xba, 0xee, 0x19, 0x81, 0x6d, 0x40, 0x83, 0x8f, 0xc7, 0x12, 0x55, 0xaf, 0x41,
+	0xb0, 0x95, 0x9c, 0xfb, 0x70, 0x76, 0x6e, 0x27, 0xbd, 0xc1, 0x38, 0xc4, 0x28, 0x0f, 0xda, 0x08,
+	0x1a, 0x9d, 0xf8, 0x51, 0x86, 0xb6, 0xad, 0x8d, 0xe0, 0x7c, 0x05, 0xac, 0x9c, 0x3e, 0x5b, 0x95,
+	0xeb, 0xd0, 0xa0, 0xf4, 0xea, 0x46, 0xd4, 0x1e, 0xaf, 0x92, 0xc7, 0x44, 0x1a, 0x28, 0x8c, 0x3d,
+	0xbb, 0xea, 0xfc, 0x5a, 0x83, 0x76, 0x81, 0x2e, 0x28, 0xfd, 0x09, 0x05, 0xa9, 0x9e, 0x54, 0x90,
+	0xbb, 0x00, 0x52, 0xf9, 0x42, 0x0d, 0xf5, 0x08, 0x79, 0x8f, 0x0c, 0xb7, 0x89, 0xad, 0x65, 0xf6,
+	0x05, 0xb4, 0x30, 0x09, 0x8c, 0xe2, 0xd2, 0x3b, 0x15, 0x9b, 0x98, 0x04, 0xa4, 0x36, 0xd3, 0xd7,
+	0x2d, 0xdb, 0xd7, 0xec, 0x32, 0x74, 0x02, 0x7b, 0x72, 0x86, 0xb1, 0xa4, 0x8c, 0x57, 0x3d, 0xc8,
+	0xa1, 0xa7, 0x92, 0x7d, 0x08, 0x6d, 0xc1, 0xb9, 0x1a, 0x26, 0x7e, 0x8c, 0xbd, 0x26, 0x85, 0xd2,
+	0xd2, 0xc0, 0x33, 0x3f, 0x46, 0xb6, 0x09, 0x60, 0xa7, 0x8a, 0x0e, 0xb4, 0x35, 0x3b, 0x67, 0x02,
+	0x76, 0x15, 0x56, 0x02, 0x4c, 0x23, 0xfe, 0x26, 0x4f, 0x45, 0x9b, 0x18, 0xcb, 0x53, 0x70, 0x10,
+	0xb0, 0x1b, 0x70, 0x56, 0x64, 0x89, 0x8e, 0x66, 0x38, 0x41, 0x21, 0xf5, 0xd9, 0x06, 0xa2, 0xad,
+	0x5a, 0xf8, 0x27, 0x83, 0x3a, 0x0c, 0xba, 0x54, 0x40, 0x3d, 0xba, 0x6d, 0xfb, 0x3b, 0x0f, 0x60,
+	0xad, 0x84, 0xd9, 0x9a, 0xde, 0x84, 0x3a, 0xcd, 0x77, 0x5b, 0xd2, 0x73, 0x2e, 0x49, 0xee, 0xc3,
+	0x91, 0x0e, 0xe9, 0x5b, 0x9e, 0x8c, 0xc3, 0x43, 0xcf, 0x30, 0x9c, 0x3b, 0x70, 0x71, 0x1f, 0xd5,
+	0x77, 0xaf, 0xd3, 0xc8, 0x0f, 0x93, 0xe7, 0x28, 0xe2, 0x50, 0x6a, 0x5b, 0xc5, 0xf1, 0xea, 0x41,
+	0x13, 0x13, 0xff, 0x20, 0x42, 0x53, 0xe7, 0x96, 0x97, 0x8b, 0xce, 0x5d, 0xd8, 0x3c, 0x45, 0xd3,
+	0x7a, 0xb1, 0x48, 0xf5, 0x8a, 0x76, 0x7a, 0xaa, 0x44, 0xbb, 0x24, 0xbe, 0x2a, 0x5b, 0x2e, 0xce,
+	0x46, 0xa5, 0x74, 0x36, 0x9c, 0x00, 0x9c, 0x45, 0xaa, 0xc5, 0xa8, 0x69, 0x09, 0xb3, 0x4d, 0x9e,
+	0x03, 0x87, 0xda, 0xda, 0xee, 0x7d, 0x8a, 0x76, 0xa1, 0xe3, 0xfc, 0x5d, 0x81, 0xcd, 0x85, 0x5c,
+	0x7d, 0x46, 0x63, 0x54, 0x47, 0x3c, 0x6f, 0x7f, 0x2b, 0x31, 0x06, 0x4b, 0xa9, 0xaf, 0x8e, 0x6c,
+	0xcf, 0xd3, 0x3f, 0x73, 0x61, 0xe9, 0x3d, 0x9b, 0x9c, 0x78, 0xec, 0x01, 0x2c, 0x63, 0xc9, 0x96,
+	0x1d, 0x25, 0x7d, 0x8a, 0xe0, 0x44, 0x77, 0xbc, 0x19, 0xbe, 0xf3, 0x47, 0x15, 0xd6, 0x4f, 0xe4,
+	0xe9, 0x9c, 0xc6, 0x3c, 0xc0, 0x28, 0x1f, 0x17, 0x24, 0xe8, 0x58, 0xfc, 0x51, 0x71, 0xa7, 0xb4,
+	0x3d, 0x2b, 0xb1, 0x4b, 0x00, 0x7e, 0xa6, 0x8e, 0xb8, 0x08, 0x25, 0x06, 0xe4, 0x7d, 0xcb, 0x2b,
+	0x21, 0x5a, 0x4f, 0xa0, 0x2f, 0x79, 0x42, 0xa7, 0xb0, 0xed, 0x59, 0x89, 0x7d, 0x0e, 0x75, 0x91,
+	0x45, 0x28, 0x7b, 0x75, 0x72, 0xfc, 0xd2, 0x9c, 0xe3, 0x5e, 0x16, 0x61, 0xd9, 0x79, 0x43, 0x66,
+	0x5d, 0xa8, 0xc9, 0xe3, 0x88, 0x0e, 0x60, 0xdb, 0xd3, 0xbf, 0x7a, 0xc8, 0xc8, 0xe3, 0x68, 0xe8,
+	0x8b, 0x43, 0xd9, 0x6b, 0x6e, 0xd5, 0xf4, 0x90, 0x91, 0xc7, 0xd1, 0x43, 0x71, 0x28, 0xd9, 0x6d,
+	0x58, 0x12, 0xfa, 0xf2, 0x6a, 0x91, 0x85, 0xcd, 0x79, 0x0b, 0xfc, 0x55, 0xd9, 0x00, 0x51, 0x9d,
+	0xbf, 0x2a, 0x70, 0xe1, 0x54, 0x27, 0x74, 0xac, 0xf8, 0x3a, 0x15, 0x48, 0x8b, 0x36, 0x3d, 0x25,
+	0x44, 0x67, 0x4e, 0x70, 0x1d, 0x53, 0x95, 0x1c, 0x31, 0x82, 0x1e, 0x1e, 0x02, 0x25, 0x8f, 0x26,
+	0x18, 0x0c, 0xc3, 0xc4, 0x5e, 0xb5, 0x90, 0x43, 0x83, 0x84, 0x5d, 0x9d, 0x49, 0x61, 0xfe, 0x5a,
+	0x28, 0x61, 0xfa, 0xb9, 0xb0, 0x02, 0x9d, 0xe1, 0x14, 0x71, 0x1e, 0x43, 0xef, 0xb4, 0x50, 0xd8,
+	0x2a, 0x54, 0x8b, 0x89, 0x5b, 0x0d, 0x83, 0xb9, 0x12, 0x55, 0xe7, 0x4b, 0xf4, 0xf1, 0x4d, 0x58,
+	0x9d, 0x7d, 0x53, 0xb0, 0x0e, 0x34, 0x65, 0x36, 0x1a, 0xa1, 0x94, 0xdd, 0x33, 0x0c, 0xa0, 0x31,
+	0xf6, 0xc3, 0x08, 0x83, 0x6e, 0x65, 0xf7, 0xb7, 0x25, 0xa8, 0x3d, 0x7c, 0x3e, 0x60, 0x5f, 0xd3,
+	0x45, 0xaf, 0x67, 0xc5, 0x04, 0xcd, 0x33, 0x8b, 0xad, 0x53, 0x7e, 0xe7, 0x1f, 0x76, 0xfd, 0x8d,
+	0x79, 0xd8, 0x9e, 0xbe, 0x3b, 0xd0, 0xf1, 0xb2, 0x24, 0xb7, 0xcb, 0xd8, 0xcc, 0xd3, 0x86, 0x9e,
+	0x67, 0xfd, 0xf5, 0x19, 0xac, 0xd0, 0xfc, 0x12, 0x5a, 0xf9, 0xb3, 0x81, 0x9d, 0xcf, 0x77, 0x2f,
+	0xbf, 0x39, 0xfa, 0xeb, 0x73, 0xa8, 0x55, 0xbc, 0x0f, 0x30, 0xbd, 0xdb, 0xd8, 0xc6, 0xec, 0xad,
+	0x9b, 0x8f, 0x94, 0xfe, 0x07, 0x6f, 0xe1, 0x56, 0xfd, 0x1e, 0xb4, 0x8b, 0x29, 0x6a, 0xa3, 0x9d,
+	0x9f, 0xb4, 0xfd, 0x8d, 0x79, 0xd8, 0xea, 0xfe, 0x0c, 0xeb, 0x27, 0xce, 0x41, 0x76, 0xc5, 0xc4,
+	0xb8, 0x60, 0xba, 0xf6, 0x9d, 0x45, 0x14, 0xbb, 0x7f, 0x0c, 0xfd, 0xd3, 0x27, 0x1e, 0xbb, 0x5e,
+	0x78, 0xb5, 0x70, 0x9a, 0xf6, 0x6f, 0xbc, 0x93, 0x67, 0xcc, 0x1d, 0x34, 0x68, 0x2c, 0x7d, 0xf6,
+	0xdf, 0x00, 0x6c, 0xce, 0x29, 0xba, 0x40, 0x0c, 0x00, 0x00,
 }
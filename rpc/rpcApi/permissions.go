@@ -0,0 +1,123 @@
+package rpcApi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/teamkeel/keel/rpc/rpc"
+	"github.com/teamkeel/keel/runtime/actions"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxExplainedRequests is the number of most recent explained requests which are kept.
+const maxExplainedRequests = 100
+
+// permissionExplanations holds the permission explanations of the most recent explained requests,
+// so that they can be retrieved over RPC rather than being returned in the API responses.
+var permissionExplanations = &explanationStore{}
+
+type explanationStore struct {
+	mu       sync.Mutex
+	enabled  bool
+	requests []*rpc.RequestPermissionExplanations
+}
+
+// ExplainPermissionsEnabled reports whether permissions should be explained for all requests.
+func ExplainPermissionsEnabled() bool {
+	permissionExplanations.mu.Lock()
+	defer permissionExplanations.mu.Unlock()
+	return permissionExplanations.enabled
+}
+
+// RecordPermissionExplanations keeps the permission explanations of an explained request.
+func RecordPermissionExplanations(method string, path string, explanations []*actions.PermissionExplanation) {
+	request := &rpc.RequestPermissionExplanations{
+		Method:       method,
+		Path:         path,
+		Time:         timestamppb.New(time.Now()),
+		Explanations: make([]*rpc.PermissionExplanation, len(explanations)),
+	}
+
+	for i, explanation := range explanations {
+		request.Explanations[i] = toRpcPermissionExplanation(explanation)
+	}
+
+	permissionExplanations.mu.Lock()
+	defer permissionExplanations.mu.Unlock()
+
+	permissionExplanations.requests = append(permissionExplanations.requests, request)
+	if len(permissionExplanations.requests) > maxExplainedRequests {
+		permissionExplanations.requests = permissionExplanations.requests[len(permissionExplanations.requests)-maxExplainedRequests:]
+	}
+}
+
+func (s *Server) SetExplainPermissions(ctx context.Context, input *rpc.SetExplainPermissionsRequest) (*rpc.SetExplainPermissionsResponse, error) {
+	permissionExplanations.mu.Lock()
+	defer permissionExplanations.mu.Unlock()
+
+	permissionExplanations.enabled = input.Enabled
+	if !input.Enabled {
+		permissionExplanations.requests = nil
+	}
+
+	return &rpc.SetExplainPermissionsResponse{
+		Enabled: permissionExplanations.enabled,
+	}, nil
+}
+
+func (s *Server) ListPermissionExplanations(ctx context.Context, input *rpc.ListPermissionExplanationsRequest) (*rpc.ListPermissionExplanationsResponse, error) {
+	permissionExplanations.mu.Lock()
+	defer permissionExplanations.mu.Unlock()
+
+	requests := permissionExplanations.requests
+	if input.Limit > 0 && int(input.Limit) < len(requests) {
+		requests = requests[len(requests)-int(input.Limit):]
+	}
+
+	// Most recent first
+	list := make([]*rpc.RequestPermissionExplanations, len(requests))
+	for i, request := range requests {
+		list[len(requests)-1-i] = request
+	}
+
+	return &rpc.ListPermissionExplanationsResponse{
+		Requests: list,
+	}, nil
+}
+
+func toRpcPermissionExplanation(explanation *actions.PermissionExplanation) *rpc.PermissionExplanation {
+	e := &rpc.PermissionExplanation{
+		Model:      explanation.Model,
+		Action:     explanation.Action,
+		Authorised: explanation.Authorised,
+		Reason:     explanation.Reason,
+		Sql:        explanation.Sql,
+		SqlArgs:    make([]string, len(explanation.SqlArgs)),
+		Rules:      make([]*rpc.PermissionRuleExplanation, len(explanation.Rules)),
+		Rows:       make([]*rpc.PermissionRowExplanation, len(explanation.Rows)),
+	}
+
+	for i, arg := range explanation.SqlArgs {
+		e.SqlArgs[i] = fmt.Sprintf("%v", arg)
+	}
+
+	for i, rule := range explanation.Rules {
+		e.Rules[i] = &rpc.PermissionRuleExplanation{
+			Expression: rule.Expression,
+			Roles:      rule.Roles,
+			ResolvedIn: rule.ResolvedIn,
+			Authorised: rule.Authorised,
+		}
+	}
+
+	for i, row := range explanation.Rows {
+		e.Rows[i] = &rpc.PermissionRowExplanation{
+			Id:         row.Id,
+			Authorised: row.Authorised,
+		}
+	}
+
+	return e
+}
@@ -8,8 +8,10 @@ import (
 	email "net/mail"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/karlseguin/typed"
+	"github.com/samber/lo"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 
@@ -29,8 +31,13 @@ var (
 	ErrInvalidToken     = common.NewAuthenticationFailedMessageErr("cannot be parsed or verified as a valid JWT")
 	ErrTokenExpired     = common.NewAuthenticationFailedMessageErr("token has expired")
 	ErrIdentityNotFound = common.NewAuthenticationFailedMessageErr("identity not found")
+	ErrClientNotFound   = common.NewAuthenticationFailedMessageErr("client not found")
 )
 
+// ReauthenticationInterval is how often the Authorization header of a long-lived connection,
+// such as a subscription or a stream of changes, is authenticated again.
+var ReauthenticationInterval = time.Minute
+
 func ResetRequestPassword(scope *Scope, input map[string]any) error {
 	var err error
 	typedInput := typed.New(input)
@@ -54,7 +61,12 @@ func ResetRequestPassword(scope *Scope, input map[string]any) error {
 		return nil
 	}
 
-	token, err := oauth.GenerateResetToken(scope.Context, identity[parser.FieldNameId].(string))
+	return sendPasswordResetEmail(scope.Context, identity, redirectUrl)
+}
+
+// sendPasswordResetEmail emails the identity a link to the redirect URL with a password reset token.
+func sendPasswordResetEmail(ctx context.Context, identity auth.Identity, redirectUrl *url.URL) error {
+	token, err := oauth.GenerateResetToken(ctx, identity[parser.FieldNameId].(string))
 	if err != nil {
 		return err
 	}
@@ -63,23 +75,26 @@ func ResetRequestPassword(scope *Scope, input map[string]any) error {
 	q.Add("token", token)
 	redirectUrl.RawQuery = q.Encode()
 
-	client, err := runtimectx.GetMailClient(scope.Context)
+	client, err := runtimectx.GetMailClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = client.Send(scope.Context, &mail.SendEmailRequest{
+	return client.Send(ctx, &mail.SendEmailRequest{
 		To:        identity["email"].(string),
 		From:      "hi@keel.xyz",
 		Subject:   "[Keel] Reset password request",
 		PlainText: fmt.Sprintf("Please follow this link to reset your password: %s", redirectUrl),
 	})
-
-	return err
 }
 
 // Deprecated: we will be deprecating the authenticate action and password flow in favour of the new auth endpoints
 func ResetPassword(scope *Scope, input map[string]any) error {
+	// An impersonator cannot change the identity's password
+	if auth.IsImpersonated(scope.Context) {
+		return common.NewPermissionError()
+	}
+
 	typedInput := typed.New(input)
 
 	token := typedInput.String("token")
@@ -119,28 +134,250 @@ func ResetPassword(scope *Scope, input map[string]any) error {
 	return nil
 }
 
-func HandleAuthorizationHeader(ctx context.Context, schema *proto.Schema, headers http.Header) (auth.Identity, error) {
+// RequestEmailVerification sends a link to the identity's email address which verifies that they own it.
+// Nothing is sent if there is no identity with the email address, or if it has already been verified.
+func RequestEmailVerification(scope *Scope, input map[string]any) error {
+	var err error
+	typedInput := typed.New(input)
+
+	emailString := typedInput.String("email")
+	if _, err = email.ParseAddress(emailString); err != nil {
+		return common.RuntimeError{Code: common.ErrInvalidInput, Message: "invalid email address"}
+	}
+
+	var redirectUrl *url.URL
+	if redirectUrl, err = url.ParseRequestURI(typedInput.String("redirectUrl")); err != nil {
+		return common.RuntimeError{Code: common.ErrInvalidInput, Message: "invalid redirect URL"}
+	}
+
+	var identity auth.Identity
+	identity, err = FindIdentityByEmail(scope.Context, scope.Schema, emailString, oauth.KeelIssuer)
+	if err != nil {
+		return err
+	}
+	if identity == nil {
+		return nil
+	}
+
+	if verified, ok := identity[parser.IdentityFieldNameEmailVerified].(bool); ok && verified {
+		return nil
+	}
+
+	token, err := oauth.GenerateVerifyEmailToken(scope.Context, identity[parser.FieldNameId].(string), emailString)
+	if err != nil {
+		return err
+	}
+
+	q := redirectUrl.Query()
+	q.Add("token", token)
+	redirectUrl.RawQuery = q.Encode()
+
+	client, err := runtimectx.GetMailClient(scope.Context)
+	if err != nil {
+		return err
+	}
+
+	err = client.Send(scope.Context, &mail.SendEmailRequest{
+		To:        emailString,
+		From:      "hi@keel.xyz",
+		Subject:   "[Keel] Verify your email address",
+		PlainText: fmt.Sprintf("Please follow this link to verify your email address: %s", redirectUrl),
+	})
+
+	return err
+}
+
+// VerifyEmail marks the identity's email address as verified using the token sent by RequestEmailVerification.
+func VerifyEmail(scope *Scope, input map[string]any) error {
+	typedInput := typed.New(input)
+
+	token := typedInput.String("token")
+
+	identityId, emailString, err := oauth.ValidateVerifyEmailToken(scope.Context, token)
+	switch {
+	case errors.Is(err, oauth.ErrInvalidToken) || errors.Is(err, oauth.ErrTokenExpired):
+		return common.RuntimeError{Code: common.ErrInvalidInput, Message: err.Error()}
+	case err != nil:
+		return err
+	}
+
+	identityModel := scope.Schema.FindModel(parser.IdentityModelName)
+
+	// The email address must not have changed since the token was issued
+	query := NewQuery(identityModel)
+	err = query.Where(IdField(), Equals, Value(identityId))
+	if err != nil {
+		return err
+	}
+	query.And()
+	err = query.Where(Field(parser.IdentityFieldNameEmail), Equals, Value(emailString))
+	if err != nil {
+		return err
+	}
+
+	query.AddWriteValue(Field(parser.IdentityFieldNameEmailVerified), Value(true))
+
+	affected, err := query.UpdateStatement(scope.Context).Execute(scope.Context)
+	if err != nil {
+		return err
+	}
+	if affected != 1 {
+		return common.RuntimeError{Code: common.ErrInvalidInput, Message: "the email address has changed since the verification was requested"}
+	}
+
+	return nil
+}
+
+// HandleAuthorizationHeader authenticates the bearer token in the Authorization header, if one is provided,
+// and returns a context with the authenticated identity or service client.
+func HandleAuthorizationHeader(ctx context.Context, schema *proto.Schema, headers http.Header) (context.Context, error) {
 	header := headers.Get("Authorization")
 	if header == "" {
-		return nil, nil
+		return ctx, nil
 	}
 
 	headerSplit := strings.Split(header, "Bearer ")
 	if len(headerSplit) != 2 {
-		return nil, common.NewAuthenticationFailedMessageErr("no 'Bearer' prefix in the Authorization header")
+		return ctx, common.NewAuthenticationFailedMessageErr("no 'Bearer' prefix in the Authorization header")
 	}
 
 	token := headerSplit[1]
 
-	if token != "" {
-		identity, err := HandleBearerToken(ctx, schema, token)
+	if token == "" {
+		return ctx, nil
+	}
+
+	if oauth.IsClientAccessToken(token) {
+		client, err := HandleClientBearerToken(ctx, token)
 		if err != nil {
-			return nil, err
+			return ctx, err
 		}
-		return identity, nil
+		return auth.WithClient(ctx, client), nil
 	}
 
-	return nil, nil
+	identity, err := HandleBearerToken(ctx, schema, token)
+	if err != nil {
+		return ctx, err
+	}
+
+	return withAuthenticatedIdentity(ctx, schema, identity, token)
+}
+
+// HandleStreamTicket authenticates the identity with a stream ticket, and returns a context with the authenticated
+// identity along with when the stream must end.
+func HandleStreamTicket(ctx context.Context, schema *proto.Schema, ticket string) (context.Context, time.Time, error) {
+	identityId, expiresAt, err := oauth.ValidateStreamTicket(ctx, ticket)
+	if err != nil {
+		return ctx, time.Time{}, err
+	}
+
+	identity, err := findAuthenticatedIdentity(ctx, schema, identityId)
+	if err != nil {
+		return ctx, time.Time{}, err
+	}
+
+	ctx, err = withAuthenticatedIdentity(ctx, schema, identity, ticket)
+	if err != nil {
+		return ctx, time.Time{}, err
+	}
+
+	return ctx, expiresAt, nil
+}
+
+// withAuthenticatedIdentity returns a context with the identity which was authenticated with the token.
+func withAuthenticatedIdentity(ctx context.Context, schema *proto.Schema, identity auth.Identity, token string) (context.Context, error) {
+	ctx = auth.WithIdentity(ctx, identity)
+
+	// Load the roles assigned at runtime so that role permissions can be resolved without further queries
+	if len(schema.Roles) > 0 {
+		memberships, err := FindRoleMemberships(ctx, identity[parser.FieldNameId].(string))
+		if err != nil {
+			return ctx, err
+		}
+		ctx = auth.WithRoleMemberships(ctx, memberships)
+	}
+
+	// The token has already been verified
+	if oauth.IsMfaAccessToken(token) {
+		ctx = auth.WithMfaAuthenticated(ctx)
+	}
+
+	ctx = auth.WithImpersonator(ctx, oauth.ImpersonatorFromAccessToken(token))
+
+	return ctx, nil
+}
+
+// WatchAuthorizationHeader returns a channel which is closed once the Authorization header can no longer be used to
+// authenticate, either because its access token has expired or because it fails to authenticate again, such as when
+// the identity has been disabled. The header is authenticated again at each ReauthenticationInterval until the context
+// is done. Revoking a session does not invalidate the access tokens already issued for it, so a connection which was
+// authenticated with one of them ends when the access token expires. The channel is never closed if there is no
+// access token in the header.
+func WatchAuthorizationHeader(ctx context.Context, schema *proto.Schema, headers http.Header) <-chan struct{} {
+	token := strings.TrimPrefix(headers.Get("Authorization"), "Bearer ")
+	if token == "" {
+		return make(chan struct{})
+	}
+
+	expiresAt, _ := oauth.ExpiryFromAccessToken(token)
+
+	return watchAuthentication(ctx, expiresAt, func() error {
+		_, err := HandleAuthorizationHeader(ctx, schema, headers)
+		return err
+	})
+}
+
+// WatchStreamTicket returns a channel which is closed at the time the stream opened with a stream ticket must end,
+// or once the identity in the context can no longer be authenticated, such as when it has been disabled. As a stream
+// ticket expires as soon as the stream is open, the identity itself is authenticated again at each ReauthenticationInterval.
+func WatchStreamTicket(ctx context.Context, schema *proto.Schema, expiresAt time.Time) <-chan struct{} {
+	identity, err := auth.GetIdentity(ctx)
+	if err != nil {
+		ended := make(chan struct{})
+		close(ended)
+		return ended
+	}
+
+	return watchAuthentication(ctx, expiresAt, func() error {
+		_, err := findAuthenticatedIdentity(ctx, schema, identity[parser.FieldNameId].(string))
+		return err
+	})
+}
+
+// watchAuthentication returns a channel which is closed at expiresAt, unless it is the zero time, or once authenticate
+// returns an error when it is called at each ReauthenticationInterval, until the context is done.
+func watchAuthentication(ctx context.Context, expiresAt time.Time, authenticate func() error) <-chan struct{} {
+	ended := make(chan struct{})
+
+	go func() {
+		var expired <-chan time.Time
+		if !expiresAt.IsZero() {
+			timer := time.NewTimer(time.Until(expiresAt))
+			defer timer.Stop()
+			expired = timer.C
+		}
+
+		ticker := time.NewTicker(ReauthenticationInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-expired:
+				close(ended)
+				return
+			case <-ticker.C:
+				err := authenticate()
+				if err != nil && ctx.Err() == nil {
+					close(ended)
+					return
+				}
+			}
+		}
+	}()
+
+	return ended
 }
 
 func HandleBearerToken(ctx context.Context, schema *proto.Schema, token string) (auth.Identity, error) {
@@ -153,7 +390,19 @@ func HandleBearerToken(ctx context.Context, schema *proto.Schema, token string)
 		return nil, err
 	}
 
-	identity, err := FindIdentityById(ctx, schema, subject)
+	identity, err := findAuthenticatedIdentity(ctx, schema, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("identity.id", identity[parser.FieldNameId].(string)))
+
+	return identity, nil
+}
+
+// findAuthenticatedIdentity finds the identity which has been authenticated, provided it still exists and is not disabled.
+func findAuthenticatedIdentity(ctx context.Context, schema *proto.Schema, identityId string) (auth.Identity, error) {
+	identity, err := FindIdentityById(ctx, schema, identityId)
 	if err != nil {
 		return nil, err
 	}
@@ -162,7 +411,44 @@ func HandleBearerToken(ctx context.Context, schema *proto.Schema, token string)
 		return nil, ErrIdentityNotFound
 	}
 
-	span.SetAttributes(attribute.String("identity.id", identity[parser.FieldNameId].(string)))
+	disabled, err := oauth.IsIdentityDisabled(ctx, identityId)
+	if err != nil {
+		return nil, err
+	}
+
+	if disabled {
+		return nil, oauth.ErrIdentityDisabled
+	}
 
 	return identity, nil
 }
+
+// HandleClientBearerToken validates an access token issued to a service client using the client credentials grant.
+func HandleClientBearerToken(ctx context.Context, token string) (*auth.Client, error) {
+	ctx, span := tracer.Start(ctx, "Client Authorization")
+	defer span.End()
+
+	clientId, scope, err := oauth.ValidateClientAccessToken(ctx, token)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	serviceClient, err := oauth.FindServiceClient(ctx, clientId)
+	if err != nil {
+		return nil, err
+	}
+
+	if serviceClient == nil {
+		return nil, ErrClientNotFound
+	}
+
+	span.SetAttributes(attribute.String("client.id", serviceClient.ClientId))
+
+	// The client may have been granted a subset of its roles when the token was issued
+	return &auth.Client{
+		Id:    serviceClient.ClientId,
+		Name:  serviceClient.Name,
+		Roles: lo.Intersect(serviceClient.Roles, scope),
+	}, nil
+}
@@ -53,6 +53,7 @@ func authorise(scope *Scope, permissions []*proto.PermissionRule, input map[stri
 	if len(permissions) == 0 {
 		span.SetAttributes(attribute.Bool("result", false))
 		span.SetAttributes(attribute.String("reason", "no permission rules"))
+		explainPermissions(scope, permissions, input, rowsToAuthorise, false)
 		return false, nil
 	}
 
@@ -63,9 +64,10 @@ func authorise(scope *Scope, permissions []*proto.PermissionRule, input map[stri
 
 	span.SetAttributes(attribute.String("reason", "permission rules"))
 
-	// If there are no expression permissions to satisfy, then access cannot be granted.
-	if len(proto.PermissionsWithExpression(permissions)) == 0 {
+	// If there are no expression permissions or record-scoped roles to satisfy, then access cannot be granted.
+	if len(proto.PermissionsWithExpression(permissions)) == 0 && len(roleScopedRecordIds(scope.Context, scope.Model, permissions)) == 0 {
 		span.SetAttributes(attribute.Bool("result", false))
+		explainPermissions(scope, permissions, input, rowsToAuthorise, false)
 		return false, nil
 	}
 
@@ -99,40 +101,29 @@ func authorise(scope *Scope, permissions []*proto.PermissionRule, input map[stri
 	}
 
 	span.SetAttributes(attribute.Bool("result", authorised))
+	explainPermissions(scope, permissions, input, rowsToAuthorise, authorised)
 	return authorised, nil
 }
 
 // TryResolveAuthorisationEarly will attempt to check authorisation early without row-based querying.
 // This will take into account logical conditions and multiple expression and role permission attributes.
 func TryResolveAuthorisationEarly(scope *Scope, permissions []*proto.PermissionRule) (canResolveAll bool, authorised bool, err error) {
+	defer func() {
+		if err == nil && canResolveAll {
+			explainPermissions(scope, permissions, map[string]any{}, nil, authorised)
+		}
+	}()
+
 	hasDatabaseCheck := false
 	canResolveAll = false
 	for _, permission := range permissions {
-		canResolve := false
-		authorised := false
-		switch {
-		case permission.Expression != nil:
-			expression, err := parser.ParseExpression(permission.Expression.Source)
-			if err != nil {
-				return false, false, err
-			}
-
-			// Try resolve the permission early.
-			canResolve, authorised = expressions.TryResolveExpressionEarly(scope.Context, scope.Schema, scope.Model, scope.Action, expression, map[string]any{})
-
-			if !canResolve {
-				hasDatabaseCheck = true
-			}
-
-		case permission.RoleNames != nil:
-			// Roles can always be resolved early.
-			canResolve = true
+		canResolve, authorised, err := tryResolvePermissionRuleEarly(scope, permission)
+		if err != nil {
+			return false, false, err
+		}
 
-			// Check if this role permission is satisfied.
-			authorised, err = resolveRolePermissionRule(scope.Context, scope.Schema, permission)
-			if err != nil {
-				return false, false, err
-			}
+		if !canResolve {
+			hasDatabaseCheck = true
 		}
 
 		// If this permission can be resolved now and is satisfied,
@@ -151,14 +142,59 @@ func TryResolveAuthorisationEarly(scope *Scope, permissions []*proto.PermissionR
 	return canResolveAll, false, nil
 }
 
+// tryResolvePermissionRuleEarly will attempt to resolve a single permission rule without row-based querying.
+func tryResolvePermissionRuleEarly(scope *Scope, permission *proto.PermissionRule) (canResolve bool, authorised bool, err error) {
+	switch {
+	case permission.Expression != nil:
+		expression, err := parser.ParseExpression(permission.Expression.Source)
+		if err != nil {
+			return false, false, err
+		}
+
+		// Try resolve the permission early.
+		canResolve, authorised = expressions.TryResolveExpressionEarly(scope.Context, scope.Schema, scope.Model, scope.Action, expression, map[string]any{})
+		return canResolve, authorised, nil
+
+	case permission.RoleNames != nil:
+		// Check if this role permission is satisfied.
+		authorised, err = resolveRolePermissionRule(scope.Context, scope.Schema, permission)
+		if err != nil {
+			return false, false, err
+		}
+
+		// Roles can be resolved early, unless the identity has any of the roles for specific records of this model.
+		canResolve = authorised || len(roleScopedRecordIds(scope.Context, scope.Model, []*proto.PermissionRule{permission})) == 0
+		return canResolve, authorised, nil
+	}
+
+	return false, false, nil
+}
+
 // resolveRolePermissionRule returns true if there is a role-based permission among the
 // given list of permissions that passes.
 func resolveRolePermissionRule(ctx context.Context, schema *proto.Schema, permission *proto.PermissionRule) (bool, error) {
+	// A service client satisfies role permissions with the roles its access token is scoped to.
+	if auth.IsClient(ctx) {
+		client, err := auth.GetClient(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		return len(lo.Intersect(client.Roles, permission.RoleNames)) > 0, nil
+	}
+
 	// If there is no authenticated user, then no role permissions can be satisfied.
 	if !auth.IsAuthenticated(ctx) {
 		return false, nil
 	}
 
+	// Roles assigned to the identity at runtime, other than those for specific records
+	for _, membership := range auth.GetRoleMemberships(ctx) {
+		if !membership.IsRecordScoped() && lo.Contains(permission.RoleNames, membership.Role) {
+			return true, nil
+		}
+	}
+
 	identityEmail, identityDomain, verified, err := getEmailAndDomain(ctx)
 	if err != nil {
 		return false, err
@@ -172,6 +208,10 @@ func resolveRolePermissionRule(ctx context.Context, schema *proto.Schema, permis
 	authorised := false
 	for _, roleName := range permission.RoleNames {
 		role := proto.FindRole(roleName, schema)
+		if role == nil {
+			continue
+		}
+
 		for _, email := range role.Emails {
 			if email == identityEmail {
 				authorised = true
@@ -189,17 +229,41 @@ func resolveRolePermissionRule(ctx context.Context, schema *proto.Schema, permis
 }
 
 func GeneratePermissionStatement(scope *Scope, permissions []*proto.PermissionRule, input map[string]any, idsToAuthorise []string) (*Statement, error) {
+	query, err := permissionQuery(scope, permissions, input, idsToAuthorise)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that the number of authorised rows matches
+	query.SelectClause(fmt.Sprintf("COUNT(DISTINCT %s) = %v AS authorised", IdField().toSqlOperandString(query), len(idsToAuthorise)))
+
+	return query.SelectStatement(), nil
+}
+
+// permissionQuery builds a query for the rows, of those with the ids to authorise, which satisfy any of the permission rules.
+func permissionQuery(scope *Scope, permissions []*proto.PermissionRule, input map[string]any, idsToAuthorise []string) (*QueryBuilder, error) {
+	roleRecordIds := roleScopedRecordIds(scope.Context, scope.Model, permissions)
 	permissions = proto.PermissionsWithExpression(permissions)
 	query := NewQuery(scope.Model, WithJoinType(JoinTypeLeft))
 
 	// We should never have an empty list of permissions as this is checked
 	// higher up in the code path, but just to be safe
-	if len(permissions) == 0 {
+	if len(permissions) == 0 && len(roleRecordIds) == 0 {
 		return nil, errors.New("no permission rules provided")
 	}
 
 	// Append SQL where conditions for each permission attribute.
 	query.OpenParenthesis()
+
+	// The records for which the identity has been assigned one of the roles
+	if len(roleRecordIds) > 0 {
+		err := query.Where(IdField(), OneOf, Value(roleRecordIds))
+		if err != nil {
+			return nil, err
+		}
+		query.Or()
+	}
+
 	for _, permission := range permissions {
 		expression, err := parser.ParseExpression(permission.Expression.Source)
 		if err != nil {
@@ -223,10 +287,7 @@ func GeneratePermissionStatement(scope *Scope, permissions []*proto.PermissionRu
 		return nil, err
 	}
 
-	// Check that the number of authorised rows matches
-	query.SelectClause(fmt.Sprintf("COUNT(DISTINCT %s) = %v AS authorised", IdField().toSqlOperandString(query), len(idsToAuthorise)))
-
-	return query.SelectStatement(), nil
+	return query, nil
 }
 
 // getEmailAndDomain requires that the the given scope's context
@@ -11,6 +11,7 @@ import (
 	"github.com/teamkeel/keel/runtime/auth"
 	"github.com/teamkeel/keel/runtime/runtimectx"
 	"github.com/teamkeel/keel/schema/parser"
+	keeltesting "github.com/teamkeel/keel/testing"
 )
 
 type authorisationTestCase struct {
@@ -1483,3 +1484,112 @@ func TestPermissionQueryBuilder(t *testing.T) {
 		})
 	}
 }
+
+func TestPermissionExplainer(t *testing.T) {
+	t.Parallel()
+
+	schema := `
+		model Thing {
+			actions {
+				get getThing(id)
+			}
+			@permission(expression: ctx.isAuthenticated, actions: [get])
+			@permission(roles: [Admin], actions: [get])
+		}
+		role Admin {
+			emails {
+				"admin@keel.xyz"
+			}
+		}`
+
+	explainer := actions.NewPermissionExplainer()
+	ctx := actions.WithPermissionExplainer(context.Background(), explainer)
+
+	scope, _, _, err := generateQueryScope(ctx, schema, "getThing")
+	require.NoError(t, err)
+
+	permissions := proto.PermissionsForAction(scope.Schema, scope.Action)
+	canResolveEarly, authorised, err := actions.TryResolveAuthorisationEarly(scope, permissions)
+	require.NoError(t, err)
+	require.True(t, canResolveEarly)
+	require.False(t, authorised)
+
+	explanations := explainer.Explanations()
+	require.Len(t, explanations, 1)
+
+	explanation := explanations[0]
+	require.Equal(t, "Thing", explanation.Model)
+	require.Equal(t, "getThing", explanation.Action)
+	require.False(t, explanation.Authorised)
+	require.Empty(t, explanation.Sql)
+	require.Empty(t, explanation.Rows)
+	require.Len(t, explanation.Rules, 2)
+
+	require.Equal(t, "ctx.isAuthenticated", explanation.Rules[0].Expression)
+	require.Equal(t, actions.PermissionResolvedEarly, explanation.Rules[0].ResolvedIn)
+	require.False(t, *explanation.Rules[0].Authorised)
+
+	require.Equal(t, []string{"Admin"}, explanation.Rules[1].Roles)
+	require.Equal(t, actions.PermissionResolvedEarly, explanation.Rules[1].ResolvedIn)
+	require.False(t, *explanation.Rules[1].Authorised)
+}
+
+func TestPermissionExplainer_RowsResolvedInDatabase(t *testing.T) {
+	schema := `
+		model Thing {
+			fields {
+				owner Identity
+			}
+			actions {
+				list listThings()
+			}
+			@permission(expression: thing.owner == ctx.identity, actions: [list])
+		}`
+
+	ctx, database, s := keeltesting.MakeContext(t, context.Background(), schema, true)
+	defer database.Close()
+
+	owner, err := actions.CreateIdentity(ctx, s, "owner@keel.xyz", "1234", "https://keel.so")
+	require.NoError(t, err)
+	other, err := actions.CreateIdentity(ctx, s, "other@keel.xyz", "1234", "https://keel.so")
+	require.NoError(t, err)
+
+	ownerId := owner[parser.FieldNameId].(string)
+	otherId := other[parser.FieldNameId].(string)
+
+	err = database.GetDB().Exec(`INSERT INTO thing (id, owner_id) VALUES ('owned', ?), ('not_owned', ?)`, ownerId, otherId).Error
+	require.NoError(t, err)
+
+	explainer := actions.NewPermissionExplainer()
+	ctx = actions.WithPermissionExplainer(ctx, explainer)
+	ctx = auth.WithIdentity(ctx, owner)
+
+	scope := actions.NewScope(ctx, s.FindAction("listThings"), s)
+	rows := []map[string]any{{"id": "owned"}, {"id": "not_owned"}}
+
+	authorised, err := actions.AuthoriseAction(scope, map[string]any{}, rows)
+	require.NoError(t, err)
+	require.False(t, authorised)
+
+	explanations := explainer.Explanations()
+	require.Len(t, explanations, 1)
+
+	explanation := explanations[0]
+	require.Equal(t, "Thing", explanation.Model)
+	require.Equal(t, "listThings", explanation.Action)
+	require.False(t, explanation.Authorised)
+
+	require.Len(t, explanation.Rules, 1)
+	require.Equal(t, "thing.owner == ctx.identity", explanation.Rules[0].Expression)
+	require.Equal(t, actions.PermissionResolvedDatabase, explanation.Rules[0].ResolvedIn)
+	require.Nil(t, explanation.Rules[0].Authorised)
+
+	require.Contains(t, explanation.Sql, `"thing"."owner_id"`)
+	require.Contains(t, explanation.SqlArgs, ownerId)
+
+	require.Len(t, explanation.Rows, 2)
+	require.Equal(t, "owned", explanation.Rows[0].Id)
+	require.True(t, explanation.Rows[0].Authorised)
+	require.Equal(t, "not_owned", explanation.Rows[1].Id)
+	require.False(t, explanation.Rows[1].Authorised)
+}
@@ -0,0 +1,117 @@
+package actions
+
+import (
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/teamkeel/keel/events"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/schema/parser"
+)
+
+// ChangeAuthoriser determines which records of a model's change events can be delivered to a client
+// streaming the changes, according to the permission rules of the model's get action. As a deleted record can no
+// longer be checked against its permission rules, records of deleted events are only delivered if the rules can be
+// resolved without the record, or if the record has already been delivered to the client. The delivered records are
+// only known for the lifetime of the authoriser, so a client which resumes a stream on a new connection does not receive
+// the deleted events of records which were only delivered on an earlier connection, and should refetch the records it holds.
+type ChangeAuthoriser struct {
+	scope     *Scope
+	delivered map[string]bool
+}
+
+func NewChangeAuthoriser(scope *Scope) *ChangeAuthoriser {
+	return &ChangeAuthoriser{
+		scope:     scope,
+		delivered: map[string]bool{},
+	}
+}
+
+// Authorise determines if the record of a created, updated or deleted event can be delivered, and if so
+// applies any field-level read permissions to the record.
+func (a *ChangeAuthoriser) Authorise(event string, record map[string]any) (bool, error) {
+	authorised, err := a.authorise(event, record)
+	if err != nil || !authorised {
+		return false, err
+	}
+
+	err = ApplyFieldReadPermissions(a.scope, []map[string]any{record})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (a *ChangeAuthoriser) authorise(event string, record map[string]any) (bool, error) {
+	id, _ := record[parser.FieldNameId].(string)
+
+	if event == events.Deleted {
+		if a.delivered[id] {
+			delete(a.delivered, id)
+			return true, nil
+		}
+
+		permissions := proto.PermissionsForActionType(a.scope.Schema, a.scope.Model.Name, proto.ActionType_ACTION_TYPE_GET)
+		if len(permissions) == 0 {
+			return false, nil
+		}
+
+		canResolve, authorised, err := TryResolveAuthorisationEarly(a.scope, permissions)
+		if err != nil {
+			return false, err
+		}
+
+		return canResolve && authorised, nil
+	}
+
+	authorised, err := AuthoriseForActionType(a.scope, proto.ActionType_ACTION_TYPE_GET, []map[string]any{record})
+	if err != nil {
+		return false, err
+	}
+
+	if authorised {
+		a.delivered[id] = true
+	}
+
+	return authorised, nil
+}
+
+// RecordFromEventData converts the model data of an event, which is a JSON snapshot of the row, into a record with
+// the same types as a record read from the database.
+func RecordFromEventData(model *proto.Model, data map[string]any) map[string]any {
+	record := map[string]any{}
+	for k, v := range data {
+		record[k] = v
+	}
+
+	for _, field := range model.Fields {
+		var layout string
+		switch field.Type.Type {
+		case proto.Type_TYPE_DATE:
+			layout = "2006-01-02"
+		case proto.Type_TYPE_DATETIME, proto.Type_TYPE_TIMESTAMP:
+			layout = time.RFC3339Nano
+		default:
+			continue
+		}
+
+		switch v := record[field.Name].(type) {
+		case string:
+			if t, err := time.Parse(layout, v); err == nil {
+				record[field.Name] = t
+			}
+		case []any:
+			record[field.Name] = lo.Map(v, func(item any, _ int) any {
+				if s, ok := item.(string); ok {
+					if t, err := time.Parse(layout, s); err == nil {
+						return t
+					}
+				}
+				return item
+			})
+		}
+	}
+
+	return record
+}
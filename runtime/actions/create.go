@@ -38,13 +38,16 @@ func Create(scope *Scope, input map[string]any) (res map[string]any, err error)
 		return nil, err
 	}
 
+	// Fields with write permissions which are being written to by the inputs
+	writeFields := writeRestrictedFields(scope, input)
+
 	switch {
 	case canResolveEarly && !authorised:
 		err = common.NewPermissionError()
-	case canResolveEarly && authorised:
+	case canResolveEarly && authorised && len(writeFields) == 0:
 		// Execute database request without starting a transaction or performing any row-based authorization
 		res, err = statement.ExecuteToSingle(scope.Context)
-	case !canResolveEarly:
+	default:
 		err = database.Transaction(scope.Context, func(ctx context.Context) error {
 			scope := scope.WithContext(ctx)
 
@@ -54,16 +57,18 @@ func Create(scope *Scope, input map[string]any) (res map[string]any, err error)
 				return err
 			}
 
-			isAuthorised, err := AuthoriseAction(scope, input, []map[string]any{res})
-			if err != nil {
-				return err
-			}
+			if !canResolveEarly {
+				isAuthorised, err := AuthoriseAction(scope, input, []map[string]any{res})
+				if err != nil {
+					return err
+				}
 
-			if !isAuthorised {
-				return common.NewPermissionError()
+				if !isAuthorised {
+					return common.NewPermissionError()
+				}
 			}
 
-			return nil
+			return authoriseFieldWrites(scope, writeFields, []map[string]any{res})
 		})
 	}
 	if err != nil {
@@ -73,9 +78,17 @@ func Create(scope *Scope, input map[string]any) (res map[string]any, err error)
 	// if we have any files in our results we need to transform them to the object structure required
 	if scope.Model.HasFiles() {
 		res, err = transformModelFileResponses(scope.Context, scope.Model, res)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = ApplyFieldReadPermissions(scope, []map[string]any{res})
+	if err != nil {
+		return nil, err
 	}
 
-	return res, err
+	return res, nil
 }
 
 func GenerateCreateStatement(query *QueryBuilder, scope *Scope, input map[string]any) (*Statement, error) {
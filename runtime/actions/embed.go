@@ -5,12 +5,33 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/samber/lo"
 	"github.com/teamkeel/keel/casing"
 	"github.com/teamkeel/keel/proto"
 	"github.com/teamkeel/keel/schema/parser"
 )
 
+const (
+	// the alias of the source model's table which is joined when resolving an embed
+	embedSourceTableAlias = "_source"
+	// the column alias of the source model's id which we select in order to stitch results back to their source records
+	embedSourceIdAlias = "_embed_source_id"
+)
+
+// resolveEmbeddedData resolves the embedded data for a single source record.
 func resolveEmbeddedData(ctx context.Context, schema *proto.Schema, sourceModel *proto.Model, sourceID string, fragments []string) (any, error) {
+	embeds, err := resolveEmbeddedDataBatch(ctx, schema, sourceModel, []string{sourceID}, fragments)
+	if err != nil {
+		return nil, err
+	}
+
+	return embeds[sourceID], nil
+}
+
+// resolveEmbeddedDataBatch resolves the embedded data for many source records at once, returning the embedded
+// data keyed by source record id. A single query is executed for each relationship in the embed path, regardless
+// of the number of source records.
+func resolveEmbeddedDataBatch(ctx context.Context, schema *proto.Schema, sourceModel *proto.Model, sourceIDs []string, fragments []string) (map[string]any, error) {
 	if len(fragments) == 0 {
 		return nil, errors.New("invalid embed resolver")
 	}
@@ -25,18 +46,113 @@ func resolveEmbeddedData(ctx context.Context, schema *proto.Schema, sourceModel
 		return nil, fmt.Errorf("field (%s) is not a embeddable model field", embedTargetField)
 	}
 
-	// we will select from the relatedModel's table, joining the source model with an alias ("source"), where source.id = sourceModel.id
+	relatedModel := schema.FindModel(field.Type.ModelName.Value)
+
+	embeds := map[string]any{}
+	for _, id := range sourceIDs {
+		if field.IsHasMany() {
+			embeds[id] = Rows{}
+		} else {
+			embeds[id] = map[string]any(nil)
+		}
+	}
+
+	if len(sourceIDs) == 0 {
+		return embeds, nil
+	}
+
+	stmt, err := GenerateEmbedStatement(schema, sourceModel, field, lo.Uniq(sourceIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	results, _, err := stmt.ExecuteToMany(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("executing embed query: %w", err)
+	}
+
+	// if we have any files in our results we need to go through each result and transform them to the object structure required
+	if relatedModel.HasFiles() {
+		for i := range results {
+			results[i], err = transformModelFileResponses(ctx, relatedModel, results[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err = ApplyFieldReadPermissions(NewModelScope(ctx, relatedModel, schema), results)
+	if err != nil {
+		return nil, err
+	}
+
+	// recurse and resolve child embeds for all of our results at once
+	if len(fragments) > 1 {
+		childIDs := []string{}
+		for _, result := range results {
+			if childID, ok := result[parser.FieldNameId].(string); ok {
+				childIDs = append(childIDs, childID)
+			}
+		}
+
+		childEmbeds, err := resolveEmbeddedDataBatch(ctx, schema, relatedModel, childIDs, fragments[1:])
+		if err != nil {
+			return nil, fmt.Errorf("retrieving child embed: %w", err)
+		}
+
+		for _, result := range results {
+			childID, ok := result[parser.FieldNameId].(string)
+			if !ok {
+				// we skip if we don't have a child embed id
+				continue
+			}
+			result[fragments[1]] = childEmbeds[childID]
+
+			// we now need to remove the foreign key field from the result (e.g. if we're embedding author, we want to remove authorId)
+			delete(result, fragments[1]+"Id")
+		}
+	}
+
+	// stitch the results back to their source records
+	sourceIdKey := casing.ToLowerCamel(embedSourceIdAlias)
+	for _, result := range results {
+		sourceID, ok := result[sourceIdKey].(string)
+		if !ok {
+			return nil, errors.New("embed result is missing its source identifier")
+		}
+		delete(result, sourceIdKey)
+
+		switch {
+		case field.IsHasMany():
+			embeds[sourceID] = append(embeds[sourceID].(Rows), result)
+		default:
+			if embeds[sourceID].(map[string]any) != nil {
+				return nil, fmt.Errorf("more than one %s returned for embed which expects 0 or 1 result", relatedModel.GetName())
+			}
+			embeds[sourceID] = result
+		}
+	}
+
+	return embeds, nil
+}
+
+// GenerateEmbedStatement generates the statement which selects the related model records for an embedded field
+// across all the given source records. Each row includes the id of the source record it relates to.
+func GenerateEmbedStatement(schema *proto.Schema, sourceModel *proto.Model, field *proto.Field, sourceIDs []string) (*Statement, error) {
+	// we will select from the relatedModel's table, joining the source model with an alias ("_source")
 	relatedModelName := field.Type.ModelName.Value
 	relatedModel := schema.FindModel(relatedModelName)
 	foreignKeyField := proto.GetForeignKeyFieldName(schema.Models, field)
-	sourceTableAlias := "_source"
 
 	dbQuery := NewQuery(relatedModel)
+	dbQuery.Select(AllFields())
+	dbQuery.SelectClause(fmt.Sprintf("%s AS %s", sqlQuote(embedSourceTableAlias, casing.ToSnake(parser.FieldNameId)), sqlQuote(embedSourceIdAlias)))
+
 	// we apply the where clause which will filter based on the joins set up depending on the relationship type
 	err := dbQuery.Where(&QueryOperand{
-		table:  sourceTableAlias,
+		table:  embedSourceTableAlias,
 		column: casing.ToSnake(parser.FieldNameId),
-	}, Equals, Value(sourceID))
+	}, OneOf, Value(sourceIDs))
 	if err != nil {
 		return nil, fmt.Errorf("applying sql where: %w", err)
 	}
@@ -46,132 +162,27 @@ func resolveEmbeddedData(ctx context.Context, schema *proto.Schema, sourceModel
 		dbQuery.Join(
 			sourceModel.Name,
 			&QueryOperand{
-				table:  sourceTableAlias,
+				table:  embedSourceTableAlias,
 				column: casing.ToSnake(foreignKeyField),
 			},
 			&QueryOperand{
 				table:  casing.ToSnake(relatedModelName),
 				column: casing.ToSnake(parser.FieldNameId),
 			})
-
-		stmt := dbQuery.SelectStatement()
-		result, err := stmt.ExecuteToSingle(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("executing query to single: %w", err)
-		}
-
-		// recurse and resolve child embeds
-		if len(fragments) > 1 {
-			if childId, ok := result[parser.FieldNameId].(string); ok {
-				childEmbed, err := resolveEmbeddedData(ctx, schema, relatedModel, childId, fragments[1:])
-				if err != nil {
-					return nil, fmt.Errorf("retrieving child embed: %w", err)
-				}
-				result[fragments[1]] = childEmbed
-
-				// we now need to remove the foreign key field from the result (e.g. if we're embedding author, we want to remove authorId)
-				delete(result, fragments[1]+"Id")
-			}
-		}
-
-		// if we have any files in our results we need to transform them to the object structure required
-		if relatedModel.HasFiles() {
-			result, err = transformModelFileResponses(ctx, relatedModel, result)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		return result, nil
-	case field.IsHasMany():
-		dbQuery.Join(
-			sourceModel.Name,
-			&QueryOperand{
-				table:  sourceTableAlias,
-				column: casing.ToSnake(parser.FieldNameId),
-			},
-			&QueryOperand{
-				table:  casing.ToSnake(relatedModelName),
-				column: casing.ToSnake(foreignKeyField),
-			})
-		stmt := dbQuery.SelectStatement()
-		result, _, err := stmt.ExecuteToMany(ctx, nil)
-		if err != nil {
-			return nil, fmt.Errorf("executing query to many: %w", err)
-		}
-
-		// if we have any files in our results we need to go through each result and transform them to the object structure required
-		if relatedModel.HasFiles() {
-			for i := range result {
-				result[i], err = transformModelFileResponses(ctx, relatedModel, result[i])
-				if err != nil {
-					return nil, err
-				}
-			}
-		}
-
-		// recurse and resolve child embeds for each of our results
-		if len(fragments) > 1 {
-			for i := range result {
-				childId, ok := result[i][parser.FieldNameId].(string)
-				if !ok {
-					// we skip if we don't have a child embed id
-					continue
-				}
-				childEmbed, err := resolveEmbeddedData(ctx, schema, relatedModel, childId, fragments[1:])
-				if err != nil {
-					return nil, fmt.Errorf("retrieving child embed: %w", err)
-				}
-				result[i][fragments[1]] = childEmbed
-
-				// we now need to remove the foreign key field from the result (e.g. if we're embedding author, we want to remove authorId)
-				delete(result[i], fragments[1]+"Id")
-			}
-		}
-
-		return result, nil
-	case field.IsHasOne():
+	case field.IsHasMany(), field.IsHasOne():
 		dbQuery.Join(
 			sourceModel.Name,
 			&QueryOperand{
-				table:  sourceTableAlias,
+				table:  embedSourceTableAlias,
 				column: casing.ToSnake(parser.FieldNameId),
 			},
 			&QueryOperand{
 				table:  casing.ToSnake(relatedModelName),
 				column: casing.ToSnake(foreignKeyField),
 			})
-
-		stmt := dbQuery.SelectStatement()
-		result, err := stmt.ExecuteToSingle(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("executing query to single: %w", err)
-		}
-
-		if relatedModel.HasFiles() {
-			// if we have any files in our results we need to transform them to the object structure required
-			result, err = transformModelFileResponses(ctx, relatedModel, result)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		// recurse and resolve child embeds
-		if len(fragments) > 1 {
-			if childId, ok := result[parser.FieldNameId].(string); ok {
-				childEmbed, err := resolveEmbeddedData(ctx, schema, relatedModel, childId, fragments[1:])
-				if err != nil {
-					return nil, fmt.Errorf("retrieving child embed: %w", err)
-				}
-				result[fragments[1]] = childEmbed
-
-				// we now need to remove the foreign key field from the result (e.g. if we're embedding author, we want to remove authorId)
-				delete(result, fragments[1]+"Id")
-			}
-		}
-
-		return result, nil
+	default:
+		return nil, errors.New("unsupported embed type")
 	}
 
-	return nil, errors.New("unsupported embed type")
+	return dbQuery.SelectStatement(), nil
 }
@@ -0,0 +1,175 @@
+package actions
+
+import (
+	"github.com/samber/lo"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/common"
+	"github.com/teamkeel/keel/schema/parser"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ApplyFieldReadPermissions sets the value of any field to null which the identity is not permitted to read,
+// according to the field-level read permission rules of the scope's model.
+func ApplyFieldReadPermissions(scope *Scope, rows []map[string]any) error {
+	fields := scope.Model.ReadRestrictedFields()
+	if len(fields) == 0 || len(rows) == 0 {
+		return nil
+	}
+
+	ctx, span := tracer.Start(scope.Context, "Check field read permissions")
+	defer span.End()
+
+	scope = NewModelScope(ctx, scope.Model, scope.Schema)
+
+	ids := lo.FilterMap(rows, func(row map[string]any, _ int) (string, bool) {
+		id, ok := row[parser.FieldNameId].(string)
+		return id, ok
+	})
+
+	for _, field := range fields {
+		authorisedIds, err := authorisedRowIds(scope, field.ReadPermissions(), ids)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			id, _ := row[parser.FieldNameId].(string)
+			if _, ok := row[field.Name]; ok && !lo.Contains(authorisedIds, id) {
+				row[field.Name] = nil
+			}
+		}
+	}
+
+	return nil
+}
+
+// authoriseFieldWrites checks that the identity is permitted to write to each of the fields for all the given rows,
+// according to the fields' write permission rules.
+func authoriseFieldWrites(scope *Scope, fields []*proto.Field, rowsToAuthorise []map[string]any) error {
+	if len(fields) == 0 || len(rowsToAuthorise) == 0 {
+		return nil
+	}
+
+	ctx, span := tracer.Start(scope.Context, "Check field write permissions")
+	defer span.End()
+
+	scope = NewModelScope(ctx, scope.Model, scope.Schema)
+
+	ids := lo.Map(rowsToAuthorise, func(row map[string]any, _ int) string {
+		return row[parser.FieldNameId].(string)
+	})
+
+	for _, field := range fields {
+		authorisedIds, err := authorisedRowIds(scope, field.WritePermissions(), ids)
+		if err != nil {
+			return err
+		}
+
+		if len(authorisedIds) != len(lo.Uniq(ids)) {
+			span.SetAttributes(attribute.String("field", field.Name))
+			return common.NewFieldPermissionError(field.Name)
+		}
+	}
+
+	return nil
+}
+
+// writeRestrictedFields returns the fields with write permission rules which are being written to by the values input.
+func writeRestrictedFields(scope *Scope, values map[string]any) []*proto.Field {
+	message := proto.FindValuesInputMessage(scope.Schema, scope.Action.Name)
+	if message == nil {
+		return nil
+	}
+
+	fields := []*proto.Field{}
+	for _, input := range message.Fields {
+		// Only inputs which directly target a field of this model
+		if len(input.Target) != 1 {
+			continue
+		}
+
+		if _, ok := values[input.Name]; !ok {
+			continue
+		}
+
+		field := proto.FindField(scope.Schema.Models, scope.Model.Name, input.Target[0])
+		if field != nil && len(field.WritePermissions()) > 0 {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// authoriseFieldFilter checks that the identity is permitted to read every field along the target path of a
+// filter or ordering input. Filtering or ordering by a field the identity cannot read would otherwise reveal
+// its values through the records returned, and so it is only permitted when the read permissions resolve
+// without needing to look at the rows.
+func authoriseFieldFilter(scope *Scope, target []string) error {
+	model := scope.Model
+	for _, name := range target {
+		field := proto.FindField(scope.Schema.Models, model.Name, name)
+		if field == nil {
+			return nil
+		}
+
+		if permissions := field.ReadPermissions(); len(permissions) > 0 {
+			canResolve, authorised, err := TryResolveAuthorisationEarly(NewModelScope(scope.Context, model, scope.Schema), permissions)
+			if err != nil {
+				return err
+			}
+
+			if !canResolve || !authorised {
+				return common.NewFieldFilterPermissionError(name)
+			}
+		}
+
+		if field.Type.ModelName == nil {
+			return nil
+		}
+
+		model = scope.Schema.FindModel(field.Type.ModelName.Value)
+		if model == nil {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// authorisedRowIds returns the ids, of those given, for which any of the permission rules are satisfied.
+func authorisedRowIds(scope *Scope, permissions []*proto.PermissionRule, ids []string) ([]string, error) {
+	canResolve, authorised, err := TryResolveAuthorisationEarly(scope, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if canResolve {
+		if authorised {
+			return lo.Uniq(ids), nil
+		}
+		return []string{}, nil
+	}
+
+	// If there are no expression permissions or record-scoped roles to satisfy, then access cannot be granted.
+	if len(proto.PermissionsWithExpression(permissions)) == 0 && len(roleScopedRecordIds(scope.Context, scope.Model, permissions)) == 0 {
+		return []string{}, nil
+	}
+
+	query, err := permissionQuery(scope, permissions, map[string]any{}, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	query.Select(IdField())
+	query.DistinctOn(IdField())
+
+	results, _, err := query.SelectStatement().ExecuteToMany(scope.Context, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(results, func(row map[string]any, _ int) string {
+		return row[parser.FieldNameId].(string)
+	}), nil
+}
@@ -56,6 +56,13 @@ func Get(scope *Scope, input map[string]any) (map[string]any, error) {
 		}
 	}
 
+	if res != nil {
+		err = ApplyFieldReadPermissions(scope, []map[string]any{res})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// if we have embedded data, let's resolve it
 	if len(scope.Action.ResponseEmbeds) > 0 {
 		for _, embed := range scope.Action.ResponseEmbeds {
@@ -59,6 +59,37 @@ func FindIdentityByEmail(ctx context.Context, schema *proto.Schema, email string
 	return result, nil
 }
 
+// FindIdentityByVerifiedEmail finds the earliest created identity with the email address, from any issuer,
+// where the email address has been verified.
+func FindIdentityByVerifiedEmail(ctx context.Context, schema *proto.Schema, email string) (auth.Identity, error) {
+	identityModel := schema.FindModel(parser.IdentityModelName)
+	query := NewQuery(identityModel)
+	err := query.Where(Field(parser.IdentityFieldNameEmail), Equals, Value(email))
+	if err != nil {
+		return nil, err
+	}
+	query.And()
+	err = query.Where(Field(parser.IdentityFieldNameEmailVerified), Equals, Value(true))
+	if err != nil {
+		return nil, err
+	}
+
+	query.AppendOrderBy(Field(parser.FieldNameCreatedAt), "ASC")
+	query.Limit(1)
+
+	query.Select(AllFields())
+	result, err := query.SelectStatement().ExecuteToSingle(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+
+	return result, nil
+}
+
 func FindIdentityByExternalId(ctx context.Context, schema *proto.Schema, externalId string, issuer string) (auth.Identity, error) {
 	identityModel := schema.FindModel(parser.IdentityModelName)
 	query := NewQuery(identityModel)
@@ -212,3 +243,20 @@ func UpdateIdentityWithClaims(ctx context.Context, schema *proto.Schema, externa
 
 	return result, nil
 }
+
+// MarkEmailVerified sets emailVerified on the identity, such as once it has signed in with a code sent to its email address.
+func MarkEmailVerified(ctx context.Context, schema *proto.Schema, id string) (auth.Identity, error) {
+	identityModel := schema.FindModel(parser.IdentityModelName)
+
+	query := NewQuery(identityModel)
+	err := query.Where(IdField(), Equals, Value(id))
+	if err != nil {
+		return nil, err
+	}
+
+	query.AddWriteValue(Field(parser.IdentityFieldNameEmailVerified), Value(true))
+	query.Select(AllFields())
+	query.AppendReturning(AllFields())
+
+	return query.UpdateStatement(ctx).ExecuteToSingle(ctx)
+}
@@ -0,0 +1,284 @@
+package actions
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/karlseguin/typed"
+	"github.com/samber/lo"
+	"github.com/teamkeel/keel/db"
+	"github.com/teamkeel/keel/runtime/auth"
+	"github.com/teamkeel/keel/runtime/common"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/schema/parser"
+)
+
+// identityDataTables are the Keel auth tables which hold data belonging to an identity.
+var identityDataTables = []string{
+	"keel_refresh_token",
+	"keel_auth_code",
+	"keel_mfa_factor",
+	"keel_mfa_recovery_code",
+	"keel_identity_link",
+	"keel_role_membership",
+	"keel_identity_disabled",
+}
+
+// ListIdentities searches the identities by email address and name, optionally filtering to
+// those which are (or are not) disabled. Requires one of the configured admin roles.
+func ListIdentities(scope *Scope, input map[string]any) (map[string]any, error) {
+	err := authoriseIdentityAdmin(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	typedInput := typed.New(input)
+
+	page, err := ParsePage(input)
+	if err != nil {
+		return nil, common.RuntimeError{Code: common.ErrInvalidInput, Message: err.Error()}
+	}
+
+	disabled, err := oauth.DisabledIdentities(scope.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	query := NewQuery(scope.Schema.FindModel(parser.IdentityModelName))
+
+	if search := typedInput.String("search"); search != "" {
+		query.OpenParenthesis()
+		err = query.Where(Field(parser.IdentityFieldNameEmail), ContainsIgnoreCase, Value(search))
+		if err != nil {
+			return nil, err
+		}
+		query.Or()
+		err = query.Where(Field(parser.IdentityFieldNameName), ContainsIgnoreCase, Value(search))
+		if err != nil {
+			return nil, err
+		}
+		query.CloseParenthesis()
+	}
+
+	if filter, ok := typedInput.BoolIf("disabled"); ok {
+		operator := OneOf
+		if !filter {
+			operator = NotOneOf
+		}
+
+		query.And()
+		err = query.Where(IdField(), operator, Value(lo.Keys(disabled)))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	query.Select(AllFields())
+
+	err = query.ApplyPaging(page)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, pageInfo, err := query.SelectStatement().ExecuteToMany(scope.Context, &page)
+	if err != nil {
+		return nil, err
+	}
+
+	identities := lo.Map(rows, func(row map[string]any, _ int) map[string]any {
+		var disabledAt *time.Time
+		if t, ok := disabled[row[parser.FieldNameId].(string)]; ok {
+			disabledAt = &t
+		}
+		return identitySummary(row, disabledAt)
+	})
+
+	return map[string]any{
+		"identities":  identities,
+		"hasNextPage": pageInfo.HasNextPage,
+	}, nil
+}
+
+// DisableIdentity prevents an identity from authenticating or refreshing its tokens, and revokes all of its sessions.
+// Requires one of the configured admin roles, and an identity cannot disable itself.
+func DisableIdentity(scope *Scope, input map[string]any) (map[string]any, error) {
+	identity, err := findIdentityToAdminister(scope, typed.New(input).String("identityId"), true)
+	if err != nil {
+		return nil, err
+	}
+
+	identityId := identity[parser.FieldNameId].(string)
+
+	err = oauth.DisableIdentity(scope.Context, identityId)
+	if err != nil {
+		return nil, err
+	}
+
+	disabledAt, err := oauth.IdentityDisabledAt(scope.Context, identityId)
+	if err != nil {
+		return nil, err
+	}
+
+	return identitySummary(identity, disabledAt), nil
+}
+
+// EnableIdentity allows a disabled identity to authenticate again. Requires one of the configured admin roles.
+func EnableIdentity(scope *Scope, input map[string]any) (map[string]any, error) {
+	identity, err := findIdentityToAdminister(scope, typed.New(input).String("identityId"), false)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = oauth.EnableIdentity(scope.Context, identity[parser.FieldNameId].(string))
+	if err != nil {
+		return nil, err
+	}
+
+	return identitySummary(identity, nil), nil
+}
+
+// ForcePasswordReset removes the identity's password, signs it out everywhere and emails it a link to reset
+// its password. Requires one of the configured admin roles.
+func ForcePasswordReset(scope *Scope, input map[string]any) error {
+	typedInput := typed.New(input)
+
+	identity, err := findIdentityToAdminister(scope, typedInput.String("identityId"), false)
+	if err != nil {
+		return err
+	}
+
+	redirectUrl, err := url.ParseRequestURI(typedInput.String("redirectUrl"))
+	if err != nil {
+		return common.RuntimeError{Code: common.ErrInvalidInput, Message: "invalid redirect URL"}
+	}
+
+	if email, _ := identity[parser.IdentityFieldNameEmail].(string); email == "" {
+		return common.RuntimeError{Code: common.ErrInvalidInput, Message: "identity does not have an email address"}
+	}
+
+	identityId := identity[parser.FieldNameId].(string)
+
+	query := NewQuery(scope.Schema.FindModel(parser.IdentityModelName))
+	err = query.Where(IdField(), Equals, Value(identityId))
+	if err != nil {
+		return err
+	}
+
+	query.AddWriteValue(Field(parser.IdentityFieldNamePassword), Null())
+
+	_, err = query.UpdateStatement(scope.Context).Execute(scope.Context)
+	if err != nil {
+		return err
+	}
+
+	_, err = oauth.RevokeAllSessions(scope.Context, identityId)
+	if err != nil {
+		return err
+	}
+
+	return sendPasswordResetEmail(scope.Context, identity, redirectUrl)
+}
+
+// DeleteIdentity deletes an identity along with its sessions, MFA factors, linked identities and role memberships.
+// Requires one of the configured admin roles, and an identity cannot delete itself. Deleting an identity which is
+// still referenced by other records will fail unless those relationships cascade.
+func DeleteIdentity(scope *Scope, input map[string]any) error {
+	identity, err := findIdentityToAdminister(scope, typed.New(input).String("identityId"), true)
+	if err != nil {
+		return err
+	}
+
+	identityId := identity[parser.FieldNameId].(string)
+
+	database, err := db.GetDatabase(scope.Context)
+	if err != nil {
+		return err
+	}
+
+	return database.Transaction(scope.Context, func(ctx context.Context) error {
+		query := NewQuery(scope.Schema.FindModel(parser.IdentityModelName))
+		err := query.Where(IdField(), Equals, Value(identityId))
+		if err != nil {
+			return err
+		}
+
+		_, err = query.DeleteStatement(ctx).Execute(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, table := range identityDataTables {
+			_, err = database.ExecuteStatement(ctx, "DELETE FROM "+table+" WHERE identity_id = ?", identityId)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// authoriseIdentityAdmin determines if the caller has one of the admin roles configured with auth.adminRoles.
+func authoriseIdentityAdmin(scope *Scope) error {
+	isAdmin, err := hasAdminRole(scope)
+	if err != nil {
+		return err
+	}
+
+	if !isAdmin {
+		return common.NewPermissionError()
+	}
+
+	return nil
+}
+
+// findIdentityToAdminister authorises the caller to administer identities and finds the identity being administered.
+func findIdentityToAdminister(scope *Scope, identityId string, preventSelf bool) (auth.Identity, error) {
+	err := authoriseIdentityAdmin(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if preventSelf && auth.IsAuthenticated(scope.Context) {
+		caller, err := auth.GetIdentity(scope.Context)
+		if err != nil {
+			return nil, err
+		}
+
+		if caller[parser.FieldNameId].(string) == identityId {
+			return nil, common.RuntimeError{Code: common.ErrInvalidInput, Message: "an identity cannot perform this action on itself"}
+		}
+	}
+
+	identity, err := FindIdentityById(scope.Context, scope.Schema, identityId)
+	if err != nil {
+		return nil, err
+	}
+
+	if identity == nil {
+		return nil, common.NewNotFoundError("identity not found")
+	}
+
+	return identity, nil
+}
+
+func identitySummary(identity map[string]any, disabledAt *time.Time) map[string]any {
+	summary := map[string]any{
+		"id":            identity[parser.FieldNameId],
+		"email":         identity[parser.IdentityFieldNameEmail],
+		"emailVerified": identity[parser.IdentityFieldNameEmailVerified],
+		"externalId":    identity[parser.IdentityFieldNameExternalId],
+		"issuer":        identity[parser.IdentityFieldNameIssuer],
+		"name":          identity[parser.IdentityFieldNameName],
+		"disabled":      disabledAt != nil,
+		"disabledAt":    nil,
+		"createdAt":     identity[parser.FieldNameCreatedAt],
+	}
+
+	if disabledAt != nil {
+		summary["disabledAt"] = *disabledAt
+	}
+
+	return summary
+}
@@ -0,0 +1,78 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/auth"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+	"github.com/teamkeel/keel/schema/parser"
+)
+
+// AuthenticateImpersonator authenticates the access token of the identity requesting to impersonate another
+// identity, and determines if they have one of the roles configured with auth.impersonatorRoles.
+// An identity which is itself being impersonated cannot impersonate another.
+func AuthenticateImpersonator(ctx context.Context, schema *proto.Schema, token string) (auth.Identity, bool, error) {
+	if oauth.ImpersonatorFromAccessToken(token) != "" {
+		return nil, false, oauth.ErrInvalidToken
+	}
+
+	identity, err := HandleBearerToken(ctx, schema, token)
+	if err != nil {
+		return nil, false, err
+	}
+
+	config, err := runtimectx.GetOAuthConfig(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(config.ImpersonatorRoles) == 0 {
+		return identity, false, nil
+	}
+
+	authorised, err := hasAnyRole(ctx, schema, identity, config.ImpersonatorRoles)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return identity, authorised, nil
+}
+
+// CanBeImpersonated determines if an identity may be impersonated. Identities with one of the roles configured
+// with auth.adminRoles or auth.impersonatorRoles cannot be, as otherwise an impersonator could escalate its own
+// privileges by acting as them.
+func CanBeImpersonated(ctx context.Context, schema *proto.Schema, identity auth.Identity) (bool, error) {
+	config, err := runtimectx.GetOAuthConfig(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	roles := append(append([]string{}, config.AdminRoles...), config.ImpersonatorRoles...)
+	if len(roles) == 0 {
+		return true, nil
+	}
+
+	privileged, err := hasAnyRole(ctx, schema, identity, roles)
+	if err != nil {
+		return false, err
+	}
+
+	return !privileged, nil
+}
+
+// hasAnyRole determines if the identity has any of the roles, either by its email or its role memberships.
+func hasAnyRole(ctx context.Context, schema *proto.Schema, identity auth.Identity, roles []string) (bool, error) {
+	ctx = auth.WithIdentity(ctx, identity)
+
+	if len(schema.Roles) > 0 {
+		memberships, err := FindRoleMemberships(ctx, identity[parser.FieldNameId].(string))
+		if err != nil {
+			return false, err
+		}
+		ctx = auth.WithRoleMemberships(ctx, memberships)
+	}
+
+	return resolveRolePermissionRule(ctx, schema, &proto.PermissionRule{RoleNames: roles})
+}
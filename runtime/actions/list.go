@@ -7,6 +7,8 @@ import (
 
 	"github.com/teamkeel/keel/proto"
 	"github.com/teamkeel/keel/runtime/common"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+	"github.com/teamkeel/keel/schema/expressions"
 	"github.com/teamkeel/keel/schema/parser"
 )
 
@@ -21,6 +23,32 @@ func (query *QueryBuilder) applyImplicitFiltersForList(scope *Scope, args map[st
 
 func (query *QueryBuilder) applyImplicitFiltersFromMessage(scope *Scope, message *proto.Message, model *proto.Model, args map[string]any) error {
 	for _, input := range message.Fields {
+		if input.IsFilterGroup() {
+			value, ok := args[input.Name]
+			if !ok || value == nil {
+				continue
+			}
+
+			err := query.applyFilterGroup(scope, input, model, value)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if input.IsHierarchyFilter() {
+			value, ok := args[input.Name]
+			if !ok || value == nil {
+				continue
+			}
+
+			err := query.applyHierarchyFilter(input, model, value)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
 		field := proto.FindField(scope.Schema.Models, model.Name, input.Name)
 
 		// If the input is not targeting a model field, then it is either a:
@@ -62,6 +90,11 @@ func (query *QueryBuilder) applyImplicitFiltersFromMessage(scope *Scope, message
 			return fmt.Errorf("'%s' input value %v is not in correct format", input.Name, value)
 		}
 
+		err := authoriseFieldFilter(scope, input.Target)
+		if err != nil {
+			return err
+		}
+
 		for operatorStr, operand := range valueMap {
 			var operator ActionOperator
 			var err error
@@ -101,6 +134,13 @@ func (query *QueryBuilder) applyImplicitFiltersFromMessage(scope *Scope, message
 					return err
 				}
 
+				if pattern, ok := operand.(string); ok && operator == Matches {
+					err = expressions.ValidatePattern(pattern)
+					if err != nil {
+						return common.NewValidationError(fmt.Sprintf("'%s' %s", input.Name, err.Error()))
+					}
+				}
+
 				// Resolve the database statement for this expression
 				err = query.whereByImplicitFilter(scope, input.Target, operator, operand)
 				if err != nil {
@@ -116,6 +156,123 @@ func (query *QueryBuilder) applyImplicitFiltersFromMessage(scope *Scope, message
 	return nil
 }
 
+// Applies a nested "and", "or" or "not" group of implicit filters to the query.
+// The conditions within each group are ANDed together, and the group as a whole is ANDed to the existing filters.
+func (query *QueryBuilder) applyFilterGroup(scope *Scope, input *proto.MessageField, model *proto.Model, value any) error {
+	groupMessage := scope.Schema.FindMessage(input.Type.MessageName.Value)
+
+	if input.Name == proto.FilterGroupNot {
+		groupArgs, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("'%s' input value %v is not in correct format", input.Name, value)
+		}
+
+		query.Not()
+		query.OpenParenthesis()
+		err := query.applyImplicitFiltersFromMessage(scope, groupMessage, model, groupArgs)
+		if err != nil {
+			return err
+		}
+		query.CloseParenthesis()
+
+		// Filter groups are ANDed to the other implicit input conditions
+		query.And()
+		return nil
+	}
+
+	groups, ok := value.([]any)
+	if !ok {
+		return fmt.Errorf("'%s' input value %v is not in correct format", input.Name, value)
+	}
+
+	members := []map[string]any{}
+	for _, group := range groups {
+		groupArgs, ok := group.(map[string]any)
+		if !ok {
+			return fmt.Errorf("'%s' input value %v is not in correct format", input.Name, group)
+		}
+		members = append(members, groupArgs)
+	}
+
+	// A member without any conditions is always true, and so then is an "or" group which contains it.
+	// This is determined on a separate query so that nothing from the group is written to this one.
+	if input.Name == proto.FilterGroupOr {
+		for _, groupArgs := range members {
+			trial := NewQuery(query.Model)
+			err := trial.applyImplicitFiltersFromMessage(scope, groupMessage, model, groupArgs)
+			if err != nil {
+				return err
+			}
+			if len(trimRhsOperators(trial.filters)) == 0 {
+				return nil
+			}
+		}
+	}
+
+	query.OpenParenthesis()
+	for _, groupArgs := range members {
+		query.OpenParenthesis()
+		err := query.applyImplicitFiltersFromMessage(scope, groupMessage, model, groupArgs)
+		if err != nil {
+			return err
+		}
+		query.CloseParenthesis()
+
+		if input.Name == proto.FilterGroupOr {
+			query.Or()
+		} else {
+			query.And()
+		}
+	}
+	query.CloseParenthesis()
+
+	// Filter groups are ANDed to the other implicit input conditions
+	query.And()
+	return nil
+}
+
+// Applies a "descendantsOf" or "ancestorsOf" filter on the model's self-referencing relationship to the query.
+func (query *QueryBuilder) applyHierarchyFilter(input *proto.MessageField, model *proto.Model, value any) error {
+	field := model.HierarchyField()
+	if field == nil {
+		return fmt.Errorf("model %s does not have a self-referencing relationship", model.Name)
+	}
+
+	valueMap, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("'%s' input value %v is not in correct format", input.Name, value)
+	}
+
+	id, ok := valueMap["id"].(string)
+	if !ok {
+		return fmt.Errorf("'%s' input requires an id", input.Name)
+	}
+
+	var depth *int
+	if d, ok := valueMap["depth"]; ok && d != nil {
+		num, err := toInt(d)
+		if err != nil {
+			return err
+		}
+		depth = &num
+	}
+
+	var err error
+	switch input.Name {
+	case proto.HierarchyDescendantsOf:
+		err = query.WhereDescendantsOf(field.ForeignKeyFieldName.Value, id, depth)
+	case proto.HierarchyAncestorsOf:
+		err = query.WhereAncestorsOf(field.ForeignKeyFieldName.Value, id, depth)
+	}
+	if err != nil {
+		return common.NewInputMalformedError(err.Error())
+	}
+
+	// Hierarchy filters are ANDed to the other implicit input conditions
+	query.And()
+	return nil
+}
+
 // Applies schema-defined @orderBy ordering to the query.
 func (query *QueryBuilder) applySchemaOrdering(scope *Scope) error {
 	for _, orderBy := range scope.Action.OrderBy {
@@ -131,13 +288,20 @@ func (query *QueryBuilder) applySchemaOrdering(scope *Scope) error {
 }
 
 // Applies ordering of @sortable fields to the query.
-func (query *QueryBuilder) applyRequestOrdering(orderBy []any) {
+func (query *QueryBuilder) applyRequestOrdering(scope *Scope, orderBy []any) error {
 	for _, item := range orderBy {
 		obj := item.(map[string]any)
 		for field, direction := range obj {
+			err := authoriseFieldFilter(scope, []string{field})
+			if err != nil {
+				return err
+			}
+
 			query.AppendOrderBy(Field(field), direction.(string))
 		}
 	}
+
+	return nil
 }
 
 func List(scope *Scope, input map[string]any) (map[string]any, error) {
@@ -183,21 +347,33 @@ func List(scope *Scope, input map[string]any) (map[string]any, error) {
 		}
 	}
 
+	err = ApplyFieldReadPermissions(scope, results)
+	if err != nil {
+		return nil, err
+	}
+
 	// if we have embedded data, let's resolve it
 	if len(scope.Action.ResponseEmbeds) > 0 {
 		for _, embed := range scope.Action.ResponseEmbeds {
 			fragments := strings.Split(embed, ".")
 
+			ids := make([]string, 0, len(results))
 			for _, res := range results {
 				id, ok := res[parser.FieldNameId].(string)
 				if !ok {
 					return nil, errors.New("missing identifier")
 				}
-				data, err := resolveEmbeddedData(scope.Context, scope.Schema, scope.Model, id, fragments)
-				if err != nil {
-					return nil, err
-				}
-				res[fragments[0]] = data
+				ids = append(ids, id)
+			}
+
+			// resolve the embedded data for all results at once to avoid querying for each result
+			embeds, err := resolveEmbeddedDataBatch(scope.Context, scope.Schema, scope.Model, ids, fragments)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, res := range results {
+				res[fragments[0]] = embeds[res[parser.FieldNameId].(string)]
 
 				// we now need to remove the foreign key field from the result (e.g. if we're embedding author, we want to remove authorId)
 				delete(res, fragments[0]+"Id")
@@ -237,13 +413,23 @@ func GenerateListStatement(query *QueryBuilder, scope *Scope, input map[string]a
 		return nil, nil, err
 	}
 
-	query.applyRequestOrdering(orderBy)
+	err = query.applyRequestOrdering(scope, orderBy)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	page, err := ParsePage(input)
+	var page Page
+	if scope.Action.PaginationMode == proto.PaginationMode_PAGINATION_MODE_OFFSET {
+		page, err = ParseOffsetPage(input)
+	} else {
+		page, err = ParsePage(input)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
 
+	page.MaxPageSize = maxPageSize(scope)
+
 	// Select all columns from this table and distinct on id
 	query.DistinctOn(IdField())
 	query.Select(AllFields())
@@ -255,3 +441,17 @@ func GenerateListStatement(query *QueryBuilder, scope *Scope, input map[string]a
 
 	return query.SelectStatement(), &page, nil
 }
+
+// maxPageSize returns the page size limit for the action, which falls back to
+// the limit of the API being used. Zero means there is no limit.
+func maxPageSize(scope *Scope) int {
+	if scope.Action.MaxPageSize > 0 {
+		return int(scope.Action.MaxPageSize)
+	}
+
+	if api, ok := runtimectx.GetApi(scope.Context); ok {
+		return int(api.MaxPageSize)
+	}
+
+	return 0
+}
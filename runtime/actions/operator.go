@@ -36,6 +36,11 @@ const (
 	Before
 	OnOrAfter
 	OnOrBefore
+	EqualsIgnoreCase
+	StartsWithIgnoreCase
+	EndsWithIgnoreCase
+	ContainsIgnoreCase
+	Matches
 
 	AllEquals
 	AnyEquals
@@ -92,6 +97,16 @@ func queryOperatorToActionOperator(in string) (out ActionOperator, err error) {
 		return OnOrBefore, nil
 	case "onOrAfter":
 		return OnOrAfter, nil
+	case "equalsIgnoreCase":
+		return EqualsIgnoreCase, nil
+	case "startsWithIgnoreCase":
+		return StartsWithIgnoreCase, nil
+	case "endsWithIgnoreCase":
+		return EndsWithIgnoreCase, nil
+	case "containsIgnoreCase":
+		return ContainsIgnoreCase, nil
+	case "matches":
+		return Matches, nil
 	default:
 		return out, fmt.Errorf("unrecognized operator: %s", in)
 	}
@@ -172,6 +187,16 @@ func expressionOperatorToActionOperator(in string) (out ActionOperator, err erro
 		return OneOf, nil
 	case parser.OperatorNotIn:
 		return NotOneOf, nil
+	case parser.OperatorEqualsIgnoreCase:
+		return EqualsIgnoreCase, nil
+	case parser.OperatorStartsWithIgnoreCase:
+		return StartsWithIgnoreCase, nil
+	case parser.OperatorEndsWithIgnoreCase:
+		return EndsWithIgnoreCase, nil
+	case parser.OperatorContainsIgnoreCase:
+		return ContainsIgnoreCase, nil
+	case parser.OperatorMatches:
+		return Matches, nil
 
 	default:
 		return Unknown, fmt.Errorf("this is not a recognized conditional operator: %s", in)
@@ -3,6 +3,8 @@ package actions
 import (
 	"fmt"
 	"strconv"
+
+	"github.com/teamkeel/keel/runtime/common"
 )
 
 // A Page describes which page you want from a list of records,
@@ -18,11 +20,20 @@ import (
 //
 // When you have no prior positional context you should specify First but leave Before and After to
 // the empty string. This gives you the first N records.
+//
+// List actions using offset pagination instead set Limit and Offset, for example a Limit of 10 and an
+// Offset of 20 gives you the third page of 10 records.
 type Page struct {
 	First  int
 	Last   int
 	After  string
 	Before string
+
+	Limit  int
+	Offset int
+
+	// MaxPageSize caps the number of records which can be requested for the page. Zero means no cap.
+	MaxPageSize int
 }
 
 // ParsePage extracts page mandate information from the given map and uses it to
@@ -88,6 +99,66 @@ func ParsePage(args map[string]any) (Page, error) {
 	return page, nil
 }
 
+// ParseOffsetPage extracts offset pagination information from the given map and uses it to
+// compose a Page.
+func ParseOffsetPage(args map[string]any) (Page, error) {
+	page := Page{}
+
+	if limit, ok := args["limit"]; ok && limit != nil {
+		num, err := toInt(limit)
+		if err != nil {
+			return page, err
+		}
+
+		// As a limit of zero would request an empty page, it is rejected rather than falling back to the default
+		if num == 0 {
+			return page, common.NewValidationError("limit must be greater than zero")
+		}
+		page.Limit = num
+	}
+
+	if offset, ok := args["offset"]; ok && offset != nil {
+		num, err := toInt(offset)
+		if err != nil {
+			return page, err
+		}
+		page.Offset = num
+	}
+
+	if page.Limit < 0 || page.Offset < 0 {
+		return page, common.NewValidationError("limit and offset cannot be negative")
+	}
+
+	// If none specified - use a sensible default
+	if page.Limit == 0 {
+		page.Limit = 50
+	}
+
+	return page, nil
+}
+
+// IsOffset tells us if the page is using offset pagination rather than cursors
+func (p *Page) IsOffset() bool {
+	return p.Limit > 0
+}
+
+// PageSize returns the number of records requested for the page, capped at MaxPageSize if set.
+func (p *Page) PageSize() int {
+	size := p.First
+	switch {
+	case p.IsOffset():
+		size = p.Limit
+	case p.First == 0:
+		size = p.Last
+	}
+
+	if p.MaxPageSize > 0 && size > p.MaxPageSize {
+		return p.MaxPageSize
+	}
+
+	return size
+}
+
 // IsBackwards tells us if the page is backwards paginated (e.g. we're requesting elements before a cursor)
 func (p *Page) IsBackwards() bool {
 	return p.Before != "" && p.Last > 0
@@ -131,6 +131,8 @@ var toInt = func(value any) (int, error) {
 	switch t := value.(type) {
 	case int:
 		return t, nil
+	case int64:
+		return int(t), nil
 	case float32:
 		return int(t), nil
 	case float64:
@@ -0,0 +1,170 @@
+package actions
+
+import (
+	"context"
+	"sync"
+
+	"github.com/samber/lo"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/schema/parser"
+)
+
+// ExplainPermissionsHeader is the request header which asks for the permission decisions of the request to be explained.
+// The explanations are rendered by the run command and can be retrieved over RPC, but are never returned in the response.
+const ExplainPermissionsHeader = "X-Keel-Explain-Permissions"
+
+const (
+	PermissionResolvedEarly    = "early"
+	PermissionResolvedDatabase = "database"
+)
+
+// PermissionExplanation describes how an authorisation decision was made.
+type PermissionExplanation struct {
+	Model      string                       `json:"model,omitempty"`
+	Action     string                       `json:"action,omitempty"`
+	Authorised bool                         `json:"authorised"`
+	Reason     string                       `json:"reason,omitempty"`
+	Rules      []*PermissionRuleExplanation `json:"rules"`
+	Sql        string                       `json:"sql,omitempty"`
+	SqlArgs    []any                        `json:"sqlArgs,omitempty"`
+	Rows       []*PermissionRowExplanation  `json:"rows,omitempty"`
+}
+
+// PermissionRuleExplanation describes how a single permission rule was evaluated.
+type PermissionRuleExplanation struct {
+	Expression string   `json:"expression,omitempty"`
+	Roles      []string `json:"roles,omitempty"`
+	// Either "early" if the rule was resolved without querying the database, or "database" if resolved in SQL.
+	ResolvedIn string `json:"resolvedIn"`
+	// Only set for rules resolved early.
+	Authorised *bool `json:"authorised,omitempty"`
+}
+
+// PermissionRowExplanation is the outcome of the permission rules for a single row.
+type PermissionRowExplanation struct {
+	Id         string `json:"id"`
+	Authorised bool   `json:"authorised"`
+}
+
+// PermissionExplainer collects the explanations of each authorisation decision made during a request.
+// This is intended for development use only as explaining permissions requires additional database queries.
+type PermissionExplainer struct {
+	mu           sync.Mutex
+	explanations []*PermissionExplanation
+}
+
+func NewPermissionExplainer() *PermissionExplainer {
+	return &PermissionExplainer{}
+}
+
+// Explanations returns the explanations collected so far.
+func (e *PermissionExplainer) Explanations() []*PermissionExplanation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]*PermissionExplanation{}, e.explanations...)
+}
+
+func (e *PermissionExplainer) add(explanation *PermissionExplanation) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.explanations = append(e.explanations, explanation)
+}
+
+type permissionExplainerContextKey struct{}
+
+// WithPermissionExplainer enables the explaining of authorisation decisions for the context.
+func WithPermissionExplainer(ctx context.Context, explainer *PermissionExplainer) context.Context {
+	return context.WithValue(ctx, permissionExplainerContextKey{}, explainer)
+}
+
+// PermissionExplainerFromContext returns the permission explainer, or nil if permissions are not being explained.
+func PermissionExplainerFromContext(ctx context.Context) *PermissionExplainer {
+	explainer, _ := ctx.Value(permissionExplainerContextKey{}).(*PermissionExplainer)
+	return explainer
+}
+
+// explainPermissions records an explanation of an authorisation decision if permissions are being explained.
+// The rows will be nil if the decision was resolved early.
+func explainPermissions(scope *Scope, permissions []*proto.PermissionRule, input map[string]any, rows []map[string]any, authorised bool) {
+	explainer := PermissionExplainerFromContext(scope.Context)
+	if explainer == nil {
+		return
+	}
+
+	explanation := &PermissionExplanation{
+		Authorised: authorised,
+		Rules:      []*PermissionRuleExplanation{},
+	}
+
+	if scope.Model != nil {
+		explanation.Model = scope.Model.Name
+	}
+
+	switch {
+	case scope.Action != nil:
+		explanation.Action = scope.Action.Name
+	case scope.Job != nil:
+		explanation.Action = scope.Job.Name
+	}
+
+	if len(permissions) == 0 {
+		explanation.Reason = "no permission rules"
+	}
+
+	hasDatabaseCheck := false
+	for _, permission := range permissions {
+		rule := &PermissionRuleExplanation{
+			Roles:      permission.RoleNames,
+			ResolvedIn: PermissionResolvedDatabase,
+		}
+		if permission.Expression != nil {
+			rule.Expression = permission.Expression.Source
+		}
+
+		canResolve, ruleAuthorised, err := tryResolvePermissionRuleEarly(scope, permission)
+		if err == nil && canResolve {
+			rule.ResolvedIn = PermissionResolvedEarly
+			rule.Authorised = &ruleAuthorised
+		} else {
+			hasDatabaseCheck = true
+		}
+
+		explanation.Rules = append(explanation.Rules, rule)
+	}
+
+	if rows != nil && hasDatabaseCheck {
+		ids := lo.Map(rows, func(row map[string]any, _ int) string {
+			id, _ := row[parser.FieldNameId].(string)
+			return id
+		})
+
+		if stmt, err := GeneratePermissionStatement(scope, permissions, input, ids); err == nil {
+			explanation.Sql = stmt.SqlTemplate()
+			explanation.SqlArgs = stmt.SqlArgs()
+		}
+
+		authorisedIds := []string{}
+		if query, err := permissionQuery(scope, permissions, input, ids); err == nil {
+			query.Select(IdField())
+			query.DistinctOn(IdField())
+
+			results, _, err := query.SelectStatement().ExecuteToMany(scope.Context, nil)
+			if err != nil {
+				explanation.Reason = err.Error()
+			}
+
+			for _, result := range results {
+				authorisedIds = append(authorisedIds, result[parser.FieldNameId].(string))
+			}
+		}
+
+		for _, id := range ids {
+			explanation.Rows = append(explanation.Rows, &PermissionRowExplanation{
+				Id:         id,
+				Authorised: lo.Contains(authorisedIds, id),
+			})
+		}
+	}
+
+	explainer.add(explanation)
+}
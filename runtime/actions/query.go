@@ -238,6 +238,8 @@ type QueryBuilder struct {
 	returning []string
 	// The value for LIMIT.
 	limit *int
+	// The value for OFFSET.
+	offset *int
 	// The ordered slice of arguments for the SQL statement template.
 	args []any
 	// The graph of rows to be written during an INSERT or UPDATE.
@@ -307,6 +309,7 @@ func NewQuery(model *proto.Model, opts ...QueryBuilderOption) *QueryBuilder {
 		filters:    []string{},
 		orderBy:    []*orderClause{},
 		limit:      nil,
+		offset:     nil,
 		returning:  []string{},
 		args:       []any{},
 		writeValues: &Row{
@@ -339,6 +342,7 @@ func (query *QueryBuilder) Copy() *QueryBuilder {
 		filters:    copySlice(query.filters),
 		orderBy:    copySlice(query.orderBy),
 		limit:      query.limit,
+		offset:     query.offset,
 		returning:  copySlice(query.returning),
 		args:       query.args,
 	}
@@ -405,10 +409,62 @@ func (query *QueryBuilder) Where(left *QueryOperand, operator ActionOperator, ri
 	return nil
 }
 
+// Include a WHERE condition which filters to the descendants of the given row in a self-referencing hierarchy,
+// where foreignKey is the column referencing the parent row. Descendants are found with a recursive CTE, optionally
+// limited to a depth (where a depth of 1 only includes the immediate children).
+func (query *QueryBuilder) WhereDescendantsOf(foreignKey string, id any, depth *int) error {
+	return query.whereInHierarchy(casing.ToSnake(foreignKey), casing.ToSnake(parser.FieldNameId), id, depth)
+}
+
+// Include a WHERE condition which filters to the ancestors of the given row in a self-referencing hierarchy,
+// where foreignKey is the column referencing the parent row. Ancestors are found with a recursive CTE, optionally
+// limited to a depth (where a depth of 1 only includes the immediate parent).
+func (query *QueryBuilder) WhereAncestorsOf(foreignKey string, id any, depth *int) error {
+	return query.whereInHierarchy(casing.ToSnake(parser.FieldNameId), casing.ToSnake(foreignKey), id, depth)
+}
+
+// Walks the hierarchy from the row with the given id by recursively joining on matchColumn and selecting nextColumn.
+// The path of visited rows is tracked so that cyclic data cannot cause infinite recursion.
+func (query *QueryBuilder) whereInHierarchy(matchColumn string, nextColumn string, id any, depth *int) error {
+	if depth != nil && *depth < 1 {
+		return errors.New("hierarchy depth must be greater than zero")
+	}
+
+	hierarchy := sqlQuote("_hierarchy")
+	node := sqlQuote("_node")
+	match := sqlQuote("_node", matchColumn)
+	next := sqlQuote("_node", nextColumn)
+
+	depthCondition := ""
+	args := []any{id}
+	if depth != nil {
+		depthCondition = fmt.Sprintf(" AND %s < ?", sqlQuote("_hierarchy", "depth"))
+		args = append(args, *depth)
+	}
+
+	template := fmt.Sprintf(
+		"%s IN (WITH RECURSIVE %s(id, depth, path) AS ("+
+			"SELECT %s, 1, ARRAY[%s, %s] FROM %s AS %s WHERE %s = ? AND %s IS NOT NULL "+
+			"UNION ALL "+
+			"SELECT %s, %s + 1, %s || %s FROM %s AS %s JOIN %s ON %s = %s "+
+			"WHERE %s IS NOT NULL AND NOT %s = ANY(%s)%s"+
+			") SELECT id FROM %s)",
+		sqlQuote(query.table, casing.ToSnake(parser.FieldNameId)), hierarchy,
+		next, match, next, sqlQuote(query.table), node, match, next,
+		next, sqlQuote("_hierarchy", "depth"), sqlQuote("_hierarchy", "path"), next, sqlQuote(query.table), node, hierarchy, match, sqlQuote("_hierarchy", "id"),
+		next, next, sqlQuote("_hierarchy", "path"), depthCondition,
+		hierarchy)
+
+	query.filters = append(query.filters, template)
+	query.args = append(query.args, args...)
+
+	return nil
+}
+
 // Appends the next condition with a logical AND.
 func (query *QueryBuilder) And() {
 	query.filters = trimRhsOperators(query.filters)
-	if len(query.filters) > 0 {
+	if len(query.filters) > 0 && !isOpenScope(query.filters) {
 		query.filters = append(query.filters, "AND")
 	}
 }
@@ -416,22 +472,39 @@ func (query *QueryBuilder) And() {
 // Appends the next condition with a logical OR.
 func (query *QueryBuilder) Or() {
 	query.filters = trimRhsOperators(query.filters)
-	if len(query.filters) > 0 {
+	if len(query.filters) > 0 && !isOpenScope(query.filters) {
 		query.filters = append(query.filters, "OR")
 	}
 }
 
+// Negates the next conditional scope in the where expression (i.e. NOT followed by an open parenthesis).
+func (query *QueryBuilder) Not() {
+	query.filters = append(query.filters, "NOT")
+}
+
 // Opens a new conditional scope in the where expression (i.e. open parethesis).
 func (query *QueryBuilder) OpenParenthesis() {
 	query.filters = append(query.filters, "(")
 }
 
 // Closes the current conditional scope in the where expression (i.e. close parethesis).
+// If no conditions were added to the scope, then the scope (and any negation of it) is removed.
 func (query *QueryBuilder) CloseParenthesis() {
 	query.filters = trimRhsOperators(query.filters)
+	if len(query.filters) > 0 && query.filters[len(query.filters)-1] == "(" {
+		query.filters = lo.DropRight(query.filters, 1)
+		query.filters = lo.DropRightWhile(query.filters, func(s string) bool { return s == "NOT" })
+		return
+	}
 	query.filters = append(query.filters, ")")
 }
 
+// Determines if the filter conditions end with an opened scope which has no conditions yet.
+func isOpenScope(filters []string) bool {
+	last := filters[len(filters)-1]
+	return last == "(" || last == "NOT"
+}
+
 // Trims an excess OR / AND operators from the rhs side of the filter conditions.
 func trimRhsOperators(filters []string) []string {
 	return lo.DropRightWhile(filters, func(s string) bool { return s == "OR" || s == "AND" })
@@ -473,6 +546,11 @@ func (query *QueryBuilder) Limit(limit int) {
 	query.limit = &limit
 }
 
+// Set the OFFSET to a number.
+func (query *QueryBuilder) Offset(offset int) {
+	query.offset = &offset
+}
+
 // Include a column in RETURNING.
 func (query *QueryBuilder) AppendReturning(operand *QueryOperand) {
 	c := operand.toSqlOperandString(query)
@@ -488,11 +566,13 @@ func (query *QueryBuilder) ApplyPaging(page Page) error {
 	query.And()
 
 	// Add where condition to implement the page size
-	switch {
-	case page.First != 0:
-		query.Limit(page.First)
-	case page.Last != 0:
-		query.Limit(page.Last)
+	if size := page.PageSize(); size != 0 {
+		query.Limit(size)
+	}
+
+	// Offset pagination skips the records of the preceding pages
+	if page.IsOffset() && page.Offset > 0 {
+		query.Offset(page.Offset)
 	}
 
 	// Specify the ORDER BY - but also a "LEAD" extra column to harvest extra data
@@ -649,6 +729,7 @@ func (query *QueryBuilder) SelectStatement() *Statement {
 	filters := ""
 	orderBy := ""
 	limit := ""
+	offset := ""
 
 	if len(query.distinctOn) > 0 {
 		distinctOn = fmt.Sprintf("DISTINCT ON(%s)", strings.Join(query.distinctOn, ", "))
@@ -685,14 +766,20 @@ func (query *QueryBuilder) SelectStatement() *Statement {
 		query.args = append(query.args, *query.limit)
 	}
 
-	sql := fmt.Sprintf("SELECT %s %s FROM %s %s %s %s %s",
+	if query.offset != nil {
+		offset = "OFFSET ?"
+		query.args = append(query.args, *query.offset)
+	}
+
+	sql := fmt.Sprintf("SELECT %s %s FROM %s %s %s %s %s %s",
 		distinctOn,
 		selection,
 		sqlQuote(query.table),
 		joins,
 		filters,
 		orderBy,
-		limit)
+		limit,
+		offset)
 
 	return &Statement{
 		template: sql,
@@ -714,6 +801,11 @@ func (query *QueryBuilder) InsertStatement(ctx context.Context) *Statement {
 		args = append(args, identity[parser.FieldNameId].(string))
 	}
 
+	if auth.IsImpersonated(ctx) {
+		selection = append(selection, setImpersonatorIdClause())
+		args = append(args, auth.GetImpersonatorId(ctx))
+	}
+
 	spanContext := trace.SpanContextFromContext(ctx)
 	if spanContext.IsValid() {
 		selection = append(selection, setTraceIdClause())
@@ -983,6 +1075,11 @@ func (query *QueryBuilder) UpdateStatement(ctx context.Context) *Statement {
 		args = append(args, identity[parser.FieldNameId].(string))
 	}
 
+	if auth.IsImpersonated(ctx) {
+		query.returning = append(query.returning, setImpersonatorIdClause())
+		args = append(args, auth.GetImpersonatorId(ctx))
+	}
+
 	spanContext := trace.SpanContextFromContext(ctx)
 	if spanContext.IsValid() {
 		query.returning = append(query.returning, setTraceIdClause())
@@ -1055,6 +1152,11 @@ func (query *QueryBuilder) DeleteStatement(ctx context.Context) *Statement {
 		query.args = append(query.args, identity[parser.FieldNameId].(string))
 	}
 
+	if auth.IsImpersonated(ctx) {
+		query.returning = append(query.returning, setImpersonatorIdClause())
+		query.args = append(query.args, auth.GetImpersonatorId(ctx))
+	}
+
 	spanContext := trace.SpanContextFromContext(ctx)
 	if spanContext.IsValid() {
 		query.returning = append(query.returning, setTraceIdClause())
@@ -1110,16 +1212,29 @@ type PageInfo struct {
 
 	// EndCursor is the identifier representing the last row in the set
 	EndCursor string
+
+	// PageNumber is the number of the current page, starting at 1. Only set when using offset pagination
+	PageNumber int
+
+	// TotalPages is the number of pages needed for all rows. Only set when using offset pagination
+	TotalPages int
 }
 
 func (pi *PageInfo) ToMap() map[string]any {
-	return map[string]any{
+	m := map[string]any{
 		"count":       pi.Count,
 		"totalCount":  pi.TotalCount,
 		"startCursor": pi.StartCursor,
 		"endCursor":   pi.EndCursor,
 		"hasNextPage": pi.HasNextPage,
 	}
+
+	if pi.PageNumber > 0 {
+		m["pageNumber"] = pi.PageNumber
+		m["totalPages"] = pi.TotalPages
+	}
+
+	return m
 }
 
 // Execute the SQL statement against the database, return the rows, number of rows affected, and a boolean to indicate if there is a next page.
@@ -1170,6 +1285,7 @@ func (statement *Statement) ExecuteToMany(ctx context.Context, page *Page) (Rows
 		// TODO: only do this if we know auditing was added
 		for _, row := range rows {
 			delete(row, setIdentityIdAlias)
+			delete(row, setImpersonatorIdAlias)
 			delete(row, setTraceIdAlias)
 		}
 	}
@@ -1182,6 +1298,12 @@ func (statement *Statement) ExecuteToMany(ctx context.Context, page *Page) (Rows
 		EndCursor:   endCursor,
 	}
 
+	if page != nil && page.IsOffset() {
+		size := page.PageSize()
+		pageInfo.PageNumber = page.Offset/size + 1
+		pageInfo.TotalPages = (int(totalCount) + size - 1) / size
+	}
+
 	// Array fields are currently read as a single string (e.g. '{science, technology, arts}'), and
 	// therefore we need to parse them into correctly typed arrays and rewrite them to the result.
 	for _, f := range statement.model.Fields {
@@ -1308,11 +1430,11 @@ func (query *QueryBuilder) generateConditionTemplate(lhs *QueryOperand, operator
 
 	if rhs.IsValue() {
 		switch operator {
-		case StartsWith:
+		case StartsWith, StartsWithIgnoreCase:
 			rhs.value = rhs.value.(string) + "%%"
-		case EndsWith:
+		case EndsWith, EndsWithIgnoreCase:
 			rhs.value = "%%" + rhs.value.(string)
-		case Contains, NotContains:
+		case Contains, NotContains, ContainsIgnoreCase:
 			rhs.value = "%%" + rhs.value.(string) + "%%"
 		}
 	}
@@ -1347,6 +1469,23 @@ func (query *QueryBuilder) generateConditionTemplate(lhs *QueryOperand, operator
 		template = fmt.Sprintf("%s LIKE %s", lhsSqlOperand, rhsSqlOperand)
 	case NotContains:
 		template = fmt.Sprintf("%s NOT LIKE %s", lhsSqlOperand, rhsSqlOperand)
+	case EqualsIgnoreCase:
+		template = fmt.Sprintf("LOWER(%s) IS NOT DISTINCT FROM LOWER(%s)", lhsSqlOperand, rhsSqlOperand)
+	case StartsWithIgnoreCase, EndsWithIgnoreCase, ContainsIgnoreCase:
+		// Wildcards can only be added to values up front, so other operands (such as fields) are concatenated in SQL
+		if !rhs.IsValue() {
+			switch operator {
+			case StartsWithIgnoreCase:
+				rhsSqlOperand = fmt.Sprintf("%s || '%%'", rhsSqlOperand)
+			case EndsWithIgnoreCase:
+				rhsSqlOperand = fmt.Sprintf("'%%' || %s", rhsSqlOperand)
+			case ContainsIgnoreCase:
+				rhsSqlOperand = fmt.Sprintf("'%%' || %s || '%%'", rhsSqlOperand)
+			}
+		}
+		template = fmt.Sprintf("%s ILIKE %s", lhsSqlOperand, rhsSqlOperand)
+	case Matches:
+		template = fmt.Sprintf("%s ~ %s", lhsSqlOperand, rhsSqlOperand)
 	case OneOf:
 		if rhs.IsInlineQuery() {
 			template = fmt.Sprintf("%s IN %s", lhsSqlOperand, rhsSqlOperand)
@@ -1474,14 +1613,19 @@ func toRuntimeError(err error) error {
 }
 
 const (
-	setIdentityIdAlias = "__keel_identity_id"
-	setTraceIdAlias    = "__keel_trace_id"
+	setIdentityIdAlias     = "__keel_identity_id"
+	setImpersonatorIdAlias = "__keel_impersonator_id"
+	setTraceIdAlias        = "__keel_trace_id"
 )
 
 func setIdentityIdClause() string {
 	return fmt.Sprintf("set_identity_id(?) AS %s", setIdentityIdAlias)
 }
 
+func setImpersonatorIdClause() string {
+	return fmt.Sprintf("set_impersonator_id(?) AS %s", setImpersonatorIdAlias)
+}
+
 func setTraceIdClause() string {
 	return fmt.Sprintf("set_trace_id(?) AS %s", setTraceIdAlias)
 }
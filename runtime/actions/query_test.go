@@ -14,6 +14,7 @@ import (
 	"github.com/teamkeel/keel/proto"
 	"github.com/teamkeel/keel/runtime/actions"
 	"github.com/teamkeel/keel/runtime/auth"
+	"github.com/teamkeel/keel/runtime/common"
 	"github.com/teamkeel/keel/schema"
 	"github.com/teamkeel/keel/schema/parser"
 	"go.opentelemetry.io/otel/trace"
@@ -683,6 +684,246 @@ var testCases = []testCase{
 				"thing"."id" ASC LIMIT ?`,
 		expectedArgs: []any{"%%bob", "%%bob", 50},
 	},
+	{
+		name: "list_op_implicit_input_text_containsIgnoreCase",
+		keelSchema: `
+			model Thing {
+				fields {
+					name Text
+				}
+				actions {
+					list listThings(name)
+				}
+				@permission(expression: true, actions: [list])
+			}`,
+		actionName: "listThings",
+		input: map[string]any{
+			"where": map[string]any{
+				"name": map[string]any{
+					"containsIgnoreCase": "Bob"}}},
+		expectedTemplate: `
+			SELECT
+				DISTINCT ON("thing"."id") "thing".*, CASE WHEN LEAD("thing"."id") OVER (ORDER BY "thing"."id" ASC) IS NOT NULL THEN true ELSE false END AS hasNext,
+				(SELECT COUNT(DISTINCT "thing"."id") FROM "thing" WHERE "thing"."name" ILIKE ?) AS totalCount
+			FROM
+				"thing"
+			WHERE
+				"thing"."name" ILIKE ?
+			ORDER BY
+				"thing"."id" ASC LIMIT ?`,
+		expectedArgs: []any{"%%Bob%%", "%%Bob%%", 50},
+	},
+	{
+		name: "list_op_implicit_input_text_equalsIgnoreCase",
+		keelSchema: `
+			model Thing {
+				fields {
+					name Text
+				}
+				actions {
+					list listThings(name)
+				}
+				@permission(expression: true, actions: [list])
+			}`,
+		actionName: "listThings",
+		input: map[string]any{
+			"where": map[string]any{
+				"name": map[string]any{
+					"equalsIgnoreCase": "Bob"}}},
+		expectedTemplate: `
+			SELECT
+				DISTINCT ON("thing"."id") "thing".*, CASE WHEN LEAD("thing"."id") OVER (ORDER BY "thing"."id" ASC) IS NOT NULL THEN true ELSE false END AS hasNext,
+				(SELECT COUNT(DISTINCT "thing"."id") FROM "thing" WHERE LOWER("thing"."name") IS NOT DISTINCT FROM LOWER(?)) AS totalCount
+			FROM
+				"thing"
+			WHERE
+				LOWER("thing"."name") IS NOT DISTINCT FROM LOWER(?)
+			ORDER BY
+				"thing"."id" ASC LIMIT ?`,
+		expectedArgs: []any{"Bob", "Bob", 50},
+	},
+	{
+		name: "list_op_implicit_input_text_matches",
+		keelSchema: `
+			model Thing {
+				fields {
+					name Text
+				}
+				actions {
+					list listThings(name)
+				}
+				@permission(expression: true, actions: [list])
+			}`,
+		actionName: "listThings",
+		input: map[string]any{
+			"where": map[string]any{
+				"name": map[string]any{
+					"matches": "^b.*b$"}}},
+		expectedTemplate: `
+			SELECT
+				DISTINCT ON("thing"."id") "thing".*, CASE WHEN LEAD("thing"."id") OVER (ORDER BY "thing"."id" ASC) IS NOT NULL THEN true ELSE false END AS hasNext,
+				(SELECT COUNT(DISTINCT "thing"."id") FROM "thing" WHERE "thing"."name" ~ ?) AS totalCount
+			FROM
+				"thing"
+			WHERE
+				"thing"."name" ~ ?
+			ORDER BY
+				"thing"."id" ASC LIMIT ?`,
+		expectedArgs: []any{"^b.*b$", "^b.*b$", 50},
+	},
+	{
+		name: "list_op_expression_text_startsWithIgnoreCase_field",
+		keelSchema: `
+			model Thing {
+				fields {
+					name Text
+					prefix Text
+				}
+				actions {
+					list listThings() {
+						@where(thing.name startsWithIgnoreCase thing.prefix)
+					}
+				}
+				@permission(expression: true, actions: [list])
+			}`,
+		actionName: "listThings",
+		input:      map[string]any{},
+		expectedTemplate: `
+			SELECT
+				DISTINCT ON("thing"."id") "thing".*, CASE WHEN LEAD("thing"."id") OVER (ORDER BY "thing"."id" ASC) IS NOT NULL THEN true ELSE false END AS hasNext,
+				(SELECT COUNT(DISTINCT "thing"."id") FROM "thing" WHERE "thing"."name" ILIKE "thing"."prefix" || '%') AS totalCount
+			FROM
+				"thing"
+			WHERE
+				"thing"."name" ILIKE "thing"."prefix" || '%'
+			ORDER BY
+				"thing"."id" ASC LIMIT ?`,
+		expectedArgs: []any{50},
+	},
+	{
+		name: "list_op_offset_pagination",
+		keelSchema: `
+			model Thing {
+				fields {
+					name Text
+				}
+				actions {
+					list listThings(name?) {
+						@pagination(mode: offset)
+					}
+				}
+				@permission(expression: true, actions: [list])
+			}`,
+		actionName: "listThings",
+		input: map[string]any{
+			"limit":  10,
+			"offset": 20,
+		},
+		expectedTemplate: `
+			SELECT
+				DISTINCT ON("thing"."id") "thing".*, CASE WHEN LEAD("thing"."id") OVER (ORDER BY "thing"."id" ASC) IS NOT NULL THEN true ELSE false END AS hasNext,
+				(SELECT COUNT(DISTINCT "thing"."id") FROM "thing") AS totalCount
+			FROM
+				"thing"
+			ORDER BY
+				"thing"."id" ASC LIMIT ? OFFSET ?`,
+		expectedArgs: []any{10, 20},
+	},
+	{
+		name: "list_op_max_page_size",
+		keelSchema: `
+			model Thing {
+				fields {
+					name Text
+				}
+				actions {
+					list listThings(name?) {
+						@pagination(maxPageSize: 25)
+					}
+				}
+				@permission(expression: true, actions: [list])
+			}`,
+		actionName: "listThings",
+		input: map[string]any{
+			"first": 1000000,
+		},
+		expectedTemplate: `
+			SELECT
+				DISTINCT ON("thing"."id") "thing".*, CASE WHEN LEAD("thing"."id") OVER (ORDER BY "thing"."id" ASC) IS NOT NULL THEN true ELSE false END AS hasNext,
+				(SELECT COUNT(DISTINCT "thing"."id") FROM "thing") AS totalCount
+			FROM
+				"thing"
+			ORDER BY
+				"thing"."id" ASC LIMIT ?`,
+		expectedArgs: []any{25},
+	},
+	{
+		name: "list_op_hierarchy_descendants_of",
+		keelSchema: `
+			model Category {
+				fields {
+					name Text
+					parent Category?
+				}
+				actions {
+					list listCategories(name?)
+				}
+				@permission(expression: true, actions: [list])
+			}`,
+		actionName: "listCategories",
+		input: map[string]any{
+			"where": map[string]any{
+				"descendantsOf": map[string]any{
+					"id":    "123",
+					"depth": 2,
+				},
+			},
+		},
+		expectedTemplate: `
+			SELECT
+				DISTINCT ON("category"."id") "category".*, CASE WHEN LEAD("category"."id") OVER (ORDER BY "category"."id" ASC) IS NOT NULL THEN true ELSE false END AS hasNext,
+				(SELECT COUNT(DISTINCT "category"."id") FROM "category" WHERE "category"."id" IN (WITH RECURSIVE "_hierarchy"(id, depth, path) AS (SELECT "_node"."id", 1, ARRAY["_node"."parent_id", "_node"."id"] FROM "category" AS "_node" WHERE "_node"."parent_id" = ? AND "_node"."id" IS NOT NULL UNION ALL SELECT "_node"."id", "_hierarchy"."depth" + 1, "_hierarchy"."path" || "_node"."id" FROM "category" AS "_node" JOIN "_hierarchy" ON "_node"."parent_id" = "_hierarchy"."id" WHERE "_node"."id" IS NOT NULL AND NOT "_node"."id" = ANY("_hierarchy"."path") AND "_hierarchy"."depth" < ?) SELECT id FROM "_hierarchy")) AS totalCount
+			FROM
+				"category"
+			WHERE
+				"category"."id" IN (WITH RECURSIVE "_hierarchy"(id, depth, path) AS (SELECT "_node"."id", 1, ARRAY["_node"."parent_id", "_node"."id"] FROM "category" AS "_node" WHERE "_node"."parent_id" = ? AND "_node"."id" IS NOT NULL UNION ALL SELECT "_node"."id", "_hierarchy"."depth" + 1, "_hierarchy"."path" || "_node"."id" FROM "category" AS "_node" JOIN "_hierarchy" ON "_node"."parent_id" = "_hierarchy"."id" WHERE "_node"."id" IS NOT NULL AND NOT "_node"."id" = ANY("_hierarchy"."path") AND "_hierarchy"."depth" < ?) SELECT id FROM "_hierarchy")
+			ORDER BY
+				"category"."id" ASC LIMIT ?`,
+		expectedArgs: []any{"123", 2, "123", 2, 50},
+	},
+	{
+		name: "list_op_hierarchy_ancestors_of",
+		keelSchema: `
+			model Category {
+				fields {
+					name Text
+					parent Category?
+				}
+				actions {
+					list listCategories(name?)
+				}
+				@permission(expression: true, actions: [list])
+			}`,
+		actionName: "listCategories",
+		input: map[string]any{
+			"where": map[string]any{
+				"ancestorsOf": map[string]any{
+					"id": "123",
+				},
+			},
+		},
+		expectedTemplate: `
+			SELECT
+				DISTINCT ON("category"."id") "category".*, CASE WHEN LEAD("category"."id") OVER (ORDER BY "category"."id" ASC) IS NOT NULL THEN true ELSE false END AS hasNext,
+				(SELECT COUNT(DISTINCT "category"."id") FROM "category" WHERE "category"."id" IN (WITH RECURSIVE "_hierarchy"(id, depth, path) AS (SELECT "_node"."parent_id", 1, ARRAY["_node"."id", "_node"."parent_id"] FROM "category" AS "_node" WHERE "_node"."id" = ? AND "_node"."parent_id" IS NOT NULL UNION ALL SELECT "_node"."parent_id", "_hierarchy"."depth" + 1, "_hierarchy"."path" || "_node"."parent_id" FROM "category" AS "_node" JOIN "_hierarchy" ON "_node"."id" = "_hierarchy"."id" WHERE "_node"."parent_id" IS NOT NULL AND NOT "_node"."parent_id" = ANY("_hierarchy"."path")) SELECT id FROM "_hierarchy")) AS totalCount
+			FROM
+				"category"
+			WHERE
+				"category"."id" IN (WITH RECURSIVE "_hierarchy"(id, depth, path) AS (SELECT "_node"."parent_id", 1, ARRAY["_node"."id", "_node"."parent_id"] FROM "category" AS "_node" WHERE "_node"."id" = ? AND "_node"."parent_id" IS NOT NULL UNION ALL SELECT "_node"."parent_id", "_hierarchy"."depth" + 1, "_hierarchy"."path" || "_node"."parent_id" FROM "category" AS "_node" JOIN "_hierarchy" ON "_node"."id" = "_hierarchy"."id" WHERE "_node"."parent_id" IS NOT NULL AND NOT "_node"."parent_id" = ANY("_hierarchy"."path")) SELECT id FROM "_hierarchy")
+			ORDER BY
+				"category"."id" ASC LIMIT ?`,
+		expectedArgs: []any{"123", "123", 50},
+	},
 	{
 		name: "list_op_implicit_input_text_oneof",
 		keelSchema: `
@@ -1266,6 +1507,215 @@ var testCases = []testCase{
 		identity:     identity,
 		expectedArgs: []any{"xyz", "identityId", true},
 	},
+	{
+		name: "list_op_implicit_input_or_group",
+		keelSchema: `
+			model Thing {
+				fields {
+					first Text
+					second Number
+				}
+				actions {
+					list listThings(first?, second?)
+				}
+				@permission(expression: true, actions: [list])
+			}`,
+		actionName: "listThings",
+		input: map[string]any{
+			"where": map[string]any{
+				"first": map[string]any{
+					"equals": "first"},
+				"or": []any{
+					map[string]any{
+						"first": map[string]any{
+							"equals": "other"}},
+					map[string]any{
+						"second": map[string]any{
+							"greaterThan": int64(5)}}}}},
+		expectedTemplate: `
+			SELECT
+				DISTINCT ON("thing"."id") "thing".*, CASE WHEN LEAD("thing"."id") OVER (ORDER BY "thing"."id" ASC) IS NOT NULL THEN true ELSE false END AS hasNext,
+				(SELECT COUNT(DISTINCT "thing"."id") FROM "thing" WHERE "thing"."first" IS NOT DISTINCT FROM ? AND ( ( "thing"."first" IS NOT DISTINCT FROM ? ) OR ( "thing"."second" > ? ) )) AS totalCount
+			FROM
+				"thing"
+			WHERE
+				"thing"."first" IS NOT DISTINCT FROM ? AND
+				( ( "thing"."first" IS NOT DISTINCT FROM ? ) OR ( "thing"."second" > ? ) )
+			ORDER BY
+				"thing"."id" ASC LIMIT ?`,
+		expectedArgs: []any{"first", "other", int64(5), "first", "other", int64(5), 50},
+	},
+	{
+		name: "list_op_implicit_input_nested_and_group",
+		keelSchema: `
+			model Thing {
+				fields {
+					first Text
+					second Number
+				}
+				actions {
+					list listThings(first?, second?)
+				}
+				@permission(expression: true, actions: [list])
+			}`,
+		actionName: "listThings",
+		input: map[string]any{
+			"where": map[string]any{
+				"or": []any{
+					map[string]any{
+						"and": []any{
+							map[string]any{
+								"first": map[string]any{
+									"equals": "first"}},
+							map[string]any{
+								"second": map[string]any{
+									"equals": int64(1)}}}},
+					map[string]any{
+						"second": map[string]any{
+							"equals": int64(2)}}}}},
+		expectedTemplate: `
+			SELECT
+				DISTINCT ON("thing"."id") "thing".*, CASE WHEN LEAD("thing"."id") OVER (ORDER BY "thing"."id" ASC) IS NOT NULL THEN true ELSE false END AS hasNext,
+				(SELECT COUNT(DISTINCT "thing"."id") FROM "thing" WHERE ( ( ( ( "thing"."first" IS NOT DISTINCT FROM ? ) AND ( "thing"."second" IS NOT DISTINCT FROM ? ) ) ) OR ( "thing"."second" IS NOT DISTINCT FROM ? ) )) AS totalCount
+			FROM
+				"thing"
+			WHERE
+				( ( ( ( "thing"."first" IS NOT DISTINCT FROM ? ) AND ( "thing"."second" IS NOT DISTINCT FROM ? ) ) ) OR ( "thing"."second" IS NOT DISTINCT FROM ? ) )
+			ORDER BY
+				"thing"."id" ASC LIMIT ?`,
+		expectedArgs: []any{"first", int64(1), int64(2), "first", int64(1), int64(2), 50},
+	},
+	{
+		name: "list_op_implicit_input_not_group_and_empty_group",
+		keelSchema: `
+			model Thing {
+				fields {
+					first Text
+					second Number
+				}
+				actions {
+					list listThings(first?, second?)
+				}
+				@permission(expression: true, actions: [list])
+			}`,
+		actionName: "listThings",
+		input: map[string]any{
+			"where": map[string]any{
+				"or": []any{
+					map[string]any{}},
+				"not": map[string]any{
+					"first": map[string]any{
+						"startsWith": "bob"}}}},
+		expectedTemplate: `
+			SELECT
+				DISTINCT ON("thing"."id") "thing".*, CASE WHEN LEAD("thing"."id") OVER (ORDER BY "thing"."id" ASC) IS NOT NULL THEN true ELSE false END AS hasNext,
+				(SELECT COUNT(DISTINCT "thing"."id") FROM "thing" WHERE NOT ( "thing"."first" LIKE ? )) AS totalCount
+			FROM
+				"thing"
+			WHERE
+				NOT ( "thing"."first" LIKE ? )
+			ORDER BY
+				"thing"."id" ASC LIMIT ?`,
+		expectedArgs: []any{"bob%%", "bob%%", 50},
+	},
+	{
+		name: "list_op_implicit_input_or_group_with_empty_group",
+		keelSchema: `
+			model Thing {
+				fields {
+					first Text
+					second Number
+				}
+				actions {
+					list listThings(first?, second?)
+				}
+				@permission(expression: true, actions: [list])
+			}`,
+		actionName: "listThings",
+		input: map[string]any{
+			"where": map[string]any{
+				"first": map[string]any{
+					"equals": "first"},
+				"or": []any{
+					map[string]any{},
+					map[string]any{
+						"second": map[string]any{
+							"greaterThan": int64(5)}}}}},
+		expectedTemplate: `
+			SELECT
+				DISTINCT ON("thing"."id") "thing".*, CASE WHEN LEAD("thing"."id") OVER (ORDER BY "thing"."id" ASC) IS NOT NULL THEN true ELSE false END AS hasNext,
+				(SELECT COUNT(DISTINCT "thing"."id") FROM "thing" WHERE "thing"."first" IS NOT DISTINCT FROM ?) AS totalCount
+			FROM
+				"thing"
+			WHERE
+				"thing"."first" IS NOT DISTINCT FROM ?
+			ORDER BY
+				"thing"."id" ASC LIMIT ?`,
+		expectedArgs: []any{"first", "first", 50},
+	},
+	{
+		name: "list_op_implicit_input_or_group_with_later_empty_group",
+		keelSchema: `
+			model Thing {
+				fields {
+					first Text
+					second Number
+				}
+				actions {
+					list listThings(first?, second?)
+				}
+				@permission(expression: true, actions: [list])
+			}`,
+		actionName: "listThings",
+		input: map[string]any{
+			"where": map[string]any{
+				"second": map[string]any{
+					"equals": int64(1)},
+				"or": []any{
+					map[string]any{
+						"first": map[string]any{
+							"equals": "x"}},
+					map[string]any{}}}},
+		expectedTemplate: `
+			SELECT
+				DISTINCT ON("thing"."id") "thing".*, CASE WHEN LEAD("thing"."id") OVER (ORDER BY "thing"."id" ASC) IS NOT NULL THEN true ELSE false END AS hasNext,
+				(SELECT COUNT(DISTINCT "thing"."id") FROM "thing" WHERE "thing"."second" IS NOT DISTINCT FROM ?) AS totalCount
+			FROM
+				"thing"
+			WHERE
+				"thing"."second" IS NOT DISTINCT FROM ?
+			ORDER BY
+				"thing"."id" ASC LIMIT ?`,
+		expectedArgs: []any{int64(1), int64(1), 50},
+	},
+	{
+		name: "list_op_implicit_input_or_group_with_only_empty_group",
+		keelSchema: `
+			model Thing {
+				fields {
+					first Text
+					second Number
+				}
+				actions {
+					list listThings(first?, second?)
+				}
+				@permission(expression: true, actions: [list])
+			}`,
+		actionName: "listThings",
+		input: map[string]any{
+			"where": map[string]any{
+				"or": []any{
+					map[string]any{}}}},
+		expectedTemplate: `
+			SELECT
+				DISTINCT ON("thing"."id") "thing".*, CASE WHEN LEAD("thing"."id") OVER (ORDER BY "thing"."id" ASC) IS NOT NULL THEN true ELSE false END AS hasNext,
+				(SELECT COUNT(DISTINCT "thing"."id") FROM "thing") AS totalCount
+			FROM
+				"thing"
+			ORDER BY
+				"thing"."id" ASC LIMIT ?`,
+		expectedArgs: []any{50},
+	},
 	{
 		name: "list_op_implicit_input_nested_model_id",
 		keelSchema: `
@@ -3435,6 +3885,63 @@ func TestQueryBuilder(t *testing.T) {
 	}
 }
 
+func TestListMatchesInvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	keelSchema := `
+		model Thing {
+			fields {
+				name Text
+			}
+			actions {
+				list listThings(name)
+			}
+			@permission(expression: true, actions: [list])
+		}`
+
+	for _, pattern := range []string{"(abc", `\bthing`, "(?i)thing"} {
+		scope, query, _, err := generateQueryScope(context.Background(), keelSchema, "listThings")
+		require.NoError(t, err)
+
+		_, _, err = actions.GenerateListStatement(query, scope, map[string]any{
+			"where": map[string]any{
+				"name": map[string]any{
+					"matches": pattern}}})
+
+		var runtimeErr common.RuntimeError
+		require.ErrorAs(t, err, &runtimeErr, pattern)
+		require.Equal(t, common.ErrInvalidInput, runtimeErr.Code, pattern)
+	}
+}
+
+func TestListOffsetPaginationInvalidLimit(t *testing.T) {
+	t.Parallel()
+
+	keelSchema := `
+		model Thing {
+			fields {
+				name Text
+			}
+			actions {
+				list listThings(name?) {
+					@pagination(mode: offset)
+				}
+			}
+			@permission(expression: true, actions: [list])
+		}`
+
+	for _, input := range []map[string]any{{"limit": 0}, {"limit": -1}, {"offset": -1}} {
+		scope, query, _, err := generateQueryScope(context.Background(), keelSchema, "listThings")
+		require.NoError(t, err)
+
+		_, _, err = actions.GenerateListStatement(query, scope, input)
+
+		var runtimeErr common.RuntimeError
+		require.ErrorAs(t, err, &runtimeErr, input)
+		require.Equal(t, common.ErrInvalidInput, runtimeErr.Code, input)
+	}
+}
+
 // Generates a scope and query builder
 func generateQueryScope(ctx context.Context, schemaString string, actionName string) (*actions.Scope, *actions.QueryBuilder, *proto.Action, error) {
 	builder := &schema.Builder{}
@@ -3637,15 +4144,44 @@ func TestDeleteStatementNoReturnWithAuditing(t *testing.T) {
 	require.Equal(t, "71f835dc7ac2750bed2135c7b30dc7fe", stmt.SqlArgs()[2])
 }
 
+func TestUpdateStatementWithImpersonationAuditing(t *testing.T) {
+	ctx := context.Background()
+	ctx = withIdentity(ctx)
+	ctx = auth.WithImpersonator(ctx, impersonatorId)
+	ctx = withTracing(t, ctx)
+
+	model := &proto.Model{Name: "Person"}
+	query := actions.NewQuery(model)
+	query.AddWriteValue(actions.Field("name"), actions.Value("Fred"))
+	err := query.Where(actions.IdField(), actions.Equals, actions.Value("1234"))
+	require.NoError(t, err)
+	query.Select(actions.AllFields())
+	stmt := query.UpdateStatement(ctx)
+
+	expected := `
+		UPDATE "person" SET name = ? WHERE "person"."id" IS NOT DISTINCT FROM ? RETURNING
+			set_identity_id(?) AS __keel_identity_id,
+			set_impersonator_id(?) AS __keel_impersonator_id,
+			set_trace_id(?) AS __keel_trace_id`
+
+	require.Equal(t, clean(expected), clean(stmt.SqlTemplate()))
+	require.Equal(t, "Fred", stmt.SqlArgs()[0])
+	require.Equal(t, "1234", stmt.SqlArgs()[1])
+	require.Equal(t, identityId, stmt.SqlArgs()[2])
+	require.Equal(t, impersonatorId, stmt.SqlArgs()[3])
+	require.Equal(t, traceId, stmt.SqlArgs()[4])
+}
+
 func withIdentity(ctx context.Context) context.Context {
 	identity := auth.Identity{"id": identityId}
 	return auth.WithIdentity(ctx, identity)
 }
 
 const (
-	identityId = "2V1gEtq4GEhvtRofqwiN9ZfapxN"
-	traceId    = "71f835dc7ac2750bed2135c7b30dc7fe"
-	spanId     = "b4c9e2a6a0d84702"
+	identityId     = "2V1gEtq4GEhvtRofqwiN9ZfapxN"
+	impersonatorId = "2V1gF0Mf8Tq8QbuVwu1GgmDJpLt"
+	traceId        = "71f835dc7ac2750bed2135c7b30dc7fe"
+	spanId         = "b4c9e2a6a0d84702"
 )
 
 func withTracing(t *testing.T, ctx context.Context) context.Context {
@@ -3687,3 +4223,52 @@ func TestParseArray(t *testing.T) {
 		assert.Equal(t, testCase.out, res)
 	}
 }
+
+func TestEmbedStatement(t *testing.T) {
+	t.Parallel()
+
+	schemaString := `
+		model Author {
+			fields {
+				name Text
+				posts Post[]
+			}
+		}
+		model Post {
+			fields {
+				title Text
+				author Author
+			}
+		}`
+
+	builder := &schema.Builder{}
+	s, err := builder.MakeFromString(schemaString, config.Empty)
+	require.NoError(t, err)
+
+	post := s.FindModel("Post")
+	author := s.FindModel("Author")
+
+	stmt, err := actions.GenerateEmbedStatement(s, post, proto.FindField(s.Models, "Post", "author"), []string{"post_1", "post_2"})
+	require.NoError(t, err)
+
+	expected := `
+		SELECT "author".*, "_source"."id" AS "_embed_source_id"
+		FROM "author"
+		LEFT JOIN "post" AS "_source" ON "_source"."author_id" = "author"."id"
+		WHERE "_source"."id" = ANY(ARRAY[?, ?]::TEXT[])`
+
+	require.Equal(t, clean(expected), clean(stmt.SqlTemplate()))
+	require.Equal(t, []any{"post_1", "post_2"}, stmt.SqlArgs())
+
+	stmt, err = actions.GenerateEmbedStatement(s, author, proto.FindField(s.Models, "Author", "posts"), []string{"author_1", "author_2"})
+	require.NoError(t, err)
+
+	expected = `
+		SELECT "post".*, "_source"."id" AS "_embed_source_id"
+		FROM "post"
+		LEFT JOIN "author" AS "_source" ON "_source"."id" = "post"."author_id"
+		WHERE "_source"."id" = ANY(ARRAY[?, ?]::TEXT[])`
+
+	require.Equal(t, clean(expected), clean(stmt.SqlTemplate()))
+	require.Equal(t, []any{"author_1", "author_2"}, stmt.SqlArgs())
+}
@@ -0,0 +1,265 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/karlseguin/typed"
+	"github.com/samber/lo"
+	"github.com/teamkeel/keel/db"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/auth"
+	"github.com/teamkeel/keel/runtime/common"
+	"github.com/teamkeel/keel/schema/parser"
+)
+
+type roleMembershipRow struct {
+	Id         string
+	IdentityId string
+	Role       string
+	Model      *string
+	RecordId   *string
+	CreatedAt  time.Time
+}
+
+func (r *roleMembershipRow) toMap() map[string]any {
+	return map[string]any{
+		"id":         r.Id,
+		"identityId": r.IdentityId,
+		"role":       r.Role,
+		"model":      r.Model,
+		"recordId":   r.RecordId,
+		"createdAt":  r.CreatedAt,
+	}
+}
+
+// FindRoleMemberships returns the roles which have been assigned to the identity at runtime.
+func FindRoleMemberships(ctx context.Context, identityId string) ([]*auth.RoleMembership, error) {
+	rows, err := findRoleMembershipRows(ctx, identityId, "")
+	if err != nil {
+		return nil, err
+	}
+
+	memberships := make([]*auth.RoleMembership, len(rows))
+	for i, row := range rows {
+		memberships[i] = &auth.RoleMembership{Role: row.Role}
+		if row.Model != nil && row.RecordId != nil {
+			memberships[i].Model = *row.Model
+			memberships[i].RecordId = *row.RecordId
+		}
+	}
+
+	return memberships, nil
+}
+
+// ListRoleMemberships lists the roles assigned to an identity, optionally filtered by role. An identity can list its own roles,
+// but listing the roles of another identity, or all identities with a role, requires one of the configured admin roles.
+func ListRoleMemberships(scope *Scope, input map[string]any) (map[string]any, error) {
+	typedInput := typed.New(input)
+	identityId := typedInput.String("identityId")
+	role := typedInput.String("role")
+
+	isSelf := false
+	if auth.IsAuthenticated(scope.Context) {
+		identity, err := auth.GetIdentity(scope.Context)
+		if err != nil {
+			return nil, err
+		}
+
+		if identityId == "" && role == "" {
+			identityId = identity[parser.FieldNameId].(string)
+		}
+		isSelf = identityId == identity[parser.FieldNameId].(string)
+	}
+
+	if !isSelf {
+		isAdmin, err := hasAdminRole(scope)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isAdmin {
+			return nil, common.NewPermissionError()
+		}
+	}
+
+	if identityId == "" && role == "" {
+		return nil, common.RuntimeError{Code: common.ErrInvalidInput, Message: "identityId or role is required when not authenticated as an identity"}
+	}
+
+	rows, err := findRoleMembershipRows(scope.Context, identityId, role)
+	if err != nil {
+		return nil, err
+	}
+
+	results := lo.Map(rows, func(row *roleMembershipRow, _ int) map[string]any {
+		return row.toMap()
+	})
+
+	return map[string]any{"memberships": results}, nil
+}
+
+// AssignRole assigns a role to an identity, optionally only for a single record. Requires one of the configured admin roles.
+// Assigning a role which the identity already has returns the existing role membership.
+func AssignRole(scope *Scope, input map[string]any) (map[string]any, error) {
+	typedInput := typed.New(input)
+
+	membership, err := roleMembershipFromInput(scope, typedInput)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := FindIdentityById(scope.Context, scope.Schema, typedInput.String("identityId"))
+	if err != nil {
+		return nil, err
+	}
+
+	if identity == nil {
+		return nil, common.NewNotFoundError("identity not found")
+	}
+
+	database, err := db.GetDatabase(scope.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := `
+		INSERT INTO
+			keel_role_membership (identity_id, role, model, record_id, created_at)
+		VALUES
+			(?, ?, ?, ?, ?)
+		ON CONFLICT (identity_id, role, (COALESCE(model, '')), (COALESCE(record_id, ''))) DO UPDATE SET
+			role = EXCLUDED.role
+		RETURNING
+			id, identity_id, role, model, record_id, created_at`
+
+	rows := []*roleMembershipRow{}
+	err = database.GetDB().Raw(sql, typedInput.String("identityId"), membership.Role, nullIfEmpty(membership.Model), nullIfEmpty(membership.RecordId), time.Now().UTC()).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) != 1 {
+		return nil, fmt.Errorf("expected one role membership, but got %d", len(rows))
+	}
+
+	return rows[0].toMap(), nil
+}
+
+// RevokeRole removes a role from an identity. Requires one of the configured admin roles.
+func RevokeRole(scope *Scope, input map[string]any) error {
+	typedInput := typed.New(input)
+
+	membership, err := roleMembershipFromInput(scope, typedInput)
+	if err != nil {
+		return err
+	}
+
+	database, err := db.GetDatabase(scope.Context)
+	if err != nil {
+		return err
+	}
+
+	sql := `
+		DELETE FROM
+			keel_role_membership
+		WHERE
+			identity_id = ? AND role = ? AND COALESCE(model, '') = ? AND COALESCE(record_id, '') = ?`
+
+	result := database.GetDB().Exec(sql, typedInput.String("identityId"), membership.Role, membership.Model, membership.RecordId)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return common.NewNotFoundError("role membership not found")
+	}
+
+	return nil
+}
+
+// roleMembershipFromInput authorises the caller to manage roles and validates the role membership provided in the input.
+func roleMembershipFromInput(scope *Scope, input typed.Typed) (*auth.RoleMembership, error) {
+	isAdmin, err := hasAdminRole(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAdmin {
+		return nil, common.NewPermissionError()
+	}
+
+	membership := &auth.RoleMembership{
+		Role:     input.String("role"),
+		Model:    input.String("model"),
+		RecordId: input.String("recordId"),
+	}
+
+	if proto.FindRole(membership.Role, scope.Schema) == nil {
+		return nil, common.RuntimeError{Code: common.ErrInvalidInput, Message: fmt.Sprintf("role '%s' does not exist", membership.Role)}
+	}
+
+	if membership.Model != "" && scope.Schema.FindModel(membership.Model) == nil {
+		return nil, common.RuntimeError{Code: common.ErrInvalidInput, Message: fmt.Sprintf("model '%s' does not exist", membership.Model)}
+	}
+
+	if (membership.Model == "") != (membership.RecordId == "") {
+		return nil, common.RuntimeError{Code: common.ErrInvalidInput, Message: "model and recordId must be provided together"}
+	}
+
+	return membership, nil
+}
+
+func findRoleMembershipRows(ctx context.Context, identityId string, role string) ([]*roleMembershipRow, error) {
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := `
+		SELECT
+			id, identity_id, role, model, record_id, created_at
+		FROM
+			keel_role_membership
+		WHERE
+			(? = '' OR identity_id = ?) AND (? = '' OR role = ?)
+		ORDER BY
+			created_at`
+
+	rows := []*roleMembershipRow{}
+	err = database.GetDB().Raw(sql, identityId, identityId, role, role).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// roleScopedRecordIds returns the ids of the records of the model for which the authenticated identity has
+// been assigned one of the roles of the role-based permission rules.
+func roleScopedRecordIds(ctx context.Context, model *proto.Model, permissions []*proto.PermissionRule) []string {
+	if model == nil {
+		return nil
+	}
+
+	roleNames := lo.FlatMap(permissions, func(p *proto.PermissionRule, _ int) []string {
+		return p.RoleNames
+	})
+
+	ids := []string{}
+	for _, membership := range auth.GetRoleMemberships(ctx) {
+		if membership.Model == model.Name && lo.Contains(roleNames, membership.Role) {
+			ids = append(ids, membership.RecordId)
+		}
+	}
+
+	return lo.Uniq(ids)
+}
+
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
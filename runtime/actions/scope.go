@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/samber/lo"
 	"github.com/teamkeel/keel/events"
 	"github.com/teamkeel/keel/functions"
 	"github.com/teamkeel/keel/proto"
@@ -17,8 +18,23 @@ import (
 var tracer = otel.Tracer("github.com/teamkeel/keel/runtime/actions")
 
 const (
-	requestPasswordResetActionName = "requestPasswordReset"
-	passwordResetActionName        = "resetPassword"
+	requestPasswordResetActionName     = "requestPasswordReset"
+	passwordResetActionName            = "resetPassword"
+	requestEmailVerificationActionName = "requestEmailVerification"
+	verifyEmailActionName              = "verifyEmail"
+	listSessionsActionName             = "listSessions"
+	revokeSessionActionName            = "revokeSession"
+	revokeAllSessionsActionName        = "revokeAllSessions"
+	listRoleMembershipsActionName      = "listRoleMemberships"
+	assignRoleActionName               = "assignRole"
+	revokeRoleActionName               = "revokeRole"
+	listIdentitiesActionName           = "listIdentities"
+	disableIdentityActionName          = "disableIdentity"
+	enableIdentityActionName           = "enableIdentity"
+	forcePasswordResetActionName       = "forcePasswordReset"
+	deleteIdentityActionName           = "deleteIdentity"
+	exportIdentityDataActionName       = "exportIdentityData"
+	eraseIdentityDataActionName        = "eraseIdentityData"
 )
 
 type Scope struct {
@@ -190,6 +206,17 @@ func executeCustomFunction(scope *Scope, inputs any) (any, *common.ResponseMetad
 	// pagination
 	if scope.Action.Type == proto.ActionType_ACTION_TYPE_LIST {
 		results, _ := resp.([]any)
+
+		rows := lo.FilterMap(results, func(result any, _ int) (map[string]any, bool) {
+			row, ok := result.(map[string]any)
+			return row, ok
+		})
+
+		err = ApplyFieldReadPermissions(scope, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+
 		return map[string]any{
 			"results": results,
 			"pageInfo": map[string]any{
@@ -205,6 +232,14 @@ func executeCustomFunction(scope *Scope, inputs any) (any, *common.ResponseMetad
 		}, m, nil
 	}
 
+	// The records returned by get, create and update functions are subject to the model's field read permissions
+	if row, ok := resp.(map[string]any); ok && scope.Action.Type != proto.ActionType_ACTION_TYPE_READ && scope.Action.Type != proto.ActionType_ACTION_TYPE_WRITE {
+		err = ApplyFieldReadPermissions(scope, []map[string]any{row})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	return resp, m, err
 }
 
@@ -216,6 +251,43 @@ func executeRuntimeAction(scope *Scope, inputs map[string]any) (any, error) {
 	case passwordResetActionName:
 		err := ResetPassword(scope, inputs)
 		return map[string]any{}, err
+	case requestEmailVerificationActionName:
+		err := RequestEmailVerification(scope, inputs)
+		return map[string]any{}, err
+	case verifyEmailActionName:
+		err := VerifyEmail(scope, inputs)
+		return map[string]any{}, err
+	case listSessionsActionName:
+		return ListSessions(scope, inputs)
+	case revokeSessionActionName:
+		err := RevokeSession(scope, inputs)
+		return map[string]any{}, err
+	case revokeAllSessionsActionName:
+		err := RevokeAllSessions(scope, inputs)
+		return map[string]any{}, err
+	case listRoleMembershipsActionName:
+		return ListRoleMemberships(scope, inputs)
+	case assignRoleActionName:
+		return AssignRole(scope, inputs)
+	case revokeRoleActionName:
+		err := RevokeRole(scope, inputs)
+		return map[string]any{}, err
+	case listIdentitiesActionName:
+		return ListIdentities(scope, inputs)
+	case disableIdentityActionName:
+		return DisableIdentity(scope, inputs)
+	case enableIdentityActionName:
+		return EnableIdentity(scope, inputs)
+	case forcePasswordResetActionName:
+		err := ForcePasswordReset(scope, inputs)
+		return map[string]any{}, err
+	case deleteIdentityActionName:
+		err := DeleteIdentity(scope, inputs)
+		return map[string]any{}, err
+	case exportIdentityDataActionName:
+		return ExportIdentityData(scope, inputs)
+	case eraseIdentityDataActionName:
+		return EraseIdentityData(scope, inputs)
 	default:
 		return nil, fmt.Errorf("unhandled runtime action: %s", scope.Action.Name)
 	}
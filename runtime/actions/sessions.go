@@ -0,0 +1,145 @@
+package actions
+
+import (
+	"github.com/karlseguin/typed"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/auth"
+	"github.com/teamkeel/keel/runtime/common"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+	"github.com/teamkeel/keel/schema/parser"
+)
+
+// ListSessions lists the signed in sessions of the current identity, or of another identity if
+// the caller has one of the configured admin roles.
+func ListSessions(scope *Scope, input map[string]any) (map[string]any, error) {
+	identityId, err := sessionsIdentityId(scope, typed.New(input))
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := oauth.ListSessions(scope.Context, identityId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]any, len(sessions))
+	for i, session := range sessions {
+		result := map[string]any{
+			"id":         session.Id,
+			"createdAt":  session.CreatedAt,
+			"lastUsedAt": nil,
+			"expiresAt":  session.ExpiresAt,
+			"userAgent":  nil,
+			"ipAddress":  nil,
+			"mfa":        session.Mfa,
+		}
+
+		if session.LastUsedAt != nil {
+			result["lastUsedAt"] = *session.LastUsedAt
+		}
+		if session.UserAgent != "" {
+			result["userAgent"] = session.UserAgent
+		}
+		if session.IpAddress != "" {
+			result["ipAddress"] = session.IpAddress
+		}
+
+		results[i] = result
+	}
+
+	return map[string]any{"sessions": results}, nil
+}
+
+// RevokeSession revokes a single session of the current identity, or of another identity if
+// the caller has one of the configured admin roles.
+func RevokeSession(scope *Scope, input map[string]any) error {
+	// An impersonator cannot sign the identity out
+	if auth.IsImpersonated(scope.Context) {
+		return common.NewPermissionError()
+	}
+
+	typedInput := typed.New(input)
+
+	identityId, err := sessionsIdentityId(scope, typedInput)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := oauth.RevokeSession(scope.Context, identityId, typedInput.String("id"))
+	if err != nil {
+		return err
+	}
+
+	if !revoked {
+		return common.NewNotFoundError("session not found")
+	}
+
+	return nil
+}
+
+// RevokeAllSessions signs the current identity out everywhere, or another identity if
+// the caller has one of the configured admin roles.
+func RevokeAllSessions(scope *Scope, input map[string]any) error {
+	// An impersonator cannot sign the identity out
+	if auth.IsImpersonated(scope.Context) {
+		return common.NewPermissionError()
+	}
+
+	identityId, err := sessionsIdentityId(scope, typed.New(input))
+	if err != nil {
+		return err
+	}
+
+	_, err = oauth.RevokeAllSessions(scope.Context, identityId)
+	return err
+}
+
+// sessionsIdentityId determines whose sessions are being managed. An identity can always manage its own
+// sessions, but managing those of another identity requires one of the configured admin roles.
+func sessionsIdentityId(scope *Scope, input typed.Typed) (string, error) {
+	targetId := input.String("identityId")
+
+	if auth.IsAuthenticated(scope.Context) {
+		identity, err := auth.GetIdentity(scope.Context)
+		if err != nil {
+			return "", err
+		}
+
+		identityId := identity[parser.FieldNameId].(string)
+		if targetId == "" || targetId == identityId {
+			return identityId, nil
+		}
+	} else if !auth.IsClient(scope.Context) {
+		return "", common.NewPermissionError()
+	}
+
+	if targetId == "" {
+		return "", common.RuntimeError{Code: common.ErrInvalidInput, Message: "identityId is required when not authenticated as an identity"}
+	}
+
+	isAdmin, err := hasAdminRole(scope)
+	if err != nil {
+		return "", err
+	}
+
+	if !isAdmin {
+		return "", common.NewPermissionError()
+	}
+
+	return targetId, nil
+}
+
+// hasAdminRole determines if the caller has one of the admin roles configured with auth.adminRoles.
+func hasAdminRole(scope *Scope) (bool, error) {
+	config, err := runtimectx.GetOAuthConfig(scope.Context)
+	if err != nil {
+		return false, err
+	}
+
+	if len(config.AdminRoles) == 0 {
+		return false, nil
+	}
+
+	return resolveRolePermissionRule(scope.Context, scope.Schema, &proto.PermissionRule{RoleNames: config.AdminRoles})
+}
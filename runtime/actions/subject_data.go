@@ -0,0 +1,433 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/samber/lo"
+	"github.com/teamkeel/keel/casing"
+	"github.com/teamkeel/keel/db"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/auth"
+	"github.com/teamkeel/keel/runtime/common"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/schema/parser"
+)
+
+// redactedValue replaces the value of required @pii fields when erasing an identity's data.
+const redactedValue = "[redacted]"
+
+// SubjectErasure describes the records affected when erasing an identity's data.
+type SubjectErasure struct {
+	// Deleted is the number of records of @pii models which were deleted
+	Deleted int
+	// Anonymised is the number of records which had the values of their @pii fields removed
+	Anonymised int
+}
+
+// subjectRecords are the records of a model which are owned by an identity.
+type subjectRecords struct {
+	model *proto.Model
+	rows  []map[string]any
+	// extendsIdentity is true when the records have a one-to-one relationship with the identity, or with
+	// another record which does, and so the records which reference them are owned by the identity too.
+	extendsIdentity bool
+}
+
+// ExportSubjectData exports the identity and every record which the identity owns. Password fields are not exported.
+func ExportSubjectData(ctx context.Context, schema *proto.Schema, identityId string) (map[string]any, error) {
+	return exportSubjectData(ctx, schema, identityId, false)
+}
+
+// exportSubjectData exports the identity and the records it owns. When applyPermissions is true, only the records
+// which the identity in the context is permitted to get are exported, with the fields it cannot read set to null.
+func exportSubjectData(ctx context.Context, schema *proto.Schema, identityId string, applyPermissions bool) (map[string]any, error) {
+	ctx, span := tracer.Start(ctx, "Export Subject Data")
+	defer span.End()
+
+	records, err := findSubjectRecords(ctx, schema, identityId)
+	if err != nil {
+		return nil, err
+	}
+
+	export := map[string]any{}
+	for _, r := range records[1:] {
+		rows := r.rows
+		if applyPermissions {
+			rows, err = readableRecords(ctx, schema, r.model, rows)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if len(rows) == 0 {
+			continue
+		}
+
+		rows = lo.Map(rows, func(row map[string]any, _ int) map[string]any {
+			return withoutPasswords(r.model, row)
+		})
+
+		if existing, ok := export[r.model.Name].([]map[string]any); ok {
+			rows = append(existing, rows...)
+		}
+		export[r.model.Name] = rows
+	}
+
+	return map[string]any{
+		"identity": withoutPasswords(records[0].model, records[0].rows[0]),
+		"records":  export,
+	}, nil
+}
+
+// EraseSubjectData erases the personal data of the identity and of every record which the identity owns.
+// Records of models marked with @pii are deleted, and the values of fields marked with @pii are removed from the
+// records of other models. Records of @pii models which are referenced by a required relationship are anonymised
+// instead, as deleting them would cascade to the records which reference them. The identity itself is anonymised
+// rather than deleted, so that records which are kept still reference it, and it is disabled with its sessions,
+// MFA factors, linked identities and roles removed. The snapshots of the erased data in the audit log are scrubbed as well.
+func EraseSubjectData(ctx context.Context, schema *proto.Schema, identityId string) (*SubjectErasure, error) {
+	ctx, span := tracer.Start(ctx, "Erase Subject Data")
+	defer span.End()
+
+	records, err := findSubjectRecords(ctx, schema, identityId)
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	erasure := &SubjectErasure{}
+
+	err = database.Transaction(ctx, func(ctx context.Context) error {
+		// Records are erased in the reverse order they were found, so that records are
+		// deleted before the records they reference.
+		for _, r := range lo.Reverse(records[1:]) {
+			ids := lo.Map(r.rows, func(row map[string]any, _ int) string {
+				return row[parser.FieldNameId].(string)
+			})
+
+			switch {
+			case r.model.Pii && deleteWouldCascade(schema, r.model):
+				affected, err := anonymiseRecords(ctx, r.model, ids, anonymisedValues(r.model, r.model.Fields))
+				if err != nil {
+					return err
+				}
+				erasure.Anonymised += affected
+			case r.model.Pii:
+				query := NewQuery(r.model)
+				err := query.Where(IdField(), OneOf, Value(ids))
+				if err != nil {
+					return err
+				}
+
+				affected, err := query.DeleteStatement(ctx).Execute(ctx)
+				if err != nil {
+					return err
+				}
+				erasure.Deleted += affected
+
+				_, err = database.ExecuteStatement(ctx, "UPDATE keel_audit SET data = jsonb_build_object('id', data->'id') WHERE table_name = ? AND data->>'id' = ANY(?)", casing.ToSnake(r.model.Name), ids)
+				if err != nil {
+					return err
+				}
+			case len(r.model.PiiFields()) > 0:
+				affected, err := anonymiseRecords(ctx, r.model, ids, anonymisedValues(r.model, r.model.PiiFields()))
+				if err != nil {
+					return err
+				}
+				erasure.Anonymised += affected
+			}
+		}
+
+		identityModel := records[0].model
+
+		values := map[string]*QueryOperand{
+			parser.IdentityFieldNameEmailVerified: Value(false),
+		}
+		for _, f := range identityModel.Fields {
+			if f.Optional && f.Type.Type != proto.Type_TYPE_MODEL && f.Name != parser.IdentityFieldNameIssuer {
+				values[f.Name] = Null()
+			}
+		}
+
+		_, err := anonymiseRecords(ctx, identityModel, []string{identityId}, values)
+		if err != nil {
+			return err
+		}
+
+		for _, table := range identityDataTables {
+			_, err = database.ExecuteStatement(ctx, "DELETE FROM "+table+" WHERE identity_id = ?", identityId)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = oauth.DisableIdentity(ctx, identityId)
+	if err != nil {
+		return nil, err
+	}
+
+	return erasure, nil
+}
+
+// anonymiseRecords updates the records with the anonymised values, and removes the original values of those
+// fields from the snapshots of the records in the audit log.
+func anonymiseRecords(ctx context.Context, model *proto.Model, ids []string, values map[string]*QueryOperand) (int, error) {
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	query := NewQuery(model)
+	err := query.Where(IdField(), OneOf, Value(ids))
+	if err != nil {
+		return 0, err
+	}
+
+	query.AddWriteValues(values)
+
+	affected, err := query.UpdateStatement(ctx).Execute(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	columns := lo.Map(lo.Keys(values), func(f string, _ int) string {
+		return casing.ToSnake(f)
+	})
+
+	_, err = database.ExecuteStatement(ctx, "UPDATE keel_audit SET data = data - ?::text[] WHERE table_name = ? AND data->>'id' = ANY(?)", columns, casing.ToSnake(model.Name), ids)
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+// anonymisedValues returns the values which anonymise the given fields of the model. Optional fields are set
+// to null and required text fields are redacted, but required fields of other types, required unique fields,
+// relationships and the built-in fields cannot be anonymised and so keep their values.
+func anonymisedValues(model *proto.Model, fields []*proto.Field) map[string]*QueryOperand {
+	values := map[string]*QueryOperand{}
+	for _, f := range fields {
+		switch {
+		case f.Type.Type == proto.Type_TYPE_MODEL || f.ForeignKeyInfo != nil:
+			continue
+		case f.Name == parser.FieldNameId || f.Name == parser.FieldNameCreatedAt || f.Name == parser.FieldNameUpdatedAt:
+			continue
+		case f.Optional:
+			values[f.Name] = Null()
+		case !f.Unique && (f.Type.Type == proto.Type_TYPE_STRING || f.Type.Type == proto.Type_TYPE_MARKDOWN):
+			values[f.Name] = Value(redactedValue)
+		}
+	}
+	return values
+}
+
+// deleteWouldCascade reports whether deleting records of the model would also delete the records of other models
+// which reference them, which is the case for any required relationship to the model.
+func deleteWouldCascade(schema *proto.Schema, model *proto.Model) bool {
+	for _, m := range schema.Models {
+		for _, fk := range m.ForeignKeyFields() {
+			if fk.ForeignKeyInfo.RelatedModelName == model.Name && !fk.Optional {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readableRecords returns the records which the identity in the context is permitted to get, according to the
+// model's permission rules, with the values of any fields it is not permitted to read set to null.
+func readableRecords(ctx context.Context, schema *proto.Schema, model *proto.Model, rows []map[string]any) ([]map[string]any, error) {
+	scope := NewModelScope(ctx, model, schema)
+
+	ids := lo.Map(rows, func(row map[string]any, _ int) string {
+		return row[parser.FieldNameId].(string)
+	})
+
+	authorisedIds, err := authorisedRowIds(scope, proto.PermissionsForActionType(schema, model.Name, proto.ActionType_ACTION_TYPE_GET), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	rows = lo.Filter(rows, func(row map[string]any, _ int) bool {
+		return lo.Contains(authorisedIds, row[parser.FieldNameId].(string))
+	})
+
+	err = ApplyFieldReadPermissions(scope, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// findSubjectRecords finds the identity and then every record which the identity owns. A record is owned by the
+// identity when it references the identity directly, or when it references a record which has a one-to-one
+// relationship with the identity, such as a profile or customer record. Records which are only related through
+// shared records, such as other members of a team the identity belongs to, are not owned by the identity.
+// The identity's records are always first, and each model's records appear after the records they reference.
+func findSubjectRecords(ctx context.Context, schema *proto.Schema, identityId string) ([]*subjectRecords, error) {
+	identity, err := FindIdentityById(ctx, schema, identityId)
+	if err != nil {
+		return nil, err
+	}
+
+	if identity == nil {
+		return nil, common.NewNotFoundError("identity not found")
+	}
+
+	records := []*subjectRecords{{model: schema.FindModel(parser.IdentityModelName), rows: []map[string]any{identity}, extendsIdentity: true}}
+	found := map[string]map[string]bool{
+		parser.IdentityModelName: {identityId: true},
+	}
+
+	// Each batch of records which extend the identity is used to find the records which reference them,
+	// until no new records are found.
+	for next := 0; next < len(records); next++ {
+		referenced := records[next]
+		if !referenced.extendsIdentity {
+			continue
+		}
+
+		for _, model := range schema.Models {
+			if model.Name == parser.IdentityModelName {
+				continue
+			}
+
+			for _, fk := range model.ForeignKeyFields() {
+				if fk.ForeignKeyInfo.RelatedModelName != referenced.model.Name {
+					continue
+				}
+
+				values := lo.Uniq(lo.FilterMap(referenced.rows, func(row map[string]any, _ int) (any, bool) {
+					value, ok := row[fk.ForeignKeyInfo.RelatedModelField]
+					return value, ok && value != nil
+				}))
+
+				if len(values) == 0 {
+					continue
+				}
+
+				query := NewQuery(model)
+				err := query.Where(Field(fk.Name), OneOf, Value(values))
+				if err != nil {
+					return nil, err
+				}
+				query.Select(AllFields())
+
+				rows, _, err := query.SelectStatement().ExecuteToMany(ctx, nil)
+				if err != nil {
+					return nil, err
+				}
+
+				if found[model.Name] == nil {
+					found[model.Name] = map[string]bool{}
+				}
+
+				rows = lo.Filter(rows, func(row map[string]any, _ int) bool {
+					id := row[parser.FieldNameId].(string)
+					isNew := !found[model.Name][id]
+					found[model.Name][id] = true
+					return isNew
+				})
+
+				if len(rows) > 0 {
+					records = append(records, &subjectRecords{model: model, rows: rows, extendsIdentity: fk.Unique})
+				}
+			}
+		}
+	}
+
+	return records, nil
+}
+
+// withoutPasswords returns a copy of the row without the values of any password fields.
+func withoutPasswords(model *proto.Model, row map[string]any) map[string]any {
+	return lo.OmitBy(row, func(key string, _ any) bool {
+		field := proto.FindField([]*proto.Model{model}, model.Name, key)
+		return field != nil && field.Type.Type == proto.Type_TYPE_PASSWORD
+	})
+}
+
+// subjectIdentityId determines whose data is being exported or erased. Exporting an identity's own data is always
+// permitted, but otherwise one of the configured admin roles is required.
+func subjectIdentityId(scope *Scope, identityId string, allowSelf bool) (string, error) {
+	if allowSelf && auth.IsAuthenticated(scope.Context) {
+		identity, err := auth.GetIdentity(scope.Context)
+		if err != nil {
+			return "", err
+		}
+
+		if identityId == "" || identityId == identity[parser.FieldNameId].(string) {
+			return identity[parser.FieldNameId].(string), nil
+		}
+	}
+
+	if identityId == "" {
+		return "", common.RuntimeError{Code: common.ErrInvalidInput, Message: "identityId is required"}
+	}
+
+	_, err := findIdentityToAdminister(scope, identityId, !allowSelf)
+	if err != nil {
+		return "", err
+	}
+
+	return identityId, nil
+}
+
+// ExportIdentityData exports the data of an identity. An identity can export its own data, limited to the records
+// and fields it is permitted to read, but exporting the data of another identity requires one of the configured admin roles.
+func ExportIdentityData(scope *Scope, input map[string]any) (map[string]any, error) {
+	identityId, _ := input["identityId"].(string)
+
+	identityId, err := subjectIdentityId(scope, identityId, true)
+	if err != nil {
+		return nil, err
+	}
+
+	self := false
+	if auth.IsAuthenticated(scope.Context) {
+		identity, err := auth.GetIdentity(scope.Context)
+		if err != nil {
+			return nil, err
+		}
+		self = identity[parser.FieldNameId].(string) == identityId
+	}
+
+	return exportSubjectData(scope.Context, scope.Schema, identityId, self)
+}
+
+// EraseIdentityData erases the personal data of an identity. Requires one of the configured admin roles,
+// and an identity cannot erase its own data.
+func EraseIdentityData(scope *Scope, input map[string]any) (map[string]any, error) {
+	identityId, _ := input["identityId"].(string)
+
+	identityId, err := subjectIdentityId(scope, identityId, false)
+	if err != nil {
+		return nil, err
+	}
+
+	erasure, err := EraseSubjectData(scope.Context, scope.Schema, identityId)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"deleted":    erasure.Deleted,
+		"anonymised": erasure.Anonymised,
+	}, nil
+}
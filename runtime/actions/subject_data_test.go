@@ -0,0 +1,218 @@
+package actions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/runtime/actions"
+	"github.com/teamkeel/keel/runtime/auth"
+	"github.com/teamkeel/keel/schema/parser"
+	keeltesting "github.com/teamkeel/keel/testing"
+)
+
+const subjectDataSchema = `
+	model Team {
+		fields {
+			name Text
+			owner Identity
+		}
+	}
+
+	model Membership {
+		fields {
+			identity Identity
+			team Team
+		}
+		@permission(expression: membership.identity == ctx.identity, actions: [get])
+	}
+
+	model TeamNote {
+		fields {
+			text Text
+			team Team
+		}
+		@pii
+	}
+
+	model Profile {
+		fields {
+			identity Identity @unique
+			bio Text? @pii
+		}
+	}
+
+	model Address {
+		fields {
+			line1 Text
+			profile Profile
+		}
+		@pii
+	}
+
+	model Contact {
+		fields {
+			phone Text
+			name Text?
+			profile Profile
+		}
+		@pii
+	}
+
+	model PhoneCall {
+		fields {
+			contact Contact
+		}
+	}`
+
+func exportedIds(t *testing.T, export map[string]any, model string) []string {
+	records, ok := export["records"].(map[string]any)
+	require.True(t, ok)
+
+	rows, _ := records[model].([]map[string]any)
+	ids := []string{}
+	for _, row := range rows {
+		ids = append(ids, row[parser.FieldNameId].(string))
+	}
+	return ids
+}
+
+func TestSubjectData_ExportsOnlyOwnedRecords(t *testing.T) {
+	ctx, database, s := keeltesting.MakeContext(t, context.Background(), subjectDataSchema, true)
+	defer database.Close()
+
+	alice, err := actions.CreateIdentity(ctx, s, "alice@keel.xyz", "1234", "https://keel.so")
+	require.NoError(t, err)
+	bob, err := actions.CreateIdentity(ctx, s, "bob@keel.xyz", "1234", "https://keel.so")
+	require.NoError(t, err)
+
+	aliceId := alice[parser.FieldNameId].(string)
+	bobId := bob[parser.FieldNameId].(string)
+
+	err = database.GetDB().Exec(`INSERT INTO team (id, name, owner_id) VALUES ('team', 'Team', ?)`, aliceId).Error
+	require.NoError(t, err)
+	err = database.GetDB().Exec(`INSERT INTO membership (id, identity_id, team_id) VALUES ('alice_membership', ?, 'team'), ('bob_membership', ?, 'team')`, aliceId, bobId).Error
+	require.NoError(t, err)
+	err = database.GetDB().Exec(`INSERT INTO team_note (id, text, team_id) VALUES ('note', 'Written by Bob', 'team')`).Error
+	require.NoError(t, err)
+	err = database.GetDB().Exec(`INSERT INTO profile (id, identity_id, bio) VALUES ('alice_profile', ?, 'Hello')`, aliceId).Error
+	require.NoError(t, err)
+	err = database.GetDB().Exec(`INSERT INTO address (id, line1, profile_id) VALUES ('alice_address', '1 High Street', 'alice_profile')`).Error
+	require.NoError(t, err)
+
+	export, err := actions.ExportSubjectData(ctx, s, aliceId)
+	require.NoError(t, err)
+
+	identity, ok := export["identity"].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, aliceId, identity[parser.FieldNameId])
+	require.NotContains(t, identity, parser.IdentityFieldNamePassword)
+
+	require.Equal(t, []string{"team"}, exportedIds(t, export, "Team"))
+	require.Equal(t, []string{"alice_membership"}, exportedIds(t, export, "Membership"))
+	require.Equal(t, []string{"alice_profile"}, exportedIds(t, export, "Profile"))
+	require.Equal(t, []string{"alice_address"}, exportedIds(t, export, "Address"))
+	require.Empty(t, exportedIds(t, export, "TeamNote"))
+}
+
+func TestSubjectData_SelfServiceExportAppliesPermissions(t *testing.T) {
+	ctx, database, s := keeltesting.MakeContext(t, context.Background(), subjectDataSchema, true)
+	defer database.Close()
+
+	alice, err := actions.CreateIdentity(ctx, s, "alice@keel.xyz", "1234", "https://keel.so")
+	require.NoError(t, err)
+
+	aliceId := alice[parser.FieldNameId].(string)
+
+	err = database.GetDB().Exec(`INSERT INTO team (id, name, owner_id) VALUES ('team', 'Team', ?)`, aliceId).Error
+	require.NoError(t, err)
+	err = database.GetDB().Exec(`INSERT INTO membership (id, identity_id, team_id) VALUES ('alice_membership', ?, 'team')`, aliceId).Error
+	require.NoError(t, err)
+
+	scope := actions.NewModelScope(auth.WithIdentity(ctx, alice), s.FindModel(parser.IdentityModelName), s)
+
+	export, err := actions.ExportIdentityData(scope, map[string]any{})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"alice_membership"}, exportedIds(t, export, "Membership"))
+	require.Empty(t, exportedIds(t, export, "Team"))
+}
+
+func TestSubjectData_EraseOnlyAffectsOwnedRecords(t *testing.T) {
+	ctx, database, s := keeltesting.MakeContext(t, context.Background(), subjectDataSchema, true)
+	defer database.Close()
+
+	alice, err := actions.CreateIdentity(ctx, s, "alice@keel.xyz", "1234", "https://keel.so")
+	require.NoError(t, err)
+	bob, err := actions.CreateIdentity(ctx, s, "bob@keel.xyz", "1234", "https://keel.so")
+	require.NoError(t, err)
+
+	aliceId := alice[parser.FieldNameId].(string)
+	bobId := bob[parser.FieldNameId].(string)
+
+	err = database.GetDB().Exec(`INSERT INTO team (id, name, owner_id) VALUES ('team', 'Team', ?)`, aliceId).Error
+	require.NoError(t, err)
+	err = database.GetDB().Exec(`INSERT INTO membership (id, identity_id, team_id) VALUES ('bob_membership', ?, 'team')`, bobId).Error
+	require.NoError(t, err)
+	err = database.GetDB().Exec(`INSERT INTO team_note (id, text, team_id) VALUES ('note', 'Written by Bob', 'team')`).Error
+	require.NoError(t, err)
+
+	erasure, err := actions.EraseSubjectData(ctx, s, aliceId)
+	require.NoError(t, err)
+	require.Equal(t, 0, erasure.Deleted)
+
+	var text string
+	err = database.GetDB().Raw(`SELECT text FROM team_note WHERE id = 'note'`).Scan(&text).Error
+	require.NoError(t, err)
+	require.Equal(t, "Written by Bob", text)
+
+	var memberships int
+	err = database.GetDB().Raw(`SELECT COUNT(*) FROM membership WHERE id = 'bob_membership'`).Scan(&memberships).Error
+	require.NoError(t, err)
+	require.Equal(t, 1, memberships)
+}
+
+func TestSubjectData_EraseAnonymisesInsteadOfCascading(t *testing.T) {
+	ctx, database, s := keeltesting.MakeContext(t, context.Background(), subjectDataSchema, true)
+	defer database.Close()
+
+	alice, err := actions.CreateIdentity(ctx, s, "alice@keel.xyz", "1234", "https://keel.so")
+	require.NoError(t, err)
+
+	aliceId := alice[parser.FieldNameId].(string)
+
+	err = database.GetDB().Exec(`INSERT INTO profile (id, identity_id, bio) VALUES ('alice_profile', ?, 'Hello')`, aliceId).Error
+	require.NoError(t, err)
+	err = database.GetDB().Exec(`INSERT INTO address (id, line1, profile_id) VALUES ('alice_address', '1 High Street', 'alice_profile')`).Error
+	require.NoError(t, err)
+	err = database.GetDB().Exec(`INSERT INTO contact (id, phone, name, profile_id) VALUES ('alice_contact', '07700 900000', 'Mum', 'alice_profile')`).Error
+	require.NoError(t, err)
+	err = database.GetDB().Exec(`INSERT INTO phone_call (id, contact_id) VALUES ('call', 'alice_contact')`).Error
+	require.NoError(t, err)
+
+	erasure, err := actions.EraseSubjectData(ctx, s, aliceId)
+	require.NoError(t, err)
+
+	// The address is deleted, and the profile's bio and the contact are anonymised
+	require.Equal(t, 1, erasure.Deleted)
+	require.Equal(t, 2, erasure.Anonymised)
+
+	var addresses int
+	err = database.GetDB().Raw(`SELECT COUNT(*) FROM address`).Scan(&addresses).Error
+	require.NoError(t, err)
+	require.Equal(t, 0, addresses)
+
+	var contact struct {
+		Phone string
+		Name  *string
+	}
+	err = database.GetDB().Raw(`SELECT phone, name FROM contact WHERE id = 'alice_contact'`).Scan(&contact).Error
+	require.NoError(t, err)
+	require.Equal(t, "[redacted]", contact.Phone)
+	require.Nil(t, contact.Name)
+
+	var calls int
+	err = database.GetDB().Raw(`SELECT COUNT(*) FROM phone_call WHERE id = 'call'`).Scan(&calls).Error
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+}
@@ -33,10 +33,15 @@ func Update(scope *Scope, input map[string]any) (res map[string]any, err error)
 		return nil, err
 	}
 
-	switch {
-	case canResolveEarly && !authorised:
+	// Fields with write permissions which are being written to by the inputs
+	values, _ := input["values"].(map[string]any)
+	writeFields := writeRestrictedFields(scope, values)
+
+	if canResolveEarly && !authorised {
 		return nil, common.NewPermissionError()
-	case !canResolveEarly:
+	}
+
+	if !canResolveEarly || len(writeFields) > 0 {
 		query.Select(IdField())
 		query.DistinctOn(IdField())
 		rowToAuthorise, err := query.SelectStatement().ExecuteToSingle(scope.Context)
@@ -49,13 +54,20 @@ func Update(scope *Scope, input map[string]any) (res map[string]any, err error)
 			rowsToAuthorise = append(rowsToAuthorise, rowToAuthorise)
 		}
 
-		isAuthorised, err := AuthoriseAction(scope, input, rowsToAuthorise)
-		if err != nil {
-			return nil, err
+		if !canResolveEarly {
+			isAuthorised, err := AuthoriseAction(scope, input, rowsToAuthorise)
+			if err != nil {
+				return nil, err
+			}
+
+			if !isAuthorised {
+				return nil, common.NewPermissionError()
+			}
 		}
 
-		if !isAuthorised {
-			return nil, common.NewPermissionError()
+		err = authoriseFieldWrites(scope, writeFields, rowsToAuthorise)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -72,9 +84,17 @@ func Update(scope *Scope, input map[string]any) (res map[string]any, err error)
 	// if we have any files in our results we need to transform them to the object structure required
 	if scope.Model.HasFiles() {
 		res, err = transformModelFileResponses(scope.Context, scope.Model, res)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = ApplyFieldReadPermissions(scope, []map[string]any{res})
+	if err != nil {
+		return nil, err
 	}
 
-	return res, err
+	return res, nil
 }
 
 func GenerateUpdateStatement(query *QueryBuilder, scope *Scope, input map[string]any) (*Statement, error) {
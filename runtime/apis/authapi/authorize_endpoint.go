@@ -6,10 +6,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/coreos/go-oidc"
-	"github.com/dchest/uniuri"
 	"github.com/teamkeel/keel/config"
 	"github.com/teamkeel/keel/functions"
 	"github.com/teamkeel/keel/proto"
@@ -43,6 +43,15 @@ const (
 	AuthorizationErrServerError = "server_error"
 )
 
+// Parameters for the authorization endpoint
+// https://datatracker.ietf.org/doc/html/rfc7636#section-4.3
+const (
+	ArgCodeChallenge       = "code_challenge"
+	ArgCodeChallengeMethod = "code_challenge_method"
+	// Links the provider to the identity which exchanges the auth code, rather than signing in.
+	ArgLink = "link"
+)
+
 // AuthorizeHandler is a redirection endpoint that will redirect to the provider's sign-in/auth page
 func AuthorizeHandler(schema *proto.Schema) common.HandlerFunc {
 	return func(r *http.Request) common.Response {
@@ -97,7 +106,39 @@ func AuthorizeHandler(schema *proto.Schema) common.HandlerFunc {
 			RedirectURL: callbackUrl.String(),
 		}
 
-		u := oauthConfig.AuthCodeURL(uniuri.New())
+		// The client may provide a PKCE code challenge which will be verified when exchanging the auth code
+		// https://datatracker.ietf.org/doc/html/rfc7636#section-4.3
+		var challenge *oauth.CodeChallenge
+		if codeChallenge := r.FormValue(ArgCodeChallenge); codeChallenge != "" {
+			challenge, err = oauth.NewCodeChallenge(codeChallenge, r.FormValue(ArgCodeChallengeMethod))
+			if err != nil {
+				return jsonErrResponse(ctx, http.StatusBadRequest, AuthorizationErrInvalidRequest, err.Error(), err)
+			}
+		} else if config.PkceRequired() {
+			return jsonErrResponse(ctx, http.StatusBadRequest, AuthorizationErrInvalidRequest, "code_challenge is required", nil)
+		}
+
+		// The provider can be linked to an existing identity, in which case the link is only made once the identity
+		// exchanges the auth code with its access token. The code challenge ensures that only the client which started
+		// the flow can exchange the auth code, so that another identity cannot be linked by completing someone else's flow.
+		link := false
+		if argLink := r.FormValue(ArgLink); argLink != "" {
+			link, err = strconv.ParseBool(argLink)
+			if err != nil {
+				return jsonErrResponse(ctx, http.StatusBadRequest, AuthorizationErrInvalidRequest, "the link field is invalid and must be either 'true' or 'false'", nil)
+			}
+		}
+
+		if link && challenge == nil {
+			return jsonErrResponse(ctx, http.StatusBadRequest, AuthorizationErrInvalidRequest, "code_challenge is required when linking a provider", nil)
+		}
+
+		state, err := oauth.NewAuthState(ctx, challenge, link)
+		if err != nil {
+			return common.InternalServerErrorResponse(ctx, err)
+		}
+
+		u := oauthConfig.AuthCodeURL(state)
 
 		redirectUrl, err := url.Parse(u)
 		if err != nil {
@@ -148,6 +189,18 @@ func CallbackHandler(schema *proto.Schema) common.HandlerFunc {
 			return redirectErrResponse(ctx, redirectUrl, AuthorizationErrAccessDenied, err.Error(), err)
 		}
 
+		// The state is signed by us when starting the authorization flow and carries the client's PKCE code challenge
+		// and whether the provider is being linked to an identity
+		challenge, link, err := oauth.ValidateAuthState(ctx, r.URL.Query().Get("state"))
+		if err != nil {
+			return redirectErrResponse(ctx, redirectUrl, AuthorizationErrInvalidRequest, "state parameter is missing or invalid", err)
+		}
+
+		if challenge == nil && cfg.PkceRequired() {
+			err := errors.New("code_challenge is required")
+			return redirectErrResponse(ctx, redirectUrl, AuthorizationErrInvalidRequest, err.Error(), err)
+		}
+
 		oidcProv, err := oidc.NewProvider(ctx, issuer)
 		if err != nil {
 			return common.InternalServerErrorResponse(ctx, err)
@@ -238,8 +291,25 @@ func CallbackHandler(schema *proto.Schema) common.HandlerFunc {
 			customClaims[c.Field] = claims[c.Key]
 		}
 
-		var identity auth.Identity
-		identity, err = actions.FindIdentityByExternalId(ctx, schema, idToken.Subject, idToken.Issuer)
+		var authCode string
+		if link {
+			authCode, err = oauth.NewLinkAuthCode(ctx, &oauth.PendingLink{
+				Issuer:     idToken.Issuer,
+				ExternalId: idToken.Subject,
+				Email:      standardClaims.Email,
+			}, challenge)
+			if err != nil {
+				return common.InternalServerErrorResponse(ctx, err)
+			}
+
+			values := url.Values{}
+			values.Add("code", authCode)
+			redirectUrl.RawQuery = values.Encode()
+
+			return common.NewRedirectResponse(redirectUrl)
+		}
+
+		identity, err := findExternalIdentity(ctx, schema, cfg, idToken.Issuer, idToken.Subject, &standardClaims, customClaims)
 		if err != nil {
 			return common.InternalServerErrorResponse(ctx, err)
 		}
@@ -255,14 +325,9 @@ func CallbackHandler(schema *proto.Schema) common.HandlerFunc {
 			if err != nil {
 				return common.InternalServerErrorResponse(ctx, err)
 			}
-		} else {
-			identity, err = actions.UpdateIdentityWithClaims(ctx, schema, idToken.Subject, idToken.Issuer, &standardClaims, customClaims)
-			if err != nil {
-				return common.InternalServerErrorResponse(ctx, err)
-			}
 		}
 
-		authCode, err := oauth.NewAuthCode(ctx, identity[parser.FieldNameId].(string))
+		authCode, err = oauth.NewAuthCode(ctx, identity[parser.FieldNameId].(string), challenge)
 		if err != nil {
 			return common.InternalServerErrorResponse(ctx, err)
 		}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -650,6 +651,203 @@ func TestSsoLogin_NoRedirectUrlInConfig(t *testing.T) {
 	require.Equal(t, "redirectUrl must be specified in keelconfig.yaml", errorResponse.ErrorDescription)
 }
 
+func TestSsoLogin_WithPkce(t *testing.T) {
+	// OIDC test server
+	server, err := oauthtest.NewServer()
+	require.NoError(t, err)
+	defer server.Close()
+
+	// Redirect handler
+	redirectHandler := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	require.NoError(t, err)
+
+	// Set up auth config
+	redirectUrl := redirectHandler.URL + "/signedup"
+	ctx := runtimectx.WithOAuthConfig(context.TODO(), &config.AuthConfig{
+		RedirectUrl: &redirectUrl,
+		Providers: []config.Provider{
+			{
+				Type:             config.OpenIdConnectProvider,
+				Name:             "myoidc",
+				ClientId:         "oidc-client-id",
+				IssuerUrl:        server.Issuer,
+				TokenUrl:         server.TokenUrl,
+				AuthorizationUrl: server.AuthorizeUrl,
+			},
+		},
+	})
+
+	ctx, database, schema := keeltesting.MakeContext(t, ctx, authTestSchema, true)
+	defer database.Close()
+
+	// Set secret for client
+	ctx = runtimectx.WithSecrets(ctx, map[string]string{
+		fmt.Sprintf("AUTH_PROVIDER_SECRET_%s", strings.ToUpper("myoidc")): "secret",
+	})
+
+	httpHandler := func(w http.ResponseWriter, r *http.Request) {
+		h := runtime.NewHttpHandler(schema)
+		r = r.WithContext(ctx)
+		h.ServeHTTP(w, r)
+	}
+	runtime := httptest.NewServer(http.HandlerFunc(httpHandler))
+	require.NoError(t, err)
+	defer runtime.Close()
+
+	t.Setenv("KEEL_API_URL", runtime.URL)
+
+	server.WithOAuthClient(&oauthtest.OAuthClient{
+		ClientId:     "oidc-client-id",
+		ClientSecret: "secret",
+		RedirectUrl:  runtime.URL + "/auth/callback/myoidc",
+	})
+
+	server.SetUser("id|285620", &oauth.UserClaims{
+		Email:         "keelson@keel.so",
+		EmailVerified: true,
+	})
+
+	codeVerifier := "dBjftJeZ4CVP-mJ92ZrZS4MnrwCODc5YBdQhIoFHBvs"
+	codeChallenge := "L3JWHu2qjlyvH1eTl9tFlqc9TKbp9KTPl3d3MW55ZLM"
+
+	login := func() string {
+		values := url.Values{}
+		values.Add("code_challenge", codeChallenge)
+		values.Add("code_challenge_method", "S256")
+
+		request, err := http.NewRequest(http.MethodPost, runtime.URL+"/auth/authorize/myoidc?"+values.Encode(), nil)
+		require.NoError(t, err)
+
+		httpResponse, err := runtime.Client().Do(request)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+		location, err := url.Parse(httpResponse.Request.Response.Header["Location"][0])
+		require.NoError(t, err)
+
+		code := location.Query().Get("code")
+		require.NotEmpty(t, code)
+		return code
+	}
+
+	exchange := func(code string, codeVerifier string) *http.Response {
+		form := url.Values{}
+		form.Add("grant_type", "authorization_code")
+		form.Add("code", code)
+		if codeVerifier != "" {
+			form.Add("code_verifier", codeVerifier)
+		}
+
+		httpResponse, err := runtime.Client().PostForm(runtime.URL+"/auth/token", form)
+		require.NoError(t, err)
+		return httpResponse
+	}
+
+	// The correct code verifier is accepted
+	httpResponse := exchange(login(), codeVerifier)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	// An incorrect code verifier is rejected
+	httpResponse = exchange(login(), "incorrect-verifier-incorrect-verifier-incorrect")
+	require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+
+	// A missing code verifier is rejected
+	httpResponse = exchange(login(), "")
+	require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+}
+
+func TestSsoLogin_InvalidCodeChallengeMethod(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	// OIDC test server
+	server, err := oauthtest.NewServer()
+	require.NoError(t, err)
+	defer server.Close()
+
+	// Set up auth config
+	redirectUrl := "https://example.com/signedup"
+	ctx = runtimectx.WithOAuthConfig(ctx, &config.AuthConfig{
+		RedirectUrl: &redirectUrl,
+		Providers: []config.Provider{
+			{
+				Type:             config.OpenIdConnectProvider,
+				Name:             "myoidc",
+				ClientId:         "oidc-client-id",
+				IssuerUrl:        server.Issuer,
+				TokenUrl:         server.TokenUrl,
+				AuthorizationUrl: server.AuthorizeUrl,
+			},
+		},
+	})
+
+	// Set secret for client
+	ctx = runtimectx.WithSecrets(ctx, map[string]string{
+		fmt.Sprintf("AUTH_PROVIDER_SECRET_%s", strings.ToUpper("myoidc")): "secret",
+	})
+
+	values := url.Values{}
+	values.Add("code_challenge", "L3JWHu2qjlyvH1eTl9tFlqc9TKbp9KTPl3d3MW55ZLM")
+	values.Add("code_challenge_method", "S512")
+
+	request := httptest.NewRequest(http.MethodGet, "http://mykeelapp.keel.so/auth/authorize/myoidc?"+values.Encode(), nil)
+	request = request.WithContext(ctx)
+
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusBadRequest, httpResponse.StatusCode)
+	require.Equal(t, "invalid_request", errorResponse.Error)
+	require.Equal(t, "code_challenge_method must be either 'S256' or 'plain'", errorResponse.ErrorDescription)
+}
+
+func TestSsoLogin_PkceRequiredMissingChallenge(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	// OIDC test server
+	server, err := oauthtest.NewServer()
+	require.NoError(t, err)
+	defer server.Close()
+
+	// Set up auth config
+	redirectUrl := "https://example.com/signedup"
+	required := true
+	ctx = runtimectx.WithOAuthConfig(ctx, &config.AuthConfig{
+		RedirectUrl: &redirectUrl,
+		Pkce: config.PkceConfig{
+			Required: &required,
+		},
+		Providers: []config.Provider{
+			{
+				Type:             config.OpenIdConnectProvider,
+				Name:             "myoidc",
+				ClientId:         "oidc-client-id",
+				IssuerUrl:        server.Issuer,
+				TokenUrl:         server.TokenUrl,
+				AuthorizationUrl: server.AuthorizeUrl,
+			},
+		},
+	})
+
+	// Set secret for client
+	ctx = runtimectx.WithSecrets(ctx, map[string]string{
+		fmt.Sprintf("AUTH_PROVIDER_SECRET_%s", strings.ToUpper("myoidc")): "secret",
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "http://mykeelapp.keel.so/auth/authorize/myoidc", nil)
+	request = request.WithContext(ctx)
+
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusBadRequest, httpResponse.StatusCode)
+	require.Equal(t, "invalid_request", errorResponse.Error)
+	require.Equal(t, "code_challenge is required", errorResponse.ErrorDescription)
+}
+
 func TestGetClientSecret(t *testing.T) {
 	provider := &config.Provider{
 		Name: "google",
@@ -0,0 +1,128 @@
+package authapi
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/samber/lo"
+	cfg "github.com/teamkeel/keel/config"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/common"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+)
+
+// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type OpenIdConfigurationResponse struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JwksUri                           string   `json:"jwks_uri"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IdTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// The standard claims which are stored on the identity and returned from the userinfo endpoint.
+// https://openid.net/specs/openid-connect-core-1_0.html#StandardClaims
+var standardClaims = []string{
+	"email",
+	"email_verified",
+	"name",
+	"given_name",
+	"family_name",
+	"middle_name",
+	"nickname",
+	"profile",
+	"picture",
+	"website",
+	"gender",
+	"zoneinfo",
+	"locale",
+}
+
+// OpenIdConfigurationHandler publishes the OpenID Connect discovery document which describes
+// Keel as an issuer, so that standard OIDC client libraries can be configured against it.
+// Identity claims are read from the userinfo endpoint, as ID tokens are not issued, but the signing
+// algorithm is still published as it is required by the specification. This is the algorithm of the
+// keys published at the jwks_uri.
+// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderConfig
+func OpenIdConfigurationHandler(schema *proto.Schema) common.HandlerFunc {
+	return func(r *http.Request) common.Response {
+		ctx, span := tracer.Start(r.Context(), "OpenID Configuration Endpoint")
+		defer span.End()
+
+		if r.Method != http.MethodGet {
+			return jsonErrResponse(ctx, http.StatusMethodNotAllowed, TokenErrInvalidRequest, "the openid configuration endpoint only accepts GET", nil)
+		}
+
+		config, err := runtimectx.GetOAuthConfig(ctx)
+		if err != nil {
+			return common.InternalServerErrorResponse(ctx, err)
+		}
+
+		apiUrl, err := url.ParseRequestURI(os.Getenv("KEEL_API_URL"))
+		if err != nil {
+			return common.InternalServerErrorResponse(ctx, errors.New("the KEEL_API_URL environment variable is not a valid url"))
+		}
+
+		response := OpenIdConfigurationResponse{
+			Issuer:                            oauth.TokenIssuer(),
+			TokenEndpoint:                     apiUrl.JoinPath("/auth/token").String(),
+			RevocationEndpoint:                apiUrl.JoinPath("/auth/revoke").String(),
+			UserInfoEndpoint:                  apiUrl.JoinPath("/auth/userinfo").String(),
+			JwksUri:                           apiUrl.JoinPath("/auth/.well-known/jwks.json").String(),
+			ResponseTypesSupported:            []string{"code"},
+			SubjectTypesSupported:             []string{"public"},
+			IdTokenSigningAlgValuesSupported:  []string{jwt.SigningMethodRS256.Alg()},
+			TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post"},
+			CodeChallengeMethodsSupported:     []string{oauth.CodeChallengeMethodS256, oauth.CodeChallengeMethodPlain},
+			ScopesSupported:                   []string{"openid", "email", "profile"},
+			GrantTypesSupported: []string{
+				GrantTypeAuthCode,
+				GrantTypeRefreshToken,
+				GrantTypePassword,
+				GrantTypePasswordless,
+				GrantTypeTokenExchange,
+				GrantTypeMfaOtp,
+				GrantTypeImpersonation,
+				GrantTypeClientCredentials,
+			},
+		}
+
+		// The authorize endpoint is specific to each provider,
+		// so we can only advertise it when there is exactly one.
+		providers := lo.Filter(config.Providers, func(p cfg.Provider, _ int) bool {
+			return !strings.HasPrefix(strings.ToLower(p.Name), cfg.ReservedProviderNamePrefix)
+		})
+		if len(providers) == 1 {
+			authUrl, err := providers[0].GetAuthorizeUrl()
+			if err != nil {
+				return common.InternalServerErrorResponse(ctx, err)
+			}
+			response.AuthorizationEndpoint = authUrl.String()
+		}
+
+		response.ClaimsSupported = append([]string{"sub", "iss"}, standardClaims...)
+		for _, c := range config.Claims {
+			response.ClaimsSupported = append(response.ClaimsSupported, c.Key)
+		}
+
+		return common.NewJsonResponse(http.StatusOK, response, &common.ResponseMetadata{
+			Headers: http.Header{
+				"Cache-Control": []string{"public, max-age=300"},
+			},
+		})
+	}
+}
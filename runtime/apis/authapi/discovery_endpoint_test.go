@@ -0,0 +1,86 @@
+package authapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/config"
+	"github.com/teamkeel/keel/runtime/apis/authapi"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+)
+
+func TestOpenIdConfiguration_Endpoints(t *testing.T) {
+	t.Setenv("KEEL_API_URL", "http://mykeelapp.keel.so")
+
+	ctx := runtimectx.WithOAuthConfig(context.Background(), &config.AuthConfig{
+		Claims: []config.IdentityClaim{
+			{Key: "https://slack.com/team_id", Field: "teamId"},
+		},
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "http://mykeelapp.keel.so/.well-known/openid-configuration", nil)
+	request = request.WithContext(ctx)
+
+	response := authapi.OpenIdConfigurationHandler(nil)(request)
+	require.Equal(t, http.StatusOK, response.Status)
+
+	var document authapi.OpenIdConfigurationResponse
+	err := json.Unmarshal(response.Body, &document)
+	require.NoError(t, err)
+
+	require.Equal(t, "http://mykeelapp.keel.so", document.Issuer)
+	require.Equal(t, "http://mykeelapp.keel.so/auth/token", document.TokenEndpoint)
+	require.Equal(t, "http://mykeelapp.keel.so/auth/revoke", document.RevocationEndpoint)
+	require.Equal(t, "http://mykeelapp.keel.so/auth/userinfo", document.UserInfoEndpoint)
+	require.Equal(t, "http://mykeelapp.keel.so/auth/.well-known/jwks.json", document.JwksUri)
+	require.Empty(t, document.AuthorizationEndpoint)
+
+	require.Equal(t, []string{"authorization_code", "refresh_token", "password", "passwordless", "token_exchange", "mfa_otp", "impersonation", "client_credentials"}, document.GrantTypesSupported)
+	require.Equal(t, []string{"S256", "plain"}, document.CodeChallengeMethodsSupported)
+	require.Equal(t, []string{"openid", "email", "profile"}, document.ScopesSupported)
+	require.Equal(t, []string{"RS256"}, document.IdTokenSigningAlgValuesSupported)
+	require.Equal(t, []string{"public"}, document.SubjectTypesSupported)
+	require.Equal(t, []string{"code"}, document.ResponseTypesSupported)
+	require.Contains(t, document.ClaimsSupported, "sub")
+	require.Contains(t, document.ClaimsSupported, "email_verified")
+	require.Contains(t, document.ClaimsSupported, "https://slack.com/team_id")
+}
+
+func TestOpenIdConfiguration_SingleProviderAuthorizationEndpoint(t *testing.T) {
+	t.Setenv("KEEL_API_URL", "http://mykeelapp.keel.so")
+
+	ctx := runtimectx.WithOAuthConfig(context.Background(), &config.AuthConfig{
+		Providers: []config.Provider{
+			{
+				Type:     config.GoogleProvider,
+				Name:     "Google",
+				ClientId: "google-client-id",
+			},
+		},
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "http://mykeelapp.keel.so/auth/.well-known/openid-configuration", nil)
+	request = request.WithContext(ctx)
+
+	response := authapi.OpenIdConfigurationHandler(nil)(request)
+	require.Equal(t, http.StatusOK, response.Status)
+
+	var document authapi.OpenIdConfigurationResponse
+	err := json.Unmarshal(response.Body, &document)
+	require.NoError(t, err)
+
+	require.Equal(t, "http://mykeelapp.keel.so/auth/authorize/google", document.AuthorizationEndpoint)
+}
+
+func TestOpenIdConfiguration_OnlyAcceptsGet(t *testing.T) {
+	t.Setenv("KEEL_API_URL", "http://mykeelapp.keel.so")
+
+	request := httptest.NewRequest(http.MethodPost, "http://mykeelapp.keel.so/.well-known/openid-configuration", nil)
+
+	response := authapi.OpenIdConfigurationHandler(nil)(request)
+	require.Equal(t, http.StatusMethodNotAllowed, response.Status)
+}
@@ -0,0 +1,87 @@
+package authapi
+
+import (
+	"context"
+
+	"github.com/teamkeel/keel/config"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/actions"
+	"github.com/teamkeel/keel/runtime/auth"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/schema/parser"
+)
+
+// findExternalIdentity finds the identity which signs in with the external identity. This is either the identity which was
+// created with the provider, in which case its claims are updated, or the identity which the provider has been linked to.
+// If enabled, a provider signing in for the first time is linked to an existing identity with the same verified email address.
+// Returns nil if there is no such identity.
+func findExternalIdentity(ctx context.Context, schema *proto.Schema, cfg *config.AuthConfig, issuer string, externalId string, standardClaims *oauth.IdTokenClaims, customClaims map[string]any) (auth.Identity, error) {
+	identity, err := actions.FindIdentityByExternalId(ctx, schema, externalId, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	if identity != nil {
+		return actions.UpdateIdentityWithClaims(ctx, schema, externalId, issuer, standardClaims, customClaims)
+	}
+
+	// The claims of linked providers are not synced as the identity's claims are from the provider it was created with
+	linkedId, err := oauth.FindLinkedIdentityId(ctx, issuer, externalId)
+	if err != nil {
+		return nil, err
+	}
+
+	if linkedId != "" {
+		return actions.FindIdentityById(ctx, schema, linkedId)
+	}
+
+	// Both the provider and the existing identity must have verified the email address,
+	// otherwise anyone could take over an identity by signing up with its email address
+	if !cfg.LinkVerifiedEmailEnabled() || !standardClaims.EmailVerified || standardClaims.Email == "" {
+		return nil, nil
+	}
+
+	identity, err = actions.FindIdentityByVerifiedEmail(ctx, schema, standardClaims.Email)
+	if err != nil || identity == nil {
+		return nil, err
+	}
+
+	err = oauth.LinkIdentity(ctx, identity[parser.FieldNameId].(string), issuer, externalId, standardClaims.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+// linkExternalIdentity links the external identity to an existing identity, which has authenticated with its access token
+// when exchanging the auth code issued for the link. If the external identity already belongs to another identity, then ErrIdentityAlreadyLinked is returned.
+func linkExternalIdentity(ctx context.Context, schema *proto.Schema, identityId string, issuer string, externalId string, email string) (auth.Identity, error) {
+	identity, err := actions.FindIdentityById(ctx, schema, identityId)
+	if err != nil {
+		return nil, err
+	}
+
+	if identity == nil {
+		return nil, actions.ErrIdentityNotFound
+	}
+
+	existing, err := actions.FindIdentityByExternalId(ctx, schema, externalId, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		if existing[parser.FieldNameId] == identityId {
+			return identity, nil
+		}
+		return nil, oauth.ErrIdentityAlreadyLinked
+	}
+
+	err = oauth.LinkIdentity(ctx, identityId, issuer, externalId, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return identity, nil
+}
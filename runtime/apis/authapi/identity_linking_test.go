@@ -0,0 +1,386 @@
+package authapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/config"
+	"github.com/teamkeel/keel/db"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime"
+	"github.com/teamkeel/keel/runtime/actions"
+	"github.com/teamkeel/keel/runtime/apis/authapi"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/runtime/oauth/oauthtest"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+	keeltesting "github.com/teamkeel/keel/testing"
+)
+
+type linkingTest struct {
+	ctx         context.Context
+	database    db.Database
+	schema      *proto.Schema
+	server      *oauthtest.OidcServer
+	runtime     *httptest.Server
+	redirectUrl string
+}
+
+func newLinkingTest(t *testing.T, linkVerifiedEmail bool) *linkingTest {
+	server, err := oauthtest.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	redirectHandler := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(redirectHandler.Close)
+
+	redirectUrl := redirectHandler.URL + "/signedup"
+	ctx := runtimectx.WithOAuthConfig(context.TODO(), &config.AuthConfig{
+		RedirectUrl: &redirectUrl,
+		Linking: config.LinkingConfig{
+			VerifiedEmail: &linkVerifiedEmail,
+		},
+		Providers: []config.Provider{
+			{
+				Type:             config.OpenIdConnectProvider,
+				Name:             "myoidc",
+				ClientId:         "oidc-client-id",
+				IssuerUrl:        server.Issuer,
+				TokenUrl:         server.TokenUrl,
+				AuthorizationUrl: server.AuthorizeUrl,
+			},
+		},
+	})
+
+	ctx, database, schema := keeltesting.MakeContext(t, ctx, authTestSchema, true)
+	t.Cleanup(func() { database.Close() })
+
+	ctx = runtimectx.WithSecrets(ctx, map[string]string{
+		fmt.Sprintf("AUTH_PROVIDER_SECRET_%s", strings.ToUpper("myoidc")): "secret",
+	})
+
+	runtime := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := runtime.NewHttpHandler(schema)
+		h.ServeHTTP(w, r.WithContext(ctx))
+	}))
+	t.Cleanup(runtime.Close)
+
+	t.Setenv("KEEL_API_URL", runtime.URL)
+
+	server.WithOAuthClient(&oauthtest.OAuthClient{
+		ClientId:     "oidc-client-id",
+		ClientSecret: "secret",
+		RedirectUrl:  runtime.URL + "/auth/callback/myoidc",
+	})
+
+	return &linkingTest{
+		ctx:         ctx,
+		database:    database,
+		schema:      schema,
+		server:      server,
+		runtime:     runtime,
+		redirectUrl: redirectUrl,
+	}
+}
+
+const (
+	linkCodeVerifier  = "dBjftJeZ4CVP-mJ92ZrZS4MnrwCODc5YBdQhIoFHBvs"
+	linkCodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+// authorize starts the SSO flow with the given query and returns the final redirect location.
+func (l *linkingTest) authorize(t *testing.T, query string) string {
+	request, err := http.NewRequest(http.MethodPost, l.runtime.URL+"/auth/authorize/myoidc?"+query, nil)
+	require.NoError(t, err)
+
+	httpResponse, err := l.runtime.Client().Do(request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.Equal(t, http.StatusFound, httpResponse.Request.Response.StatusCode)
+
+	return httpResponse.Request.Response.Header["Location"][0]
+}
+
+// link completes the SSO flow to link the provider and returns the auth code which is exchanged to complete the link.
+func (l *linkingTest) link(t *testing.T) string {
+	location := l.authorize(t, fmt.Sprintf("link=true&code_challenge=%s&code_challenge_method=S256", linkCodeChallenge))
+	require.Contains(t, location, l.redirectUrl+"?code=")
+
+	redirect, err := url.Parse(location)
+	require.NoError(t, err)
+
+	return redirect.Query().Get("code")
+}
+
+// exchange exchanges the auth code at the token endpoint, optionally authenticated as an identity.
+func (l *linkingTest) exchange(t *testing.T, code string, codeVerifier string, accessToken string) *http.Response {
+	form := url.Values{}
+	form.Add("grant_type", "authorization_code")
+	form.Add("code", code)
+	if codeVerifier != "" {
+		form.Add("code_verifier", codeVerifier)
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "http://mykeelapp.keel.so/auth/token", strings.NewReader(form.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	if accessToken != "" {
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	w := httptest.NewRecorder()
+	runtime.NewHttpHandler(l.schema).ServeHTTP(w, request.WithContext(l.ctx))
+
+	return w.Result()
+}
+
+func (l *linkingTest) identities(t *testing.T) []map[string]any {
+	var identities []map[string]any
+	err := l.database.GetDB().Raw("SELECT * FROM identity").Scan(&identities).Error
+	require.NoError(t, err)
+	return identities
+}
+
+func (l *linkingTest) links(t *testing.T, identityId string) []*oauth.LinkedIdentity {
+	links, err := oauth.ListLinkedIdentities(l.ctx, identityId)
+	require.NoError(t, err)
+	return links
+}
+
+func TestSsoLogin_LinkProviderToAuthenticatedIdentity(t *testing.T) {
+	l := newLinkingTest(t, false)
+
+	identity, err := actions.CreateIdentity(l.ctx, l.schema, "keelson@keel.so", "", oauth.KeelIssuer)
+	require.NoError(t, err)
+	identityId := identity["id"].(string)
+
+	accessToken, _, err := oauth.GenerateAccessToken(l.ctx, identityId)
+	require.NoError(t, err)
+
+	// A different email address at the provider does not prevent linking
+	l.server.SetUser("id|285620", &oauth.UserClaims{Email: "keelson@gmail.com"})
+
+	// The provider is not linked until the auth code is exchanged by the identity
+	code := l.link(t)
+	require.Len(t, l.identities(t), 1)
+	require.Len(t, l.links(t, identityId), 0)
+
+	httpResponse := l.exchange(t, code, linkCodeVerifier, accessToken)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	links := l.links(t, identityId)
+	require.Len(t, links, 1)
+	require.Equal(t, l.server.Issuer, links[0].Issuer)
+	require.Equal(t, "id|285620", links[0].ExternalId)
+	require.Equal(t, "keelson@gmail.com", *links[0].Email)
+
+	// Signing in with the linked provider authenticates as the existing identity
+	location := l.authorize(t, "")
+	require.Contains(t, location, l.redirectUrl+"?code=")
+
+	identities := l.identities(t)
+	require.Len(t, identities, 1)
+	require.Equal(t, identityId, identities[0]["id"])
+
+	// Linking again has no effect
+	httpResponse = l.exchange(t, l.link(t), linkCodeVerifier, accessToken)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.Len(t, l.links(t, identityId), 1)
+}
+
+func TestSsoLogin_LinkProviderAlreadyBelongingToAnotherIdentity(t *testing.T) {
+	l := newLinkingTest(t, false)
+
+	l.server.SetUser("id|285620", &oauth.UserClaims{Email: "keelson@gmail.com"})
+
+	location := l.authorize(t, "")
+	require.Contains(t, location, l.redirectUrl+"?code=")
+	require.Len(t, l.identities(t), 1)
+
+	identity, err := actions.CreateIdentity(l.ctx, l.schema, "keelson@keel.so", "", oauth.KeelIssuer)
+	require.NoError(t, err)
+
+	accessToken, _, err := oauth.GenerateAccessToken(l.ctx, identity["id"].(string))
+	require.NoError(t, err)
+
+	httpResponse := l.exchange(t, l.link(t), linkCodeVerifier, accessToken)
+	require.Equal(t, http.StatusBadRequest, httpResponse.StatusCode)
+	require.Len(t, l.links(t, identity["id"].(string)), 0)
+}
+
+func TestSsoLogin_LinkProviderRequiresCodeChallenge(t *testing.T) {
+	l := newLinkingTest(t, false)
+
+	request, err := http.NewRequest(http.MethodPost, l.runtime.URL+"/auth/authorize/myoidc?link=true", nil)
+	require.NoError(t, err)
+
+	httpResponse, err := l.runtime.Client().Do(request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, httpResponse.StatusCode)
+}
+
+func TestSsoLogin_LinkProviderFromAnotherFlow(t *testing.T) {
+	l := newLinkingTest(t, false)
+
+	identity, err := actions.CreateIdentity(l.ctx, l.schema, "keelson@keel.so", "", oauth.KeelIssuer)
+	require.NoError(t, err)
+	identityId := identity["id"].(string)
+
+	accessToken, _, err := oauth.GenerateAccessToken(l.ctx, identityId)
+	require.NoError(t, err)
+
+	l.server.SetUser("id|285620", &oauth.UserClaims{Email: "keelson@gmail.com"})
+
+	// Without the code verifier of the client which started the flow, the auth code cannot be exchanged
+	httpResponse := l.exchange(t, l.link(t), "", accessToken)
+	require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+
+	// The auth code does not sign in without an identity to link to
+	httpResponse = l.exchange(t, l.link(t), linkCodeVerifier, "")
+	require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+
+	require.Len(t, l.links(t, identityId), 0)
+	require.Len(t, l.identities(t), 1)
+}
+
+func TestSsoLogin_LinkProviderAccessTokenParameterIgnored(t *testing.T) {
+	l := newLinkingTest(t, false)
+
+	identity, err := actions.CreateIdentity(l.ctx, l.schema, "keelson@keel.so", "", oauth.KeelIssuer)
+	require.NoError(t, err)
+
+	accessToken, _, err := oauth.GenerateAccessToken(l.ctx, identity["id"].(string))
+	require.NoError(t, err)
+
+	l.server.SetUser("id|285620", &oauth.UserClaims{Email: "keelson@gmail.com"})
+
+	// Signs in with the provider rather than linking it
+	location := l.authorize(t, "access_token="+accessToken)
+	require.Contains(t, location, l.redirectUrl+"?code=")
+	require.Len(t, l.identities(t), 2)
+	require.Len(t, l.links(t, identity["id"].(string)), 0)
+}
+
+func TestLinks_ListAndRemove(t *testing.T) {
+	l := newLinkingTest(t, false)
+
+	identity, err := actions.CreateIdentity(l.ctx, l.schema, "keelson@keel.so", "", oauth.KeelIssuer)
+	require.NoError(t, err)
+
+	accessToken, _, err := oauth.GenerateAccessToken(l.ctx, identity["id"].(string))
+	require.NoError(t, err)
+
+	l.server.SetUser("id|285620", &oauth.UserClaims{Email: "keelson@gmail.com"})
+
+	httpResponse := l.exchange(t, l.link(t), linkCodeVerifier, accessToken)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	request := httptest.NewRequest(http.MethodGet, "http://mykeelapp.keel.so/auth/links", nil)
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+	response, httpResponse, err := handleRuntimeRequest[authapi.LinkedIdentitiesResponse](l.schema, request.WithContext(l.ctx))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.Len(t, response.Links, 1)
+	require.Equal(t, l.server.Issuer, response.Links[0].Issuer)
+	require.Equal(t, "id|285620", response.Links[0].ExternalId)
+
+	form := url.Values{}
+	form.Add("issuer", l.server.Issuer)
+	form.Add("external_id", "id|285620")
+	request = httptest.NewRequest(http.MethodPost, "http://mykeelapp.keel.so/auth/links/remove", strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+	_, httpResponse, err = handleRuntimeRequest[any](l.schema, request.WithContext(l.ctx))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.Len(t, l.links(t, identity["id"].(string)), 0)
+
+	// Removing it again is not found
+	request = httptest.NewRequest(http.MethodPost, "http://mykeelapp.keel.so/auth/links/remove", strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+	_, httpResponse, err = handleRuntimeRequest[authapi.ErrorResponse](l.schema, request.WithContext(l.ctx))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, httpResponse.StatusCode)
+}
+
+func TestLinks_RequiresAccessToken(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "http://mykeelapp.keel.so/auth/links", nil)
+	response := authapi.LinksHandler(nil)(request)
+	require.Equal(t, http.StatusUnauthorized, response.Status)
+}
+
+func TestSsoLogin_LinkVerifiedEmail(t *testing.T) {
+	l := newLinkingTest(t, true)
+
+	identity, err := actions.CreateIdentity(l.ctx, l.schema, "keelson@keel.so", "", oauth.KeelIssuer)
+	require.NoError(t, err)
+	identityId := identity["id"].(string)
+
+	err = l.database.GetDB().Exec("UPDATE identity SET email_verified = true WHERE id = ?", identityId).Error
+	require.NoError(t, err)
+
+	l.server.SetUser("id|285620", &oauth.UserClaims{Email: "keelson@keel.so", EmailVerified: true})
+
+	location := l.authorize(t, "")
+	require.Contains(t, location, l.redirectUrl+"?code=")
+
+	identities := l.identities(t)
+	require.Len(t, identities, 1)
+	require.Equal(t, identityId, identities[0]["id"])
+	require.Len(t, l.links(t, identityId), 1)
+}
+
+func TestSsoLogin_LinkVerifiedEmailDisabled(t *testing.T) {
+	l := newLinkingTest(t, false)
+
+	identity, err := actions.CreateIdentity(l.ctx, l.schema, "keelson@keel.so", "", oauth.KeelIssuer)
+	require.NoError(t, err)
+
+	err = l.database.GetDB().Exec("UPDATE identity SET email_verified = true WHERE id = ?", identity["id"]).Error
+	require.NoError(t, err)
+
+	l.server.SetUser("id|285620", &oauth.UserClaims{Email: "keelson@keel.so", EmailVerified: true})
+
+	location := l.authorize(t, "")
+	require.Contains(t, location, l.redirectUrl+"?code=")
+	require.Len(t, l.identities(t), 2)
+	require.Len(t, l.links(t, identity["id"].(string)), 0)
+}
+
+func TestSsoLogin_LinkVerifiedEmailNotVerifiedByIdentity(t *testing.T) {
+	l := newLinkingTest(t, true)
+
+	identity, err := actions.CreateIdentity(l.ctx, l.schema, "keelson@keel.so", "", oauth.KeelIssuer)
+	require.NoError(t, err)
+
+	l.server.SetUser("id|285620", &oauth.UserClaims{Email: "keelson@keel.so", EmailVerified: true})
+
+	location := l.authorize(t, "")
+	require.Contains(t, location, l.redirectUrl+"?code=")
+	require.Len(t, l.identities(t), 2)
+	require.Len(t, l.links(t, identity["id"].(string)), 0)
+}
+
+func TestSsoLogin_LinkVerifiedEmailNotVerifiedByProvider(t *testing.T) {
+	l := newLinkingTest(t, true)
+
+	identity, err := actions.CreateIdentity(l.ctx, l.schema, "keelson@keel.so", "", oauth.KeelIssuer)
+	require.NoError(t, err)
+
+	err = l.database.GetDB().Exec("UPDATE identity SET email_verified = true WHERE id = ?", identity["id"]).Error
+	require.NoError(t, err)
+
+	l.server.SetUser("id|285620", &oauth.UserClaims{Email: "keelson@keel.so", EmailVerified: false})
+
+	location := l.authorize(t, "")
+	require.Contains(t, location, l.redirectUrl+"?code=")
+	require.Len(t, l.identities(t), 2)
+	require.Len(t, l.links(t, identity["id"].(string)), 0)
+}
@@ -0,0 +1,33 @@
+package authapi
+
+import (
+	"net/http"
+
+	"github.com/teamkeel/keel/runtime/common"
+	"github.com/teamkeel/keel/runtime/oauth"
+)
+
+// JwksHandler publishes the public keys which can be used to verify Keel-issued tokens.
+// Keys which have been retired are not included.
+// https://datatracker.ietf.org/doc/html/rfc7517#section-5
+func JwksHandler() common.HandlerFunc {
+	return func(r *http.Request) common.Response {
+		ctx, span := tracer.Start(r.Context(), "JWKS Endpoint")
+		defer span.End()
+
+		if r.Method != http.MethodGet {
+			return jsonErrResponse(ctx, http.StatusMethodNotAllowed, TokenErrInvalidRequest, "the jwks endpoint only accepts GET", nil)
+		}
+
+		jwks, err := oauth.PublicKeySet(ctx)
+		if err != nil {
+			return common.InternalServerErrorResponse(ctx, err)
+		}
+
+		return common.NewJsonResponse(http.StatusOK, jwks, &common.ResponseMetadata{
+			Headers: http.Header{
+				"Cache-Control": []string{"public, max-age=300"},
+			},
+		})
+	}
+}
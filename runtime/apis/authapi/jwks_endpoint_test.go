@@ -0,0 +1,51 @@
+package authapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/runtime/apis/authapi"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+	"github.com/teamkeel/keel/testhelpers"
+)
+
+func TestJwks_PublishesActiveKeys(t *testing.T) {
+	pk, err := testhelpers.GetEmbeddedPrivateKey()
+	require.NoError(t, err)
+
+	ctx := runtimectx.WithPrivateKey(context.Background(), pk)
+
+	request := httptest.NewRequest(http.MethodGet, "http://mykeelapp.keel.so/auth/.well-known/jwks.json", nil)
+	request = request.WithContext(ctx)
+
+	response := authapi.JwksHandler()(request)
+	require.Equal(t, http.StatusOK, response.Status)
+	require.Equal(t, "public, max-age=300", response.Headers["Cache-Control"][0])
+
+	var jwks oauth.JSONWebKeySet
+	err = json.Unmarshal(response.Body, &jwks)
+	require.NoError(t, err)
+
+	require.Len(t, jwks.Keys, 1)
+	require.Equal(t, oauth.KeyId(&pk.PublicKey), jwks.Keys[0].KeyId)
+	require.Equal(t, "RSA", jwks.Keys[0].KeyType)
+	require.Equal(t, "RS256", jwks.Keys[0].Algorithm)
+}
+
+func TestJwks_OnlyAcceptsGet(t *testing.T) {
+	pk, err := testhelpers.GetEmbeddedPrivateKey()
+	require.NoError(t, err)
+
+	ctx := runtimectx.WithPrivateKey(context.Background(), pk)
+
+	request := httptest.NewRequest(http.MethodPost, "http://mykeelapp.keel.so/auth/.well-known/jwks.json", nil)
+	request = request.WithContext(ctx)
+
+	response := authapi.JwksHandler()(request)
+	require.Equal(t, http.StatusMethodNotAllowed, response.Status)
+}
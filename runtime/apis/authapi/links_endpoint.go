@@ -0,0 +1,124 @@
+package authapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/common"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/schema/parser"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	ArgIssuer     = "issuer"
+	ArgExternalId = "external_id"
+)
+
+type LinkedIdentitiesResponse struct {
+	Links []*LinkedIdentityResponse `json:"links"`
+}
+
+type LinkedIdentityResponse struct {
+	Issuer     string    `json:"issuer"`
+	ExternalId string    `json:"external_id"`
+	Email      *string   `json:"email"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// LinksHandler lists the providers which have been linked to the authenticated identity,
+// in addition to the provider it was created with.
+func LinksHandler(schema *proto.Schema) common.HandlerFunc {
+	return func(r *http.Request) common.Response {
+		ctx, span := tracer.Start(r.Context(), "Links Endpoint")
+		defer span.End()
+
+		if r.Method != http.MethodGet {
+			return jsonErrResponse(ctx, http.StatusMethodNotAllowed, TokenErrInvalidRequest, "the links endpoint only accepts GET", nil)
+		}
+
+		identity, errResponse := authenticateBearerToken(ctx, schema, r)
+		if errResponse != nil {
+			return *errResponse
+		}
+
+		identityId := identity[parser.FieldNameId].(string)
+		span.SetAttributes(attribute.String("identity.id", identityId))
+
+		links, err := oauth.ListLinkedIdentities(ctx, identityId)
+		if err != nil {
+			return common.InternalServerErrorResponse(ctx, err)
+		}
+
+		response := &LinkedIdentitiesResponse{
+			Links: make([]*LinkedIdentityResponse, len(links)),
+		}
+		for i, link := range links {
+			response.Links[i] = &LinkedIdentityResponse{
+				Issuer:     link.Issuer,
+				ExternalId: link.ExternalId,
+				Email:      link.Email,
+				CreatedAt:  link.CreatedAt,
+			}
+		}
+
+		return common.NewJsonResponse(http.StatusOK, response, nil)
+	}
+}
+
+// LinksRemoveHandler unlinks a provider from the authenticated identity, after which signing in
+// with the provider no longer authenticates as the identity.
+func LinksRemoveHandler(schema *proto.Schema) common.HandlerFunc {
+	return func(r *http.Request) common.Response {
+		ctx, span := tracer.Start(r.Context(), "Links Remove Endpoint")
+		defer span.End()
+
+		if r.Method != http.MethodPost {
+			return jsonErrResponse(ctx, http.StatusMethodNotAllowed, TokenErrInvalidRequest, "the links remove endpoint only accepts POST", nil)
+		}
+
+		identity, errResponse := authenticateCredentialsBearerToken(ctx, schema, r)
+		if errResponse != nil {
+			return *errResponse
+		}
+
+		identityId := identity[parser.FieldNameId].(string)
+		span.SetAttributes(attribute.String("identity.id", identityId))
+
+		if !common.HasContentType(r.Header, "application/x-www-form-urlencoded") && !common.HasContentType(r.Header, "application/json") {
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the request body must either be an encoded form (Content-Type: application/x-www-form-urlencoded) or JSON (Content-Type: application/json)", nil)
+		}
+
+		data, err := common.ParseRequestData(r)
+		if err != nil {
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "request payload is malformed", err)
+		}
+
+		inputs, ok := data.(map[string]any)
+		if !ok {
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "request payload is malformed", nil)
+		}
+
+		issuer, hasIssuer := inputs[ArgIssuer].(string)
+		if !hasIssuer || issuer == "" {
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the provider's issuer in the 'issuer' field is required", nil)
+		}
+
+		externalId, hasExternalId := inputs[ArgExternalId].(string)
+		if !hasExternalId || externalId == "" {
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the identity at the provider in the 'external_id' field is required", nil)
+		}
+
+		unlinked, err := oauth.UnlinkIdentity(ctx, identityId, issuer, externalId)
+		if err != nil {
+			return common.InternalServerErrorResponse(ctx, err)
+		}
+
+		if !unlinked {
+			return jsonErrResponse(ctx, http.StatusNotFound, TokenErrInvalidRequest, "the provider's identity is not linked to this identity", nil)
+		}
+
+		return common.NewJsonResponse(http.StatusOK, nil, nil)
+	}
+}
@@ -0,0 +1,47 @@
+package authapi
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/teamkeel/keel/config"
+	"github.com/teamkeel/keel/functions"
+	"github.com/teamkeel/keel/runtime/common"
+	"github.com/teamkeel/keel/runtime/oauth"
+)
+
+// lockedOutResponse rejects a password attempt while the email address or IP address is locked out
+// or waiting for the progressive delay. The Retry-After header indicates when to try again.
+func lockedOutResponse(ctx context.Context, retryAfter time.Duration) common.Response {
+	resp := jsonErrResponse(ctx, http.StatusTooManyRequests, TokenErrTooManyAttempts, "too many failed sign in attempts, try again later", nil)
+	resp.Headers["Retry-After"] = []string{strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))}
+	return resp
+}
+
+// recordFailedPasswordAttempt counts the failed attempt and calls the afterLockout hook if it caused a lockout.
+func recordFailedPasswordAttempt(ctx context.Context, username string, ipAddress string) error {
+	lockout, err := oauth.RecordFailedAttempt(ctx, username, ipAddress)
+	if err != nil {
+		return err
+	}
+
+	if lockout == nil {
+		return nil
+	}
+
+	var ip any
+	if lockout.IpAddress != "" {
+		ip = lockout.IpAddress
+	}
+
+	return functions.CallPredefinedHookWithInputs(ctx, config.HookAfterLockout, map[string]any{
+		"email":           lockout.Email,
+		"ipAddress":       ip,
+		"emailLocked":     lockout.EmailLocked,
+		"ipAddressLocked": lockout.IpAddressLocked,
+		"lockedUntil":     lockout.LockedUntil,
+	})
+}
@@ -0,0 +1,302 @@
+package authapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/config"
+	"github.com/teamkeel/keel/runtime/apis/authapi"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+	keeltesting "github.com/teamkeel/keel/testing"
+)
+
+func withLockoutConfig(ctx context.Context, maxAttempts int, maxAttemptsPerIp int, delay int) context.Context {
+	enabled := true
+	duration := 600
+	return runtimectx.WithOAuthConfig(ctx, &config.AuthConfig{
+		Lockout: config.LockoutConfig{
+			Enabled:          &enabled,
+			MaxAttempts:      &maxAttempts,
+			MaxAttemptsPerIp: &maxAttemptsPerIp,
+			Duration:         &duration,
+			Delay:            &delay,
+		},
+	})
+}
+
+func TestPasswordGrant_LockedOutAfterMaxAttempts(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	ctx = withLockoutConfig(ctx, 3, 50, 0)
+
+	request := makePasswordFormRequest(ctx, "user@example.com", "myP@ssword1234!", nil)
+	_, httpResponse, err := handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	for i := 0; i < 3; i++ {
+		request = makePasswordFormRequest(ctx, "user@example.com", "whoops!", nil)
+		errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+		require.Equal(t, "invalid_client", errorResponse.Error)
+	}
+
+	// Even the correct credentials are rejected while locked out
+	request = makePasswordFormRequest(ctx, "user@example.com", "myP@ssword1234!", nil)
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, httpResponse.StatusCode)
+	require.Equal(t, "too_many_attempts", errorResponse.Error)
+	require.Equal(t, "too many failed sign in attempts, try again later", errorResponse.ErrorDescription)
+
+	retryAfter, err := strconv.Atoi(httpResponse.Header.Get("Retry-After"))
+	require.NoError(t, err)
+	require.Greater(t, retryAfter, 590)
+	require.LessOrEqual(t, retryAfter, 600)
+
+	// Email addresses are matched case-insensitively
+	request = makePasswordFormRequest(ctx, "USER@example.com", "myP@ssword1234!", nil)
+	_, httpResponse, err = handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, httpResponse.StatusCode)
+
+	// Other identities are not locked out
+	request = makePasswordFormRequest(ctx, "another@example.com", "myP@ssword1234!", nil)
+	_, httpResponse, err = handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+}
+
+func TestPasswordGrant_UnknownIdentityLockedOut(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	ctx = withLockoutConfig(ctx, 2, 50, 0)
+	createIfNotExists := false
+
+	for i := 0; i < 2; i++ {
+		request := makePasswordFormRequest(ctx, "nobody@example.com", "whoops!", &createIfNotExists)
+		_, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+	}
+
+	request := makePasswordFormRequest(ctx, "nobody@example.com", "whoops!", &createIfNotExists)
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, httpResponse.StatusCode)
+	require.Equal(t, "too_many_attempts", errorResponse.Error)
+}
+
+func TestPasswordGrant_LockedOutPerIpAddress(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	ctx = withLockoutConfig(ctx, 5, 2, 0)
+	createIfNotExists := false
+
+	for _, username := range []string{"one@example.com", "two@example.com"} {
+		request := makePasswordFormRequest(ctx, username, "whoops!", &createIfNotExists)
+		request.RemoteAddr = "203.0.113.7:1234"
+		_, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+	}
+
+	request := makePasswordFormRequest(ctx, "three@example.com", "myP@ssword1234!", nil)
+	request.RemoteAddr = "203.0.113.7:1234"
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, httpResponse.StatusCode)
+	require.Equal(t, "too_many_attempts", errorResponse.Error)
+
+	// Attempts from other IP addresses are permitted
+	request = makePasswordFormRequest(ctx, "three@example.com", "myP@ssword1234!", nil)
+	request.RemoteAddr = "198.51.100.2:1234"
+	_, httpResponse, err = handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+}
+
+func TestPasswordGrant_ForwardedForIgnoredWithoutTrustedProxy(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	ctx = withLockoutConfig(ctx, 5, 2, 0)
+	createIfNotExists := false
+
+	for i, username := range []string{"one@example.com", "two@example.com"} {
+		request := makePasswordFormRequest(ctx, username, "whoops!", &createIfNotExists)
+		request.RemoteAddr = "203.0.113.7:1234"
+		request.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+		_, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+	}
+
+	// Rotating the header does not avoid the IP address limit
+	request := makePasswordFormRequest(ctx, "three@example.com", "myP@ssword1234!", nil)
+	request.RemoteAddr = "203.0.113.7:1234"
+	request.Header.Set("X-Forwarded-For", "198.51.100.99")
+	_, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, httpResponse.StatusCode)
+}
+
+func TestPasswordGrant_ForwardedForFromTrustedProxy(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	ctx = withLockoutConfig(ctx, 5, 2, 0)
+	cfg, err := runtimectx.GetOAuthConfig(ctx)
+	require.NoError(t, err)
+	cfg.Lockout.TrustedProxies = []string{"10.0.0.0/8"}
+	createIfNotExists := false
+
+	// The client is the last address which is not a trusted proxy, regardless of what the client adds before it
+	for i, username := range []string{"one@example.com", "two@example.com"} {
+		request := makePasswordFormRequest(ctx, username, "whoops!", &createIfNotExists)
+		request.RemoteAddr = "10.0.0.5:1234"
+		request.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d, 203.0.113.7, 10.0.0.9", i))
+		_, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+	}
+
+	request := makePasswordFormRequest(ctx, "three@example.com", "myP@ssword1234!", nil)
+	request.RemoteAddr = "10.0.0.5:1234"
+	request.Header.Set("X-Forwarded-For", "203.0.113.7")
+	_, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, httpResponse.StatusCode)
+
+	request = makePasswordFormRequest(ctx, "three@example.com", "myP@ssword1234!", nil)
+	request.RemoteAddr = "10.0.0.5:1234"
+	request.Header.Set("X-Forwarded-For", "203.0.113.7, 198.51.100.2")
+	_, httpResponse, err = handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+}
+
+func TestPasswordGrant_ConcurrentAttemptsDelayed(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	ctx = withLockoutConfig(ctx, 5, 50, 30)
+
+	request := makePasswordFormRequest(ctx, "user@example.com", "myP@ssword1234!", nil)
+	_, httpResponse, err := handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	statuses := make([]int, 5)
+	var wg sync.WaitGroup
+	for i := range statuses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			request := makePasswordFormRequest(ctx, "user@example.com", "whoops!", nil)
+			_, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+			if err == nil {
+				statuses[i] = httpResponse.StatusCode
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Only the first attempt is checked, and the others must wait for the delay which follows it
+	require.Equal(t, 1, lo.Count(statuses, http.StatusUnauthorized))
+	require.Equal(t, 4, lo.Count(statuses, http.StatusTooManyRequests))
+}
+
+func TestPasswordGrant_ProgressiveDelay(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	ctx = withLockoutConfig(ctx, 5, 50, 30)
+
+	request := makePasswordFormRequest(ctx, "user@example.com", "myP@ssword1234!", nil)
+	_, httpResponse, err := handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	request = makePasswordFormRequest(ctx, "user@example.com", "whoops!", nil)
+	_, httpResponse, err = handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+
+	// The next attempt must wait for the delay
+	request = makePasswordFormRequest(ctx, "user@example.com", "myP@ssword1234!", nil)
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, httpResponse.StatusCode)
+	require.Equal(t, "too_many_attempts", errorResponse.Error)
+
+	retryAfter, err := strconv.Atoi(httpResponse.Header.Get("Retry-After"))
+	require.NoError(t, err)
+	require.Greater(t, retryAfter, 20)
+	require.LessOrEqual(t, retryAfter, 30)
+}
+
+func TestPasswordGrant_SuccessfulAttemptClearsFailures(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	ctx = withLockoutConfig(ctx, 2, 50, 0)
+
+	request := makePasswordFormRequest(ctx, "user@example.com", "myP@ssword1234!", nil)
+	_, httpResponse, err := handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	for i := 0; i < 3; i++ {
+		request = makePasswordFormRequest(ctx, "user@example.com", "whoops!", nil)
+		_, httpResponse, err = handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+
+		request = makePasswordFormRequest(ctx, "user@example.com", "myP@ssword1234!", nil)
+		_, httpResponse, err = handleRuntimeRequest[authapi.TokenResponse](schema, request)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	}
+}
+
+func TestPasswordGrant_AfterForcedPasswordReset(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	ctx = withLockoutConfig(ctx, 2, 50, 0)
+
+	request := makePasswordFormRequest(ctx, "user@example.com", "myP@ssword1234!", nil)
+	_, httpResponse, err := handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	// Forcing a password reset removes the identity's password
+	err = database.GetDB().Exec("UPDATE identity SET password = NULL WHERE email = ?", "user@example.com").Error
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		request = makePasswordFormRequest(ctx, "user@example.com", "myP@ssword1234!", nil)
+		errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+		require.Equal(t, "invalid_client", errorResponse.Error)
+	}
+
+	// The failed attempts are still recorded
+	request = makePasswordFormRequest(ctx, "user@example.com", "myP@ssword1234!", nil)
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, httpResponse.StatusCode)
+	require.Equal(t, "too_many_attempts", errorResponse.Error)
+}
@@ -0,0 +1,184 @@
+package authapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/auth"
+	"github.com/teamkeel/keel/runtime/common"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/schema/parser"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// Used as the issuer in the authenticator app when KEEL_API_URL is not set
+	defaultTotpIssuer = "Keel"
+)
+
+type MfaEnrolResponse struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+}
+
+type MfaConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MfaEnrolHandler generates a new TOTP secret for the authenticated identity. The otpauth uri
+// can be displayed as a QR code for an authenticator app to scan. The authenticator is
+// only required when authenticating once it has been confirmed.
+func MfaEnrolHandler(schema *proto.Schema) common.HandlerFunc {
+	return func(r *http.Request) common.Response {
+		ctx, span := tracer.Start(r.Context(), "MFA Enrol Endpoint")
+		defer span.End()
+
+		if r.Method != http.MethodPost {
+			return jsonErrResponse(ctx, http.StatusMethodNotAllowed, TokenErrInvalidRequest, "the mfa enrol endpoint only accepts POST", nil)
+		}
+
+		identity, errResponse := authenticateCredentialsBearerToken(ctx, schema, r)
+		if errResponse != nil {
+			return *errResponse
+		}
+
+		identityId := identity[parser.FieldNameId].(string)
+		span.SetAttributes(attribute.String("identity.id", identityId))
+
+		secret, err := oauth.EnrolTotp(ctx, identityId)
+		if errors.Is(err, oauth.ErrMfaAlreadyEnrolled) {
+			return jsonErrResponse(ctx, http.StatusConflict, TokenErrInvalidRequest, "an authenticator has already been enrolled and must be removed before enrolling another", nil)
+		} else if err != nil {
+			return common.InternalServerErrorResponse(ctx, err)
+		}
+
+		issuer := defaultTotpIssuer
+		if apiUrl, err := url.ParseRequestURI(os.Getenv("KEEL_API_URL")); err == nil && apiUrl.Hostname() != "" {
+			issuer = apiUrl.Hostname()
+		}
+
+		accountName, _ := identity[parser.IdentityFieldNameEmail].(string)
+		if accountName == "" {
+			accountName = identityId
+		}
+
+		return common.NewJsonResponse(http.StatusOK, &MfaEnrolResponse{
+			Secret: secret,
+			Uri:    oauth.TotpUri(secret, issuer, accountName),
+		}, nil)
+	}
+}
+
+// MfaConfirmHandler confirms the enrolled authenticator with a code from it, after which a code will be
+// required when authenticating. The recovery codes are only ever returned in this response.
+func MfaConfirmHandler(schema *proto.Schema) common.HandlerFunc {
+	return func(r *http.Request) common.Response {
+		ctx, span := tracer.Start(r.Context(), "MFA Confirm Endpoint")
+		defer span.End()
+
+		identity, code, errResponse := parseMfaRequest(ctx, r, schema, "confirm")
+		if errResponse != nil {
+			return *errResponse
+		}
+
+		identityId := identity[parser.FieldNameId].(string)
+		span.SetAttributes(attribute.String("identity.id", identityId))
+
+		recoveryCodes, err := oauth.ConfirmTotp(ctx, identityId, code)
+		if errors.Is(err, oauth.ErrMfaAlreadyEnrolled) {
+			return jsonErrResponse(ctx, http.StatusConflict, TokenErrInvalidRequest, "the authenticator has already been confirmed", nil)
+		} else if err != nil {
+			return common.InternalServerErrorResponse(ctx, err)
+		}
+
+		if recoveryCodes == nil {
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "possible causes may be that the code is incorrect or no authenticator has been enrolled", nil)
+		}
+
+		return common.NewJsonResponse(http.StatusOK, &MfaConfirmResponse{
+			RecoveryCodes: recoveryCodes,
+		}, nil)
+	}
+}
+
+// MfaRemoveHandler removes the authenticator and recovery codes from the authenticated identity.
+// A code from the authenticator or a recovery code is required.
+func MfaRemoveHandler(schema *proto.Schema) common.HandlerFunc {
+	return func(r *http.Request) common.Response {
+		ctx, span := tracer.Start(r.Context(), "MFA Remove Endpoint")
+		defer span.End()
+
+		identity, code, errResponse := parseMfaRequest(ctx, r, schema, "remove")
+		if errResponse != nil {
+			return *errResponse
+		}
+
+		identityId := identity[parser.FieldNameId].(string)
+		span.SetAttributes(attribute.String("identity.id", identityId))
+
+		isValid, err := oauth.VerifyMfaCode(ctx, identityId, code)
+		if err != nil {
+			return common.InternalServerErrorResponse(ctx, err)
+		}
+
+		if !isValid {
+			isValid, err = oauth.ConsumeRecoveryCode(ctx, identityId, code)
+			if err != nil {
+				return common.InternalServerErrorResponse(ctx, err)
+			}
+		}
+
+		if !isValid {
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "possible causes may be that the code is incorrect or has already been used", nil)
+		}
+
+		err = oauth.RemoveMfa(ctx, identityId)
+		if err != nil {
+			return common.InternalServerErrorResponse(ctx, err)
+		}
+
+		return common.NewJsonResponse(http.StatusOK, nil, nil)
+	}
+}
+
+// parseMfaRequest authenticates the bearer token and parses the code from the request body.
+func parseMfaRequest(ctx context.Context, r *http.Request, schema *proto.Schema, endpoint string) (auth.Identity, string, *common.Response) {
+	if r.Method != http.MethodPost {
+		resp := jsonErrResponse(ctx, http.StatusMethodNotAllowed, TokenErrInvalidRequest, "the mfa "+endpoint+" endpoint only accepts POST", nil)
+		return nil, "", &resp
+	}
+
+	identity, errResponse := authenticateCredentialsBearerToken(ctx, schema, r)
+	if errResponse != nil {
+		return nil, "", errResponse
+	}
+
+	if !common.HasContentType(r.Header, "application/x-www-form-urlencoded") && !common.HasContentType(r.Header, "application/json") {
+		resp := jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the request body must either be an encoded form (Content-Type: application/x-www-form-urlencoded) or JSON (Content-Type: application/json)", nil)
+		return nil, "", &resp
+	}
+
+	data, err := common.ParseRequestData(r)
+	if err != nil {
+		resp := jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "request payload is malformed", err)
+		return nil, "", &resp
+	}
+
+	inputs, ok := data.(map[string]any)
+	if !ok {
+		resp := jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "request payload is malformed", nil)
+		return nil, "", &resp
+	}
+
+	code, hasCode := inputs[ArgCode].(string)
+	if !hasCode || code == "" {
+		resp := jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the code in the 'code' field is required", nil)
+		return nil, "", &resp
+	}
+
+	return identity, code, nil
+}
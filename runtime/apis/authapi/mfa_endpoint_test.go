@@ -0,0 +1,354 @@
+package authapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/config"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/apis/authapi"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+	"github.com/teamkeel/keel/testhelpers"
+	keeltesting "github.com/teamkeel/keel/testing"
+)
+
+func TestMfa_PasswordGrantRequiresCode(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	secret := signUpAndEnrolMfa(t, ctx, schema, "keelson@keel.so", "1234")
+
+	request := makePasswordFormRequest(ctx, "keelson@keel.so", "1234", nil)
+	challenge, httpResponse, err := handleRuntimeRequest[authapi.MfaRequiredResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusForbidden, httpResponse.StatusCode)
+	require.Equal(t, "mfa_required", challenge.Error)
+	require.NotEmpty(t, challenge.MfaToken)
+
+	// The code used to confirm the authenticator cannot be used again, so use the next one
+	code, err := oauth.GenerateTotpCode(secret, time.Now().Add(30*time.Second))
+	require.NoError(t, err)
+
+	request = makeMfaOtpFormRequest(ctx, challenge.MfaToken, "otp", code)
+	response, httpResponse, err := handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.NotEmpty(t, response.AccessToken)
+	require.NotEmpty(t, response.RefreshToken)
+	require.True(t, oauth.IsMfaAccessToken(response.AccessToken))
+
+	// Refreshed access tokens continue to be marked as having used a second factor
+	request = makeRefreshTokenFormRequest(ctx, response.RefreshToken)
+	refreshed, httpResponse, err := handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.True(t, oauth.IsMfaAccessToken(refreshed.AccessToken))
+}
+
+func TestMfa_EmailVerificationRequiredBeforeCode(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	signUpAndEnrolMfa(t, ctx, schema, "keelson@keel.so", "1234")
+
+	required := true
+	ctx = runtimectx.WithOAuthConfig(ctx, &config.AuthConfig{
+		Email: config.EmailConfig{
+			VerificationRequired: &required,
+		},
+	})
+
+	request := makePasswordFormRequest(ctx, "keelson@keel.so", "1234", nil)
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusForbidden, httpResponse.StatusCode)
+	require.Equal(t, "email_not_verified", errorResponse.Error)
+}
+
+func TestMfa_RecoveryCode(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	accessToken := signUp(t, ctx, schema, "keelson@keel.so", "1234")
+	secret := enrolMfa(t, ctx, schema, accessToken)
+	recoveryCodes := confirmMfa(t, ctx, schema, accessToken, secret)
+
+	request := makePasswordFormRequest(ctx, "keelson@keel.so", "1234", nil)
+	challenge, _, err := handleRuntimeRequest[authapi.MfaRequiredResponse](schema, request)
+	require.NoError(t, err)
+
+	request = makeMfaOtpFormRequest(ctx, challenge.MfaToken, "recovery_code", recoveryCodes[0])
+	response, httpResponse, err := handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.True(t, oauth.IsMfaAccessToken(response.AccessToken))
+
+	// Recovery codes can only be used once
+	request = makeMfaOtpFormRequest(ctx, challenge.MfaToken, "recovery_code", recoveryCodes[0])
+	errResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+	require.Equal(t, "invalid_client", errResponse.Error)
+}
+
+func TestMfa_IncorrectCode(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	signUpAndEnrolMfa(t, ctx, schema, "keelson@keel.so", "1234")
+
+	request := makePasswordFormRequest(ctx, "keelson@keel.so", "1234", nil)
+	challenge, _, err := handleRuntimeRequest[authapi.MfaRequiredResponse](schema, request)
+	require.NoError(t, err)
+
+	request = makeMfaOtpFormRequest(ctx, challenge.MfaToken, "otp", "abcdef")
+	errResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+	require.Equal(t, "invalid_client", errResponse.Error)
+	require.Equal(t, "possible causes may be that the code is incorrect or has already been used", errResponse.ErrorDescription)
+}
+
+func TestMfa_InvalidMfaToken(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	accessToken := signUp(t, ctx, schema, "keelson@keel.so", "1234")
+
+	// An access token cannot be used in place of an mfa token
+	request := makeMfaOtpFormRequest(ctx, accessToken, "otp", "123456")
+	errResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+	require.Equal(t, "invalid_client", errResponse.Error)
+	require.Equal(t, "possible causes may be that the mfa token is invalid or has expired", errResponse.ErrorDescription)
+}
+
+func TestMfa_MissingCode(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	signUpAndEnrolMfa(t, ctx, schema, "keelson@keel.so", "1234")
+
+	request := makePasswordFormRequest(ctx, "keelson@keel.so", "1234", nil)
+	challenge, _, err := handleRuntimeRequest[authapi.MfaRequiredResponse](schema, request)
+	require.NoError(t, err)
+
+	request = makeMfaOtpFormRequest(ctx, challenge.MfaToken, "otp", "")
+	errResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, httpResponse.StatusCode)
+	require.Equal(t, "invalid_request", errResponse.Error)
+}
+
+func TestMfa_WithoutEnrolmentNoChallenge(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	accessToken := signUp(t, ctx, schema, "keelson@keel.so", "1234")
+
+	// Enrolled but not yet confirmed
+	enrolMfa(t, ctx, schema, accessToken)
+
+	request := makePasswordFormRequest(ctx, "keelson@keel.so", "1234", nil)
+	response, httpResponse, err := handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.False(t, oauth.IsMfaAccessToken(response.AccessToken))
+}
+
+func TestMfa_EnrolTwice(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	accessToken := signUp(t, ctx, schema, "keelson@keel.so", "1234")
+	secret := enrolMfa(t, ctx, schema, accessToken)
+	confirmMfa(t, ctx, schema, accessToken, secret)
+
+	request := makeMfaRequest(ctx, "enrol", accessToken, "")
+	errResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusConflict, httpResponse.StatusCode)
+	require.Equal(t, "invalid_request", errResponse.Error)
+}
+
+func TestMfa_EnrolUri(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	t.Setenv("KEEL_API_URL", "https://myapp.keelapps.xyz")
+
+	accessToken := signUp(t, ctx, schema, "keelson@keel.so", "1234")
+
+	request := makeMfaRequest(ctx, "enrol", accessToken, "")
+	response, httpResponse, err := handleRuntimeRequest[authapi.MfaEnrolResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	uri, err := url.Parse(response.Uri)
+	require.NoError(t, err)
+	require.Equal(t, "/myapp.keelapps.xyz:keelson@keel.so", uri.Path)
+	require.Equal(t, response.Secret, uri.Query().Get("secret"))
+}
+
+func TestMfa_ConfirmIncorrectCode(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	accessToken := signUp(t, ctx, schema, "keelson@keel.so", "1234")
+	enrolMfa(t, ctx, schema, accessToken)
+
+	request := makeMfaRequest(ctx, "confirm", accessToken, "000000")
+	errResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, httpResponse.StatusCode)
+	require.Equal(t, "invalid_request", errResponse.Error)
+}
+
+func TestMfa_Remove(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	accessToken := signUp(t, ctx, schema, "keelson@keel.so", "1234")
+	secret := enrolMfa(t, ctx, schema, accessToken)
+	recoveryCodes := confirmMfa(t, ctx, schema, accessToken, secret)
+
+	request := makeMfaRequest(ctx, "remove", accessToken, "wrong-code")
+	_, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, httpResponse.StatusCode)
+
+	request = makeMfaRequest(ctx, "remove", accessToken, recoveryCodes[0])
+	_, httpResponse, err = handleRuntimeRequest[any](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	request = makePasswordFormRequest(ctx, "keelson@keel.so", "1234", nil)
+	_, httpResponse, err = handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+}
+
+func TestMfaEnrol_HttpGet(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "http://mykeelapp.keel.so/auth/mfa/enrol", nil)
+
+	response := authapi.MfaEnrolHandler(nil)(request)
+	require.Equal(t, http.StatusMethodNotAllowed, response.Status)
+}
+
+func TestMfaEnrol_MissingToken(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "http://mykeelapp.keel.so/auth/mfa/enrol", nil)
+
+	response := authapi.MfaEnrolHandler(nil)(request)
+	require.Equal(t, http.StatusUnauthorized, response.Status)
+	require.Contains(t, response.Headers["WWW-Authenticate"][0], `error="invalid_request"`)
+}
+
+func TestMfaEnrol_MfaTokenRejected(t *testing.T) {
+	pk, err := testhelpers.GetEmbeddedPrivateKey()
+	require.NoError(t, err)
+
+	ctx := runtimectx.WithPrivateKey(context.Background(), pk)
+
+	mfaToken, err := oauth.GenerateMfaToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	request := makeMfaRequest(ctx, "enrol", mfaToken, "")
+
+	response := authapi.MfaEnrolHandler(nil)(request)
+	require.Equal(t, http.StatusUnauthorized, response.Status)
+	require.Contains(t, response.Headers["WWW-Authenticate"][0], `error="invalid_token"`)
+}
+
+func TestMfaConfirm_ClientTokenRejected(t *testing.T) {
+	pk, err := testhelpers.GetEmbeddedPrivateKey()
+	require.NoError(t, err)
+
+	ctx := runtimectx.WithPrivateKey(context.Background(), pk)
+
+	accessToken, _, err := oauth.GenerateClientAccessToken(ctx, "my-client", []string{"Admin"})
+	require.NoError(t, err)
+
+	request := makeMfaRequest(ctx, "confirm", accessToken, "123456")
+
+	response := authapi.MfaConfirmHandler(nil)(request)
+	require.Equal(t, http.StatusUnauthorized, response.Status)
+	require.Contains(t, response.Headers["WWW-Authenticate"][0], `error="invalid_token"`)
+}
+
+func signUp(t *testing.T, ctx context.Context, schema *proto.Schema, email string, password string) string {
+	request := makePasswordFormRequest(ctx, email, password, nil)
+	response, httpResponse, err := handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	return response.AccessToken
+}
+
+func enrolMfa(t *testing.T, ctx context.Context, schema *proto.Schema, accessToken string) string {
+	request := makeMfaRequest(ctx, "enrol", accessToken, "")
+	response, httpResponse, err := handleRuntimeRequest[authapi.MfaEnrolResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.NotEmpty(t, response.Secret)
+
+	return response.Secret
+}
+
+func confirmMfa(t *testing.T, ctx context.Context, schema *proto.Schema, accessToken string, secret string) []string {
+	code, err := oauth.GenerateTotpCode(secret, time.Now())
+	require.NoError(t, err)
+
+	request := makeMfaRequest(ctx, "confirm", accessToken, code)
+	response, httpResponse, err := handleRuntimeRequest[authapi.MfaConfirmResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.Len(t, response.RecoveryCodes, 10)
+
+	return response.RecoveryCodes
+}
+
+func signUpAndEnrolMfa(t *testing.T, ctx context.Context, schema *proto.Schema, email string, password string) string {
+	accessToken := signUp(t, ctx, schema, email, password)
+	secret := enrolMfa(t, ctx, schema, accessToken)
+	confirmMfa(t, ctx, schema, accessToken, secret)
+
+	return secret
+}
+
+func makeMfaRequest(ctx context.Context, endpoint string, accessToken string, code string) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "http://mykeelapp.keel.so/auth/mfa/"+endpoint, nil)
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+
+	form := url.Values{}
+	if code != "" {
+		form.Add("code", code)
+	}
+
+	request.URL.RawQuery = form.Encode()
+	request = request.WithContext(ctx)
+
+	return request
+}
+
+func makeMfaOtpFormRequest(ctx context.Context, mfaToken string, codeField string, code string) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "http://mykeelapp.keel.so/auth/token", nil)
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	form := url.Values{}
+	form.Add("grant_type", "mfa_otp")
+	form.Add("mfa_token", mfaToken)
+	form.Add(codeField, code)
+
+	request.URL.RawQuery = form.Encode()
+	request = request.WithContext(ctx)
+
+	return request
+}
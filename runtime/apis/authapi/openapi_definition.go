@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/samber/lo"
 	cfg "github.com/teamkeel/keel/config"
 	"github.com/teamkeel/keel/runtime/common"
 	"github.com/teamkeel/keel/runtime/jsonschema"
@@ -54,6 +55,67 @@ func OAuthOpenApiSchema() common.HandlerFunc {
 			},
 		}
 
+		definition.Paths["/auth/.well-known/jwks.json"] = openapi.PathItemObject{
+			Get: &openapi.OperationObject{
+				Responses: map[string]openapi.ResponseObject{
+					"200": {
+						Description: "JSON Web Key Set",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/JwksResponse",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		definition.Paths["/auth/.well-known/openid-configuration"] = openapi.PathItemObject{
+			Get: &openapi.OperationObject{
+				Responses: map[string]openapi.ResponseObject{
+					"200": {
+						Description: "OpenID Connect Discovery Document",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/OpenIdConfigurationResponse",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		definition.Paths["/auth/userinfo"] = openapi.PathItemObject{
+			Get: &openapi.OperationObject{
+				Responses: map[string]openapi.ResponseObject{
+					"200": {
+						Description: "Identity Claims",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/UserInfoResponse",
+								},
+							},
+						},
+					},
+					"401": {
+						Description: "Access Token Missing or Invalid",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/TokenErrorResponse",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
 		definition.Paths["/auth/token"] = openapi.PathItemObject{
 			Post: &openapi.OperationObject{
 				RequestBody: &openapi.RequestBodyObject{
@@ -103,6 +165,26 @@ func OAuthOpenApiSchema() common.HandlerFunc {
 							},
 						},
 					},
+					"403": {
+						Description: "Multi-factor Authentication Required",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/MfaRequiredResponse",
+								},
+							},
+						},
+					},
+					"429": {
+						Description: "Too Many Failed Attempts",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/TokenErrorResponse",
+								},
+							},
+						},
+					},
 				},
 			},
 		}
@@ -134,6 +216,271 @@ func OAuthOpenApiSchema() common.HandlerFunc {
 			}
 		}
 
+		definition.Paths["/auth/passwordless"] = openapi.PathItemObject{
+			Post: &openapi.OperationObject{
+				RequestBody: &openapi.RequestBodyObject{
+					Description: "Passwordless Code Request",
+					Content: map[string]openapi.MediaTypeObject{
+						"application/json": {
+							Schema: jsonschema.JSONSchema{
+								Ref: "#/components/schemas/PasswordlessRequest",
+							},
+						},
+						"application/x-www-form-urlencoded": {
+							Schema: jsonschema.JSONSchema{
+								Ref: "#/components/schemas/PasswordlessRequest",
+							},
+						},
+					},
+					Required: &boolTrue,
+				},
+				Responses: map[string]openapi.ResponseObject{
+					"200": {
+						Description: "Passwordless Code Sent",
+					},
+					"400": {
+						Description: "Passwordless Code Request Badly Formed",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/TokenErrorResponse",
+								},
+							},
+						},
+					},
+					"429": {
+						Description: "Too Many Passwordless Codes Requested",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/TokenErrorResponse",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		definition.Paths["/auth/mfa/enrol"] = openapi.PathItemObject{
+			Post: &openapi.OperationObject{
+				Responses: map[string]openapi.ResponseObject{
+					"200": {
+						Description: "Authenticator Enrolled",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/MfaEnrolResponse",
+								},
+							},
+						},
+					},
+					"400": {
+						Description: "MFA Enrol Request Badly Formed",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/TokenErrorResponse",
+								},
+							},
+						},
+					},
+					"401": {
+						Description: "Access Token Missing or Invalid",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/TokenErrorResponse",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		definition.Paths["/auth/mfa/confirm"] = openapi.PathItemObject{
+			Post: &openapi.OperationObject{
+				RequestBody: &openapi.RequestBodyObject{
+					Description: "MFA Confirm Request",
+					Content: map[string]openapi.MediaTypeObject{
+						"application/json": {
+							Schema: jsonschema.JSONSchema{
+								Ref: "#/components/schemas/MfaCodeRequest",
+							},
+						},
+						"application/x-www-form-urlencoded": {
+							Schema: jsonschema.JSONSchema{
+								Ref: "#/components/schemas/MfaCodeRequest",
+							},
+						},
+					},
+					Required: &boolTrue,
+				},
+				Responses: map[string]openapi.ResponseObject{
+					"200": {
+						Description: "Authenticator Confirmed",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/MfaConfirmResponse",
+								},
+							},
+						},
+					},
+					"400": {
+						Description: "MFA Confirm Request Badly Formed",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/TokenErrorResponse",
+								},
+							},
+						},
+					},
+					"401": {
+						Description: "Access Token Missing or Invalid",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/TokenErrorResponse",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		definition.Paths["/auth/links"] = openapi.PathItemObject{
+			Get: &openapi.OperationObject{
+				Responses: map[string]openapi.ResponseObject{
+					"200": {
+						Description: "Linked Providers",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/LinkedIdentitiesResponse",
+								},
+							},
+						},
+					},
+					"401": {
+						Description: "Access Token Missing or Invalid",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/TokenErrorResponse",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		definition.Paths["/auth/links/remove"] = openapi.PathItemObject{
+			Post: &openapi.OperationObject{
+				RequestBody: &openapi.RequestBodyObject{
+					Description: "Unlink Provider Request",
+					Content: map[string]openapi.MediaTypeObject{
+						"application/json": {
+							Schema: jsonschema.JSONSchema{
+								Ref: "#/components/schemas/LinksRemoveRequest",
+							},
+						},
+						"application/x-www-form-urlencoded": {
+							Schema: jsonschema.JSONSchema{
+								Ref: "#/components/schemas/LinksRemoveRequest",
+							},
+						},
+					},
+					Required: &boolTrue,
+				},
+				Responses: map[string]openapi.ResponseObject{
+					"200": {
+						Description: "Provider Unlinked",
+					},
+					"400": {
+						Description: "Unlink Provider Request Badly Formed",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/TokenErrorResponse",
+								},
+							},
+						},
+					},
+					"401": {
+						Description: "Access Token Missing or Invalid",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/TokenErrorResponse",
+								},
+							},
+						},
+					},
+					"404": {
+						Description: "Provider Not Linked",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/TokenErrorResponse",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		definition.Paths["/auth/mfa/remove"] = openapi.PathItemObject{
+			Post: &openapi.OperationObject{
+				RequestBody: &openapi.RequestBodyObject{
+					Description: "MFA Remove Request",
+					Content: map[string]openapi.MediaTypeObject{
+						"application/json": {
+							Schema: jsonschema.JSONSchema{
+								Ref: "#/components/schemas/MfaCodeRequest",
+							},
+						},
+						"application/x-www-form-urlencoded": {
+							Schema: jsonschema.JSONSchema{
+								Ref: "#/components/schemas/MfaCodeRequest",
+							},
+						},
+					},
+					Required: &boolTrue,
+				},
+				Responses: map[string]openapi.ResponseObject{
+					"200": {
+						Description: "Authenticator Removed",
+					},
+					"400": {
+						Description: "MFA Remove Request Badly Formed",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/TokenErrorResponse",
+								},
+							},
+						},
+					},
+					"401": {
+						Description: "Access Token Missing or Invalid",
+						Content: map[string]openapi.MediaTypeObject{
+							"application/json": {
+								Schema: jsonschema.JSONSchema{
+									Ref: "#/components/schemas/TokenErrorResponse",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
 		definition.Paths["/auth/revoke"] = openapi.PathItemObject{
 			Post: &openapi.OperationObject{
 				RequestBody: &openapi.RequestBodyObject{
@@ -223,6 +570,27 @@ func OAuthOpenApiSchema() common.HandlerFunc {
 					Title:                "Password",
 					AdditionalProperties: &boolFalse,
 				},
+				{
+					Type: "object",
+					Properties: map[string]jsonschema.JSONSchema{
+						"grant_type": {
+							Const:   "passwordless",
+							Default: "passwordless",
+						},
+						"username": {
+							Type: "string",
+						},
+						"code": {
+							Type: "string",
+						},
+						"create_if_not_exists": {
+							Type: "boolean",
+						},
+					},
+					Required:             []string{"grant_type", "username", "code"},
+					Title:                "Passwordless",
+					AdditionalProperties: &boolFalse,
+				},
 				{
 					Type: "object",
 					Properties: map[string]jsonschema.JSONSchema{
@@ -251,6 +619,9 @@ func OAuthOpenApiSchema() common.HandlerFunc {
 						"code": {
 							Type: "string",
 						},
+						"code_verifier": {
+							Type: "string",
+						},
 					},
 					Required:             []string{"grant_type", "code"},
 					Title:                "Authorization Code",
@@ -271,6 +642,164 @@ func OAuthOpenApiSchema() common.HandlerFunc {
 					Title:                "Refresh Token",
 					AdditionalProperties: &boolFalse,
 				},
+				{
+					Type: "object",
+					Properties: map[string]jsonschema.JSONSchema{
+						"grant_type": {
+							Const:   "mfa_otp",
+							Default: "mfa_otp",
+						},
+						"mfa_token": {
+							Type: "string",
+						},
+						"otp": {
+							Type: "string",
+						},
+						"recovery_code": {
+							Type: "string",
+						},
+					},
+					Required:             []string{"grant_type", "mfa_token"},
+					Title:                "MFA One-time Password",
+					AdditionalProperties: &boolFalse,
+				},
+				{
+					Type: "object",
+					Properties: map[string]jsonschema.JSONSchema{
+						"grant_type": {
+							Const:   "impersonation",
+							Default: "impersonation",
+						},
+						"actor_token": {
+							Type: "string",
+						},
+						"identity_id": {
+							Type: "string",
+						},
+					},
+					Required:             []string{"grant_type", "actor_token", "identity_id"},
+					Title:                "Impersonation",
+					AdditionalProperties: &boolFalse,
+				},
+				{
+					Type: "object",
+					Properties: map[string]jsonschema.JSONSchema{
+						"grant_type": {
+							Const:   "client_credentials",
+							Default: "client_credentials",
+						},
+						"client_id": {
+							Type: "string",
+						},
+						"client_secret": {
+							Type: "string",
+						},
+						"scope": {
+							Type: "string",
+						},
+					},
+					Required:             []string{"grant_type"},
+					Title:                "Client Credentials",
+					AdditionalProperties: &boolFalse,
+				},
+			},
+		}
+
+		definition.Components.Schemas["PasswordlessRequest"] = jsonschema.JSONSchema{
+			Type: "object",
+			Properties: map[string]jsonschema.JSONSchema{
+				"email": {
+					Type: "string",
+				},
+				"code_type": {
+					Type: "string",
+					Enum: []*string{lo.ToPtr("magic_link"), lo.ToPtr("otp")},
+				},
+				"create_if_not_exists": {
+					Type: "boolean",
+				},
+			},
+			Required:             []string{"email", "code_type"},
+			AdditionalProperties: &boolFalse,
+		}
+
+		definition.Components.Schemas["MfaCodeRequest"] = jsonschema.JSONSchema{
+			Type: "object",
+			Properties: map[string]jsonschema.JSONSchema{
+				"code": {
+					Type: "string",
+				},
+			},
+			Required:             []string{"code"},
+			AdditionalProperties: &boolFalse,
+		}
+
+		definition.Components.Schemas["LinksRemoveRequest"] = jsonschema.JSONSchema{
+			Type: "object",
+			Properties: map[string]jsonschema.JSONSchema{
+				"issuer": {
+					Type: "string",
+				},
+				"external_id": {
+					Type: "string",
+				},
+			},
+			Required:             []string{"issuer", "external_id"},
+			AdditionalProperties: &boolFalse,
+		}
+
+		definition.Components.Schemas["LinkedIdentitiesResponse"] = jsonschema.JSONSchema{
+			Type: "object",
+			Properties: map[string]jsonschema.JSONSchema{
+				"links": {
+					Type: "array",
+					Items: &jsonschema.JSONSchema{
+						Type: "object",
+						Properties: map[string]jsonschema.JSONSchema{
+							"issuer":      {Type: "string"},
+							"external_id": {Type: "string"},
+							"email":       {Type: []string{"string", "null"}},
+							"created_at":  {Type: "string", Format: "date-time"},
+						},
+					},
+				},
+			},
+		}
+
+		definition.Components.Schemas["MfaEnrolResponse"] = jsonschema.JSONSchema{
+			Type: "object",
+			Properties: map[string]jsonschema.JSONSchema{
+				"secret": {
+					Type: "string",
+				},
+				"uri": {
+					Type: "string",
+				},
+			},
+		}
+
+		definition.Components.Schemas["MfaConfirmResponse"] = jsonschema.JSONSchema{
+			Type: "object",
+			Properties: map[string]jsonschema.JSONSchema{
+				"recovery_codes": {
+					Type:  "array",
+					Items: &jsonschema.JSONSchema{Type: "string"},
+				},
+			},
+		}
+
+		definition.Components.Schemas["MfaRequiredResponse"] = jsonschema.JSONSchema{
+			Type: "object",
+			Properties: map[string]jsonschema.JSONSchema{
+				"error": {
+					Type: "string",
+				},
+				"error_description": {
+					Type: "string",
+				},
+				"mfa_token": {
+					Type: "string",
+				},
 			},
 		}
 
@@ -304,9 +833,84 @@ func OAuthOpenApiSchema() common.HandlerFunc {
 				"identity_created": {
 					Type: "boolean",
 				},
+				"scope": {
+					Type: "string",
+				},
 			},
 		}
 
+		definition.Components.Schemas["JwksResponse"] = jsonschema.JSONSchema{
+			Type: "object",
+			Properties: map[string]jsonschema.JSONSchema{
+				"keys": {
+					Type: "array",
+					Items: &jsonschema.JSONSchema{
+						Type: "object",
+						Properties: map[string]jsonschema.JSONSchema{
+							"kty": {
+								Type: "string",
+							},
+							"use": {
+								Type: "string",
+							},
+							"alg": {
+								Type: "string",
+							},
+							"kid": {
+								Type: "string",
+							},
+							"n": {
+								Type: "string",
+							},
+							"e": {
+								Type: "string",
+							},
+						},
+					},
+				},
+			},
+		}
+
+		stringArray := jsonschema.JSONSchema{
+			Type:  "array",
+			Items: &jsonschema.JSONSchema{Type: "string"},
+		}
+
+		definition.Components.Schemas["OpenIdConfigurationResponse"] = jsonschema.JSONSchema{
+			Type: "object",
+			Properties: map[string]jsonschema.JSONSchema{
+				"issuer":                                {Type: "string"},
+				"authorization_endpoint":                {Type: "string"},
+				"token_endpoint":                        {Type: "string"},
+				"revocation_endpoint":                   {Type: "string"},
+				"userinfo_endpoint":                     {Type: "string"},
+				"jwks_uri":                              {Type: "string"},
+				"response_types_supported":              stringArray,
+				"subject_types_supported":               stringArray,
+				"id_token_signing_alg_values_supported": stringArray,
+				"grant_types_supported":                 stringArray,
+				"token_endpoint_auth_methods_supported": stringArray,
+				"code_challenge_methods_supported":      stringArray,
+				"scopes_supported":                      stringArray,
+				"claims_supported":                      stringArray,
+			},
+		}
+
+		userInfo := jsonschema.JSONSchema{
+			Type: "object",
+			Properties: map[string]jsonschema.JSONSchema{
+				"sub": {Type: "string"},
+			},
+		}
+		for _, claim := range standardClaims {
+			if claim == "email_verified" {
+				userInfo.Properties[claim] = jsonschema.JSONSchema{Type: "boolean"}
+			} else {
+				userInfo.Properties[claim] = jsonschema.JSONSchema{Type: "string"}
+			}
+		}
+		definition.Components.Schemas["UserInfoResponse"] = userInfo
+
 		definition.Components.Schemas["TokenErrorResponse"] = jsonschema.JSONSchema{
 			Type: "object",
 			Properties: map[string]jsonschema.JSONSchema{
@@ -0,0 +1,155 @@
+package authapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	email "net/mail"
+
+	"github.com/teamkeel/keel/mail"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/actions"
+	"github.com/teamkeel/keel/runtime/common"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	ArgEmail    = "email"
+	ArgCodeType = "code_type"
+)
+
+// PasswordlessHandler sends a single-use code to the email address, which can then be exchanged
+// at the token endpoint using the passwordless grant. The code is either sent as a magic link to the
+// configured redirect url, or as a one-time code which the user enters manually.
+//
+// The response does not reveal whether an identity exists for the email address.
+func PasswordlessHandler(schema *proto.Schema) common.HandlerFunc {
+	return func(r *http.Request) common.Response {
+		ctx, span := tracer.Start(r.Context(), "Passwordless Endpoint")
+		defer span.End()
+
+		config, err := runtimectx.GetOAuthConfig(ctx)
+		if err != nil {
+			return common.InternalServerErrorResponse(ctx, err)
+		}
+
+		if r.Method != http.MethodPost {
+			return jsonErrResponse(ctx, http.StatusMethodNotAllowed, TokenErrInvalidRequest, "the passwordless endpoint only accepts POST", nil)
+		}
+
+		if !common.HasContentType(r.Header, "application/x-www-form-urlencoded") && !common.HasContentType(r.Header, "application/json") {
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the request body must either be an encoded form (Content-Type: application/x-www-form-urlencoded) or JSON (Content-Type: application/json)", nil)
+		}
+
+		data, err := common.ParseRequestData(r)
+		if err != nil {
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "request payload is malformed", err)
+		}
+
+		inputs, ok := data.(map[string]any)
+		if !ok {
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "request payload is malformed", err)
+		}
+
+		emailAddress, hasEmail := inputs[ArgEmail].(string)
+		if !hasEmail || emailAddress == "" {
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the identity's email in the 'email' field is required", nil)
+		}
+
+		if _, err := email.ParseAddress(emailAddress); err != nil {
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "invalid email address", nil)
+		}
+
+		codeType := oauth.PasswordlessCodeType(fmt.Sprint(inputs[ArgCodeType]))
+		if codeType != oauth.PasswordlessMagicLink && codeType != oauth.PasswordlessOneTimeCode {
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the code_type field is required with either 'magic_link' or 'otp'", nil)
+		}
+
+		span.SetAttributes(attribute.String(ArgCodeType, string(codeType)))
+
+		// Magic links are only ever sent to the configured redirect url,
+		// otherwise the link could be used to send the code to a third party.
+		var redirectUrl *url.URL
+		if codeType == oauth.PasswordlessMagicLink {
+			if config.RedirectUrl == nil {
+				return common.InternalServerErrorResponse(ctx, errors.New("redirectUrl must be specified in keelconfig.yaml to send magic links"))
+			}
+
+			redirectUrl, err = url.Parse(*config.RedirectUrl)
+			if err != nil {
+				return common.InternalServerErrorResponse(ctx, err)
+			}
+		}
+
+		createIfNotExists := true
+		if argCreateIfNotExists, has := inputs[ArgCreateIfNotExists]; has {
+			if b, ok := argCreateIfNotExists.(bool); ok {
+				createIfNotExists = b
+			} else if createIfNotExists, err = strconv.ParseBool(fmt.Sprint(argCreateIfNotExists)); err != nil {
+				return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the create_if_not_exists field is invalid and must be either 'true' or 'false'", nil)
+			}
+		}
+
+		if !createIfNotExists {
+			identity, err := actions.FindIdentityByEmail(ctx, schema, emailAddress, oauth.KeelIssuer)
+			if err != nil {
+				return common.InternalServerErrorResponse(ctx, err)
+			}
+
+			// No identity will be created, so there is nothing to sign in to
+			if identity == nil {
+				span.SetStatus(codes.Ok, "identity does not exist")
+				return common.NewJsonResponse(http.StatusOK, nil, nil)
+			}
+		}
+
+		code, err := oauth.NewPasswordlessCode(ctx, emailAddress, codeType)
+		if errors.Is(err, oauth.ErrTooManyPasswordlessCodes) {
+			return jsonErrResponse(ctx, http.StatusTooManyRequests, TokenErrTooManyAttempts, "too many sign in codes have been requested for this email address, try again later", nil)
+		}
+		if err != nil {
+			return common.InternalServerErrorResponse(ctx, err)
+		}
+
+		client, err := runtimectx.GetMailClient(ctx)
+		if err != nil {
+			return common.InternalServerErrorResponse(ctx, err)
+		}
+
+		var request *mail.SendEmailRequest
+		switch codeType {
+		case oauth.PasswordlessMagicLink:
+			q := redirectUrl.Query()
+			q.Add(ArgEmail, emailAddress)
+			q.Add(ArgCode, code)
+			redirectUrl.RawQuery = q.Encode()
+
+			request = &mail.SendEmailRequest{
+				To:        emailAddress,
+				From:      "hi@keel.xyz",
+				Subject:   "[Keel] Sign in link",
+				PlainText: fmt.Sprintf("Please follow this link to sign in: %s", redirectUrl),
+			}
+		case oauth.PasswordlessOneTimeCode:
+			request = &mail.SendEmailRequest{
+				To:        emailAddress,
+				From:      "hi@keel.xyz",
+				Subject:   "[Keel] Sign in code",
+				PlainText: fmt.Sprintf("Your sign in code is %s", code),
+			}
+		}
+
+		err = client.Send(ctx, request)
+		if err != nil {
+			return common.InternalServerErrorResponse(ctx, err)
+		}
+
+		return common.NewJsonResponse(http.StatusOK, nil, nil)
+	}
+}
@@ -0,0 +1,233 @@
+package authapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/config"
+	"github.com/teamkeel/keel/mail"
+	"github.com/teamkeel/keel/runtime/actions"
+	"github.com/teamkeel/keel/runtime/apis/authapi"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+	keeltesting "github.com/teamkeel/keel/testing"
+)
+
+type testMailClient struct {
+	sent []*mail.SendEmailRequest
+}
+
+func (c *testMailClient) Send(ctx context.Context, req *mail.SendEmailRequest) error {
+	c.sent = append(c.sent, req)
+	return nil
+}
+
+func TestPasswordless_OneTimeCodeSignIn(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	mailClient := &testMailClient{}
+	ctx = runtimectx.WithMailClient(ctx, mailClient)
+
+	request := makePasswordlessFormRequest(ctx, "keelson@keel.so", "otp", nil)
+	_, httpResponse, err := handleRuntimeRequest[any](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	require.Len(t, mailClient.sent, 1)
+	require.Equal(t, "keelson@keel.so", mailClient.sent[0].To)
+
+	code := regexp.MustCompile(`\d{6}`).FindString(mailClient.sent[0].PlainText)
+	require.NotEmpty(t, code)
+
+	tokenRequest := makePasswordlessGrantFormRequest(ctx, "keelson@keel.so", code, nil)
+	validResponse, httpResponse, err := handleRuntimeRequest[authapi.TokenResponse](schema, tokenRequest)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.NotEmpty(t, validResponse.AccessToken)
+	require.NotEmpty(t, validResponse.RefreshToken)
+	require.True(t, validResponse.Created)
+
+	sub, err := oauth.ValidateAccessToken(ctx, validResponse.AccessToken)
+	require.NoError(t, err)
+
+	identity, err := actions.FindIdentityByEmail(ctx, schema, "keelson@keel.so", oauth.KeelIssuer)
+	require.NoError(t, err)
+	require.Equal(t, identity["id"], sub)
+	require.Equal(t, true, identity["emailVerified"])
+}
+
+func TestPasswordless_TooManyCodesRequested(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	mailClient := &testMailClient{}
+	ctx = runtimectx.WithMailClient(ctx, mailClient)
+
+	for i := 0; i < 5; i++ {
+		request := makePasswordlessFormRequest(ctx, "keelson@keel.so", "otp", nil)
+		_, httpResponse, err := handleRuntimeRequest[any](schema, request)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	}
+
+	request := makePasswordlessFormRequest(ctx, "keelson@keel.so", "otp", nil)
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, httpResponse.StatusCode)
+	require.Equal(t, "too_many_attempts", errorResponse.Error)
+	require.Len(t, mailClient.sent, 5)
+}
+
+func TestPasswordless_MagicLinkSignIn(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	mailClient := &testMailClient{}
+	ctx = runtimectx.WithMailClient(ctx, mailClient)
+
+	redirectUrl := "https://myapp.com/signedin"
+	ctx = runtimectx.WithOAuthConfig(ctx, &config.AuthConfig{
+		RedirectUrl: &redirectUrl,
+	})
+
+	identity, err := actions.CreateIdentity(ctx, schema, "keelson@keel.so", "", oauth.KeelIssuer)
+	require.NoError(t, err)
+
+	request := makePasswordlessFormRequest(ctx, "keelson@keel.so", "magic_link", nil)
+	_, httpResponse, err := handleRuntimeRequest[any](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	require.Len(t, mailClient.sent, 1)
+
+	link := regexp.MustCompile(`https://\S+`).FindString(mailClient.sent[0].PlainText)
+	linkUrl, err := url.Parse(link)
+	require.NoError(t, err)
+	require.Equal(t, "myapp.com", linkUrl.Host)
+	require.Equal(t, "/signedin", linkUrl.Path)
+	require.Equal(t, "keelson@keel.so", linkUrl.Query().Get("email"))
+
+	tokenRequest := makePasswordlessGrantFormRequest(ctx, linkUrl.Query().Get("email"), linkUrl.Query().Get("code"), nil)
+	validResponse, httpResponse, err := handleRuntimeRequest[authapi.TokenResponse](schema, tokenRequest)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.False(t, validResponse.Created)
+
+	sub, err := oauth.ValidateAccessToken(ctx, validResponse.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, identity["id"], sub)
+}
+
+func TestPasswordless_CreateIfNotExistsFalse_NoEmailSent(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	mailClient := &testMailClient{}
+	ctx = runtimectx.WithMailClient(ctx, mailClient)
+
+	createIfNotExists := false
+	request := makePasswordlessFormRequest(ctx, "keelson@keel.so", "otp", &createIfNotExists)
+	_, httpResponse, err := handleRuntimeRequest[any](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.Empty(t, mailClient.sent)
+}
+
+func TestPasswordlessGrantCreateIfNotExistsFalse_IdentityNotCreated(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	code, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessOneTimeCode)
+	require.NoError(t, err)
+
+	createIfNotExists := false
+	tokenRequest := makePasswordlessGrantFormRequest(ctx, "keelson@keel.so", code, &createIfNotExists)
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, tokenRequest)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+	require.Equal(t, "invalid_client", errorResponse.Error)
+
+	identity, err := actions.FindIdentityByEmail(ctx, schema, "keelson@keel.so", oauth.KeelIssuer)
+	require.NoError(t, err)
+	require.Nil(t, identity)
+}
+
+func TestPasswordlessGrant_IncorrectCode(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	_, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessOneTimeCode)
+	require.NoError(t, err)
+
+	tokenRequest := makePasswordlessGrantFormRequest(ctx, "keelson@keel.so", "wrong", nil)
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, tokenRequest)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+	require.Equal(t, "invalid_client", errorResponse.Error)
+	require.Equal(t, "possible causes may be that the code is incorrect, has been consumed or has expired", errorResponse.ErrorDescription)
+}
+
+func TestPasswordless_InvalidCodeType(t *testing.T) {
+	request := makePasswordlessFormRequest(context.Background(), "keelson@keel.so", "sms", nil)
+
+	response := authapi.PasswordlessHandler(nil)(request)
+	require.Equal(t, http.StatusBadRequest, response.Status)
+}
+
+func TestPasswordless_InvalidEmail(t *testing.T) {
+	request := makePasswordlessFormRequest(context.Background(), "keelson", "otp", nil)
+
+	response := authapi.PasswordlessHandler(nil)(request)
+	require.Equal(t, http.StatusBadRequest, response.Status)
+}
+
+func TestPasswordless_MagicLinkRequiresRedirectUrl(t *testing.T) {
+	request := makePasswordlessFormRequest(context.Background(), "keelson@keel.so", "magic_link", nil)
+
+	response := authapi.PasswordlessHandler(nil)(request)
+	require.Equal(t, http.StatusInternalServerError, response.Status)
+}
+
+func makePasswordlessFormRequest(ctx context.Context, email string, codeType string, createIfNotExists *bool) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "http://mykeelapp.keel.so/auth/passwordless", nil)
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	form := url.Values{}
+	form.Add("email", email)
+	form.Add("code_type", codeType)
+
+	if createIfNotExists != nil {
+		form.Add("create_if_not_exists", strconv.FormatBool(*createIfNotExists))
+	}
+
+	request.URL.RawQuery = form.Encode()
+	request = request.WithContext(ctx)
+
+	return request
+}
+
+func makePasswordlessGrantFormRequest(ctx context.Context, username string, code string, createIfNotExists *bool) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "http://mykeelapp.keel.so/auth/token", nil)
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	form := url.Values{}
+	form.Add("grant_type", "passwordless")
+	form.Add("username", username)
+	form.Add("code", code)
+
+	if createIfNotExists != nil {
+		form.Add("create_if_not_exists", strconv.FormatBool(*createIfNotExists))
+	}
+
+	request.URL.RawQuery = form.Encode()
+	request = request.WithContext(ctx)
+
+	return request
+}
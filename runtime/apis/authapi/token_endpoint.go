@@ -1,11 +1,17 @@
 package authapi
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	email "net/mail"
 
+	"github.com/samber/lo"
 	"github.com/teamkeel/keel/config"
 	"github.com/teamkeel/keel/functions"
 	"github.com/teamkeel/keel/proto"
@@ -17,6 +23,7 @@ import (
 	"github.com/teamkeel/keel/schema/parser"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -30,11 +37,20 @@ const (
 	ArgSubjectTokenType   = "subject_token_type"
 	ArgRequestedTokenType = "requested_token_type"
 	ArgCode               = "code"
+	ArgCodeVerifier       = "code_verifier"
 	ArgRefreshToken       = "refresh_token"
 	ArgToken              = "token"
 	ArgUsername           = "username"
 	ArgPassword           = "password"
 	ArgCreateIfNotExists  = "create_if_not_exists"
+	ArgClientId           = "client_id"
+	ArgClientSecret       = "client_secret"
+	ArgScope              = "scope"
+	ArgMfaToken           = "mfa_token"
+	ArgOtp                = "otp"
+	ArgRecoveryCode       = "recovery_code"
+	ArgActorToken         = "actor_token"
+	ArgIdentityId         = "identity_id"
 )
 
 const (
@@ -50,20 +66,44 @@ type TokenResponse struct {
 	Created      bool   `json:"identity_created"`
 }
 
+// https://datatracker.ietf.org/doc/html/rfc6749#section-4.4.3
+type ClientCredentialsTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// Returned when the identity has enrolled an authenticator. The mfa_token is exchanged
+// along with a code from the authenticator using the mfa_otp grant to complete authentication.
+type MfaRequiredResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+	MfaToken         string `json:"mfa_token"`
+}
+
 // https://datatracker.ietf.org/doc/html/rfc6749#section-5.2
 const (
 	TokenErrUnsupportedGrantType = "unsupported_grant_type"
 	TokenErrInvalidClient        = "invalid_client"
 	TokenErrInvalidRequest       = "invalid_request"
+	TokenErrInvalidScope         = "invalid_scope"
+	TokenErrMfaRequired          = "mfa_required"
+	TokenErrEmailNotVerified     = "email_not_verified"
+	TokenErrTooManyAttempts      = "too_many_attempts"
+	TokenErrUnauthorizedClient   = "unauthorized_client"
 )
 
 const (
 	GrantTypeImplicit          = "implicit"
 	GrantTypePassword          = "password"
+	GrantTypePasswordless      = "passwordless"
 	GrantTypeClientCredentials = "client_credentials"
 	GrantTypeAuthCode          = "authorization_code"
 	GrantTypeRefreshToken      = "refresh_token"
 	GrantTypeTokenExchange     = "token_exchange"
+	GrantTypeMfaOtp            = "mfa_otp"
+	GrantTypeImpersonation     = "impersonation"
 )
 
 // TokenEndpointHandler handles requests to the token endpoint for the various grant types we support.
@@ -77,6 +117,7 @@ func TokenEndpointHandler(schema *proto.Schema) common.HandlerFunc {
 		var err error
 		var identity auth.Identity
 		var refreshToken string
+		usedMfa := false
 		createIfNotExists := true
 		identityCreated := false
 
@@ -105,12 +146,23 @@ func TokenEndpointHandler(schema *proto.Schema) common.HandlerFunc {
 
 		grantType, hasGrantType := inputs[ArgGrantType].(string)
 		if !hasGrantType || grantType == "" {
-			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the grant_type field is required with either 'refresh_token', 'token_exchange', 'authorization_code' or 'password'", nil)
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the grant_type field is required with either 'refresh_token', 'token_exchange', 'authorization_code', 'password', 'passwordless', 'mfa_otp', 'impersonation' or 'client_credentials'", nil)
 		}
 
 		span.SetAttributes(
 			attribute.String(ArgGrantType, grantType),
 		)
+
+		// Service clients do not authenticate as an identity and so are handled separately
+		if grantType == GrantTypeClientCredentials {
+			return handleClientCredentialsGrant(ctx, r, inputs)
+		}
+
+		// Impersonation does not authenticate the target identity and so is handled separately
+		if grantType == GrantTypeImpersonation {
+			return handleImpersonationGrant(ctx, schema, inputs)
+		}
+
 		argCreateIfNotExists, hasCreateIfNotExists := inputs[ArgCreateIfNotExists]
 		if hasCreateIfNotExists {
 			if b, ok := argCreateIfNotExists.(bool); ok {
@@ -123,7 +175,7 @@ func TokenEndpointHandler(schema *proto.Schema) common.HandlerFunc {
 		}
 
 		defer func(grant string) {
-			if grant != GrantTypeRefreshToken {
+			if grant != GrantTypeRefreshToken && resp.Status == http.StatusOK {
 				err = functions.CallPredefinedHook(ctx, config.HookAfterAuthentication)
 				if err != nil {
 					resp = common.InternalServerErrorResponse(ctx, err)
@@ -138,6 +190,12 @@ func TokenEndpointHandler(schema *proto.Schema) common.HandlerFunc {
 				return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the refresh token in the 'refresh_token' field is required", nil)
 			}
 
+			// Access tokens continue to be marked as having used a second factor
+			usedMfa, err = oauth.IsMfaRefreshToken(ctx, refreshTokenRaw)
+			if err != nil {
+				return common.InternalServerErrorResponse(ctx, err)
+			}
+
 			var identityId string
 			var isValid bool
 			if cfg.RefreshTokenRotationEnabled() {
@@ -181,6 +239,18 @@ func TokenEndpointHandler(schema *proto.Schema) common.HandlerFunc {
 				return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the identity's password in the 'password' field is required", nil)
 			}
 
+			ipAddress := oauth.ClientIpAddress(r.RemoteAddr, r.Header.Values("X-Forwarded-For"), cfg.LockoutTrustedProxies())
+			if cfg.LockoutEnabled() {
+				retryAfter, err := oauth.CheckLockout(ctx, username, ipAddress)
+				if err != nil {
+					return common.InternalServerErrorResponse(ctx, err)
+				}
+
+				if retryAfter > 0 {
+					return lockedOutResponse(ctx, retryAfter)
+				}
+			}
+
 			ident, err := actions.FindIdentityByEmail(ctx, schema, username, oauth.KeelIssuer)
 			if err != nil {
 				return common.InternalServerErrorResponse(ctx, err)
@@ -188,6 +258,12 @@ func TokenEndpointHandler(schema *proto.Schema) common.HandlerFunc {
 
 			if ident == nil {
 				if !createIfNotExists {
+					if cfg.LockoutEnabled() {
+						if err := recordFailedPasswordAttempt(ctx, username, ipAddress); err != nil {
+							return common.InternalServerErrorResponse(ctx, err)
+						}
+					}
+
 					return jsonErrResponse(ctx, http.StatusUnauthorized, TokenErrInvalidClient, "the identity does not exist or the credentials are incorrect", nil)
 				}
 
@@ -203,18 +279,75 @@ func TokenEndpointHandler(schema *proto.Schema) common.HandlerFunc {
 
 				identityCreated = true
 			} else {
-				correct := bcrypt.CompareHashAndPassword([]byte(ident[parser.IdentityFieldNamePassword].(string)), []byte(password)) == nil
+				// An identity has no password if it was created by another grant or its password has been reset
+				hashedPassword, ok := ident[parser.IdentityFieldNamePassword].(string)
+				correct := ok && hashedPassword != "" && bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)) == nil
 				if !correct {
+					if cfg.LockoutEnabled() {
+						if err := recordFailedPasswordAttempt(ctx, username, ipAddress); err != nil {
+							return common.InternalServerErrorResponse(ctx, err)
+						}
+					}
+
 					return jsonErrResponse(ctx, http.StatusUnauthorized, TokenErrInvalidClient, "the identity does not exist or the credentials are incorrect", nil)
 				}
 			}
 
-			// Generate a refresh token.
-			refreshToken, err = oauth.NewRefreshToken(ctx, ident[parser.FieldNameId].(string))
+			if cfg.LockoutEnabled() {
+				if err := oauth.ClearFailedAttempts(ctx, username, ipAddress); err != nil {
+					return common.InternalServerErrorResponse(ctx, err)
+				}
+			}
+
+			identity = ident
+
+		case GrantTypePasswordless:
+			username, hasUsername := inputs[ArgUsername].(string)
+			if !hasUsername || username == "" {
+				return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the identity's email in the 'username' field is required", nil)
+			}
+
+			code, hasCode := inputs[ArgCode].(string)
+			if !hasCode || code == "" {
+				return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the passwordless code in the 'code' field is required", nil)
+			}
+
+			isValid, err := oauth.ConsumePasswordlessCode(ctx, username, code)
 			if err != nil {
 				return common.InternalServerErrorResponse(ctx, err)
 			}
 
+			if !isValid {
+				return jsonErrResponse(ctx, http.StatusUnauthorized, TokenErrInvalidClient, "possible causes may be that the code is incorrect, has been consumed or has expired", nil)
+			}
+
+			ident, err := actions.FindIdentityByEmail(ctx, schema, username, oauth.KeelIssuer)
+			if err != nil {
+				return common.InternalServerErrorResponse(ctx, err)
+			}
+
+			if ident == nil {
+				if !createIfNotExists {
+					return jsonErrResponse(ctx, http.StatusUnauthorized, TokenErrInvalidClient, "the identity does not exist or the credentials are incorrect", nil)
+				}
+
+				// Identities created without a password cannot use the password grant until one is set
+				ident, err = actions.CreateIdentity(ctx, schema, username, "", oauth.KeelIssuer)
+				if err != nil {
+					return common.InternalServerErrorResponse(ctx, err)
+				}
+
+				identityCreated = true
+			}
+
+			// Receiving the code proves that the identity controls the email address
+			if verified, _ := ident[parser.IdentityFieldNameEmailVerified].(bool); !verified {
+				ident, err = actions.MarkEmailVerified(ctx, schema, ident[parser.FieldNameId].(string))
+				if err != nil {
+					return common.InternalServerErrorResponse(ctx, err)
+				}
+			}
+
 			identity = ident
 
 		case GrantTypeAuthCode:
@@ -223,9 +356,36 @@ func TokenEndpointHandler(schema *proto.Schema) common.HandlerFunc {
 				return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the authorization code in the 'code' field is required", nil)
 			}
 
+			codeVerifier, _ := inputs[ArgCodeVerifier].(string)
+
+			// An auth code which links a provider is exchanged by the identity which is linking it, authenticated with its access token
+			if strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+				linkingIdentity, errResponse := authenticateCredentialsBearerToken(ctx, schema, r)
+				if errResponse != nil {
+					return *errResponse
+				}
+
+				isValid, link, err := oauth.ConsumeLinkAuthCode(ctx, authCode, codeVerifier)
+				if err != nil {
+					return common.InternalServerErrorResponse(ctx, err)
+				}
+
+				if !isValid {
+					return jsonErrResponse(ctx, http.StatusUnauthorized, TokenErrInvalidClient, "possible causes may be that the auth code has been consumed or has expired", nil)
+				}
+
+				identity, err = linkExternalIdentity(ctx, schema, linkingIdentity[parser.FieldNameId].(string), link.Issuer, link.ExternalId, link.Email)
+				if errors.Is(err, oauth.ErrIdentityAlreadyLinked) {
+					return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the provider's identity is already linked to another identity", nil)
+				} else if err != nil {
+					return common.InternalServerErrorResponse(ctx, err)
+				}
+
+				break
+			}
+
 			// Consume the auth code
-			var isValid bool
-			isValid, identityId, err := oauth.ConsumeAuthCode(ctx, authCode)
+			isValid, identityId, err := oauth.ConsumeAuthCode(ctx, authCode, codeVerifier)
 			if err != nil {
 				return common.InternalServerErrorResponse(ctx, err)
 			}
@@ -234,12 +394,6 @@ func TokenEndpointHandler(schema *proto.Schema) common.HandlerFunc {
 				return jsonErrResponse(ctx, http.StatusUnauthorized, TokenErrInvalidClient, "possible causes may be that the auth code has been consumed or has expired", nil)
 			}
 
-			// Generate a refresh token.
-			refreshToken, err = oauth.NewRefreshToken(ctx, identityId)
-			if err != nil {
-				return common.InternalServerErrorResponse(ctx, err)
-			}
-
 			identity, err = actions.FindIdentityById(ctx, schema, identityId)
 			if err != nil {
 				return common.InternalServerErrorResponse(ctx, err)
@@ -287,7 +441,7 @@ func TokenEndpointHandler(schema *proto.Schema) common.HandlerFunc {
 				customClaims[c.Field] = claims[c.Key]
 			}
 
-			ident, err := actions.FindIdentityByExternalId(ctx, schema, idToken.Subject, idToken.Issuer)
+			ident, err := findExternalIdentity(ctx, schema, cfg, idToken.Issuer, idToken.Subject, &standardClaims, customClaims)
 			if err != nil {
 				return common.InternalServerErrorResponse(ctx, err)
 			}
@@ -303,23 +457,61 @@ func TokenEndpointHandler(schema *proto.Schema) common.HandlerFunc {
 				}
 
 				identityCreated = true
-			} else {
-				ident, err = actions.UpdateIdentityWithClaims(ctx, schema, idToken.Subject, idToken.Issuer, &standardClaims, customClaims)
-				if err != nil {
-					return common.InternalServerErrorResponse(ctx, err)
-				}
 			}
 
-			// Generate a refresh token.
-			refreshToken, err = oauth.NewRefreshToken(ctx, ident[parser.FieldNameId].(string))
+			identity = ident
+
+		case GrantTypeMfaOtp:
+			mfaToken, hasMfaToken := inputs[ArgMfaToken].(string)
+			if !hasMfaToken || mfaToken == "" {
+				return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the mfa token in the 'mfa_token' field is required", nil)
+			}
+
+			identityId, err := oauth.ValidateMfaToken(ctx, mfaToken)
+			if err != nil {
+				return jsonErrResponse(ctx, http.StatusUnauthorized, TokenErrInvalidClient, "possible causes may be that the mfa token is invalid or has expired", err)
+			}
+
+			var isValid bool
+			otp, _ := inputs[ArgOtp].(string)
+			recoveryCode, _ := inputs[ArgRecoveryCode].(string)
+			switch {
+			case otp != "":
+				isValid, err = oauth.VerifyMfaCode(ctx, identityId, otp)
+			case recoveryCode != "":
+				isValid, err = oauth.ConsumeRecoveryCode(ctx, identityId, recoveryCode)
+			default:
+				return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the code from the authenticator in the 'otp' field or a recovery code in the 'recovery_code' field is required", nil)
+			}
 			if err != nil {
 				return common.InternalServerErrorResponse(ctx, err)
 			}
 
-			identity = ident
+			if !isValid {
+				return jsonErrResponse(ctx, http.StatusUnauthorized, TokenErrInvalidClient, "possible causes may be that the code is incorrect or has already been used", nil)
+			}
+
+			identity, err = actions.FindIdentityById(ctx, schema, identityId)
+			if err != nil {
+				return common.InternalServerErrorResponse(ctx, err)
+			}
+
+			usedMfa = true
 
 		default:
-			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrUnsupportedGrantType, "the only supported grants are 'refresh_token', 'token_exchange', 'authorization_code' or 'password'", nil)
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrUnsupportedGrantType, "the only supported grants are 'refresh_token', 'token_exchange', 'authorization_code', 'password', 'passwordless', 'mfa_otp', 'impersonation' or 'client_credentials'", nil)
+		}
+
+		identityId := identity[parser.FieldNameId].(string)
+
+		// Disabled identities cannot authenticate or refresh their tokens
+		disabled, err := oauth.IsIdentityDisabled(ctx, identityId)
+		if err != nil {
+			return common.InternalServerErrorResponse(ctx, err)
+		}
+
+		if disabled {
+			return jsonErrResponse(ctx, http.StatusUnauthorized, TokenErrInvalidClient, "the identity has been disabled", nil)
 		}
 
 		ctx = auth.WithIdentity(ctx, identity)
@@ -331,8 +523,55 @@ func TokenEndpointHandler(schema *proto.Schema) common.HandlerFunc {
 			}
 		}
 
+		// Identities signing in with a password may be required to have verified their email address first,
+		// which is checked before any authenticator code is requested as the mfa_otp grant does not repeat it
+		if grantType == GrantTypePassword && cfg.EmailVerificationRequired() {
+			if verified, ok := identity[parser.IdentityFieldNameEmailVerified].(bool); !ok || !verified {
+				return jsonErrResponse(ctx, http.StatusForbidden, TokenErrEmailNotVerified, "the identity's email address must be verified before signing in with a password", nil)
+			}
+		}
+
+		// Identities which have enrolled an authenticator must complete authentication with a code from it
+		if grantType != GrantTypeRefreshToken && grantType != GrantTypeMfaOtp {
+			hasMfa, err := oauth.HasMfa(ctx, identityId)
+			if err != nil {
+				return common.InternalServerErrorResponse(ctx, err)
+			}
+
+			if hasMfa {
+				mfaToken, err := oauth.GenerateMfaToken(ctx, identityId)
+				if err != nil {
+					return common.InternalServerErrorResponse(ctx, err)
+				}
+
+				return common.NewJsonResponse(http.StatusForbidden, &MfaRequiredResponse{
+					Error:            TokenErrMfaRequired,
+					ErrorDescription: "a code from the identity's authenticator is required using the 'mfa_otp' grant",
+					MfaToken:         mfaToken,
+				}, nil)
+			}
+		}
+
+		// Generate a refresh token, except when refreshing where it has already been rotated.
+		if grantType != GrantTypeRefreshToken {
+			if usedMfa {
+				refreshToken, err = oauth.NewMfaRefreshToken(ctx, identityId)
+			} else {
+				refreshToken, err = oauth.NewRefreshToken(ctx, identityId)
+			}
+			if err != nil {
+				return common.InternalServerErrorResponse(ctx, err)
+			}
+		}
+
 		// Generate a new access token for this identity.
-		accessTokenRaw, expiresIn, err := oauth.GenerateAccessToken(ctx, identity["id"].(string))
+		var accessTokenRaw string
+		var expiresIn time.Duration
+		if usedMfa {
+			accessTokenRaw, expiresIn, err = oauth.GenerateMfaAccessToken(ctx, identityId)
+		} else {
+			accessTokenRaw, expiresIn, err = oauth.GenerateAccessToken(ctx, identityId)
+		}
 		if err != nil {
 			return common.InternalServerErrorResponse(ctx, err)
 		}
@@ -348,3 +587,123 @@ func TokenEndpointHandler(schema *proto.Schema) common.HandlerFunc {
 		return common.NewJsonResponse(http.StatusOK, response, nil)
 	}
 }
+
+// handleClientCredentialsGrant issues an access token to a service client. The client credentials can be
+// provided using HTTP Basic authentication or in the request body. No refresh token is issued.
+// https://datatracker.ietf.org/doc/html/rfc6749#section-4.4
+func handleClientCredentialsGrant(ctx context.Context, r *http.Request, inputs map[string]any) common.Response {
+	clientId, clientSecret, hasBasicAuth := r.BasicAuth()
+	if !hasBasicAuth {
+		clientId, _ = inputs[ArgClientId].(string)
+		clientSecret, _ = inputs[ArgClientSecret].(string)
+	}
+
+	if clientId == "" || clientSecret == "" {
+		return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the client credentials are required in the 'client_id' and 'client_secret' fields or using HTTP Basic authentication", nil)
+	}
+
+	client, err := oauth.AuthenticateServiceClient(ctx, clientId, clientSecret)
+	if err != nil {
+		return common.InternalServerErrorResponse(ctx, err)
+	}
+
+	if client == nil {
+		return jsonErrResponse(ctx, http.StatusUnauthorized, TokenErrInvalidClient, "the client does not exist or the credentials are incorrect", nil)
+	}
+
+	// The client can request a subset of its roles, otherwise it is granted all of them
+	roles := client.Roles
+	if scope, hasScope := inputs[ArgScope].(string); hasScope && scope != "" {
+		roles = strings.Fields(scope)
+		if unknown, _ := lo.Difference(roles, client.Roles); len(unknown) > 0 {
+			return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidScope, fmt.Sprintf("the client has not been granted the role '%s'", unknown[0]), nil)
+		}
+	}
+
+	accessTokenRaw, expiresIn, err := oauth.GenerateClientAccessToken(ctx, client.ClientId, roles)
+	if err != nil {
+		return common.InternalServerErrorResponse(ctx, err)
+	}
+
+	response := &ClientCredentialsTokenResponse{
+		AccessToken: accessTokenRaw,
+		TokenType:   TokenType,
+		ExpiresIn:   int(expiresIn.Seconds()),
+		Scope:       strings.Join(roles, " "),
+	}
+
+	return common.NewJsonResponse(http.StatusOK, response, nil)
+}
+
+// handleImpersonationGrant issues an access token for the identity being impersonated to an identity with one
+// of the roles configured with auth.impersonatorRoles. The token's act claim names the impersonator so that it can
+// be recorded in the audit log. No refresh token is issued, so the impersonator must request a new token once it expires.
+// https://datatracker.ietf.org/doc/html/rfc8693#section-4.1
+func handleImpersonationGrant(ctx context.Context, schema *proto.Schema, inputs map[string]any) common.Response {
+	actorToken, hasActorToken := inputs[ArgActorToken].(string)
+	if !hasActorToken || actorToken == "" {
+		return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the impersonator's access token in the 'actor_token' field is required", nil)
+	}
+
+	identityId, hasIdentityId := inputs[ArgIdentityId].(string)
+	if !hasIdentityId || identityId == "" {
+		return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the id of the identity to impersonate in the 'identity_id' field is required", nil)
+	}
+
+	impersonator, authorised, err := actions.AuthenticateImpersonator(ctx, schema, actorToken)
+	if err != nil {
+		return jsonErrResponse(ctx, http.StatusUnauthorized, TokenErrInvalidClient, "possible causes may be that the actor token is invalid, has expired, or is itself an impersonation token", err)
+	}
+
+	if !authorised {
+		return jsonErrResponse(ctx, http.StatusForbidden, TokenErrUnauthorizedClient, "the identity does not have a role which is permitted to impersonate", nil)
+	}
+
+	identity, err := actions.FindIdentityById(ctx, schema, identityId)
+	if err != nil {
+		return common.InternalServerErrorResponse(ctx, err)
+	}
+
+	if identity == nil {
+		return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the identity to impersonate does not exist", nil)
+	}
+
+	disabled, err := oauth.IsIdentityDisabled(ctx, identityId)
+	if err != nil {
+		return common.InternalServerErrorResponse(ctx, err)
+	}
+
+	if disabled {
+		return jsonErrResponse(ctx, http.StatusBadRequest, TokenErrInvalidRequest, "the identity to impersonate has been disabled", nil)
+	}
+
+	canBeImpersonated, err := actions.CanBeImpersonated(ctx, schema, identity)
+	if err != nil {
+		return common.InternalServerErrorResponse(ctx, err)
+	}
+
+	if !canBeImpersonated {
+		return jsonErrResponse(ctx, http.StatusForbidden, TokenErrUnauthorizedClient, "identities with an admin or impersonator role cannot be impersonated", nil)
+	}
+
+	impersonatorId := impersonator[parser.FieldNameId].(string)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("identity.id", identityId),
+		attribute.String("impersonator.id", impersonatorId),
+	)
+
+	accessTokenRaw, expiresIn, err := oauth.GenerateImpersonationAccessToken(ctx, identityId, impersonatorId)
+	if err != nil {
+		return common.InternalServerErrorResponse(ctx, err)
+	}
+
+	response := &TokenResponse{
+		AccessToken: accessTokenRaw,
+		TokenType:   TokenType,
+		ExpiresIn:   int(expiresIn.Seconds()),
+	}
+
+	return common.NewJsonResponse(http.StatusOK, response, nil)
+}
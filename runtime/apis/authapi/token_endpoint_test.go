@@ -810,7 +810,7 @@ func TestTokenEndpointJson_MissingGrantType(t *testing.T) {
 
 	require.Equal(t, http.StatusBadRequest, httpResponse.StatusCode)
 	require.Equal(t, "invalid_request", errorResponse.Error)
-	require.Equal(t, "the grant_type field is required with either 'refresh_token', 'token_exchange', 'authorization_code' or 'password'", errorResponse.ErrorDescription)
+	require.Equal(t, "the grant_type field is required with either 'refresh_token', 'token_exchange', 'authorization_code', 'password', 'passwordless', 'mfa_otp', 'impersonation' or 'client_credentials'", errorResponse.ErrorDescription)
 	require.True(t, common.HasContentType(httpResponse.Header, "application/json"))
 }
 
@@ -830,7 +830,7 @@ func TestTokenEndpoint_MissingGrantType(t *testing.T) {
 
 	require.Equal(t, http.StatusBadRequest, httpResponse.StatusCode)
 	require.Equal(t, "invalid_request", errorResponse.Error)
-	require.Equal(t, "the grant_type field is required with either 'refresh_token', 'token_exchange', 'authorization_code' or 'password'", errorResponse.ErrorDescription)
+	require.Equal(t, "the grant_type field is required with either 'refresh_token', 'token_exchange', 'authorization_code', 'password', 'passwordless', 'mfa_otp', 'impersonation' or 'client_credentials'", errorResponse.ErrorDescription)
 	require.True(t, common.HasContentType(httpResponse.Header, "application/json"))
 }
 
@@ -851,7 +851,7 @@ func TestTokenEndpoint_WrongGrantType(t *testing.T) {
 
 	require.Equal(t, http.StatusBadRequest, httpResponse.StatusCode)
 	require.Equal(t, "unsupported_grant_type", errorResponse.Error)
-	require.Equal(t, "the only supported grants are 'refresh_token', 'token_exchange', 'authorization_code' or 'password'", errorResponse.ErrorDescription)
+	require.Equal(t, "the only supported grants are 'refresh_token', 'token_exchange', 'authorization_code', 'password', 'passwordless', 'mfa_otp', 'impersonation' or 'client_credentials'", errorResponse.ErrorDescription)
 	require.True(t, common.HasContentType(httpResponse.Header, "application/json"))
 }
 
@@ -1323,7 +1323,7 @@ func TestAuthorizationCodeGrant_Valid(t *testing.T) {
 	identity, err := actions.CreateIdentity(ctx, schema, "test@keel.xyz", "1234", oauth.KeelIssuer)
 	require.NoError(t, err)
 
-	code, err := oauth.NewAuthCode(ctx, identity["id"].(string))
+	code, err := oauth.NewAuthCode(ctx, identity["id"].(string), nil)
 	require.NoError(t, err)
 
 	// Make a auth code grant request
@@ -1356,7 +1356,7 @@ func TestAuthorizationCodeGrantJson_Valid(t *testing.T) {
 	identity, err := actions.CreateIdentity(ctx, schema, "test@keel.xyz", "1234", oauth.KeelIssuer)
 	require.NoError(t, err)
 
-	code, err := oauth.NewAuthCode(ctx, identity["id"].(string))
+	code, err := oauth.NewAuthCode(ctx, identity["id"].(string), nil)
 	require.NoError(t, err)
 
 	// Make a auth code grant request
@@ -1640,6 +1640,81 @@ func TestPasswordGrant_CorrectCredentials(t *testing.T) {
 	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
 }
 
+func TestPasswordGrant_DisabledIdentity(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	// Make a password grant request
+	request := makePasswordFormRequest(ctx, "user@example.com", "myP@ssword1234!", nil)
+
+	// Handle runtime request, expecting TokenResponse
+	_, httpResponse, err := handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	identity, err := actions.FindIdentityByEmail(ctx, schema, "user@example.com", oauth.KeelIssuer)
+	require.NoError(t, err)
+
+	err = oauth.DisableIdentity(ctx, identity["id"].(string))
+	require.NoError(t, err)
+
+	// Make another password grant request
+	request = makePasswordFormRequest(ctx, "user@example.com", "myP@ssword1234!", nil)
+
+	// Handle runtime request, expecting TokenErrorResponse
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+	require.Equal(t, "invalid_client", errorResponse.Error)
+	require.Equal(t, "the identity has been disabled", errorResponse.ErrorDescription)
+
+	_, err = oauth.EnableIdentity(ctx, identity["id"].(string))
+	require.NoError(t, err)
+
+	// Make another password grant request
+	request = makePasswordFormRequest(ctx, "user@example.com", "myP@ssword1234!", nil)
+
+	// Handle runtime request, expecting TokenResponse
+	_, httpResponse, err = handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+}
+
+func TestPasswordGrant_EmailVerificationRequired(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	required := true
+	ctx = runtimectx.WithOAuthConfig(ctx, &config.AuthConfig{
+		Email: config.EmailConfig{
+			VerificationRequired: &required,
+		},
+	})
+
+	// Make a password grant request
+	request := makePasswordFormRequest(ctx, "user@example.com", "myP@ssword1234!", nil)
+
+	// Handle runtime request, expecting TokenErrorResponse
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusForbidden, httpResponse.StatusCode)
+	require.Equal(t, "email_not_verified", errorResponse.Error)
+	require.Equal(t, "the identity's email address must be verified before signing in with a password", errorResponse.ErrorDescription)
+
+	// The identity is still created so that verification can be requested
+	_, err = database.ExecuteStatement(ctx, "UPDATE identity SET email_verified = true WHERE email = ?", "user@example.com")
+	require.NoError(t, err)
+
+	// Make another password grant request
+	request = makePasswordFormRequest(ctx, "user@example.com", "myP@ssword1234!", nil)
+
+	// Handle runtime request, expecting TokenResponse
+	_, httpResponse, err = handleRuntimeRequest[authapi.TokenResponse](schema, request)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+}
+
 func TestPasswordGrant_InvalidEmail(t *testing.T) {
 	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
 	defer database.Close()
@@ -1723,6 +1798,188 @@ func TestPasswordGrant_InvalidCreateIfNotExists(t *testing.T) {
 	require.True(t, common.HasContentType(httpResponse.Header, "application/json"))
 }
 
+func TestClientCredentialsGrant_Valid(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	client, secret, err := oauth.NewServiceClient(ctx, "billing", []string{"Admin", "Staff"})
+	require.NoError(t, err)
+
+	// Make a client credentials grant request
+	request := makeClientCredentialsFormRequest(ctx, client.ClientId, secret, "")
+
+	// Handle runtime request, expecting ClientCredentialsTokenResponse
+	validResponse, httpResponse, err := handleRuntimeRequest[authapi.ClientCredentialsTokenResponse](schema, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.NotEmpty(t, validResponse.AccessToken)
+	require.Equal(t, "bearer", validResponse.TokenType)
+	require.NotEmpty(t, validResponse.ExpiresIn)
+	require.Equal(t, "Admin Staff", validResponse.Scope)
+	require.True(t, common.HasContentType(httpResponse.Header, "application/json"))
+
+	clientId, roles, err := oauth.ValidateClientAccessToken(ctx, validResponse.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, client.ClientId, clientId)
+	require.Equal(t, []string{"Admin", "Staff"}, roles)
+
+	// A service client's access token cannot be used as an identity
+	_, err = oauth.ValidateAccessToken(ctx, validResponse.AccessToken)
+	require.ErrorIs(t, err, oauth.ErrInvalidToken)
+}
+
+func TestClientCredentialsGrant_ValidWithBasicAuth(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	client, secret, err := oauth.NewServiceClient(ctx, "billing", []string{"Admin"})
+	require.NoError(t, err)
+
+	// Make a client credentials grant request with the credentials in the Authorization header
+	request := makeClientCredentialsFormRequest(ctx, "", "", "")
+	request.SetBasicAuth(client.ClientId, secret)
+
+	// Handle runtime request, expecting ClientCredentialsTokenResponse
+	validResponse, httpResponse, err := handleRuntimeRequest[authapi.ClientCredentialsTokenResponse](schema, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.NotEmpty(t, validResponse.AccessToken)
+	require.Equal(t, "Admin", validResponse.Scope)
+}
+
+func TestClientCredentialsGrant_ValidJson(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	client, secret, err := oauth.NewServiceClient(ctx, "billing", []string{"Admin"})
+	require.NoError(t, err)
+
+	// Make a client credentials grant request
+	request := makeClientCredentialsJsonRequest(ctx, client.ClientId, secret)
+
+	// Handle runtime request, expecting ClientCredentialsTokenResponse
+	validResponse, httpResponse, err := handleRuntimeRequest[authapi.ClientCredentialsTokenResponse](schema, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.NotEmpty(t, validResponse.AccessToken)
+	require.Equal(t, "Admin", validResponse.Scope)
+}
+
+func TestClientCredentialsGrant_ScopedRoles(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	client, secret, err := oauth.NewServiceClient(ctx, "billing", []string{"Admin", "Staff"})
+	require.NoError(t, err)
+
+	// Make a client credentials grant request for only one of the client's roles
+	request := makeClientCredentialsFormRequest(ctx, client.ClientId, secret, "Staff")
+
+	// Handle runtime request, expecting ClientCredentialsTokenResponse
+	validResponse, httpResponse, err := handleRuntimeRequest[authapi.ClientCredentialsTokenResponse](schema, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.Equal(t, "Staff", validResponse.Scope)
+
+	_, roles, err := oauth.ValidateClientAccessToken(ctx, validResponse.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Staff"}, roles)
+}
+
+func TestClientCredentialsGrant_ScopeNotGranted(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	client, secret, err := oauth.NewServiceClient(ctx, "billing", []string{"Staff"})
+	require.NoError(t, err)
+
+	// Make a client credentials grant request for a role the client has not been granted
+	request := makeClientCredentialsFormRequest(ctx, client.ClientId, secret, "Admin")
+
+	// Handle runtime request, expecting TokenErrorResponse
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusBadRequest, httpResponse.StatusCode)
+	require.Equal(t, "invalid_scope", errorResponse.Error)
+	require.Equal(t, "the client has not been granted the role 'Admin'", errorResponse.ErrorDescription)
+	require.True(t, common.HasContentType(httpResponse.Header, "application/json"))
+}
+
+func TestClientCredentialsGrant_IncorrectSecret(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	client, _, err := oauth.NewServiceClient(ctx, "billing", []string{"Admin"})
+	require.NoError(t, err)
+
+	// Make a client credentials grant request
+	request := makeClientCredentialsFormRequest(ctx, client.ClientId, "incorrect", "")
+
+	// Handle runtime request, expecting TokenErrorResponse
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusUnauthorized, httpResponse.StatusCode)
+	require.Equal(t, "invalid_client", errorResponse.Error)
+	require.Equal(t, "the client does not exist or the credentials are incorrect", errorResponse.ErrorDescription)
+	require.True(t, common.HasContentType(httpResponse.Header, "application/json"))
+}
+
+func TestClientCredentialsGrant_MissingCredentials(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	// Make a client credentials grant request
+	request := makeClientCredentialsFormRequest(ctx, "", "", "")
+
+	// Handle runtime request, expecting TokenErrorResponse
+	errorResponse, httpResponse, err := handleRuntimeRequest[authapi.ErrorResponse](schema, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusBadRequest, httpResponse.StatusCode)
+	require.Equal(t, "invalid_request", errorResponse.Error)
+	require.Equal(t, "the client credentials are required in the 'client_id' and 'client_secret' fields or using HTTP Basic authentication", errorResponse.ErrorDescription)
+	require.True(t, common.HasContentType(httpResponse.Header, "application/json"))
+}
+
+func TestClientCredentialsGrant_RolePermission(t *testing.T) {
+	schemaWithRoles := `
+		model Post {
+			actions {
+				list listPosts()
+			}
+			@permission(roles: [Admin], actions: [list])
+		}
+		role Admin {}
+		role Staff {}`
+
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), schemaWithRoles, true)
+	defer database.Close()
+
+	client, secret, err := oauth.NewServiceClient(ctx, "billing", []string{"Admin", "Staff"})
+	require.NoError(t, err)
+
+	for scope, expectedStatus := range map[string]int{"Admin": http.StatusOK, "Staff": http.StatusForbidden} {
+		request := makeClientCredentialsFormRequest(ctx, client.ClientId, secret, scope)
+		tokenResponse, _, err := handleRuntimeRequest[authapi.ClientCredentialsTokenResponse](schema, request)
+		require.NoError(t, err)
+
+		request = httptest.NewRequest(http.MethodPost, "http://mykeelapp.keel.so/api/json/listPosts", bytes.NewBufferString("{}"))
+		request.Header.Add("Content-Type", "application/json")
+		request.Header.Add("Authorization", "Bearer "+tokenResponse.AccessToken)
+		request = request.WithContext(ctx)
+
+		_, httpResponse, err := handleRuntimeRequest[map[string]any](schema, request)
+		require.NoError(t, err)
+		require.Equal(t, expectedStatus, httpResponse.StatusCode, "scope: %s", scope)
+	}
+}
+
 func handleRuntimeRequest[T any](schema *proto.Schema, req *http.Request) (T, *http.Response, error) {
 	var response T
 	handler := runtime.NewHttpHandler(schema)
@@ -1879,3 +2136,45 @@ func makePasswordJsonRequest(ctx context.Context, username string, password stri
 
 	return request
 }
+
+func makeClientCredentialsFormRequest(ctx context.Context, clientId string, clientSecret string, scope string) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "http://mykeelapp.keel.so/auth/token", nil)
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	form := url.Values{}
+	form.Add("grant_type", "client_credentials")
+
+	if clientId != "" {
+		form.Add("client_id", clientId)
+	}
+
+	if clientSecret != "" {
+		form.Add("client_secret", clientSecret)
+	}
+
+	if scope != "" {
+		form.Add("scope", scope)
+	}
+
+	request.URL.RawQuery = form.Encode()
+	request = request.WithContext(ctx)
+
+	return request
+}
+
+func makeClientCredentialsJsonRequest(ctx context.Context, clientId string, clientSecret string) *http.Request {
+	values := map[string]string{
+		"grant_type":    "client_credentials",
+		"client_id":     clientId,
+		"client_secret": clientSecret,
+	}
+
+	jsonValue, _ := json.Marshal(values)
+	responseBody := bytes.NewBuffer(jsonValue)
+
+	request := httptest.NewRequest(http.MethodPost, "http://mykeelapp.keel.so/auth/token", responseBody)
+	request.Header.Add("Content-Type", "application/json")
+	request = request.WithContext(ctx)
+
+	return request
+}
@@ -0,0 +1,140 @@
+package authapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/actions"
+	"github.com/teamkeel/keel/runtime/auth"
+	"github.com/teamkeel/keel/runtime/common"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+	"github.com/teamkeel/keel/schema/parser"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// https://datatracker.ietf.org/doc/html/rfc6750#section-3.1
+const (
+	UserInfoErrInvalidToken = "invalid_token"
+)
+
+// Maps the standard claims onto the fields of the identity model.
+var standardClaimFields = map[string]string{
+	"email":          parser.IdentityFieldNameEmail,
+	"email_verified": parser.IdentityFieldNameEmailVerified,
+	"name":           parser.IdentityFieldNameName,
+	"given_name":     parser.IdentityFieldNameGivenName,
+	"family_name":    parser.IdentityFieldNameFamilyName,
+	"middle_name":    parser.IdentityFieldNameMiddleName,
+	"nickname":       parser.IdentityFieldNameNickName,
+	"profile":        parser.IdentityFieldNameProfile,
+	"picture":        parser.IdentityFieldNamePicture,
+	"website":        parser.IdentityFieldNameWebsite,
+	"gender":         parser.IdentityFieldNameGender,
+	"zoneinfo":       parser.IdentityFieldNameZoneInfo,
+	"locale":         parser.IdentityFieldNameLocale,
+}
+
+// UserInfoHandler returns the claims of the identity which the access token was issued to.
+// Claims which have no value on the identity are omitted.
+// https://openid.net/specs/openid-connect-core-1_0.html#UserInfo
+func UserInfoHandler(schema *proto.Schema) common.HandlerFunc {
+	return func(r *http.Request) common.Response {
+		ctx, span := tracer.Start(r.Context(), "UserInfo Endpoint")
+		defer span.End()
+
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			return jsonErrResponse(ctx, http.StatusMethodNotAllowed, TokenErrInvalidRequest, "the userinfo endpoint only accepts GET or POST", nil)
+		}
+
+		identity, errResponse := authenticateBearerToken(ctx, schema, r)
+		if errResponse != nil {
+			return *errResponse
+		}
+
+		config, err := runtimectx.GetOAuthConfig(ctx)
+		if err != nil {
+			return common.InternalServerErrorResponse(ctx, err)
+		}
+
+		id := identity[parser.FieldNameId].(string)
+		span.SetAttributes(attribute.String("identity.id", id))
+
+		claims := map[string]any{
+			"sub": id,
+		}
+
+		for claim, field := range standardClaimFields {
+			if v, ok := identity[field]; ok && v != nil {
+				claims[claim] = v
+			}
+		}
+
+		for _, c := range config.Claims {
+			if v, ok := identity[c.Field]; ok && v != nil {
+				claims[c.Key] = v
+			}
+		}
+
+		return common.NewJsonResponse(http.StatusOK, claims, nil)
+	}
+}
+
+// authenticateBearerToken finds the identity which the bearer token in the Authorization header was issued to.
+// If the token is missing or invalid, then the error response is returned instead.
+func authenticateBearerToken(ctx context.Context, schema *proto.Schema, r *http.Request) (auth.Identity, *common.Response) {
+	header := r.Header.Get("Authorization")
+	token, hasBearer := strings.CutPrefix(header, "Bearer ")
+	if !hasBearer || token == "" {
+		resp := userInfoErrResponse(ctx, TokenErrInvalidRequest, "the access token is required as a bearer token in the Authorization header", nil)
+		return nil, &resp
+	}
+
+	if oauth.IsClientAccessToken(token) {
+		resp := userInfoErrResponse(ctx, UserInfoErrInvalidToken, "the access token was not issued to an identity", nil)
+		return nil, &resp
+	}
+
+	identity, err := actions.HandleBearerToken(ctx, schema, token)
+	if errors.Is(err, oauth.ErrInvalidToken) || errors.Is(err, oauth.ErrTokenExpired) || errors.Is(err, actions.ErrIdentityNotFound) {
+		resp := userInfoErrResponse(ctx, UserInfoErrInvalidToken, "the access token is invalid or has expired", err)
+		return nil, &resp
+	} else if err != nil {
+		resp := common.InternalServerErrorResponse(ctx, err)
+		return nil, &resp
+	}
+
+	return identity, nil
+}
+
+// authenticateCredentialsBearerToken authenticates the access token of a request which changes how the identity
+// authenticates, such as its authenticator app or linked providers. Impersonation tokens are rejected so that
+// an impersonator cannot take over the identity.
+func authenticateCredentialsBearerToken(ctx context.Context, schema *proto.Schema, r *http.Request) (auth.Identity, *common.Response) {
+	identity, errResponse := authenticateBearerToken(ctx, schema, r)
+	if errResponse != nil {
+		return nil, errResponse
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if oauth.ImpersonatorFromAccessToken(token) != "" {
+		resp := jsonErrResponse(ctx, http.StatusForbidden, TokenErrUnauthorizedClient, "an impersonation access token cannot change how the identity authenticates", nil)
+		return nil, &resp
+	}
+
+	return identity, nil
+}
+
+// Errors are communicated using the WWW-Authenticate header with a 401 status.
+// https://datatracker.ietf.org/doc/html/rfc6750#section-3
+func userInfoErrResponse(ctx context.Context, errorType string, errorDescription string, err error) common.Response {
+	response := jsonErrResponse(ctx, http.StatusUnauthorized, errorType, errorDescription, err)
+	response.Headers = http.Header{
+		"WWW-Authenticate": []string{fmt.Sprintf(`Bearer error="%s", error_description="%s"`, errorType, errorDescription)},
+	}
+	return response
+}
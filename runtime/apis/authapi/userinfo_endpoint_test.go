@@ -0,0 +1,81 @@
+package authapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/runtime/actions"
+	"github.com/teamkeel/keel/runtime/apis/authapi"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+	"github.com/teamkeel/keel/testhelpers"
+	keeltesting "github.com/teamkeel/keel/testing"
+)
+
+func TestUserInfo_ReturnsIdentityClaims(t *testing.T) {
+	ctx, database, schema := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	identity, err := actions.CreateIdentity(ctx, schema, "keelson@keel.so", "1234", oauth.KeelIssuer)
+	require.NoError(t, err)
+
+	accessToken, _, err := oauth.GenerateAccessToken(ctx, identity["id"].(string))
+	require.NoError(t, err)
+
+	request := httptest.NewRequest(http.MethodGet, "http://mykeelapp.keel.so/auth/userinfo", nil)
+	request = request.WithContext(ctx)
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+
+	claims, httpResponse, err := handleRuntimeRequest[map[string]any](schema, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, httpResponse.StatusCode)
+	require.Equal(t, identity["id"], claims["sub"])
+	require.Equal(t, "keelson@keel.so", claims["email"])
+	require.Equal(t, false, claims["email_verified"])
+	require.NotContains(t, claims, "name")
+}
+
+func TestUserInfo_MissingToken(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "http://mykeelapp.keel.so/auth/userinfo", nil)
+
+	response := authapi.UserInfoHandler(nil)(request)
+	require.Equal(t, http.StatusUnauthorized, response.Status)
+	require.Equal(t, `Bearer error="invalid_request", error_description="the access token is required as a bearer token in the Authorization header"`, response.Headers["WWW-Authenticate"][0])
+}
+
+func TestUserInfo_InvalidToken(t *testing.T) {
+	pk, err := testhelpers.GetEmbeddedPrivateKey()
+	require.NoError(t, err)
+
+	ctx := runtimectx.WithPrivateKey(context.Background(), pk)
+
+	request := httptest.NewRequest(http.MethodGet, "http://mykeelapp.keel.so/auth/userinfo", nil)
+	request = request.WithContext(ctx)
+	request.Header.Set("Authorization", "Bearer not-a-token")
+
+	response := authapi.UserInfoHandler(nil)(request)
+	require.Equal(t, http.StatusUnauthorized, response.Status)
+	require.Contains(t, response.Headers["WWW-Authenticate"][0], `error="invalid_token"`)
+}
+
+func TestUserInfo_ClientTokenRejected(t *testing.T) {
+	pk, err := testhelpers.GetEmbeddedPrivateKey()
+	require.NoError(t, err)
+
+	ctx := runtimectx.WithPrivateKey(context.Background(), pk)
+
+	accessToken, _, err := oauth.GenerateClientAccessToken(ctx, "my-client", []string{"Admin"})
+	require.NoError(t, err)
+
+	request := httptest.NewRequest(http.MethodGet, "http://mykeelapp.keel.so/auth/userinfo", nil)
+	request = request.WithContext(ctx)
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+
+	response := authapi.UserInfoHandler(nil)(request)
+	require.Equal(t, http.StatusUnauthorized, response.Status)
+	require.Contains(t, response.Headers["WWW-Authenticate"][0], `error="invalid_token"`)
+}
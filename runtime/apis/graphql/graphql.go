@@ -20,8 +20,8 @@ import (
 	"github.com/teamkeel/graphql/gqlerrors"
 	"github.com/teamkeel/keel/proto"
 	"github.com/teamkeel/keel/runtime/actions"
-	"github.com/teamkeel/keel/runtime/auth"
 	"github.com/teamkeel/keel/runtime/common"
+	"github.com/teamkeel/keel/runtime/runtimectx"
 	"github.com/teamkeel/keel/schema/parser"
 )
 
@@ -41,7 +41,7 @@ func NewHandler(s *proto.Schema, api *proto.Api) common.HandlerFunc {
 		ctx, span := tracer.Start(r.Context(), "GraphQL")
 		defer span.End()
 
-		identity, err := actions.HandleAuthorizationHeader(ctx, s, r.Header)
+		ctx, err := actions.HandleAuthorizationHeader(ctx, s, r.Header)
 		if err != nil {
 			var extensions map[string]interface{}
 
@@ -59,9 +59,6 @@ func NewHandler(s *proto.Schema, api *proto.Api) common.HandlerFunc {
 				},
 			}, nil)
 		}
-		if identity != nil {
-			ctx = auth.WithIdentity(ctx, identity)
-		}
 
 		// We lazily initialise the GraphQL schema as until there is actually
 		// a GraphQL request to handle we don't need it. Also we don't want the
@@ -167,6 +164,10 @@ func NewGraphQLSchema(proto *proto.Schema, api *proto.Api) (*graphql.Schema, err
 			Name:   "Mutation",
 			Fields: graphql.Fields{},
 		}),
+		subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Subscription",
+			Fields: graphql.Fields{},
+		}),
 		inputs: map[string]*graphql.InputObject{},
 		types:  make(map[string]graphql.Type),
 		enums:  map[string]*graphql.Enum{},
@@ -178,13 +179,14 @@ func NewGraphQLSchema(proto *proto.Schema, api *proto.Api) (*graphql.Schema, err
 // A graphqlSchemaBuilder exposes a Make method, that makes a set of graphql.Schema objects - one for each
 // of the APIs defined in the keel schema provided at construction time.
 type graphqlSchemaBuilder struct {
-	proto    *proto.Schema
-	query    *graphql.Object
-	mutation *graphql.Object
-	inputs   map[string]*graphql.InputObject
-	types    map[string]graphql.Type
-	enums    map[string]*graphql.Enum
-	globals  map[string]*graphql.Scalar
+	proto        *proto.Schema
+	query        *graphql.Object
+	mutation     *graphql.Object
+	subscription *graphql.Object
+	inputs       map[string]*graphql.InputObject
+	types        map[string]graphql.Type
+	enums        map[string]*graphql.Enum
+	globals      map[string]*graphql.Scalar
 }
 
 // build returns a graphql.Schema that implements the given API.
@@ -197,6 +199,15 @@ func (mk *graphqlSchemaBuilder) build(api *proto.Api, schema *proto.Schema) (*gr
 		}
 	}
 
+	// Models can be subscribed to if they have a get action in the API, as events are
+	// delivered according to the permission rules of the model's get action.
+	for _, model := range proto.GetModelsWithActionTypeForApi(schema, api, proto.ActionType_ACTION_TYPE_GET) {
+		err := mk.addSubscriptions(model)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	mk.addGlobals()
 
 	// The graphql handler cannot manage an empty query object,
@@ -215,12 +226,14 @@ func (mk *graphqlSchemaBuilder) build(api *proto.Api, schema *proto.Schema) (*gr
 	}
 
 	mutation := lo.Ternary(len(mk.mutation.Fields()) > 0, mk.mutation, nil)
+	subscription := lo.Ternary(len(mk.subscription.Fields()) > 0, mk.subscription, nil)
 
 	gSchema, err := graphql.NewSchema(graphql.SchemaConfig{
 		Query: mk.query,
 		Types: types,
-		// graphql won't accept a mutation object that has zero fields.
-		Mutation: mutation,
+		// graphql won't accept a mutation or subscription object that has zero fields.
+		Mutation:     mutation,
+		Subscription: subscription,
 	})
 	if err != nil {
 		return nil, err
@@ -268,9 +281,15 @@ func (mk *graphqlSchemaBuilder) addModel(model *proto.Model) (*graphql.Object, e
 		}
 
 		if field.Type.Type != proto.Type_TYPE_MODEL {
+			// Fields with read permissions are null when the identity is not permitted to read them
+			if nonNull, ok := outputType.(*graphql.NonNull); ok && len(field.ReadPermissions()) > 0 {
+				outputType = nonNull.OfType
+			}
+
 			object.AddFieldConfig(field.Name, &graphql.Field{
-				Name: field.Name,
-				Type: outputType,
+				Name:        field.Name,
+				Type:        outputType,
+				Description: field.PermissionsDescription(),
 			})
 			continue
 		}
@@ -379,6 +398,13 @@ func (mk *graphqlSchemaBuilder) addModel(model *proto.Model) (*graphql.Object, e
 						return nil, errors.New("record expected in database but nothing found")
 					}
 
+					err = actions.ApplyFieldReadPermissions(scope, []map[string]any{result})
+					if err != nil {
+						span.RecordError(err, trace.WithStackTrace(true))
+						span.SetStatus(codes.Error, err.Error())
+						return nil, err
+					}
+
 					return result, nil
 				case field.IsHasMany():
 					page, err := actions.ParsePage(p.Args)
@@ -388,6 +414,10 @@ func (mk *graphqlSchemaBuilder) addModel(model *proto.Model) (*graphql.Object, e
 						return nil, err
 					}
 
+					if api, ok := runtimectx.GetApi(ctx); ok {
+						page.MaxPageSize = int(api.MaxPageSize)
+					}
+
 					// Select all columns from this table and distinct on id
 					query.DistinctOn(actions.IdField())
 					query.Select(actions.AllFields())
@@ -418,6 +448,13 @@ func (mk *graphqlSchemaBuilder) addModel(model *proto.Model) (*graphql.Object, e
 						return nil, common.NewPermissionError()
 					}
 
+					err = actions.ApplyFieldReadPermissions(scope, results)
+					if err != nil {
+						span.RecordError(err, trace.WithStackTrace(true))
+						span.SetStatus(codes.Error, err.Error())
+						return nil, err
+					}
+
 					res, err := connectionResponse(map[string]any{
 						"results":  results,
 						"pageInfo": pageInfo.ToMap(),
@@ -788,6 +825,9 @@ func (mk *graphqlSchemaBuilder) inputTypeFromMessageField(field *proto.MessageFi
 			Fields: graphql.InputObjectConfigFieldMap{},
 		})
 
+		// Registered before adding the fields as a message can reference itself (such as with nested filter groups).
+		mk.inputs[messageName] = inputObject
+
 		for _, input := range message.Fields {
 			inputField, err := mk.inputTypeFromMessageField(input)
 			if err != nil {
@@ -799,7 +839,6 @@ func (mk *graphqlSchemaBuilder) inputTypeFromMessageField(field *proto.MessageFi
 			})
 		}
 
-		mk.inputs[messageName] = inputObject
 		in = inputObject
 	case field.Type.Type == proto.Type_TYPE_UNION:
 		// GraphQL doesn't support union type or the concept of oneOf for inputs _yet_,
@@ -0,0 +1,176 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iancoleman/strcase"
+	"github.com/sirupsen/logrus"
+	"github.com/teamkeel/graphql"
+	"github.com/teamkeel/keel/events"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/actions"
+)
+
+// subscriptionEvents are the events which can be subscribed to for each model.
+var subscriptionEvents = []string{events.Created, events.Updated, events.Deleted}
+
+// addSubscriptions generates the created, updated and deleted subscriptions for the given model.
+func (mk *graphqlSchemaBuilder) addSubscriptions(model *proto.Model) error {
+	modelType, err := mk.addModel(model)
+	if err != nil {
+		return err
+	}
+
+	whereType, err := mk.makeSubscriptionWhereType(model)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range subscriptionEvents {
+		event := event
+		name := strcase.ToLowerCamel(model.Name) + strcase.ToCamel(event)
+
+		mk.subscription.AddFieldConfig(name, &graphql.Field{
+			Name:        name,
+			Type:        graphql.NewNonNull(modelType),
+			Description: fmt.Sprintf("Subscribe to each %s which is %s and which you have permission to get.", model.Name, event),
+			Args: graphql.FieldConfigArgument{
+				"where": &graphql.ArgumentConfig{
+					Type: whereType,
+				},
+			},
+			Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+				where, _ := p.Args["where"].(map[string]any)
+				return subscribe(p.Context, mk.proto, model, event, where)
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				// The source is the record delivered by the subscription
+				return p.Source, nil
+			},
+		})
+	}
+
+	return nil
+}
+
+// makeSubscriptionWhereType generates the input type used to filter the records delivered by a model's
+// subscriptions. Each of the model's fields which hold a single value can be matched against a value.
+func (mk *graphqlSchemaBuilder) makeSubscriptionWhereType(model *proto.Model) (*graphql.InputObject, error) {
+	input := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:   model.Name + "SubscriptionWhere",
+		Fields: graphql.InputObjectConfigFieldMap{},
+	})
+
+	for _, field := range model.Fields {
+		if !isSubscriptionFilterField(field) {
+			continue
+		}
+
+		var in graphql.Input
+		if field.Type.Type == proto.Type_TYPE_ENUM {
+			in = mk.addEnum(proto.FindEnum(mk.proto.Enums, field.Type.EnumName.Value))
+		} else {
+			in = protoTypeToGraphQLInput[field.Type.Type]
+		}
+
+		input.AddFieldConfig(field.Name, &graphql.InputObjectFieldConfig{
+			Type: in,
+		})
+	}
+
+	return input, nil
+}
+
+func isSubscriptionFilterField(field *proto.Field) bool {
+	if field.Type.Repeated {
+		return false
+	}
+
+	switch field.Type.Type {
+	case proto.Type_TYPE_ID, proto.Type_TYPE_STRING, proto.Type_TYPE_MARKDOWN, proto.Type_TYPE_INT, proto.Type_TYPE_DECIMAL, proto.Type_TYPE_BOOL, proto.Type_TYPE_ENUM:
+		return true
+	default:
+		return false
+	}
+}
+
+// subscribe reads the model's events from the audit trail until the context is done, and delivers the record of
+// each matching event which the identity has permission to get on the returned channel.
+func subscribe(ctx context.Context, schema *proto.Schema, model *proto.Model, event string, where map[string]any) (chan interface{}, error) {
+	feed, err := events.NewFeed(ctx, []*proto.Model{model}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(chan interface{})
+
+	go func() {
+		defer close(records)
+
+		ticker := time.NewTicker(events.FeedPollInterval)
+		defer ticker.Stop()
+
+		authoriser := actions.NewChangeAuthoriser(actions.NewModelScope(ctx, model, schema))
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			feedEvents, err := feed.Next(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					logrus.WithError(err).Error("error reading events for subscription")
+				}
+				return
+			}
+
+			for _, e := range feedEvents {
+				if !strings.HasSuffix(e.Event.EventName, "."+event) {
+					continue
+				}
+
+				record := actions.RecordFromEventData(model, e.Event.Target.Data)
+				if !matchesWhere(record, where) {
+					continue
+				}
+
+				authorised, err := authoriser.Authorise(event, record)
+				if err != nil {
+					if ctx.Err() == nil {
+						logrus.WithError(err).Error("error checking permissions for subscription")
+					}
+					return
+				}
+
+				if !authorised {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case records <- record:
+				}
+			}
+		}
+	}()
+
+	return records, nil
+}
+
+// matchesWhere determines if the record has each of the values being filtered on.
+func matchesWhere(record map[string]any, where map[string]any) bool {
+	for field, value := range where {
+		if fmt.Sprint(record[field]) != fmt.Sprint(value) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -173,6 +173,14 @@ var pageInfoType = graphql.NewObject(graphql.ObjectConfig{
 			Type:        graphql.NewNonNull(graphql.Int),
 			Description: "Count of nodes on the current page.",
 		},
+		"pageNumber": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The number of the current page, starting at 1. Only set when using offset pagination.",
+		},
+		"totalPages": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "Total number of pages. Only set when using offset pagination.",
+		},
 	},
 })
 
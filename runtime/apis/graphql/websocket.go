@@ -0,0 +1,319 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
+	"github.com/teamkeel/graphql"
+	"github.com/teamkeel/graphql/gqlerrors"
+	"github.com/teamkeel/graphql/language/ast"
+	"github.com/teamkeel/graphql/language/parser"
+	"github.com/teamkeel/graphql/language/source"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/actions"
+	"golang.org/x/net/websocket"
+)
+
+// The GraphQL over WebSocket protocol, as implemented by graphql-ws.
+// https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+const subProtocol = "graphql-transport-ws"
+
+// Message types of the GraphQL over WebSocket protocol
+const (
+	messageConnectionInit = "connection_init"
+	messageConnectionAck  = "connection_ack"
+	messagePing           = "ping"
+	messagePong           = "pong"
+	messageSubscribe      = "subscribe"
+	messageNext           = "next"
+	messageError          = "error"
+	messageComplete       = "complete"
+)
+
+// Close codes of the GraphQL over WebSocket protocol
+const (
+	closeInvalidMessage          = 4400
+	closeUnauthorized            = 4401
+	closeForbidden               = 4403
+	closeInitialisationTimeout   = 4408
+	closeSubscriberAlreadyExists = 4409
+	closeTooManyInitialisations  = 4429
+)
+
+// connectionInitTimeout is how long a client has to initialise the connection once it has been opened.
+const connectionInitTimeout = 10 * time.Second
+
+type wsMessage struct {
+	Id      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// NewSubscriptionHandler serves GraphQL subscriptions over WebSockets using the graphql-ws protocol. Queries and
+// mutations can also be sent over the connection. The identity is authenticated when the connection is initialised,
+// either with the Authorization header of the upgrade request or with an Authorization value in the payload
+// of the connection_init message, since browsers cannot set headers when opening a WebSocket. The identity is
+// authenticated again periodically, and the connection is closed once its access token expires or it can no longer
+// be authenticated, such as when the identity has been disabled.
+func NewSubscriptionHandler(s *proto.Schema, api *proto.Api) http.Handler {
+	var schema *graphql.Schema
+	var mutex sync.Mutex
+
+	return websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			if !lo.Contains(config.Protocol, subProtocol) {
+				return fmt.Errorf("the %s subprotocol is required", subProtocol)
+			}
+			config.Protocol = []string{subProtocol}
+			return nil
+		},
+		Handler: func(conn *websocket.Conn) {
+			// As with the GraphQL handler, the schema is lazily initialised.
+			mutex.Lock()
+			if schema == nil {
+				var err error
+				schema, err = NewGraphQLSchema(s, api)
+				if err != nil {
+					mutex.Unlock()
+					logrus.WithError(err).Error("error initialising GraphQL")
+					_ = conn.Close()
+					return
+				}
+			}
+			mutex.Unlock()
+
+			c := &subscriptionConnection{
+				conn:          conn,
+				schema:        *schema,
+				proto:         s,
+				subscriptions: map[string]context.CancelFunc{},
+			}
+			c.serve()
+		},
+	}
+}
+
+type subscriptionConnection struct {
+	conn          *websocket.Conn
+	schema        graphql.Schema
+	proto         *proto.Schema
+	mutex         sync.Mutex
+	subscriptions map[string]context.CancelFunc
+}
+
+// serve reads the messages sent by the client until the connection is closed.
+func (c *subscriptionConnection) serve() {
+	ctx, cancel := context.WithCancel(c.conn.Request().Context())
+	defer cancel()
+	defer c.conn.Close()
+
+	initialised := false
+	_ = c.conn.SetReadDeadline(time.Now().Add(connectionInitTimeout))
+
+	for {
+		var msg wsMessage
+		err := websocket.JSON.Receive(c.conn, &msg)
+		if err != nil {
+			var netErr net.Error
+			switch {
+			case errors.As(err, &netErr) && netErr.Timeout():
+				c.close(closeInitialisationTimeout)
+			case errors.As(err, new(*json.SyntaxError)), errors.As(err, new(*json.UnmarshalTypeError)):
+				c.close(closeInvalidMessage)
+			}
+			return
+		}
+
+		switch msg.Type {
+		case messageConnectionInit:
+			if initialised {
+				c.close(closeTooManyInitialisations)
+				return
+			}
+
+			header := c.authorizationHeader(msg.Payload)
+
+			ctx, err = actions.HandleAuthorizationHeader(ctx, c.proto, header)
+			if err != nil {
+				c.close(closeForbidden)
+				return
+			}
+
+			initialised = true
+			_ = c.conn.SetReadDeadline(time.Time{})
+			c.send(&wsMessage{Type: messageConnectionAck})
+
+			go c.closeWhenUnauthenticated(ctx, header)
+		case messagePing:
+			c.send(&wsMessage{Type: messagePong})
+		case messagePong:
+			// Nothing to do
+		case messageSubscribe:
+			if !initialised {
+				c.close(closeUnauthorized)
+				return
+			}
+
+			var params GraphQLRequest
+			if msg.Id == "" || json.Unmarshal(msg.Payload, &params) != nil {
+				c.close(closeInvalidMessage)
+				return
+			}
+
+			c.mutex.Lock()
+			_, exists := c.subscriptions[msg.Id]
+			if exists {
+				c.mutex.Unlock()
+				c.close(closeSubscriberAlreadyExists)
+				return
+			}
+
+			subCtx, cancelSub := context.WithCancel(ctx)
+			c.subscriptions[msg.Id] = cancelSub
+			c.mutex.Unlock()
+
+			go c.execute(subCtx, msg.Id, params)
+		case messageComplete:
+			c.mutex.Lock()
+			if cancelSub, ok := c.subscriptions[msg.Id]; ok {
+				cancelSub()
+				delete(c.subscriptions, msg.Id)
+			}
+			c.mutex.Unlock()
+		default:
+			c.close(closeInvalidMessage)
+			return
+		}
+	}
+}
+
+// authorizationHeader returns the headers to authenticate the identity with, using the Authorization value in the
+// connection_init payload, or otherwise the Authorization header of the upgrade request.
+func (c *subscriptionConnection) authorizationHeader(payload json.RawMessage) http.Header {
+	header := c.conn.Request().Header.Clone()
+
+	var values map[string]any
+	if len(payload) > 0 && json.Unmarshal(payload, &values) == nil {
+		for k, v := range values {
+			if s, ok := v.(string); ok && strings.EqualFold(k, "authorization") {
+				header.Set("Authorization", s)
+			}
+		}
+	}
+
+	return header
+}
+
+// closeWhenUnauthenticated closes the connection, and so ends its operations, once the Authorization header
+// can no longer be used to authenticate.
+func (c *subscriptionConnection) closeWhenUnauthenticated(ctx context.Context, header http.Header) {
+	select {
+	case <-ctx.Done():
+	case <-actions.WatchAuthorizationHeader(ctx, c.proto, header):
+		c.close(closeForbidden)
+		_ = c.conn.Close()
+	}
+}
+
+// execute executes the operation, sending each result to the client until the operation has completed
+// or the client has stopped listening.
+func (c *subscriptionConnection) execute(ctx context.Context, id string, params GraphQLRequest) {
+	defer func() {
+		c.mutex.Lock()
+		if cancelSub, ok := c.subscriptions[id]; ok {
+			cancelSub()
+			delete(c.subscriptions, id)
+		}
+		c.mutex.Unlock()
+	}()
+
+	p := graphql.Params{
+		Schema:         c.schema,
+		Context:        ctx,
+		RequestString:  params.Query,
+		VariableValues: params.Variables,
+		OperationName:  params.OperationName,
+		RootObject: map[string]interface{}{
+			"headers": map[string][]string{},
+		},
+	}
+
+	var results chan *graphql.Result
+	if isSubscription(params.Query, params.OperationName) {
+		results = graphql.Subscribe(p)
+	} else {
+		results = make(chan *graphql.Result, 1)
+		results <- graphql.Do(p)
+		close(results)
+	}
+
+	first := true
+	for result := range results {
+		// Errors which prevent the operation from executing, such as validation errors, are sent as an error message.
+		if first && result.HasErrors() && result.Data == nil {
+			c.send(&wsMessage{Id: id, Type: messageError, Payload: marshal(result.Errors)})
+			return
+		}
+		first = false
+
+		c.send(&wsMessage{Id: id, Type: messageNext, Payload: marshal(result)})
+	}
+
+	// The client does not expect a complete message if it has stopped listening.
+	if ctx.Err() == nil {
+		c.send(&wsMessage{Id: id, Type: messageComplete})
+	}
+}
+
+func (c *subscriptionConnection) send(msg *wsMessage) {
+	err := websocket.JSON.Send(c.conn, msg)
+	if err != nil {
+		logrus.WithError(err).Debug("error sending graphql-ws message")
+	}
+}
+
+// close closes the connection with one of the close codes of the protocol.
+func (c *subscriptionConnection) close(code int) {
+	_ = c.conn.WriteClose(code)
+}
+
+func marshal(v any) json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal([]gqlerrors.FormattedError{{Message: err.Error()}})
+	}
+	return b
+}
+
+// isSubscription determines if the operation being executed is a subscription.
+func isSubscription(query string, operationName string) bool {
+	document, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(query)}),
+	})
+	if err != nil {
+		// The error is reported when the operation is executed
+		return false
+	}
+
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		if operationName == "" || (operation.Name != nil && operation.Name.Value == operationName) {
+			return operation.Operation == ast.OperationTypeSubscription
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,251 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/events"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/actions"
+	"github.com/teamkeel/keel/runtime/apis/graphql"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/schema"
+	"github.com/teamkeel/keel/schema/parser"
+	"github.com/teamkeel/keel/schema/reader"
+	keeltesting "github.com/teamkeel/keel/testing"
+	"golang.org/x/net/websocket"
+)
+
+const subscriptionSchema = `
+model Post {
+	fields {
+		title Text
+	}
+	actions {
+		get getPost(id)
+	}
+	@permission(expression: true, actions: [get])
+}`
+
+type message struct {
+	Id      string         `json:"id,omitempty"`
+	Type    string         `json:"type"`
+	Payload map[string]any `json:"payload,omitempty"`
+}
+
+const subscriptionPermissionsSchema = `
+model Post {
+	fields {
+		title Text
+		author Identity
+	}
+	actions {
+		get getPost(id)
+	}
+	@permission(expression: post.author == ctx.identity, actions: [get])
+}`
+
+func dialSubscriptions(t *testing.T) *websocket.Conn {
+	builder := schema.Builder{}
+	protoSchema, err := builder.MakeFromInputs(&reader.Inputs{
+		SchemaFiles: []*reader.SchemaFile{{Contents: subscriptionSchema}},
+	})
+	require.NoError(t, err)
+
+	return dial(t, graphql.NewSubscriptionHandler(protoSchema, protoSchema.Apis[0]))
+}
+
+// dialSubscriptionsWithContext serves the subscriptions with the context, which provides the database.
+func dialSubscriptionsWithContext(t *testing.T, ctx context.Context, protoSchema *proto.Schema) *websocket.Conn {
+	handler := graphql.NewSubscriptionHandler(protoSchema, protoSchema.Apis[0])
+
+	return dial(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	}))
+}
+
+func dial(t *testing.T, handler http.Handler) *websocket.Conn {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config, err := websocket.NewConfig("ws"+strings.TrimPrefix(server.URL, "http")+"/api/graphql", server.URL)
+	require.NoError(t, err)
+	config.Protocol = []string{"graphql-transport-ws"}
+
+	conn, err := websocket.DialConfig(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestSubscriptionsConnectionInit(t *testing.T) {
+	conn := dialSubscriptions(t)
+
+	require.NoError(t, websocket.JSON.Send(conn, message{Type: "connection_init"}))
+
+	var ack message
+	require.NoError(t, websocket.JSON.Receive(conn, &ack))
+	assert.Equal(t, "connection_ack", ack.Type)
+
+	require.NoError(t, websocket.JSON.Send(conn, message{Type: "ping"}))
+
+	var pong message
+	require.NoError(t, websocket.JSON.Receive(conn, &pong))
+	assert.Equal(t, "pong", pong.Type)
+}
+
+func TestSubscriptionsQuery(t *testing.T) {
+	conn := dialSubscriptions(t)
+
+	require.NoError(t, websocket.JSON.Send(conn, message{Type: "connection_init"}))
+
+	var ack message
+	require.NoError(t, websocket.JSON.Receive(conn, &ack))
+
+	require.NoError(t, websocket.JSON.Send(conn, message{Id: "1", Type: "subscribe", Payload: map[string]any{
+		"query": "{ __typename }",
+	}}))
+
+	var next message
+	require.NoError(t, websocket.JSON.Receive(conn, &next))
+	assert.Equal(t, "next", next.Type)
+	assert.Equal(t, "1", next.Id)
+	assert.Equal(t, map[string]any{"__typename": "Query"}, next.Payload["data"])
+
+	var complete message
+	require.NoError(t, websocket.JSON.Receive(conn, &complete))
+	assert.Equal(t, "complete", complete.Type)
+	assert.Equal(t, "1", complete.Id)
+}
+
+func TestSubscriptionsValidationError(t *testing.T) {
+	conn := dialSubscriptions(t)
+
+	require.NoError(t, websocket.JSON.Send(conn, message{Type: "connection_init"}))
+
+	var ack message
+	require.NoError(t, websocket.JSON.Receive(conn, &ack))
+
+	require.NoError(t, websocket.JSON.Send(conn, message{Id: "1", Type: "subscribe", Payload: map[string]any{
+		"query": "subscription { postCreated { unknown } }",
+	}}))
+
+	var msg struct {
+		Id      string           `json:"id"`
+		Type    string           `json:"type"`
+		Payload []map[string]any `json:"payload"`
+	}
+	require.NoError(t, websocket.JSON.Receive(conn, &msg))
+	assert.Equal(t, "error", msg.Type)
+	assert.Equal(t, "1", msg.Id)
+	assert.Len(t, msg.Payload, 1)
+}
+
+func TestSubscriptionsSubscribeBeforeInit(t *testing.T) {
+	conn := dialSubscriptions(t)
+
+	require.NoError(t, websocket.JSON.Send(conn, message{Id: "1", Type: "subscribe", Payload: map[string]any{
+		"query": "subscription { postCreated { id } }",
+	}}))
+
+	var msg message
+	assert.Error(t, websocket.JSON.Receive(conn, &msg))
+}
+
+func TestSubscriptionsInvalidMessage(t *testing.T) {
+	conn := dialSubscriptions(t)
+
+	require.NoError(t, websocket.JSON.Send(conn, message{Type: "unknown"}))
+
+	var msg message
+	assert.Error(t, websocket.JSON.Receive(conn, &msg))
+}
+
+// initWithToken initialises the connection, authenticating with the access token.
+func initWithToken(t *testing.T, conn *websocket.Conn, token string) {
+	require.NoError(t, websocket.JSON.Send(conn, message{Type: "connection_init", Payload: map[string]any{
+		"Authorization": "Bearer " + token,
+	}}))
+
+	var ack message
+	require.NoError(t, websocket.JSON.Receive(conn, &ack))
+	require.Equal(t, "connection_ack", ack.Type)
+}
+
+func TestSubscriptionsDeliversOnlyPermittedRecords(t *testing.T) {
+	pollInterval := events.FeedPollInterval
+	events.FeedPollInterval = 50 * time.Millisecond
+	t.Cleanup(func() { events.FeedPollInterval = pollInterval })
+
+	ctx, database, s := keeltesting.MakeContext(t, context.Background(), subscriptionPermissionsSchema, true)
+	defer database.Close()
+
+	alice, err := actions.CreateIdentity(ctx, s, "alice@keel.xyz", "1234", oauth.KeelIssuer)
+	require.NoError(t, err)
+	bob, err := actions.CreateIdentity(ctx, s, "bob@keel.xyz", "1234", oauth.KeelIssuer)
+	require.NoError(t, err)
+
+	token, _, err := oauth.GenerateAccessToken(ctx, alice[parser.FieldNameId].(string))
+	require.NoError(t, err)
+
+	conn := dialSubscriptionsWithContext(t, ctx, s)
+	initWithToken(t, conn, token)
+
+	require.NoError(t, websocket.JSON.Send(conn, message{Id: "1", Type: "subscribe", Payload: map[string]any{
+		"query": "subscription { postCreated { title } }",
+	}}))
+
+	// Allow the subscription to start reading the audit trail
+	time.Sleep(200 * time.Millisecond)
+
+	err = database.GetDB().Exec(`INSERT INTO post (id, title, author_id) VALUES ('bobs', 'Bob''s post', ?)`, bob[parser.FieldNameId]).Error
+	require.NoError(t, err)
+	err = database.GetDB().Exec(`INSERT INTO post (id, title, author_id) VALUES ('alices', 'Alice''s post', ?)`, alice[parser.FieldNameId]).Error
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	var next message
+	require.NoError(t, websocket.JSON.Receive(conn, &next))
+	assert.Equal(t, "next", next.Type)
+	assert.Equal(t, "1", next.Id)
+	assert.Equal(t, map[string]any{"postCreated": map[string]any{"title": "Alice's post"}}, next.Payload["data"])
+}
+
+func TestSubscriptionsClosedWhenIdentityDisabled(t *testing.T) {
+	interval := actions.ReauthenticationInterval
+	actions.ReauthenticationInterval = 50 * time.Millisecond
+	t.Cleanup(func() { actions.ReauthenticationInterval = interval })
+
+	ctx, database, s := keeltesting.MakeContext(t, context.Background(), subscriptionPermissionsSchema, true)
+	defer database.Close()
+
+	alice, err := actions.CreateIdentity(ctx, s, "alice@keel.xyz", "1234", oauth.KeelIssuer)
+	require.NoError(t, err)
+
+	token, _, err := oauth.GenerateAccessToken(ctx, alice[parser.FieldNameId].(string))
+	require.NoError(t, err)
+
+	conn := dialSubscriptionsWithContext(t, ctx, s)
+	initWithToken(t, conn, token)
+
+	require.NoError(t, oauth.DisableIdentity(ctx, alice[parser.FieldNameId].(string)))
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	var msg message
+	err = websocket.JSON.Receive(conn, &msg)
+	require.Error(t, err)
+
+	var netErr net.Error
+	assert.False(t, errors.As(err, &netErr) && netErr.Timeout(), "expected the connection to be closed")
+}
@@ -0,0 +1,217 @@
+package httpjson
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/teamkeel/keel/events"
+	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/runtime/actions"
+	"github.com/teamkeel/keel/runtime/common"
+	"github.com/teamkeel/keel/runtime/oauth"
+)
+
+// changesKeepAliveInterval is how often a comment is sent while there are no changes,
+// so that idle connections are not closed by proxies.
+const changesKeepAliveInterval = 15 * time.Second
+
+// NewChangesHandler streams the changes to the records of a model as Server-Sent Events for as long as the client
+// is connected. Each event has the id of its audit log, so a client which reconnects with the Last-Event-ID header
+// resumes after the last event it received, although deleted events are then only sent for the records which were
+// delivered on the new connection. Records are only sent if the identity has permission to get them.
+// The stream ends when the access token expires, or once the identity can no longer be authenticated, such as when
+// it has been disabled, which is checked periodically.
+//
+// As EventSource clients cannot set headers, a POST request with the Authorization header returns a short-lived
+// stream ticket, which authenticates the identity when it is provided with the ticket parameter to open the stream.
+// This keeps the access token itself out of URLs, and so out of access logs.
+func NewChangesHandler(p *proto.Schema, model *proto.Model) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "HttpJson Changes")
+		defer span.End()
+
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			writeResponse(w, createStreamTicket(ctx, p, r))
+			return
+		default:
+			writeResponse(w, NewErrorResponse(ctx, common.NewHttpMethodNotAllowedError("only HTTP GET and POST accepted"), nil))
+			return
+		}
+
+		var err error
+		var ticketExpiresAt time.Time
+
+		ticket := r.URL.Query().Get("ticket")
+		withTicket := ticket != "" && r.Header.Get("Authorization") == ""
+
+		if withTicket {
+			ctx, ticketExpiresAt, err = actions.HandleStreamTicket(ctx, p, ticket)
+		} else {
+			ctx, err = actions.HandleAuthorizationHeader(ctx, p, r.Header)
+		}
+		if err != nil {
+			writeResponse(w, NewErrorResponse(ctx, err, nil))
+			return
+		}
+
+		scope := actions.NewModelScope(ctx, model, p)
+
+		// Deny the request up front if the identity cannot get any of the model's records
+		permissions := proto.PermissionsForActionType(p, model.Name, proto.ActionType_ACTION_TYPE_GET)
+		canResolve, authorised, err := actions.TryResolveAuthorisationEarly(scope, permissions)
+		if err != nil {
+			writeResponse(w, NewErrorResponse(ctx, err, nil))
+			return
+		}
+
+		if len(permissions) == 0 || (canResolve && !authorised) {
+			writeResponse(w, NewErrorResponse(ctx, common.NewPermissionError(), nil))
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeResponse(w, NewErrorResponse(ctx, errors.New("streaming is not supported"), nil))
+			return
+		}
+
+		feed, err := events.NewFeed(ctx, []*proto.Model{model}, r.Header.Get("Last-Event-ID"))
+		if errors.Is(err, events.ErrEventNotFound) {
+			writeResponse(w, NewErrorResponse(ctx, common.NewValidationError(err.Error()), nil))
+			return
+		}
+		if err != nil {
+			writeResponse(w, NewErrorResponse(ctx, err, nil))
+			return
+		}
+
+		var ended <-chan struct{}
+		if withTicket {
+			ended = actions.WatchStreamTicket(ctx, p, ticketExpiresAt)
+		} else {
+			ended = actions.WatchAuthorizationHeader(ctx, p, r.Header)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		err = streamChanges(ctx, w, flusher, feed, scope, ended)
+		if err != nil && ctx.Err() == nil {
+			logrus.WithError(err).Error("error streaming changes")
+		}
+	})
+}
+
+// createStreamTicket exchanges the identity's access token in the Authorization header for a stream ticket.
+func createStreamTicket(ctx context.Context, p *proto.Schema, r *http.Request) common.Response {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || oauth.IsClientAccessToken(token) {
+		return NewErrorResponse(ctx, common.NewAuthenticationFailedMessageErr("an identity's access token is required to create a stream ticket"), nil)
+	}
+
+	_, err := actions.HandleBearerToken(ctx, p, token)
+	if err != nil {
+		return NewErrorResponse(ctx, err, nil)
+	}
+
+	ticket, err := oauth.GenerateStreamTicket(ctx, token)
+	if err != nil {
+		return NewErrorResponse(ctx, err, nil)
+	}
+
+	return common.NewJsonResponse(http.StatusOK, map[string]any{
+		"ticket":     ticket,
+		"expires_in": int(oauth.StreamTicketExpiry.Seconds()),
+	}, nil)
+}
+
+// streamChanges writes each authorised change to the stream until the client disconnects or the stream has ended.
+func streamChanges(ctx context.Context, w http.ResponseWriter, flusher http.Flusher, feed *events.Feed, scope *actions.Scope, ended <-chan struct{}) error {
+	poll := time.NewTicker(events.FeedPollInterval)
+	defer poll.Stop()
+
+	keepAlive := time.NewTicker(changesKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	authoriser := actions.NewChangeAuthoriser(scope)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ended:
+			return nil
+		case <-keepAlive.C:
+			_, err := fmt.Fprint(w, ": keep-alive\n\n")
+			if err != nil {
+				return err
+			}
+			flusher.Flush()
+			continue
+		case <-poll.C:
+		}
+
+		feedEvents, err := feed.Next(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range feedEvents {
+			record := actions.RecordFromEventData(scope.Model, e.Event.Target.Data)
+
+			authorised, err := authoriser.Authorise(eventType(e.Event), record)
+			if err != nil {
+				return err
+			}
+
+			if !authorised {
+				continue
+			}
+
+			e.Event.Target.Data = record
+
+			data, err := json.Marshal(e.Event)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.Id, e.Event.EventName, data)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(feedEvents) > 0 {
+			flusher.Flush()
+			keepAlive.Reset(changesKeepAliveInterval)
+		}
+	}
+}
+
+// eventType returns the type of change of an event, e.g. created for post.created.
+func eventType(event *events.Event) string {
+	return event.EventName[strings.LastIndex(event.EventName, ".")+1:]
+}
+
+func writeResponse(w http.ResponseWriter, response common.Response) {
+	for k, values := range response.Headers {
+		for _, value := range values {
+			w.Header().Add(k, value)
+		}
+	}
+
+	w.WriteHeader(response.Status)
+	_, _ = w.Write(response.Body)
+}
@@ -9,7 +9,6 @@ import (
 
 	"github.com/teamkeel/keel/proto"
 	"github.com/teamkeel/keel/runtime/actions"
-	"github.com/teamkeel/keel/runtime/auth"
 	"github.com/teamkeel/keel/runtime/common"
 	"github.com/teamkeel/keel/runtime/jsonschema"
 	"github.com/teamkeel/keel/runtime/openapi"
@@ -41,13 +40,10 @@ func NewHandler(p *proto.Schema, api *proto.Api) common.HandlerFunc {
 			attribute.String("api.protocol", "HTTP JSON"),
 		)
 
-		identity, err := actions.HandleAuthorizationHeader(ctx, p, r.Header)
+		ctx, err := actions.HandleAuthorizationHeader(ctx, p, r.Header)
 		if err != nil {
 			return NewErrorResponse(ctx, err, nil)
 		}
-		if identity != nil {
-			ctx = auth.WithIdentity(ctx, identity)
-		}
 
 		switch r.Method {
 		case http.MethodGet:
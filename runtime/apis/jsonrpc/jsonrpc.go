@@ -10,7 +10,6 @@ import (
 
 	"github.com/teamkeel/keel/proto"
 	"github.com/teamkeel/keel/runtime/actions"
-	"github.com/teamkeel/keel/runtime/auth"
 	"github.com/teamkeel/keel/runtime/common"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -41,13 +40,10 @@ func NewHandler(schema *proto.Schema, api *proto.Api) common.HandlerFunc {
 			return NewErrorResponse(ctx, nil, err)
 		}
 
-		identity, err := actions.HandleAuthorizationHeader(ctx, schema, r.Header)
+		ctx, err := actions.HandleAuthorizationHeader(ctx, schema, r.Header)
 		if err != nil {
 			return NewErrorResponse(ctx, nil, err)
 		}
-		if identity != nil {
-			ctx = auth.WithIdentity(ctx, identity)
-		}
 
 		req, err := parseJsonRpcRequest(r.Body)
 		if err != nil {
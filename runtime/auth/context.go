@@ -9,6 +9,10 @@ type contextKey string
 
 const (
 	identityContextKey contextKey = "identityId"
+	clientContextKey   contextKey = "client"
+	mfaContextKey      contextKey = "mfa"
+	rolesContextKey    contextKey = "roles"
+	actorContextKey    contextKey = "impersonatorId"
 )
 
 type Identity map[string]any
@@ -32,3 +36,83 @@ func GetIdentity(ctx context.Context) (Identity, error) {
 func IsAuthenticated(ctx context.Context) bool {
 	return ctx.Value(identityContextKey) != nil
 }
+
+// WithMfaAuthenticated marks that the identity authenticated using a second factor.
+func WithMfaAuthenticated(ctx context.Context) context.Context {
+	return context.WithValue(ctx, mfaContextKey, true)
+}
+
+// IsMfaAuthenticated determines if the identity authenticated using a second factor.
+func IsMfaAuthenticated(ctx context.Context) bool {
+	return IsAuthenticated(ctx) && ctx.Value(mfaContextKey) == true
+}
+
+// WithImpersonator marks that the identity is being impersonated by another identity.
+func WithImpersonator(ctx context.Context, impersonatorId string) context.Context {
+	if impersonatorId != "" {
+		ctx = context.WithValue(ctx, actorContextKey, impersonatorId)
+	}
+
+	return ctx
+}
+
+// GetImpersonatorId returns the identity which is impersonating the authenticated identity, if any.
+func GetImpersonatorId(ctx context.Context) string {
+	v, _ := ctx.Value(actorContextKey).(string)
+	return v
+}
+
+// IsImpersonated determines if the authenticated identity is being impersonated by another identity.
+func IsImpersonated(ctx context.Context) bool {
+	return IsAuthenticated(ctx) && GetImpersonatorId(ctx) != ""
+}
+
+// Client is a service client which has authenticated using the client credentials grant.
+type Client struct {
+	Id    string   `json:"id"`
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+func WithClient(ctx context.Context, client *Client) context.Context {
+	if client != nil {
+		ctx = context.WithValue(ctx, clientContextKey, client)
+	}
+
+	return ctx
+}
+
+func GetClient(ctx context.Context) (*Client, error) {
+	v, ok := ctx.Value(clientContextKey).(*Client)
+	if !ok {
+		return nil, fmt.Errorf("context does not have a key or is not Client: %s", clientContextKey)
+	}
+	return v, nil
+}
+
+func IsClient(ctx context.Context) bool {
+	return ctx.Value(clientContextKey) != nil
+}
+
+// RoleMembership is a role which has been assigned to an identity at runtime, in addition to the emails and domains
+// of the role in the schema. If Model and RecordId are set, then the role only applies to that record.
+type RoleMembership struct {
+	Role     string
+	Model    string
+	RecordId string
+}
+
+// IsRecordScoped determines if the role only applies to a single record.
+func (m *RoleMembership) IsRecordScoped() bool {
+	return m.Model != ""
+}
+
+func WithRoleMemberships(ctx context.Context, memberships []*RoleMembership) context.Context {
+	return context.WithValue(ctx, rolesContextKey, memberships)
+}
+
+// GetRoleMemberships returns the roles assigned to the authenticated identity, if any.
+func GetRoleMemberships(ctx context.Context) []*RoleMembership {
+	v, _ := ctx.Value(rolesContextKey).([]*RoleMembership)
+	return v
+}
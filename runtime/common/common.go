@@ -227,6 +227,20 @@ func NewPermissionError() RuntimeError {
 	}
 }
 
+func NewFieldPermissionError(field string) RuntimeError {
+	return RuntimeError{
+		Code:    ErrPermissionDenied,
+		Message: fmt.Sprintf("not authorized to write to field '%s'", field),
+	}
+}
+
+func NewFieldFilterPermissionError(field string) RuntimeError {
+	return RuntimeError{
+		Code:    ErrPermissionDenied,
+		Message: fmt.Sprintf("not authorized to filter or order by field '%s'", field),
+	}
+}
+
 func NewAuthenticationFailedErr() RuntimeError {
 	return RuntimeError{
 		Code:    ErrAuthenticationFailed,
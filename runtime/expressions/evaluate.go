@@ -3,9 +3,11 @@ package expressions
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/segmentio/ksuid"
 	"github.com/teamkeel/keel/proto"
+	"github.com/teamkeel/keel/schema/expressions"
 	"github.com/teamkeel/keel/schema/parser"
 )
 
@@ -139,6 +141,16 @@ func compareString(
 		return lhs == rhs, nil
 	case parser.OperatorNotEquals:
 		return lhs != rhs, nil
+	case parser.OperatorEqualsIgnoreCase:
+		return strings.EqualFold(lhs, rhs), nil
+	case parser.OperatorStartsWithIgnoreCase:
+		return strings.HasPrefix(strings.ToLower(lhs), strings.ToLower(rhs)), nil
+	case parser.OperatorEndsWithIgnoreCase:
+		return strings.HasSuffix(strings.ToLower(lhs), strings.ToLower(rhs)), nil
+	case parser.OperatorContainsIgnoreCase:
+		return strings.Contains(strings.ToLower(lhs), strings.ToLower(rhs)), nil
+	case parser.OperatorMatches:
+		return expressions.MatchPattern(rhs, lhs)
 	default:
 		return false, fmt.Errorf("operator: %s, not supported for type: %s", operator.Symbol, proto.Type_TYPE_STRING)
 	}
@@ -325,6 +325,8 @@ func (resolver *OperandResolver) GetOperandType() (proto.Type, bool, error) {
 		return proto.Type_TYPE_STRING, false, nil
 	case resolver.operand.Ident.IsContextHeadersField():
 		return proto.Type_TYPE_STRING, false, nil
+	case resolver.operand.Ident.IsContextClientField():
+		return proto.Type_TYPE_STRING, false, nil
 	case operand.Ident.IsContext():
 		fieldName := operand.Ident.Fragments[1].Fragment
 		return runtimectx.ContextFieldTypes[fieldName], false, nil
@@ -392,6 +394,8 @@ func (resolver *OperandResolver) ResolveValue(args map[string]any) (any, error)
 	case resolver.operand.Ident.IsContextIsAuthenticatedField():
 		isAuthenticated := auth.IsAuthenticated(resolver.Context)
 		return isAuthenticated, nil
+	case resolver.operand.Ident.IsContextIsMfaAuthenticatedField():
+		return auth.IsMfaAuthenticated(resolver.Context), nil
 	case resolver.operand.Ident.IsContextNowField():
 		return runtimectx.GetNow(), nil
 	case resolver.operand.Ident.IsContextEnvField():
@@ -400,6 +404,24 @@ func (resolver *OperandResolver) ResolveValue(args map[string]any) (any, error)
 	case resolver.operand.Ident.IsContextSecretField():
 		secret := resolver.operand.Ident.Fragments[2].Fragment
 		return runtimectx.GetSecret(resolver.Context, secret)
+	case resolver.operand.Ident.IsContextClientField():
+		if !auth.IsClient(resolver.Context) {
+			return nil, nil
+		}
+
+		client, err := auth.GetClient(resolver.Context)
+		if err != nil {
+			return nil, err
+		}
+
+		switch resolver.operand.Ident.Fragments[2].Fragment {
+		case "id":
+			return client.Id, nil
+		case "name":
+			return client.Name, nil
+		default:
+			return nil, fmt.Errorf("unknown client field '%s'", resolver.operand.Ident.Fragments[2].Fragment)
+		}
 	case resolver.operand.Ident.IsContextHeadersField():
 		headerName := resolver.operand.Ident.Fragments[2].Fragment
 
@@ -34,6 +34,12 @@ var (
 			"hasNextPage": {
 				Type: "boolean",
 			},
+			"pageNumber": {
+				Type: "number",
+			},
+			"totalPages": {
+				Type: "number",
+			},
 		},
 	}
 )
@@ -65,6 +71,7 @@ type JSONSchema struct {
 	OneOf                 []JSONSchema          `json:"oneOf,omitempty"`
 	AnyOf                 []JSONSchema          `json:"anyOf,omitempty"`
 	Title                 string                `json:"title,omitempty"`
+	Description           string                `json:"description,omitempty"`
 	Default               string                `json:"default,omitempty"`
 
 	// For arrays
@@ -304,7 +311,7 @@ func jsonSchemaForModel(ctx context.Context, schema *proto.Schema, model *proto.
 			continue
 		}
 
-		fieldSchema := jsonSchemaForField(ctx, schema, nil, field.Type, field.Optional, []string{}, false)
+		fieldSchema := jsonSchemaForModelField(ctx, schema, field, []string{})
 
 		definitionSchema.Properties[field.Name] = fieldSchema
 
@@ -375,7 +382,7 @@ func objectSchemaForModel(ctx context.Context, schema *proto.Schema, model *prot
 			}
 		}
 
-		fieldSchema := jsonSchemaForField(ctx, schema, nil, field.Type, field.Optional, fieldEmbeddings, false)
+		fieldSchema := jsonSchemaForModelField(ctx, schema, field, fieldEmbeddings)
 		// If that nested field component has ref fields itself, then its components must be bundled.
 		if fieldSchema.Components != nil {
 			for cName, comp := range fieldSchema.Components.Schemas {
@@ -404,6 +411,17 @@ func objectSchemaForModel(ctx context.Context, schema *proto.Schema, model *prot
 	return s
 }
 
+// jsonSchemaForModelField generates the schema of a model field in a response. A field with read permission
+// rules is null when the identity is not permitted to read it, even if the field is not optional.
+func jsonSchemaForModelField(ctx context.Context, schema *proto.Schema, field *proto.Field, embeddings []string) JSONSchema {
+	isNullable := field.Optional || len(field.ReadPermissions()) > 0
+
+	fieldSchema := jsonSchemaForField(ctx, schema, nil, field.Type, isNullable, embeddings, false)
+	fieldSchema.Description = field.PermissionsDescription()
+
+	return fieldSchema
+}
+
 func jsonSchemaForField(ctx context.Context, schema *proto.Schema, action *proto.Action, t *proto.TypeInfo, isNullableField bool, embeddings []string, isInput bool) JSONSchema {
 	components := &Components{
 		Schemas: map[string]JSONSchema{},
@@ -418,9 +436,22 @@ func jsonSchemaForField(ctx context.Context, schema *proto.Schema, action *proto
 		}
 		prop.AnyOf = anyOf
 	case proto.Type_TYPE_MESSAGE:
+		// A self-referencing message (such as a nested filter group) is already
+		// being added to the schema components further up, so we only reference it.
+		if isGeneratingMessage(ctx, t.MessageName.Value) {
+			ref := JSONSchema{Ref: fmt.Sprintf("#/components/schemas/%s", t.MessageName.Value)}
+			if t.Repeated {
+				prop.Type = "array"
+				prop.Items = &ref
+			} else {
+				prop = ref
+			}
+			break
+		}
+
 		// Add the nested message to schema components.
 		message := schema.FindMessage(t.MessageName.Value)
-		component := JSONSchemaForMessage(ctx, schema, action, message, isInput)
+		component := JSONSchemaForMessage(withGeneratingMessage(ctx, message.Name), schema, action, message, isInput)
 
 		// If that nested message component has ref fields itself, then its components must be bundled.
 		if component.Components != nil {
@@ -586,6 +617,20 @@ func jsonSchemaForField(ctx context.Context, schema *proto.Schema, action *proto
 	return prop
 }
 
+type generatingMessagesKey struct{}
+
+// withGeneratingMessage marks that the components for the given message are being generated.
+func withGeneratingMessage(ctx context.Context, messageName string) context.Context {
+	names, _ := ctx.Value(generatingMessagesKey{}).([]string)
+	return context.WithValue(ctx, generatingMessagesKey{}, append(names[:len(names):len(names)], messageName))
+}
+
+// isGeneratingMessage determines if the components for the given message are being generated further up.
+func isGeneratingMessage(ctx context.Context, messageName string) bool {
+	names, _ := ctx.Value(generatingMessagesKey{}).([]string)
+	return lo.Contains(names, messageName)
+}
+
 // allowNull makes sure that it allows null, either by modifying
 // the type field or the enum field
 //
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -16,8 +19,36 @@ const (
 	KeelIssuer                          = "https://keel.so"
 	resetPasswordAudClaim               = "password-reset"
 	ResetTokenExpiry      time.Duration = time.Minute * 15
+	mfaAudClaim                         = "mfa"
+	MfaTokenExpiry        time.Duration = time.Minute * 5
+	verifyEmailAudClaim                 = "email-verification"
+	VerifyTokenExpiry     time.Duration = time.Hour * 24
+	streamTicketAudClaim                = "stream-ticket"
+	StreamTicketExpiry    time.Duration = time.Second * 30
 )
 
+// Authentication method reference values.
+// https://datatracker.ietf.org/doc/html/rfc8176#section-2
+const (
+	AuthMethodMfa = "mfa"
+	AuthMethodOtp = "otp"
+)
+
+// TokenIssuer is the issuer of the tokens issued by Keel. This is the URL of the API when KEEL_API_URL is set, so
+// that it matches the issuer published in the OpenID Connect discovery document, and otherwise KeelIssuer.
+func TokenIssuer() string {
+	if apiUrl, err := url.ParseRequestURI(os.Getenv("KEEL_API_URL")); err == nil && apiUrl.Host != "" {
+		return strings.TrimSuffix(apiUrl.String(), "/")
+	}
+	return KeelIssuer
+}
+
+// isTokenIssuer determines if Keel issued a token with the given issuer. Tokens issued
+// before the issuer was derived from the API's URL have KeelIssuer as their issuer.
+func isTokenIssuer(issuer string) bool {
+	return issuer == TokenIssuer() || issuer == KeelIssuer
+}
+
 var (
 	ErrInvalidToken     = common.NewAuthenticationFailedMessageErr("cannot be parsed or verified as a valid JWT")
 	ErrTokenExpired     = common.NewAuthenticationFailedMessageErr("token has expired")
@@ -27,9 +58,48 @@ var (
 // https://pkg.go.dev/github.com/golang-jwt/jwt/v4#RegisteredClaims
 type AccessTokenClaims struct {
 	jwt.RegisteredClaims
+	// The service client which the token was issued to using the client credentials grant.
+	// https://datatracker.ietf.org/doc/html/rfc9068#section-2.2
+	ClientId string `json:"client_id,omitempty"`
+	// Space-delimited roles which the service client has been granted.
+	Scope string `json:"scope,omitempty"`
+	// The methods which the identity used to authenticate, which includes 'mfa' if a second factor was used.
+	// https://datatracker.ietf.org/doc/html/rfc8176
+	AuthMethods []string `json:"amr,omitempty"`
+	// The email address which an email verification token was issued for.
+	Email string `json:"email,omitempty"`
+	// The identity which is acting as the subject, when the token was issued using the impersonation grant.
+	// https://datatracker.ietf.org/doc/html/rfc8693#section-4.1
+	Actor *ActorClaim `json:"act,omitempty"`
+	// When a stream opened with a stream ticket ends, which is when the access token it was issued for expires.
+	StreamExpiresAt *jwt.NumericDate `json:"stream_exp,omitempty"`
+}
+
+// ActorClaim identifies the party which is acting on behalf of the subject of a token.
+type ActorClaim struct {
+	Subject string `json:"sub"`
 }
 
 func GenerateAccessToken(ctx context.Context, identityId string) (string, time.Duration, error) {
+	return generateAccessToken(ctx, identityId, nil, nil)
+}
+
+// GenerateMfaAccessToken generates an access token for an identity which has authenticated with a second factor.
+func GenerateMfaAccessToken(ctx context.Context, identityId string) (string, time.Duration, error) {
+	return generateAccessToken(ctx, identityId, []string{AuthMethodOtp, AuthMethodMfa}, nil)
+}
+
+// GenerateImpersonationAccessToken generates an access token for an identity which is being impersonated,
+// with the impersonator's identity in the act claim.
+func GenerateImpersonationAccessToken(ctx context.Context, identityId string, impersonatorId string) (string, time.Duration, error) {
+	if impersonatorId == "" {
+		return "", 0, errors.New("cannot generate impersonation access token with an empty impersonatorId intended for the act claim")
+	}
+
+	return generateAccessToken(ctx, identityId, nil, &ActorClaim{Subject: impersonatorId})
+}
+
+func generateAccessToken(ctx context.Context, identityId string, authMethods []string, actor *ActorClaim) (string, time.Duration, error) {
 	if identityId == "" {
 		return "", 0, errors.New("cannot generate access token with an empty identityId intended for the sub claim")
 	}
@@ -41,7 +111,20 @@ func GenerateAccessToken(ctx context.Context, identityId string) (string, time.D
 
 	expiry := config.AccessTokenExpiry()
 
-	token, err := generateToken(ctx, identityId, []string{}, expiry)
+	now := time.Now().UTC()
+	claims := AccessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   identityId,
+			Audience:  []string{},
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    TokenIssuer(),
+		},
+		AuthMethods: authMethods,
+		Actor:       actor,
+	}
+
+	token, err := signToken(ctx, claims)
 	if err != nil {
 		return "", 0, err
 	}
@@ -50,7 +133,186 @@ func GenerateAccessToken(ctx context.Context, identityId string) (string, time.D
 }
 
 func ValidateAccessToken(ctx context.Context, tokenString string) (string, error) {
-	return validateToken(ctx, tokenString, "")
+	claims, err := validateToken(ctx, tokenString, "")
+	if err != nil {
+		return "", err
+	}
+
+	// Service client tokens cannot be used to authenticate as an identity
+	if claims.ClientId != "" {
+		return "", ErrInvalidToken
+	}
+
+	// MFA tokens only prove the first factor and cannot be used to authenticate
+	if lo.Contains(claims.Audience, mfaAudClaim) {
+		return "", ErrInvalidToken
+	}
+
+	// Stream tickets can only be used to open a stream
+	if lo.Contains(claims.Audience, streamTicketAudClaim) {
+		return "", ErrInvalidToken
+	}
+
+	return claims.Subject, nil
+}
+
+// GenerateClientAccessToken generates an access token for a service client which is scoped to the provided roles.
+func GenerateClientAccessToken(ctx context.Context, clientId string, roles []string) (string, time.Duration, error) {
+	if clientId == "" {
+		return "", 0, errors.New("cannot generate access token with an empty clientId")
+	}
+
+	config, err := runtimectx.GetOAuthConfig(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+
+	expiry := config.AccessTokenExpiry()
+
+	now := time.Now().UTC()
+	claims := AccessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   clientId,
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    TokenIssuer(),
+		},
+		ClientId: clientId,
+		Scope:    strings.Join(roles, " "),
+	}
+
+	token, err := signToken(ctx, claims)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return token, expiry, nil
+}
+
+// ValidateClientAccessToken validates an access token issued to a service client
+// and returns the client id and the roles which the token is scoped to.
+func ValidateClientAccessToken(ctx context.Context, tokenString string) (string, []string, error) {
+	claims, err := validateToken(ctx, tokenString, "")
+	if err != nil {
+		return "", nil, err
+	}
+
+	if claims.ClientId == "" || claims.ClientId != claims.Subject {
+		return "", nil, ErrInvalidToken
+	}
+
+	return claims.ClientId, strings.Fields(claims.Scope), nil
+}
+
+// IsClientAccessToken determines if the token was issued to a service client without verifying it.
+func IsClientAccessToken(tokenString string) bool {
+	claims := &AccessTokenClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(tokenString, claims)
+	if err != nil {
+		return false
+	}
+
+	return claims.ClientId != ""
+}
+
+// IsMfaAccessToken determines if the identity used a second factor to authenticate, without verifying the token.
+func IsMfaAccessToken(tokenString string) bool {
+	claims := &AccessTokenClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(tokenString, claims)
+	if err != nil {
+		return false
+	}
+
+	return lo.Contains(claims.AuthMethods, AuthMethodMfa)
+}
+
+// ImpersonatorFromAccessToken returns the identity which is impersonating the subject of the token,
+// or an empty string if the token was not issued using the impersonation grant, without verifying the token.
+func ImpersonatorFromAccessToken(tokenString string) string {
+	claims := &AccessTokenClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(tokenString, claims)
+	if err != nil || claims.Actor == nil {
+		return ""
+	}
+
+	return claims.Actor.Subject
+}
+
+// ExpiryFromAccessToken returns when the token expires, without verifying the token.
+func ExpiryFromAccessToken(tokenString string) (time.Time, bool) {
+	claims := &AccessTokenClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(tokenString, claims)
+	if err != nil || claims.ExpiresAt == nil {
+		return time.Time{}, false
+	}
+
+	return claims.ExpiresAt.Time, true
+}
+
+// GenerateStreamTicket generates a short-lived ticket in exchange for an identity's access token, which opens a stream
+// of changes. Unlike the access token, the ticket can be put in the URL of an EventSource, which cannot set headers,
+// as it expires quickly and cannot be used to authenticate any other request. The ticket keeps the authentication
+// methods and any impersonator of the access token, and the stream it opens ends when the access token expires.
+func GenerateStreamTicket(ctx context.Context, accessToken string) (string, error) {
+	identityId, err := ValidateAccessToken(ctx, accessToken)
+	if err != nil {
+		return "", err
+	}
+
+	accessClaims := &AccessTokenClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(accessToken, accessClaims)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+
+	now := time.Now().UTC()
+	claims := AccessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   identityId,
+			Audience:  []string{streamTicketAudClaim},
+			ExpiresAt: jwt.NewNumericDate(now.Add(StreamTicketExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    TokenIssuer(),
+		},
+		AuthMethods:     accessClaims.AuthMethods,
+		Actor:           accessClaims.Actor,
+		StreamExpiresAt: accessClaims.ExpiresAt,
+	}
+
+	return signToken(ctx, claims)
+}
+
+// ValidateStreamTicket returns the identity which the stream ticket was issued for, and when the stream it opens ends.
+func ValidateStreamTicket(ctx context.Context, ticket string) (string, time.Time, error) {
+	claims, err := validateToken(ctx, ticket, streamTicketAudClaim)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if claims.StreamExpiresAt == nil {
+		return "", time.Time{}, ErrInvalidToken
+	}
+
+	return claims.Subject, claims.StreamExpiresAt.Time, nil
+}
+
+// GenerateMfaToken generates a short-lived token which proves that the identity has authenticated with
+// their first factor, and which is exchanged along with a code from their authenticator to complete authentication.
+func GenerateMfaToken(ctx context.Context, identityId string) (string, error) {
+	if identityId == "" {
+		return "", errors.New("cannot generate mfa token with an empty identityId intended for the sub claim")
+	}
+
+	return generateToken(ctx, identityId, []string{mfaAudClaim}, MfaTokenExpiry)
+}
+
+func ValidateMfaToken(ctx context.Context, tokenString string) (string, error) {
+	claims, err := validateToken(ctx, tokenString, mfaAudClaim)
+	if err != nil {
+		return "", err
+	}
+
+	return claims.Subject, nil
 }
 
 func GenerateResetToken(ctx context.Context, identityId string) (string, error) {
@@ -62,90 +324,115 @@ func GenerateResetToken(ctx context.Context, identityId string) (string, error)
 }
 
 func ValidateResetToken(ctx context.Context, tokenString string) (string, error) {
-	subject, err := validateToken(ctx, tokenString, resetPasswordAudClaim)
+	claims, err := validateToken(ctx, tokenString, resetPasswordAudClaim)
+	if err != nil {
+		return "", err
+	}
 
-	return subject, err
+	return claims.Subject, nil
 }
 
-func generateToken(ctx context.Context, sub string, aud []string, expiresIn time.Duration) (string, error) {
+// GenerateVerifyEmailToken generates a token which verifies the identity's email address. The token is
+// bound to the email address so that it cannot be used to verify a different address after it has been changed.
+func GenerateVerifyEmailToken(ctx context.Context, identityId string, email string) (string, error) {
+	if identityId == "" {
+		return "", errors.New("cannot generate email verification token with an empty identityId intended for the sub claim")
+	}
+
+	if email == "" {
+		return "", errors.New("cannot generate email verification token with an empty email")
+	}
+
 	now := time.Now().UTC()
 	claims := AccessTokenClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
-			Subject:   sub,
-			Audience:  aud,
-			ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+			Subject:   identityId,
+			Audience:  []string{verifyEmailAudClaim},
+			ExpiresAt: jwt.NewNumericDate(now.Add(VerifyTokenExpiry)),
 			IssuedAt:  jwt.NewNumericDate(now),
-			Issuer:    KeelIssuer,
+			Issuer:    TokenIssuer(),
 		},
+		Email: email,
 	}
 
-	privateKey, err := runtimectx.GetPrivateKey(ctx)
+	return signToken(ctx, claims)
+}
+
+// ValidateVerifyEmailToken returns the identity and the email address which the token was issued for.
+func ValidateVerifyEmailToken(ctx context.Context, tokenString string) (string, string, error) {
+	claims, err := validateToken(ctx, tokenString, verifyEmailAudClaim)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
-	if privateKey == nil {
-		return "", fmt.Errorf("no private key set")
+	if claims.Email == "" {
+		return "", "", ErrInvalidToken
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	tokenString, err := token.SignedString(privateKey)
+	return claims.Subject, claims.Email, nil
+}
+
+func generateToken(ctx context.Context, sub string, aud []string, expiresIn time.Duration) (string, error) {
+	now := time.Now().UTC()
+	claims := AccessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   sub,
+			Audience:  aud,
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    TokenIssuer(),
+		},
+	}
+
+	return signToken(ctx, claims)
+}
+
+func signToken(ctx context.Context, claims AccessTokenClaims) (string, error) {
+	tokenString, err := signJwt(ctx, claims)
 	if err != nil {
 		return "", fmt.Errorf("cannot create signed jwt: %w", err)
 	}
 	return tokenString, nil
 }
 
-func validateToken(ctx context.Context, tokenString string, audienceClaim string) (string, error) {
+func validateToken(ctx context.Context, tokenString string, audienceClaim string) (*AccessTokenClaims, error) {
 	ctx, span := tracer.Start(ctx, "Validate access token")
 	defer span.End()
 
-	privateKey, err := runtimectx.GetPrivateKey(ctx)
-	if err != nil {
-		return "", err
-	}
-
-	if privateKey == nil {
-		return "", errors.New("no private key set")
-	}
-
-	var token *jwt.Token
 	claims := &AccessTokenClaims{}
 
-	token, err = jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
-		return &privateKey.PublicKey, nil
-	})
+	token, err := parseJwt(ctx, tokenString, claims)
 
 	var validationErr *jwt.ValidationError
 	if errors.As(err, &validationErr) && validationErr.Errors == jwt.ValidationErrorExpired {
-		return "", ErrTokenExpired
+		return nil, ErrTokenExpired
 	}
 
 	if err != nil {
-		return "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 
 	if !claims.VerifyExpiresAt(time.Now().UTC(), true) {
-		return "", ErrTokenExpired
+		return nil, ErrTokenExpired
 	}
 
 	if audienceClaim != "" {
 		if !lo.Contains(claims.Audience, audienceClaim) {
-			return "", ErrInvalidToken
+			return nil, ErrInvalidToken
 		}
 	}
 
 	if !token.Valid {
-		return "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 
 	if claims.Subject == "" {
-		return "", errors.New("subject claim cannot be empty")
+		return nil, errors.New("subject claim cannot be empty")
 	}
 
-	if claims.Issuer != KeelIssuer {
-		return "", errors.New("invalid issuer")
+	if !isTokenIssuer(claims.Issuer) {
+		return nil, errors.New("invalid issuer")
 	}
 
-	return claims.Subject, nil
+	return claims, nil
 }
@@ -357,3 +357,308 @@ func TestResetTokenMissingAudIsInvalid(t *testing.T) {
 	require.ErrorIs(t, oauth.ErrInvalidToken, err)
 	require.Empty(t, parsedId)
 }
+
+func TestVerifyEmailTokenGenerationAndParsing(t *testing.T) {
+	ctx := newContextWithPK()
+	identityId := ksuid.New()
+
+	token, err := oauth.GenerateVerifyEmailToken(ctx, identityId.String(), "user@keel.xyz")
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	parsedId, email, err := oauth.ValidateVerifyEmailToken(ctx, token)
+	require.NoError(t, err)
+	require.Equal(t, identityId.String(), parsedId)
+	require.Equal(t, "user@keel.xyz", email)
+}
+
+func TestVerifyEmailTokenWithoutEmailCannotBeGenerated(t *testing.T) {
+	ctx := newContextWithPK()
+	identityId := ksuid.New()
+
+	token, err := oauth.GenerateVerifyEmailToken(ctx, identityId.String(), "")
+	require.Error(t, err)
+	require.Empty(t, token)
+}
+
+func TestResetTokenIsInvalidAsVerifyEmailToken(t *testing.T) {
+	ctx := newContextWithPK()
+	identityId := ksuid.New()
+
+	token, err := oauth.GenerateResetToken(ctx, identityId.String())
+	require.NoError(t, err)
+
+	parsedId, email, err := oauth.ValidateVerifyEmailToken(ctx, token)
+	require.ErrorIs(t, oauth.ErrInvalidToken, err)
+	require.Empty(t, parsedId)
+	require.Empty(t, email)
+}
+
+func TestVerifyEmailTokenIsInvalidAsResetToken(t *testing.T) {
+	ctx := newContextWithPK()
+	identityId := ksuid.New()
+
+	token, err := oauth.GenerateVerifyEmailToken(ctx, identityId.String(), "user@keel.xyz")
+	require.NoError(t, err)
+
+	parsedId, err := oauth.ValidateResetToken(ctx, token)
+	require.ErrorIs(t, oauth.ErrInvalidToken, err)
+	require.Empty(t, parsedId)
+}
+
+func TestExpiredVerifyEmailTokenIsInvalid(t *testing.T) {
+	ctx := newContextWithPK()
+	identityId := ksuid.New()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ctx = runtimectx.WithPrivateKey(ctx, privateKey)
+
+	// Create the jwt 1 second expired.
+	now := time.Now().UTC().Add(-oauth.VerifyTokenExpiry).Add(time.Second * -1)
+	claims := oauth.AccessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   identityId.String(),
+			Audience:  jwt.ClaimStrings{"email-verification"},
+			ExpiresAt: jwt.NewNumericDate(now.Add(oauth.VerifyTokenExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Email: "user@keel.xyz",
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tokenString, err := token.SignedString(privateKey)
+	require.NoError(t, err)
+
+	parsedId, email, err := oauth.ValidateVerifyEmailToken(ctx, tokenString)
+	require.ErrorIs(t, oauth.ErrTokenExpired, err)
+	require.Empty(t, parsedId)
+	require.Empty(t, email)
+}
+
+func TestClientAccessTokenGenerationAndParsing(t *testing.T) {
+	ctx := newContextWithPK()
+
+	bearerJwt, expiry, err := oauth.GenerateClientAccessToken(ctx, "client_id", []string{"Admin", "Staff"})
+	require.NoError(t, err)
+	require.NotEmpty(t, bearerJwt)
+	require.Equal(t, time.Hour*24, expiry)
+	require.True(t, oauth.IsClientAccessToken(bearerJwt))
+
+	clientId, roles, err := oauth.ValidateClientAccessToken(ctx, bearerJwt)
+	require.NoError(t, err)
+	require.Equal(t, "client_id", clientId)
+	require.Equal(t, []string{"Admin", "Staff"}, roles)
+}
+
+func TestClientAccessTokenClaims(t *testing.T) {
+	ctx := newContextWithPK()
+
+	bearerJwt, _, err := oauth.GenerateClientAccessToken(ctx, "client_id", []string{"Admin"})
+	require.NoError(t, err)
+
+	claims := &oauth.AccessTokenClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(bearerJwt, claims)
+	require.NoError(t, err)
+
+	require.Equal(t, "client_id", claims.Subject)
+	require.Equal(t, "client_id", claims.ClientId)
+	require.Equal(t, "Admin", claims.Scope)
+	require.Equal(t, oauth.KeelIssuer, claims.Issuer)
+}
+
+func TestAccessTokenIssuerIsApiUrl(t *testing.T) {
+	ctx := newContextWithPK()
+
+	// Tokens issued before the issuer was derived from the API's URL
+	legacyJwt, _, err := oauth.GenerateAccessToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	t.Setenv("KEEL_API_URL", "https://mykeelapp.keel.so/")
+	require.Equal(t, "https://mykeelapp.keel.so", oauth.TokenIssuer())
+
+	bearerJwt, _, err := oauth.GenerateAccessToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	claims := &oauth.AccessTokenClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(bearerJwt, claims)
+	require.NoError(t, err)
+	require.Equal(t, "https://mykeelapp.keel.so", claims.Issuer)
+
+	for _, token := range []string{bearerJwt, legacyJwt} {
+		identityId, err := oauth.ValidateAccessToken(ctx, token)
+		require.NoError(t, err)
+		require.Equal(t, "identity_id", identityId)
+	}
+}
+
+func TestClientAccessTokenCannotAuthenticateIdentity(t *testing.T) {
+	ctx := newContextWithPK()
+
+	bearerJwt, _, err := oauth.GenerateClientAccessToken(ctx, "client_id", []string{"Admin"})
+	require.NoError(t, err)
+
+	identityId, err := oauth.ValidateAccessToken(ctx, bearerJwt)
+	require.ErrorIs(t, err, oauth.ErrInvalidToken)
+	require.Empty(t, identityId)
+}
+
+func TestIdentityAccessTokenCannotAuthenticateClient(t *testing.T) {
+	ctx := newContextWithPK()
+
+	bearerJwt, _, err := oauth.GenerateAccessToken(ctx, ksuid.New().String())
+	require.NoError(t, err)
+	require.False(t, oauth.IsClientAccessToken(bearerJwt))
+
+	clientId, roles, err := oauth.ValidateClientAccessToken(ctx, bearerJwt)
+	require.ErrorIs(t, err, oauth.ErrInvalidToken)
+	require.Empty(t, clientId)
+	require.Empty(t, roles)
+}
+
+func TestMfaAccessTokenAuthMethods(t *testing.T) {
+	ctx := newContextWithPK()
+
+	bearerJwt, _, err := oauth.GenerateMfaAccessToken(ctx, "identity_id")
+	require.NoError(t, err)
+	require.True(t, oauth.IsMfaAccessToken(bearerJwt))
+
+	claims := &oauth.AccessTokenClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(bearerJwt, claims)
+	require.NoError(t, err)
+	require.Equal(t, []string{oauth.AuthMethodOtp, oauth.AuthMethodMfa}, claims.AuthMethods)
+
+	identityId, err := oauth.ValidateAccessToken(ctx, bearerJwt)
+	require.NoError(t, err)
+	require.Equal(t, "identity_id", identityId)
+}
+
+func TestAccessTokenWithoutMfa(t *testing.T) {
+	ctx := newContextWithPK()
+
+	bearerJwt, _, err := oauth.GenerateAccessToken(ctx, "identity_id")
+	require.NoError(t, err)
+	require.False(t, oauth.IsMfaAccessToken(bearerJwt))
+
+	claims := &oauth.AccessTokenClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(bearerJwt, claims)
+	require.NoError(t, err)
+	require.Empty(t, claims.AuthMethods)
+}
+
+func TestMfaTokenGenerationAndParsing(t *testing.T) {
+	ctx := newContextWithPK()
+
+	mfaToken, err := oauth.GenerateMfaToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	identityId, err := oauth.ValidateMfaToken(ctx, mfaToken)
+	require.NoError(t, err)
+	require.Equal(t, "identity_id", identityId)
+}
+
+func TestMfaTokenCannotAuthenticate(t *testing.T) {
+	ctx := newContextWithPK()
+
+	mfaToken, err := oauth.GenerateMfaToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	identityId, err := oauth.ValidateAccessToken(ctx, mfaToken)
+	require.ErrorIs(t, err, oauth.ErrInvalidToken)
+	require.Empty(t, identityId)
+}
+
+func TestAccessTokenIsNotMfaToken(t *testing.T) {
+	ctx := newContextWithPK()
+
+	bearerJwt, _, err := oauth.GenerateAccessToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	identityId, err := oauth.ValidateMfaToken(ctx, bearerJwt)
+	require.ErrorIs(t, err, oauth.ErrInvalidToken)
+	require.Empty(t, identityId)
+}
+
+func TestImpersonationAccessTokenActClaim(t *testing.T) {
+	ctx := newContextWithPK()
+
+	bearerJwt, _, err := oauth.GenerateImpersonationAccessToken(ctx, "identity_id", "impersonator_id")
+	require.NoError(t, err)
+	require.Equal(t, "impersonator_id", oauth.ImpersonatorFromAccessToken(bearerJwt))
+	require.False(t, oauth.IsMfaAccessToken(bearerJwt))
+
+	claims := &oauth.AccessTokenClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(bearerJwt, claims)
+	require.NoError(t, err)
+	require.Equal(t, "impersonator_id", claims.Actor.Subject)
+
+	identityId, err := oauth.ValidateAccessToken(ctx, bearerJwt)
+	require.NoError(t, err)
+	require.Equal(t, "identity_id", identityId)
+}
+
+func TestImpersonationAccessTokenWithoutImpersonator(t *testing.T) {
+	ctx := newContextWithPK()
+
+	_, _, err := oauth.GenerateImpersonationAccessToken(ctx, "identity_id", "")
+	require.Error(t, err)
+}
+
+func TestAccessTokenIsNotImpersonation(t *testing.T) {
+	ctx := newContextWithPK()
+
+	bearerJwt, _, err := oauth.GenerateAccessToken(ctx, "identity_id")
+	require.NoError(t, err)
+	require.Empty(t, oauth.ImpersonatorFromAccessToken(bearerJwt))
+}
+
+func TestStreamTicketGenerationAndParsing(t *testing.T) {
+	ctx := newContextWithPK()
+
+	bearerJwt, _, err := oauth.GenerateImpersonationAccessToken(ctx, "identity_id", "impersonator_id")
+	require.NoError(t, err)
+
+	accessTokenExpiry, ok := oauth.ExpiryFromAccessToken(bearerJwt)
+	require.True(t, ok)
+
+	ticket, err := oauth.GenerateStreamTicket(ctx, bearerJwt)
+	require.NoError(t, err)
+	require.Equal(t, "impersonator_id", oauth.ImpersonatorFromAccessToken(ticket))
+
+	ticketExpiry, ok := oauth.ExpiryFromAccessToken(ticket)
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(oauth.StreamTicketExpiry), ticketExpiry, 2*time.Second)
+
+	identityId, streamExpiry, err := oauth.ValidateStreamTicket(ctx, ticket)
+	require.NoError(t, err)
+	require.Equal(t, "identity_id", identityId)
+	require.Equal(t, accessTokenExpiry, streamExpiry)
+}
+
+func TestStreamTicketCannotAuthenticate(t *testing.T) {
+	ctx := newContextWithPK()
+
+	bearerJwt, _, err := oauth.GenerateAccessToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	ticket, err := oauth.GenerateStreamTicket(ctx, bearerJwt)
+	require.NoError(t, err)
+
+	identityId, err := oauth.ValidateAccessToken(ctx, ticket)
+	require.ErrorIs(t, err, oauth.ErrInvalidToken)
+	require.Empty(t, identityId)
+
+	_, err = oauth.GenerateStreamTicket(ctx, ticket)
+	require.ErrorIs(t, err, oauth.ErrInvalidToken)
+}
+
+func TestAccessTokenIsNotStreamTicket(t *testing.T) {
+	ctx := newContextWithPK()
+
+	bearerJwt, _, err := oauth.GenerateAccessToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	identityId, _, err := oauth.ValidateStreamTicket(ctx, bearerJwt)
+	require.ErrorIs(t, err, oauth.ErrInvalidToken)
+	require.Empty(t, identityId)
+}
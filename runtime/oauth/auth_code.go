@@ -15,9 +15,18 @@ const (
 	authCodeExpiry = time.Duration(60) * time.Second
 )
 
+// PendingLink is an external identity which is linked to the identity which exchanges the auth code,
+// rather than signing in, so that the link is only made with the consent of that identity.
+type PendingLink struct {
+	Issuer     string
+	ExternalId string
+	Email      string
+}
+
 // NewAuthCode generates a new auth code for the identity using the
-// configured or default expiry time.
-func NewAuthCode(ctx context.Context, identityId string) (string, error) {
+// configured or default expiry time. If the client provided a PKCE code challenge
+// when starting the authorization flow, then it is stored alongside the auth code.
+func NewAuthCode(ctx context.Context, identityId string, challenge *CodeChallenge) (string, error) {
 	ctx, span := tracer.Start(ctx, "New Auth Code")
 	defer span.End()
 
@@ -25,6 +34,27 @@ func NewAuthCode(ctx context.Context, identityId string) (string, error) {
 		return "", errors.New("identity ID cannot be empty when generating new auth code")
 	}
 
+	return newAuthCode(ctx, &identityId, nil, challenge)
+}
+
+// NewLinkAuthCode generates a new auth code which links the external identity to the identity which exchanges it.
+// A PKCE code challenge is required so that only the client which started the authorization flow can exchange it.
+func NewLinkAuthCode(ctx context.Context, link *PendingLink, challenge *CodeChallenge) (string, error) {
+	ctx, span := tracer.Start(ctx, "New Link Auth Code")
+	defer span.End()
+
+	if link == nil || link.Issuer == "" || link.ExternalId == "" {
+		return "", errors.New("external identity cannot be empty when generating new link auth code")
+	}
+
+	if challenge == nil {
+		return "", errors.New("code challenge is required when generating new link auth code")
+	}
+
+	return newAuthCode(ctx, nil, link, challenge)
+}
+
+func newAuthCode(ctx context.Context, identityId *string, link *PendingLink, challenge *CodeChallenge) (string, error) {
 	code := uniuri.NewLen(authCodeLength)
 	hash, err := hashToken(code)
 	if err != nil {
@@ -39,13 +69,28 @@ func NewAuthCode(ctx context.Context, identityId string) (string, error) {
 	now := time.Now().UTC()
 	expiresAt := now.Add(authCodeExpiry)
 
+	var codeChallenge, codeChallengeMethod *string
+	if challenge != nil {
+		codeChallenge = &challenge.Challenge
+		codeChallengeMethod = &challenge.Method
+	}
+
+	var linkIssuer, linkExternalId, linkEmail *string
+	if link != nil {
+		linkIssuer = &link.Issuer
+		linkExternalId = &link.ExternalId
+		if link.Email != "" {
+			linkEmail = &link.Email
+		}
+	}
+
 	sql := `
 		INSERT INTO 
-			keel_auth_code (code, identity_id, expires_at, created_at) 
+			keel_auth_code (code, identity_id, expires_at, created_at, code_challenge, code_challenge_method, link_issuer, link_external_id, link_email) 
 		VALUES 
-			(?, ?, ?, ?)`
+			(?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	db := database.GetDB().Exec(sql, hash, identityId, expiresAt, now)
+	db := database.GetDB().Exec(sql, hash, identityId, expiresAt, now, codeChallenge, codeChallengeMethod, linkIssuer, linkExternalId, linkEmail)
 	if db.Error != nil {
 		return "", db.Error
 	}
@@ -59,18 +104,54 @@ func NewAuthCode(ctx context.Context, identityId string) (string, error) {
 
 // ConsumeAuthCode checks that the provided auth code has not expired,
 // consumes it (making it unusable again), and returning the identity it is associated with.
-func ConsumeAuthCode(ctx context.Context, code string) (isValid bool, identityId string, err error) {
+// If the auth code was issued with a PKCE code challenge, then the code verifier must match it.
+// Auth codes which link an external identity cannot be consumed with ConsumeAuthCode.
+func ConsumeAuthCode(ctx context.Context, code string, codeVerifier string) (isValid bool, identityId string, err error) {
 	ctx, span := tracer.Start(ctx, "Consume Auth Code")
 	defer span.End()
 
+	row, err := consumeAuthCode(ctx, code, codeVerifier, false)
+	if err != nil || row == nil {
+		return false, "", err
+	}
+
+	identityId, ok := row["identity_id"].(string)
+	if !ok {
+		return false, "", errors.New("could not parse identity_id from database result")
+	}
+
+	return true, identityId, nil
+}
+
+// ConsumeLinkAuthCode checks and consumes an auth code issued by NewLinkAuthCode in the same way as ConsumeAuthCode,
+// and returns the external identity which is to be linked to the identity exchanging it.
+func ConsumeLinkAuthCode(ctx context.Context, code string, codeVerifier string) (isValid bool, link *PendingLink, err error) {
+	ctx, span := tracer.Start(ctx, "Consume Link Auth Code")
+	defer span.End()
+
+	row, err := consumeAuthCode(ctx, code, codeVerifier, true)
+	if err != nil || row == nil {
+		return false, nil, err
+	}
+
+	link = &PendingLink{}
+	link.Issuer, _ = row["link_issuer"].(string)
+	link.ExternalId, _ = row["link_external_id"].(string)
+	link.Email, _ = row["link_email"].(string)
+
+	return true, link, nil
+}
+
+// consumeAuthCode consumes the auth code and returns its row, or nil if the auth code is not valid.
+func consumeAuthCode(ctx context.Context, code string, codeVerifier string, linking bool) (map[string]any, error) {
 	codeHash, err := hashToken(code)
 	if err != nil {
-		return false, "", err
+		return nil, err
 	}
 
 	database, err := db.GetDatabase(ctx)
 	if err != nil {
-		return false, "", err
+		return nil, err
 	}
 
 	sql := `
@@ -78,25 +159,38 @@ func ConsumeAuthCode(ctx context.Context, code string) (isValid bool, identityId
 			keel_auth_code
 		WHERE 
 			code = ? AND
-			expires_at >= now()
+			expires_at >= now() AND
+			(link_issuer IS NOT NULL) = ?
 		RETURNING 
-			code, identity_id, expires_at, now()`
+			code, identity_id, expires_at, now(), code_challenge, code_challenge_method, link_issuer, link_external_id, link_email`
 
 	rows := []map[string]any{}
-	err = database.GetDB().Raw(sql, codeHash).Scan(&rows).Error
+	err = database.GetDB().Raw(sql, codeHash, linking).Scan(&rows).Error
 	if err != nil {
-		return false, "", err
+		return nil, err
 	}
 
 	// There was no auth code found, and thus it is not valid
 	if len(rows) != 1 {
-		return false, "", nil
+		return nil, nil
 	}
 
-	identityId, ok := rows[0]["identity_id"].(string)
-	if !ok {
-		return false, "", errors.New("could not parse identity_id from database result")
+	codeChallenge, hasCodeChallenge := rows[0]["code_challenge"].(string)
+	if hasCodeChallenge {
+		challenge := &CodeChallenge{
+			Challenge: codeChallenge,
+			Method:    rows[0]["code_challenge_method"].(string),
+		}
+
+		// The auth code has still been consumed, so it cannot be retried with another verifier
+		if !challenge.Verify(codeVerifier) {
+			return nil, nil
+		}
+	} else if codeVerifier != "" {
+		// A code verifier for an auth code issued without a code challenge is rejected
+		// https://datatracker.ietf.org/doc/html/draft-ietf-oauth-v2-1#section-4.1.3
+		return nil, nil
 	}
 
-	return true, identityId, nil
+	return rows[0], nil
 }
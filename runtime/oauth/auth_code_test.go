@@ -13,7 +13,7 @@ func TestNewAuthCode_NotEmpty(t *testing.T) {
 	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
 	defer database.Close()
 
-	code, err := oauth.NewAuthCode(ctx, "identity_id")
+	code, err := oauth.NewAuthCode(ctx, "identity_id", nil)
 	require.NoError(t, err)
 	require.Len(t, code, 32)
 }
@@ -21,7 +21,7 @@ func TestNewAuthCode_NotEmpty(t *testing.T) {
 func TestNewAuthCode_ErrorOnEmptyIdentityId(t *testing.T) {
 	ctx := context.Background()
 
-	_, err := oauth.NewAuthCode(ctx, "")
+	_, err := oauth.NewAuthCode(ctx, "", nil)
 	require.Error(t, err)
 }
 
@@ -29,10 +29,10 @@ func TestConsumeAuthCode_Success(t *testing.T) {
 	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
 	defer database.Close()
 
-	code, err := oauth.NewAuthCode(ctx, "identity_id")
+	code, err := oauth.NewAuthCode(ctx, "identity_id", nil)
 	require.NoError(t, err)
 
-	isValid, identityId, err := oauth.ConsumeAuthCode(ctx, code)
+	isValid, identityId, err := oauth.ConsumeAuthCode(ctx, code, "")
 	require.NoError(t, err)
 	require.True(t, isValid)
 	require.Equal(t, "identity_id", identityId)
@@ -42,7 +42,7 @@ func TestConsumeAuthCode_DoesNotExist(t *testing.T) {
 	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
 	defer database.Close()
 
-	isValid, identityId, err := oauth.ConsumeAuthCode(ctx, "notexists")
+	isValid, identityId, err := oauth.ConsumeAuthCode(ctx, "notexists", "")
 	require.NoError(t, err)
 	require.False(t, isValid)
 	require.Empty(t, identityId)
@@ -52,15 +52,81 @@ func TestConsumeAuthCode_AlreadyConsumed(t *testing.T) {
 	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
 	defer database.Close()
 
-	code, err := oauth.NewAuthCode(ctx, "identity_id")
+	code, err := oauth.NewAuthCode(ctx, "identity_id", nil)
 	require.NoError(t, err)
 
-	isValid, identityId, err := oauth.ConsumeAuthCode(ctx, code)
+	isValid, identityId, err := oauth.ConsumeAuthCode(ctx, code, "")
 	require.NoError(t, err)
 	require.True(t, isValid)
 	require.Equal(t, "identity_id", identityId)
 
-	isValid, identityId, err = oauth.ConsumeAuthCode(ctx, code)
+	isValid, identityId, err = oauth.ConsumeAuthCode(ctx, code, "")
+	require.NoError(t, err)
+	require.False(t, isValid)
+	require.Empty(t, identityId)
+}
+
+func TestConsumeAuthCode_CodeVerifierSuccess(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	challenge, err := oauth.NewCodeChallenge("L3JWHu2qjlyvH1eTl9tFlqc9TKbp9KTPl3d3MW55ZLM", oauth.CodeChallengeMethodS256)
+	require.NoError(t, err)
+
+	code, err := oauth.NewAuthCode(ctx, "identity_id", challenge)
+	require.NoError(t, err)
+
+	isValid, identityId, err := oauth.ConsumeAuthCode(ctx, code, "dBjftJeZ4CVP-mJ92ZrZS4MnrwCODc5YBdQhIoFHBvs")
+	require.NoError(t, err)
+	require.True(t, isValid)
+	require.Equal(t, "identity_id", identityId)
+}
+
+func TestConsumeAuthCode_CodeVerifierIncorrect(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	challenge, err := oauth.NewCodeChallenge("L3JWHu2qjlyvH1eTl9tFlqc9TKbp9KTPl3d3MW55ZLM", oauth.CodeChallengeMethodS256)
+	require.NoError(t, err)
+
+	code, err := oauth.NewAuthCode(ctx, "identity_id", challenge)
+	require.NoError(t, err)
+
+	isValid, identityId, err := oauth.ConsumeAuthCode(ctx, code, "incorrect-verifier-incorrect-verifier-incorrect")
+	require.NoError(t, err)
+	require.False(t, isValid)
+	require.Empty(t, identityId)
+
+	// The auth code has been consumed by the failed attempt
+	isValid, _, err = oauth.ConsumeAuthCode(ctx, code, "dBjftJeZ4CVP-mJ92ZrZS4MnrwCODc5YBdQhIoFHBvs")
+	require.NoError(t, err)
+	require.False(t, isValid)
+}
+
+func TestConsumeAuthCode_CodeVerifierMissing(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	challenge, err := oauth.NewCodeChallenge("L3JWHu2qjlyvH1eTl9tFlqc9TKbp9KTPl3d3MW55ZLM", oauth.CodeChallengeMethodS256)
+	require.NoError(t, err)
+
+	code, err := oauth.NewAuthCode(ctx, "identity_id", challenge)
+	require.NoError(t, err)
+
+	isValid, identityId, err := oauth.ConsumeAuthCode(ctx, code, "")
+	require.NoError(t, err)
+	require.False(t, isValid)
+	require.Empty(t, identityId)
+}
+
+func TestConsumeAuthCode_CodeVerifierWithoutChallenge(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	code, err := oauth.NewAuthCode(ctx, "identity_id", nil)
+	require.NoError(t, err)
+
+	isValid, identityId, err := oauth.ConsumeAuthCode(ctx, code, "dBjftJeZ4CVP-mJ92ZrZS4MnrwCODc5YBdQhIoFHBvs")
 	require.NoError(t, err)
 	require.False(t, isValid)
 	require.Empty(t, identityId)
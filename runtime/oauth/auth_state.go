@@ -0,0 +1,72 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dchest/uniuri"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/samber/lo"
+)
+
+const (
+	authStateAudClaim = "auth-state"
+	authStateExpiry   = time.Duration(10) * time.Minute
+)
+
+// AuthStateClaims are the claims of the signed state parameter which is passed through a
+// provider's authorization flow and returned to us at the callback endpoint.
+type AuthStateClaims struct {
+	jwt.RegisteredClaims
+	CodeChallenge       string `json:"code_challenge,omitempty"`
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty"`
+	Link                bool   `json:"link,omitempty"`
+}
+
+// NewAuthState generates a signed state parameter for the authorization flow with the provider.
+// Any PKCE code challenge from the client is carried in the state until the auth code is issued.
+// If link is true, then the provider's identity will be linked to the identity which exchanges the auth code.
+func NewAuthState(ctx context.Context, challenge *CodeChallenge, link bool) (string, error) {
+	now := time.Now().UTC()
+	claims := AuthStateClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uniuri.New(),
+			Audience:  []string{authStateAudClaim},
+			ExpiresAt: jwt.NewNumericDate(now.Add(authStateExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    TokenIssuer(),
+		},
+		Link: link,
+	}
+
+	if challenge != nil {
+		claims.CodeChallenge = challenge.Challenge
+		claims.CodeChallengeMethod = challenge.Method
+	}
+
+	return signJwt(ctx, claims)
+}
+
+// ValidateAuthState verifies the state parameter returned from the provider and returns the client's
+// PKCE code challenge, if one was provided, and whether the provider is being linked to an identity.
+func ValidateAuthState(ctx context.Context, state string) (*CodeChallenge, bool, error) {
+	claims := &AuthStateClaims{}
+	token, err := parseJwt(ctx, state, claims)
+	if err != nil || !token.Valid {
+		return nil, false, errors.New("state cannot be parsed or verified")
+	}
+
+	if !isTokenIssuer(claims.Issuer) || !lo.Contains(claims.Audience, authStateAudClaim) {
+		return nil, false, errors.New("state cannot be parsed or verified")
+	}
+
+	if claims.CodeChallenge == "" {
+		return nil, claims.Link, nil
+	}
+
+	return &CodeChallenge{
+		Challenge: claims.CodeChallenge,
+		Method:    claims.CodeChallengeMethod,
+	}, claims.Link, nil
+}
@@ -0,0 +1,53 @@
+package oauth
+
+import (
+	"net"
+	"strings"
+)
+
+// ClientIpAddress determines the IP address of the client, which is the address of the connection unless that is
+// a trusted proxy. Each proxy appends the address it received the request from to X-Forwarded-For, so the client
+// is then the last address which is not a trusted proxy, as any earlier addresses could have been set by the client.
+func ClientIpAddress(remoteAddr string, forwardedFor []string, trustedProxies []*net.IPNet) string {
+	ipAddress, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		ipAddress = remoteAddr
+	}
+
+	if !isTrustedProxy(ipAddress, trustedProxies) {
+		return ipAddress
+	}
+
+	hops := []string{}
+	for _, header := range forwardedFor {
+		for _, hop := range strings.Split(header, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		ipAddress = hops[i]
+		if !isTrustedProxy(ipAddress, trustedProxies) {
+			break
+		}
+	}
+
+	return ipAddress
+}
+
+func isTrustedProxy(ipAddress string, trustedProxies []*net.IPNet) bool {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return false
+	}
+
+	for _, proxy := range trustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,30 @@
+package oauth_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/runtime/oauth"
+)
+
+func TestClientIpAddress_ConnectionWithoutTrustedProxy(t *testing.T) {
+	ipAddress := oauth.ClientIpAddress("198.51.100.2:1234", []string{"203.0.113.7"}, nil)
+	require.Equal(t, "198.51.100.2", ipAddress)
+}
+
+func TestClientIpAddress_LastUntrustedForwardedAddress(t *testing.T) {
+	_, proxies, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	ipAddress := oauth.ClientIpAddress("10.0.0.5:1234", []string{"198.51.100.9, 203.0.113.7", "10.0.0.9"}, []*net.IPNet{proxies})
+	require.Equal(t, "203.0.113.7", ipAddress)
+}
+
+func TestClientIpAddress_NoForwardedAddress(t *testing.T) {
+	_, proxies, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	ipAddress := oauth.ClientIpAddress("10.0.0.5:1234", nil, []*net.IPNet{proxies})
+	require.Equal(t, "10.0.0.5", ipAddress)
+}
@@ -0,0 +1,141 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/teamkeel/keel/db"
+	"github.com/teamkeel/keel/runtime/common"
+)
+
+var ErrIdentityDisabled = common.NewAuthenticationFailedMessageErr("identity has been disabled")
+
+// DisableIdentity prevents the identity from authenticating and revokes all of its sessions.
+// Disabling an identity which is already disabled keeps the time it was first disabled.
+func DisableIdentity(ctx context.Context, identityId string) error {
+	ctx, span := tracer.Start(ctx, "Disable Identity")
+	defer span.End()
+
+	if identityId == "" {
+		return errors.New("identity ID cannot be empty when disabling an identity")
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return err
+	}
+
+	sql := `
+		INSERT INTO
+			keel_identity_disabled (identity_id, disabled_at)
+		VALUES
+			(?, ?)
+		ON CONFLICT (identity_id) DO NOTHING`
+
+	err = database.GetDB().Exec(sql, identityId, time.Now().UTC()).Error
+	if err != nil {
+		return err
+	}
+
+	_, err = RevokeAllSessions(ctx, identityId)
+	return err
+}
+
+// EnableIdentity allows a disabled identity to authenticate again. Returns false if the identity was not disabled.
+func EnableIdentity(ctx context.Context, identityId string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "Enable Identity")
+	defer span.End()
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	sql := `
+		DELETE FROM
+			keel_identity_disabled
+		WHERE
+			identity_id = ?`
+
+	db := database.GetDB().Exec(sql, identityId)
+	if db.Error != nil {
+		return false, db.Error
+	}
+
+	return db.RowsAffected > 0, nil
+}
+
+// IdentityDisabledAt returns when the identity was disabled, or nil if it is not disabled.
+func IdentityDisabledAt(ctx context.Context, identityId string) (*time.Time, error) {
+	ctx, span := tracer.Start(ctx, "Identity Disabled")
+	defer span.End()
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := `
+		SELECT
+			disabled_at
+		FROM
+			keel_identity_disabled
+		WHERE
+			identity_id = ?`
+
+	rows := []*time.Time{}
+	err = database.GetDB().Raw(sql, identityId).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	return rows[0], nil
+}
+
+// IsIdentityDisabled determines if the identity has been disabled and so cannot authenticate.
+func IsIdentityDisabled(ctx context.Context, identityId string) (bool, error) {
+	disabledAt, err := IdentityDisabledAt(ctx, identityId)
+	if err != nil {
+		return false, err
+	}
+
+	return disabledAt != nil, nil
+}
+
+// DisabledIdentities returns the time each disabled identity was disabled, keyed by identity ID.
+func DisabledIdentities(ctx context.Context) (map[string]time.Time, error) {
+	ctx, span := tracer.Start(ctx, "Disabled Identities")
+	defer span.End()
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := `
+		SELECT
+			identity_id, disabled_at
+		FROM
+			keel_identity_disabled`
+
+	rows := []*struct {
+		IdentityId string
+		DisabledAt time.Time
+	}{}
+	err = database.GetDB().Raw(sql).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	disabled := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		disabled[row.IdentityId] = row.DisabledAt
+	}
+
+	return disabled, nil
+}
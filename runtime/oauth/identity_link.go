@@ -0,0 +1,149 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/teamkeel/keel/db"
+)
+
+// ErrIdentityAlreadyLinked is returned when the external identity already belongs to another identity.
+var ErrIdentityAlreadyLinked = errors.New("external identity is already linked to another identity")
+
+// LinkedIdentity is an identity at an external provider which has been linked to an identity,
+// in addition to the provider the identity was originally created with.
+type LinkedIdentity struct {
+	IdentityId string
+	Issuer     string
+	ExternalId string
+	Email      *string
+	CreatedAt  time.Time
+}
+
+// FindLinkedIdentityId finds the identity which the external identity has been linked to.
+// An empty string is returned if the external identity has not been linked.
+func FindLinkedIdentityId(ctx context.Context, issuer string, externalId string) (string, error) {
+	ctx, span := tracer.Start(ctx, "Find Linked Identity")
+	defer span.End()
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	sql := `
+		SELECT
+			identity_id
+		FROM
+			keel_identity_link
+		WHERE
+			issuer = ? AND external_id = ?`
+
+	rows := []map[string]any{}
+	err = database.GetDB().Raw(sql, issuer, externalId).Scan(&rows).Error
+	if err != nil {
+		return "", err
+	}
+
+	if len(rows) == 0 {
+		return "", nil
+	}
+
+	return rows[0]["identity_id"].(string), nil
+}
+
+// LinkIdentity links the external identity to the identity so that signing in with the external
+// provider authenticates as the identity. Linking an external identity which is already linked to
+// the same identity has no effect, but ErrIdentityAlreadyLinked is returned if it is linked to another.
+func LinkIdentity(ctx context.Context, identityId string, issuer string, externalId string, email string) error {
+	ctx, span := tracer.Start(ctx, "Link Identity")
+	defer span.End()
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return err
+	}
+
+	var emailValue *string
+	if email != "" {
+		emailValue = &email
+	}
+
+	sql := `
+		INSERT INTO
+			keel_identity_link (issuer, external_id, identity_id, email, created_at)
+		VALUES
+			(?, ?, ?, ?, ?)
+		ON CONFLICT (issuer, external_id) DO NOTHING`
+
+	err = database.GetDB().Exec(sql, issuer, externalId, identityId, emailValue, time.Now().UTC()).Error
+	if err != nil {
+		return err
+	}
+
+	linkedId, err := FindLinkedIdentityId(ctx, issuer, externalId)
+	if err != nil {
+		return err
+	}
+
+	if linkedId != identityId {
+		return ErrIdentityAlreadyLinked
+	}
+
+	return nil
+}
+
+// ListLinkedIdentities returns the external identities linked to the identity, ordered by when they were linked.
+func ListLinkedIdentities(ctx context.Context, identityId string) ([]*LinkedIdentity, error) {
+	ctx, span := tracer.Start(ctx, "List Linked Identities")
+	defer span.End()
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := `
+		SELECT
+			identity_id, issuer, external_id, email, created_at
+		FROM
+			keel_identity_link
+		WHERE
+			identity_id = ?
+		ORDER BY
+			created_at`
+
+	links := []*LinkedIdentity{}
+	err = database.GetDB().Raw(sql, identityId).Scan(&links).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// UnlinkIdentity removes the link between the external identity and the identity.
+// Returns false if the external identity was not linked to the identity.
+func UnlinkIdentity(ctx context.Context, identityId string, issuer string, externalId string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "Unlink Identity")
+	defer span.End()
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	sql := `
+		DELETE FROM
+			keel_identity_link
+		WHERE
+			identity_id = ? AND issuer = ? AND external_id = ?`
+
+	result := database.GetDB().Exec(sql, identityId, issuer, externalId)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -1,9 +1,18 @@
 package oauth
 
 import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/samber/lo"
+	"github.com/teamkeel/keel/config"
+	"github.com/teamkeel/keel/runtime/runtimectx"
 )
 
 func ExtractClaimFromJwt(token string, claim string) (string, error) {
@@ -26,3 +35,161 @@ func ExtractClaimFromJwt(token string, claim string) (string, error) {
 
 	return value, nil
 }
+
+// JSONWebKey is the public part of a signing key in the JSON Web Key format.
+// https://datatracker.ietf.org/doc/html/rfc7517#section-4
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyId     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// JSONWebKeySet is the set of public keys which can be used to verify Keel-issued tokens.
+// https://datatracker.ietf.org/doc/html/rfc7517#section-5
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// KeyId generates the key id (kid) of a key, which is its JWK thumbprint.
+// https://datatracker.ietf.org/doc/html/rfc7638
+func KeyId(key *rsa.PublicKey) string {
+	n, e := encodePublicKey(key)
+
+	// The required members of the JWK in lexicographic order and without whitespace
+	thumbprint := fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, e, n)
+	hash := sha256.Sum256([]byte(thumbprint))
+
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// NewPrivateKeySet determines the primary and active keys using the signing keys config.
+// If no primary key is configured then the first key is used as the primary.
+func NewPrivateKeySet(keys []*rsa.PrivateKey, cfg *config.SigningKeys) (*runtimectx.PrivateKeySet, error) {
+	set := &runtimectx.PrivateKeySet{}
+
+	for _, key := range keys {
+		kid := KeyId(&key.PublicKey)
+		if lo.Contains(cfg.Retired, kid) {
+			continue
+		}
+
+		set.Active = append(set.Active, key)
+
+		if cfg.Primary == nil && set.Primary == nil {
+			set.Primary = key
+		} else if cfg.Primary != nil && *cfg.Primary == kid {
+			set.Primary = key
+		}
+	}
+
+	if set.Primary == nil {
+		if cfg.Primary != nil {
+			return nil, fmt.Errorf("primary signing key '%s' has not been provided", *cfg.Primary)
+		}
+		return nil, errors.New("no active signing keys have been provided")
+	}
+
+	return set, nil
+}
+
+// PublicKeySet returns the public keys of all the active signing keys.
+func PublicKeySet(ctx context.Context) (*JSONWebKeySet, error) {
+	keys, err := runtimectx.GetPrivateKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jwks := &JSONWebKeySet{
+		Keys: []JSONWebKey{},
+	}
+
+	if keys == nil {
+		return jwks, nil
+	}
+
+	for _, key := range keys.Active {
+		n, e := encodePublicKey(&key.PublicKey)
+		jwks.Keys = append(jwks.Keys, JSONWebKey{
+			KeyType:   "RSA",
+			Use:       "sig",
+			Algorithm: jwt.SigningMethodRS256.Alg(),
+			KeyId:     KeyId(&key.PublicKey),
+			Modulus:   n,
+			Exponent:  e,
+		})
+	}
+
+	return jwks, nil
+}
+
+// signJwt signs the claims with the primary signing key, including its key id in the header.
+func signJwt(ctx context.Context, claims jwt.Claims) (string, error) {
+	privateKey, err := runtimectx.GetPrivateKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if privateKey == nil {
+		return "", errors.New("no private key set")
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = KeyId(&privateKey.PublicKey)
+
+	return token.SignedString(privateKey)
+}
+
+// parseJwt parses the token and verifies it with the active signing key which it was signed with. Tokens without
+// a key id were issued before key rotation was supported, and so are verified against each active key in turn.
+func parseJwt(ctx context.Context, tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	keys, err := runtimectx.GetPrivateKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if keys == nil || keys.Primary == nil {
+		return nil, errors.New("no private key set")
+	}
+
+	candidates := append([]*rsa.PrivateKey{keys.Primary}, keys.Active...)
+
+	var token *jwt.Token
+	for _, candidate := range lo.Uniq(candidates) {
+		candidate := candidate
+		token, err = jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+			if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+				return nil, errors.New("unexpected signing method")
+			}
+
+			kid, hasKid := t.Header["kid"].(string)
+			if !hasKid {
+				return &candidate.PublicKey, nil
+			}
+
+			for _, key := range keys.Active {
+				if KeyId(&key.PublicKey) == kid {
+					return &key.PublicKey, nil
+				}
+			}
+
+			return nil, fmt.Errorf("no active signing key with kid '%s'", kid)
+		})
+
+		// Only a token without a key id which failed verification is tried with the next key
+		var validationErr *jwt.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Errors&jwt.ValidationErrorSignatureInvalid == 0 || token == nil || token.Header["kid"] != nil {
+			return token, err
+		}
+	}
+
+	return token, err
+}
+
+func encodePublicKey(key *rsa.PublicKey) (n string, e string) {
+	n = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+	e = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	return n, e
+}
@@ -0,0 +1,215 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/config"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+	"github.com/teamkeel/keel/testhelpers"
+)
+
+func TestKeyId(t *testing.T) {
+	pk, err := testhelpers.GetEmbeddedPrivateKey()
+	require.NoError(t, err)
+
+	// The JWK thumbprint of the embedded private key
+	require.Equal(t, "4L_IwYHYPla2W4j7pauLBeP7j7QMulpH53VZI3Vyz4o", oauth.KeyId(&pk.PublicKey))
+}
+
+func TestNewPrivateKeySet_DefaultsToFirstKey(t *testing.T) {
+	key1, key2 := generateKeys(t)
+
+	keys, err := oauth.NewPrivateKeySet([]*rsa.PrivateKey{key1, key2}, &config.SigningKeys{})
+	require.NoError(t, err)
+	require.Equal(t, key1, keys.Primary)
+	require.Equal(t, []*rsa.PrivateKey{key1, key2}, keys.Active)
+}
+
+func TestNewPrivateKeySet_ConfiguredPrimary(t *testing.T) {
+	key1, key2 := generateKeys(t)
+	primary := oauth.KeyId(&key2.PublicKey)
+
+	keys, err := oauth.NewPrivateKeySet([]*rsa.PrivateKey{key1, key2}, &config.SigningKeys{Primary: &primary})
+	require.NoError(t, err)
+	require.Equal(t, key2, keys.Primary)
+	require.Equal(t, []*rsa.PrivateKey{key1, key2}, keys.Active)
+}
+
+func TestNewPrivateKeySet_RetiredKey(t *testing.T) {
+	key1, key2 := generateKeys(t)
+
+	keys, err := oauth.NewPrivateKeySet([]*rsa.PrivateKey{key1, key2}, &config.SigningKeys{Retired: []string{oauth.KeyId(&key1.PublicKey)}})
+	require.NoError(t, err)
+	require.Equal(t, key2, keys.Primary)
+	require.Equal(t, []*rsa.PrivateKey{key2}, keys.Active)
+}
+
+func TestNewPrivateKeySet_PrimaryNotProvided(t *testing.T) {
+	key1, key2 := generateKeys(t)
+	primary := "unknown"
+
+	_, err := oauth.NewPrivateKeySet([]*rsa.PrivateKey{key1, key2}, &config.SigningKeys{Primary: &primary})
+	require.ErrorContains(t, err, "primary signing key 'unknown' has not been provided")
+}
+
+func TestNewPrivateKeySet_AllKeysRetired(t *testing.T) {
+	key1, _ := generateKeys(t)
+
+	_, err := oauth.NewPrivateKeySet([]*rsa.PrivateKey{key1}, &config.SigningKeys{Retired: []string{oauth.KeyId(&key1.PublicKey)}})
+	require.ErrorContains(t, err, "no active signing keys have been provided")
+}
+
+func TestAccessTokenHasKeyId(t *testing.T) {
+	ctx := newContextWithPK()
+
+	bearerJwt, _, err := oauth.GenerateAccessToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(bearerJwt, &oauth.AccessTokenClaims{})
+	require.NoError(t, err)
+
+	pk, err := runtimectx.GetPrivateKey(ctx)
+	require.NoError(t, err)
+	require.Equal(t, oauth.KeyId(&pk.PublicKey), token.Header["kid"])
+}
+
+func TestAccessTokenValidAfterRotation(t *testing.T) {
+	key1, key2 := generateKeys(t)
+
+	// Issue a token with the original primary key
+	ctx := runtimectx.WithPrivateKey(context.Background(), key1)
+	bearerJwt, _, err := oauth.GenerateAccessToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	// Rotate to a new primary key, keeping the original key active
+	primary := oauth.KeyId(&key2.PublicKey)
+	keys, err := oauth.NewPrivateKeySet([]*rsa.PrivateKey{key1, key2}, &config.SigningKeys{Primary: &primary})
+	require.NoError(t, err)
+	ctx = runtimectx.WithPrivateKeys(context.Background(), keys)
+
+	identityId, err := oauth.ValidateAccessToken(ctx, bearerJwt)
+	require.NoError(t, err)
+	require.Equal(t, "identity_id", identityId)
+
+	// New tokens are signed with the new primary key
+	newBearerJwt, _, err := oauth.GenerateAccessToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(newBearerJwt, &oauth.AccessTokenClaims{})
+	require.NoError(t, err)
+	require.Equal(t, primary, token.Header["kid"])
+}
+
+func TestAccessTokenInvalidAfterRetirement(t *testing.T) {
+	key1, key2 := generateKeys(t)
+
+	// Issue a token with the original primary key
+	ctx := runtimectx.WithPrivateKey(context.Background(), key1)
+	bearerJwt, _, err := oauth.GenerateAccessToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	// Retire the original key
+	keys, err := oauth.NewPrivateKeySet([]*rsa.PrivateKey{key1, key2}, &config.SigningKeys{Retired: []string{oauth.KeyId(&key1.PublicKey)}})
+	require.NoError(t, err)
+	ctx = runtimectx.WithPrivateKeys(context.Background(), keys)
+
+	identityId, err := oauth.ValidateAccessToken(ctx, bearerJwt)
+	require.ErrorIs(t, err, oauth.ErrInvalidToken)
+	require.Empty(t, identityId)
+}
+
+func TestAccessTokenWithoutKeyIdUsesPrimary(t *testing.T) {
+	ctx := newContextWithPK()
+
+	pk, err := runtimectx.GetPrivateKey(ctx)
+	require.NoError(t, err)
+
+	// Tokens issued before key ids were introduced have no kid header
+	claims := oauth.AccessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "identity_id",
+			ExpiresAt: jwt.NewNumericDate(jwt.TimeFunc().Add(time.Hour)),
+			Issuer:    oauth.KeelIssuer,
+		},
+	}
+	bearerJwt, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(pk)
+	require.NoError(t, err)
+
+	identityId, err := oauth.ValidateAccessToken(ctx, bearerJwt)
+	require.NoError(t, err)
+	require.Equal(t, "identity_id", identityId)
+}
+
+func TestAccessTokenWithoutKeyIdAfterRotation(t *testing.T) {
+	key1, key2 := generateKeys(t)
+
+	// Tokens issued before key ids were introduced have no kid header
+	signWithoutKeyId := func(expiresAt time.Time) string {
+		claims := oauth.AccessTokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "identity_id",
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+				Issuer:    oauth.KeelIssuer,
+			},
+		}
+		bearerJwt, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key1)
+		require.NoError(t, err)
+		return bearerJwt
+	}
+
+	// Rotate to a new primary key, keeping the original key active
+	primary := oauth.KeyId(&key2.PublicKey)
+	keys, err := oauth.NewPrivateKeySet([]*rsa.PrivateKey{key1, key2}, &config.SigningKeys{Primary: &primary})
+	require.NoError(t, err)
+	ctx := runtimectx.WithPrivateKeys(context.Background(), keys)
+
+	identityId, err := oauth.ValidateAccessToken(ctx, signWithoutKeyId(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	require.Equal(t, "identity_id", identityId)
+
+	_, err = oauth.ValidateAccessToken(ctx, signWithoutKeyId(time.Now().Add(-time.Hour)))
+	require.ErrorIs(t, err, oauth.ErrTokenExpired)
+
+	// Retire the original key
+	keys, err = oauth.NewPrivateKeySet([]*rsa.PrivateKey{key1, key2}, &config.SigningKeys{Retired: []string{oauth.KeyId(&key1.PublicKey)}})
+	require.NoError(t, err)
+	ctx = runtimectx.WithPrivateKeys(context.Background(), keys)
+
+	_, err = oauth.ValidateAccessToken(ctx, signWithoutKeyId(time.Now().Add(time.Hour)))
+	require.ErrorIs(t, err, oauth.ErrInvalidToken)
+}
+
+func TestPublicKeySet(t *testing.T) {
+	key1, key2 := generateKeys(t)
+
+	keys, err := oauth.NewPrivateKeySet([]*rsa.PrivateKey{key1, key2}, &config.SigningKeys{Retired: []string{oauth.KeyId(&key1.PublicKey)}})
+	require.NoError(t, err)
+	ctx := runtimectx.WithPrivateKeys(context.Background(), keys)
+
+	jwks, err := oauth.PublicKeySet(ctx)
+	require.NoError(t, err)
+	require.Len(t, jwks.Keys, 1)
+	require.Equal(t, oauth.KeyId(&key2.PublicKey), jwks.Keys[0].KeyId)
+	require.Equal(t, "RSA", jwks.Keys[0].KeyType)
+	require.Equal(t, "sig", jwks.Keys[0].Use)
+	require.Equal(t, "RS256", jwks.Keys[0].Algorithm)
+	require.Equal(t, "AQAB", jwks.Keys[0].Exponent)
+	require.NotEmpty(t, jwks.Keys[0].Modulus)
+}
+
+func generateKeys(t *testing.T) (*rsa.PrivateKey, *rsa.PrivateKey) {
+	key1, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	key2, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	return key1, key2
+}
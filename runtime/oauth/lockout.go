@@ -0,0 +1,310 @@
+package oauth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/teamkeel/keel/db"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+)
+
+const (
+	lockoutEmailKeyPrefix     = "email:"
+	lockoutIpAddressKeyPrefix = "ip:"
+)
+
+// Lockout describes a failed password attempt which caused the email address,
+// the IP address, or both to be locked out.
+type Lockout struct {
+	Email           string
+	IpAddress       string
+	EmailLocked     bool
+	IpAddressLocked bool
+	LockedUntil     time.Time
+}
+
+type authAttemptRow struct {
+	Key          string
+	Failures     int
+	LastFailedAt *time.Time
+	LockedUntil  *time.Time
+}
+
+// CheckLockout determines how long the caller must wait before a password attempt for the email
+// address from the IP address is permitted. A zero duration means the attempt is permitted, in which
+// case it has already been counted as a failure so that concurrent attempts can neither exceed the
+// maximum attempts nor avoid the progressive delay. The failure is then either confirmed with
+// RecordFailedAttempt or forgotten with ClearFailedAttempts once the outcome of the attempt is known.
+// Attempts for an email address are delayed progressively after each consecutive failure.
+func CheckLockout(ctx context.Context, email string, ipAddress string) (time.Duration, error) {
+	ctx, span := tracer.Start(ctx, "Check Lockout")
+	defer span.End()
+
+	config, err := runtimectx.GetOAuthConfig(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	emailKey := lockoutEmailKeyPrefix + normaliseEmail(email)
+	ipAddressKey := lockoutIpAddressKeyPrefix + ipAddress
+
+	// The IP address is reserved first as it has no progressive delay, so releasing it has no effect on later attempts
+	reserved := true
+	if ipAddress != "" {
+		reserved, err = reserveAttempt(ctx, ipAddressKey, config.LockoutMaxAttemptsPerIp(), 0, config.LockoutDuration(), now)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if reserved {
+		reserved, err = reserveAttempt(ctx, emailKey, config.LockoutMaxAttempts(), config.LockoutDelay(), config.LockoutDuration(), now)
+		if err != nil {
+			return 0, err
+		}
+
+		if !reserved && ipAddress != "" {
+			err = releaseAttempt(ctx, ipAddressKey)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if reserved {
+		return 0, nil
+	}
+
+	wait, err := lockoutWait(ctx, emailKey, ipAddressKey, now)
+	if err != nil {
+		return 0, err
+	}
+
+	// The maximum attempts are taken by attempts which are still in progress
+	if wait <= 0 {
+		wait = time.Second
+	}
+
+	return wait, nil
+}
+
+// lockoutWait determines how long remains of any lockout or progressive delay for the keys.
+func lockoutWait(ctx context.Context, emailKey string, ipAddressKey string, now time.Time) (time.Duration, error) {
+	config, err := runtimectx.GetOAuthConfig(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sql := `
+		SELECT
+			key, failures, last_failed_at, locked_until
+		FROM
+			keel_auth_attempt
+		WHERE
+			key IN (?, ?)`
+
+	rows := []*authAttemptRow{}
+	err = database.GetDB().Raw(sql, emailKey, ipAddressKey).Scan(&rows).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var wait time.Duration
+
+	for _, row := range rows {
+		if row.LockedUntil != nil && row.LockedUntil.Sub(now) > wait {
+			wait = row.LockedUntil.Sub(now)
+		}
+
+		if row.Key != emailKey || row.Failures == 0 || row.LastFailedAt == nil || config.LockoutDelay() == 0 {
+			continue
+		}
+
+		if next := row.LastFailedAt.Add(progressiveDelay(row.Failures, config.LockoutDelay(), config.LockoutDuration())); next.Sub(now) > wait {
+			wait = next.Sub(now)
+		}
+	}
+
+	return wait, nil
+}
+
+// progressiveDelay is the delay required after the given number of consecutive failures. It doubles
+// with each consecutive failure, but never exceeds the lockout duration.
+func progressiveDelay(failures int, delay time.Duration, duration time.Duration) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+
+	for i := 1; i < failures && delay < duration; i++ {
+		delay *= 2
+	}
+	if delay > duration {
+		delay = duration
+	}
+
+	return delay
+}
+
+// RecordFailedAttempt confirms the failed password attempt which was counted by CheckLockout against the email address
+// and IP address. If either has now reached its maximum attempts, then it is locked out and the lockout is returned.
+// Failures older than the lockout duration are forgotten.
+func RecordFailedAttempt(ctx context.Context, email string, ipAddress string) (*Lockout, error) {
+	ctx, span := tracer.Start(ctx, "Record Failed Attempt")
+	defer span.End()
+
+	config, err := runtimectx.GetOAuthConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lockedUntil := time.Now().UTC().Add(config.LockoutDuration())
+
+	lockout := &Lockout{
+		Email:       email,
+		IpAddress:   ipAddress,
+		LockedUntil: lockedUntil,
+	}
+
+	lockout.EmailLocked, err = lockOutIfExceeded(ctx, lockoutEmailKeyPrefix+normaliseEmail(email), config.LockoutMaxAttempts(), lockedUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	if ipAddress != "" {
+		lockout.IpAddressLocked, err = lockOutIfExceeded(ctx, lockoutIpAddressKeyPrefix+ipAddress, config.LockoutMaxAttemptsPerIp(), lockedUntil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !lockout.EmailLocked && !lockout.IpAddressLocked {
+		return nil, nil
+	}
+
+	return lockout, nil
+}
+
+// ClearFailedAttempts forgets the failed password attempts for the email address after a successful sign in, and
+// the attempt counted by CheckLockout against the IP address. Other failures from the IP address are retained so
+// that successful attempts cannot be used to avoid the IP address limit.
+func ClearFailedAttempts(ctx context.Context, email string, ipAddress string) error {
+	ctx, span := tracer.Start(ctx, "Clear Failed Attempts")
+	defer span.End()
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return err
+	}
+
+	sql := `
+		DELETE FROM
+			keel_auth_attempt
+		WHERE
+			key = ?`
+
+	err = database.GetDB().Exec(sql, lockoutEmailKeyPrefix+normaliseEmail(email)).Error
+	if err != nil {
+		return err
+	}
+
+	if ipAddress == "" {
+		return nil
+	}
+
+	return releaseAttempt(ctx, lockoutIpAddressKeyPrefix+ipAddress)
+}
+
+// reserveAttempt atomically counts an attempt against the key, provided that the key is not locked out, has not reached
+// its maximum attempts and is not waiting for the progressive delay. Returns false if the attempt was not counted.
+func reserveAttempt(ctx context.Context, key string, maxAttempts int, delay time.Duration, window time.Duration, now time.Time) (bool, error) {
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// The conditions are evaluated against the existing row in the same statement as the increment,
+	// so that concurrent attempts across runtime instances are each checked against the others.
+	// Failures older than the window are forgotten, in which case the count starts over.
+	sql := `
+		INSERT INTO
+			keel_auth_attempt (key, failures, last_failed_at)
+		VALUES
+			(?, 1, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN keel_auth_attempt.last_failed_at < ? THEN 1 ELSE keel_auth_attempt.failures + 1 END,
+			last_failed_at = EXCLUDED.last_failed_at
+		WHERE
+			(keel_auth_attempt.locked_until IS NULL OR keel_auth_attempt.locked_until <= ?) AND
+			(keel_auth_attempt.last_failed_at IS NULL OR keel_auth_attempt.last_failed_at < ? OR (
+				keel_auth_attempt.failures < ? AND
+				(keel_auth_attempt.failures = 0 OR keel_auth_attempt.last_failed_at + LEAST(? * power(2, keel_auth_attempt.failures - 1), ?) * interval '1 second' <= ?)))
+		RETURNING
+			key, failures, last_failed_at, locked_until`
+
+	windowStart := now.Add(-window)
+	rows := []*authAttemptRow{}
+	err = database.GetDB().Raw(sql, key, now, windowStart, now, windowStart, maxAttempts, delay.Seconds(), window.Seconds(), now).Scan(&rows).Error
+	if err != nil {
+		return false, err
+	}
+
+	return len(rows) == 1, nil
+}
+
+// releaseAttempt forgets an attempt counted by reserveAttempt which did not fail.
+func releaseAttempt(ctx context.Context, key string) error {
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return err
+	}
+
+	sql := `
+		UPDATE
+			keel_auth_attempt
+		SET
+			failures = GREATEST(failures - 1, 0)
+		WHERE
+			key = ?`
+
+	return database.GetDB().Exec(sql, key).Error
+}
+
+// lockOutIfExceeded locks out the key if it has reached its maximum attempts, after which the maximum attempts are
+// available again once the lockout has passed. Returns true if the key was locked out.
+func lockOutIfExceeded(ctx context.Context, key string, maxAttempts int, lockedUntil time.Time) (bool, error) {
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	sql := `
+		UPDATE
+			keel_auth_attempt
+		SET
+			failures = 0,
+			locked_until = ?
+		WHERE
+			key = ? AND
+			failures >= ?
+		RETURNING
+			key`
+
+	rows := []map[string]any{}
+	err = database.GetDB().Raw(sql, lockedUntil, key, maxAttempts).Scan(&rows).Error
+	if err != nil {
+		return false, err
+	}
+
+	return len(rows) == 1, nil
+}
+
+func normaliseEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,341 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dchest/uniuri"
+	"github.com/teamkeel/keel/db"
+)
+
+const (
+	// Number of recovery codes issued when an authenticator is enrolled
+	recoveryCodeCount = 10
+	// Character length of a recovery code, excluding the separator
+	recoveryCodeLength = 10
+	// Number of consecutive incorrect codes after which only a recovery code will be accepted
+	mfaMaxFailedAttempts = 5
+)
+
+// Characters which are not easily confused with each other when read back by a user.
+var recoveryCodeChars = []byte("abcdefghjkmnpqrstuvwxyz23456789")
+
+var ErrMfaAlreadyEnrolled = errors.New("an authenticator has already been enrolled for this identity")
+
+type mfaFactorRow struct {
+	IdentityId     string
+	Secret         string
+	Confirmed      bool
+	LastUsedStep   int64
+	FailedAttempts int
+}
+
+// EnrolTotp generates a new TOTP secret for the identity. The authenticator is not required when
+// authenticating until it has been confirmed with a valid code using ConfirmTotp. Enrolling again
+// before confirming will replace the secret.
+func EnrolTotp(ctx context.Context, identityId string) (string, error) {
+	ctx, span := tracer.Start(ctx, "Enrol TOTP")
+	defer span.End()
+
+	if identityId == "" {
+		return "", errors.New("identity ID cannot be empty when enrolling an authenticator")
+	}
+
+	secret, err := newTotpSecret()
+	if err != nil {
+		return "", err
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	sql := `
+		INSERT INTO
+			keel_mfa_factor (identity_id, secret, confirmed, created_at)
+		VALUES
+			(?, ?, false, ?)
+		ON CONFLICT (identity_id) DO UPDATE SET
+			secret = EXCLUDED.secret,
+			created_at = EXCLUDED.created_at
+		WHERE
+			keel_mfa_factor.confirmed = false`
+
+	db := database.GetDB().Exec(sql, identityId, secret, time.Now().UTC())
+	if db.Error != nil {
+		return "", db.Error
+	}
+
+	if db.RowsAffected != 1 {
+		return "", ErrMfaAlreadyEnrolled
+	}
+
+	return secret, nil
+}
+
+// ConfirmTotp confirms the enrolled authenticator with a code generated from it, after which
+// the authenticator will be required when authenticating. The recovery codes are returned only once
+// and only their hashes are stored. If the code is not valid, then no recovery codes are returned.
+func ConfirmTotp(ctx context.Context, identityId string, code string) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "Confirm TOTP")
+	defer span.End()
+
+	factor, err := findMfaFactor(ctx, identityId)
+	if err != nil {
+		return nil, err
+	}
+
+	if factor == nil {
+		return nil, nil
+	}
+
+	if factor.Confirmed {
+		return nil, ErrMfaAlreadyEnrolled
+	}
+
+	valid, step, err := verifyTotpCode(factor.Secret, code, time.Now().UTC(), factor.LastUsedStep)
+	if err != nil || !valid {
+		return nil, err
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes := make([]string, recoveryCodeCount)
+
+	err = database.Transaction(ctx, func(ctx context.Context) error {
+		sql := `
+			UPDATE
+				keel_mfa_factor
+			SET
+				confirmed = true,
+				last_used_step = ?
+			WHERE
+				identity_id = ?`
+
+		_, err := database.ExecuteStatement(ctx, sql, step, identityId)
+		if err != nil {
+			return err
+		}
+
+		sql = `
+			DELETE FROM
+				keel_mfa_recovery_code
+			WHERE
+				identity_id = ?`
+
+		_, err = database.ExecuteStatement(ctx, sql, identityId)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		for i := range recoveryCodes {
+			raw := uniuri.NewLenChars(recoveryCodeLength, recoveryCodeChars)
+			recoveryCodes[i] = raw[:recoveryCodeLength/2] + "-" + raw[recoveryCodeLength/2:]
+
+			hash, err := hashToken(raw)
+			if err != nil {
+				return err
+			}
+
+			sql = `
+				INSERT INTO
+					keel_mfa_recovery_code (identity_id, code, created_at)
+				VALUES
+					(?, ?, ?)`
+
+			_, err = database.ExecuteStatement(ctx, sql, identityId, hash, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// HasMfa determines if the identity has a confirmed authenticator.
+func HasMfa(ctx context.Context, identityId string) (bool, error) {
+	factor, err := findMfaFactor(ctx, identityId)
+	if err != nil {
+		return false, err
+	}
+
+	return factor != nil && factor.Confirmed, nil
+}
+
+// VerifyMfaCode checks a code from the identity's confirmed authenticator. Each code can only be used once,
+// and after too many consecutive incorrect codes only a recovery code will be accepted.
+func VerifyMfaCode(ctx context.Context, identityId string, code string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "Verify MFA Code")
+	defer span.End()
+
+	if identityId == "" || code == "" {
+		return false, nil
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// Reserve an attempt before checking the code, so that concurrent attempts cannot exceed the maximum
+	sql := `
+		UPDATE
+			keel_mfa_factor
+		SET
+			failed_attempts = failed_attempts + 1
+		WHERE
+			identity_id = ? AND
+			confirmed = true AND
+			failed_attempts < ?
+		RETURNING
+			identity_id, secret, confirmed, last_used_step, failed_attempts`
+
+	rows := []*mfaFactorRow{}
+	err = database.GetDB().Raw(sql, identityId, mfaMaxFailedAttempts).Scan(&rows).Error
+	if err != nil {
+		return false, err
+	}
+
+	if len(rows) != 1 {
+		return false, nil
+	}
+
+	valid, step, err := verifyTotpCode(rows[0].Secret, code, time.Now().UTC(), rows[0].LastUsedStep)
+	if err != nil {
+		return false, err
+	}
+
+	if !valid {
+		return false, nil
+	}
+
+	// Guards against the same code being used concurrently, and releases the reserved attempt
+	sql = `
+		UPDATE
+			keel_mfa_factor
+		SET
+			last_used_step = ?,
+			failed_attempts = 0
+		WHERE
+			identity_id = ? AND
+			last_used_step < ?`
+
+	db := database.GetDB().Exec(sql, step, identityId, step)
+	if db.Error != nil {
+		return false, db.Error
+	}
+
+	return db.RowsAffected == 1, nil
+}
+
+// ConsumeRecoveryCode checks the recovery code and consumes it so that it cannot be used again.
+func ConsumeRecoveryCode(ctx context.Context, identityId string, code string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "Consume Recovery Code")
+	defer span.End()
+
+	normalised := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if identityId == "" || normalised == "" {
+		return false, nil
+	}
+
+	hash, err := hashToken(normalised)
+	if err != nil {
+		return false, err
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	sql := `
+		DELETE FROM
+			keel_mfa_recovery_code
+		WHERE
+			identity_id = ? AND
+			code = ?`
+
+	db := database.GetDB().Exec(sql, identityId, hash)
+	if db.Error != nil {
+		return false, db.Error
+	}
+
+	if db.RowsAffected != 1 {
+		return false, nil
+	}
+
+	sql = `
+		UPDATE
+			keel_mfa_factor
+		SET
+			failed_attempts = 0
+		WHERE
+			identity_id = ?`
+
+	return true, database.GetDB().Exec(sql, identityId).Error
+}
+
+// RemoveMfa removes the identity's authenticator and recovery codes.
+func RemoveMfa(ctx context.Context, identityId string) error {
+	ctx, span := tracer.Start(ctx, "Remove MFA")
+	defer span.End()
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return err
+	}
+
+	return database.Transaction(ctx, func(ctx context.Context) error {
+		_, err := database.ExecuteStatement(ctx, "DELETE FROM keel_mfa_factor WHERE identity_id = ?", identityId)
+		if err != nil {
+			return err
+		}
+
+		_, err = database.ExecuteStatement(ctx, "DELETE FROM keel_mfa_recovery_code WHERE identity_id = ?", identityId)
+		return err
+	})
+}
+
+func findMfaFactor(ctx context.Context, identityId string) (*mfaFactorRow, error) {
+	if identityId == "" {
+		return nil, nil
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := `
+		SELECT
+			identity_id, secret, confirmed, last_used_step, failed_attempts
+		FROM
+			keel_mfa_factor
+		WHERE
+			identity_id = ?`
+
+	rows := []*mfaFactorRow{}
+	err = database.GetDB().Raw(sql, identityId).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) != 1 {
+		return nil, nil
+	}
+
+	return rows[0], nil
+}
@@ -0,0 +1,259 @@
+package oauth_test
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/runtime/oauth"
+	keeltesting "github.com/teamkeel/keel/testing"
+)
+
+func enrolAndConfirmTotp(t *testing.T, ctx context.Context, identityId string) (string, []string) {
+	secret, err := oauth.EnrolTotp(ctx, identityId)
+	require.NoError(t, err)
+
+	code, err := oauth.GenerateTotpCode(secret, time.Now())
+	require.NoError(t, err)
+
+	recoveryCodes, err := oauth.ConfirmTotp(ctx, identityId, code)
+	require.NoError(t, err)
+	require.Len(t, recoveryCodes, 10)
+
+	return secret, recoveryCodes
+}
+
+func TestEnrolTotp_NotRequiredUntilConfirmed(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	identityId := ksuid.New().String()
+
+	secret, err := oauth.EnrolTotp(ctx, identityId)
+	require.NoError(t, err)
+	require.NotEmpty(t, secret)
+
+	hasMfa, err := oauth.HasMfa(ctx, identityId)
+	require.NoError(t, err)
+	require.False(t, hasMfa)
+}
+
+func TestEnrolTotp_ReplacesUnconfirmedSecret(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	identityId := ksuid.New().String()
+
+	first, err := oauth.EnrolTotp(ctx, identityId)
+	require.NoError(t, err)
+
+	second, err := oauth.EnrolTotp(ctx, identityId)
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+
+	// A code from the replaced secret cannot confirm the authenticator
+	code, err := oauth.GenerateTotpCode(first, time.Now())
+	require.NoError(t, err)
+
+	recoveryCodes, err := oauth.ConfirmTotp(ctx, identityId, code)
+	require.NoError(t, err)
+	require.Nil(t, recoveryCodes)
+}
+
+func TestEnrolTotp_ErrorWhenAlreadyConfirmed(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	identityId := ksuid.New().String()
+	enrolAndConfirmTotp(t, ctx, identityId)
+
+	_, err := oauth.EnrolTotp(ctx, identityId)
+	require.ErrorIs(t, err, oauth.ErrMfaAlreadyEnrolled)
+}
+
+func TestConfirmTotp_IssuesRecoveryCodes(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	identityId := ksuid.New().String()
+	_, recoveryCodes := enrolAndConfirmTotp(t, ctx, identityId)
+
+	for _, c := range recoveryCodes {
+		require.Regexp(t, regexp.MustCompile(`^[a-z2-9]{5}-[a-z2-9]{5}$`), c)
+	}
+
+	hasMfa, err := oauth.HasMfa(ctx, identityId)
+	require.NoError(t, err)
+	require.True(t, hasMfa)
+
+	var stored []map[string]any
+	database.GetDB().Raw("SELECT * FROM keel_mfa_recovery_code").Scan(&stored)
+	require.Len(t, stored, 10)
+	for _, s := range stored {
+		require.NotContains(t, recoveryCodes, s["code"])
+	}
+}
+
+func TestConfirmTotp_IncorrectCode(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	identityId := ksuid.New().String()
+
+	_, err := oauth.EnrolTotp(ctx, identityId)
+	require.NoError(t, err)
+
+	recoveryCodes, err := oauth.ConfirmTotp(ctx, identityId, "000000")
+	require.NoError(t, err)
+	require.Nil(t, recoveryCodes)
+
+	hasMfa, err := oauth.HasMfa(ctx, identityId)
+	require.NoError(t, err)
+	require.False(t, hasMfa)
+}
+
+func TestVerifyMfaCode_CodeCannotBeReplayed(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	identityId := ksuid.New().String()
+	secret, _ := enrolAndConfirmTotp(t, ctx, identityId)
+
+	// The code was already used to confirm the authenticator
+	code, err := oauth.GenerateTotpCode(secret, time.Now())
+	require.NoError(t, err)
+
+	isValid, err := oauth.VerifyMfaCode(ctx, identityId, code)
+	require.NoError(t, err)
+	require.False(t, isValid)
+
+	// The next code is accepted to allow for clock drift, but only once
+	code, err = oauth.GenerateTotpCode(secret, time.Now().Add(30*time.Second))
+	require.NoError(t, err)
+
+	isValid, err = oauth.VerifyMfaCode(ctx, identityId, code)
+	require.NoError(t, err)
+	require.True(t, isValid)
+
+	isValid, err = oauth.VerifyMfaCode(ctx, identityId, code)
+	require.NoError(t, err)
+	require.False(t, isValid)
+}
+
+func TestVerifyMfaCode_LockedAfterFailedAttempts(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	identityId := ksuid.New().String()
+	secret, recoveryCodes := enrolAndConfirmTotp(t, ctx, identityId)
+
+	for i := 0; i < 5; i++ {
+		isValid, err := oauth.VerifyMfaCode(ctx, identityId, "abcdef")
+		require.NoError(t, err)
+		require.False(t, isValid)
+	}
+
+	code, err := oauth.GenerateTotpCode(secret, time.Now().Add(30*time.Second))
+	require.NoError(t, err)
+
+	isValid, err := oauth.VerifyMfaCode(ctx, identityId, code)
+	require.NoError(t, err)
+	require.False(t, isValid)
+
+	// A recovery code resets the failed attempts
+	isValid, err = oauth.ConsumeRecoveryCode(ctx, identityId, recoveryCodes[0])
+	require.NoError(t, err)
+	require.True(t, isValid)
+
+	isValid, err = oauth.VerifyMfaCode(ctx, identityId, code)
+	require.NoError(t, err)
+	require.True(t, isValid)
+}
+
+func TestVerifyMfaCode_ConcurrentAttemptsCannotExceedMaximum(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	identityId := ksuid.New().String()
+	enrolAndConfirmTotp(t, ctx, identityId)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			isValid, err := oauth.VerifyMfaCode(ctx, identityId, "abcdef")
+			require.NoError(t, err)
+			require.False(t, isValid)
+		}()
+	}
+	wg.Wait()
+
+	var failedAttempts int
+	err := database.GetDB().Raw("SELECT failed_attempts FROM keel_mfa_factor WHERE identity_id = ?", identityId).Scan(&failedAttempts).Error
+	require.NoError(t, err)
+	require.Equal(t, 5, failedAttempts)
+}
+
+func TestConsumeRecoveryCode_SingleUse(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	identityId := ksuid.New().String()
+	_, recoveryCodes := enrolAndConfirmTotp(t, ctx, identityId)
+
+	isValid, err := oauth.ConsumeRecoveryCode(ctx, identityId, recoveryCodes[0])
+	require.NoError(t, err)
+	require.True(t, isValid)
+
+	isValid, err = oauth.ConsumeRecoveryCode(ctx, identityId, recoveryCodes[0])
+	require.NoError(t, err)
+	require.False(t, isValid)
+}
+
+func TestConsumeRecoveryCode_IgnoresFormatting(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	identityId := ksuid.New().String()
+	_, recoveryCodes := enrolAndConfirmTotp(t, ctx, identityId)
+
+	isValid, err := oauth.ConsumeRecoveryCode(ctx, identityId, " "+strings.ToUpper(strings.ReplaceAll(recoveryCodes[1], "-", ""))+" ")
+	require.NoError(t, err)
+	require.True(t, isValid)
+}
+
+func TestConsumeRecoveryCode_OtherIdentity(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	_, recoveryCodes := enrolAndConfirmTotp(t, ctx, ksuid.New().String())
+
+	isValid, err := oauth.ConsumeRecoveryCode(ctx, ksuid.New().String(), recoveryCodes[0])
+	require.NoError(t, err)
+	require.False(t, isValid)
+}
+
+func TestRemoveMfa(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	identityId := ksuid.New().String()
+	_, recoveryCodes := enrolAndConfirmTotp(t, ctx, identityId)
+
+	err := oauth.RemoveMfa(ctx, identityId)
+	require.NoError(t, err)
+
+	hasMfa, err := oauth.HasMfa(ctx, identityId)
+	require.NoError(t, err)
+	require.False(t, hasMfa)
+
+	isValid, err := oauth.ConsumeRecoveryCode(ctx, identityId, recoveryCodes[0])
+	require.NoError(t, err)
+	require.False(t, isValid)
+}
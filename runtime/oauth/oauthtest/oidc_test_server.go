@@ -218,6 +218,9 @@ func NewServer() (*OidcServer, error) {
 
 			values.Add("iss", oidcServer.Issuer)
 			values.Add("code", uniuri.NewLen(10))
+			if state := r.URL.Query().Get("state"); state != "" {
+				values.Add("state", state)
+			}
 			redirectUrl.RawQuery = values.Encode()
 
 			// If the end-user denies the login request or if the request fails for reasons other than an
@@ -0,0 +1,233 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/dchest/uniuri"
+	"github.com/teamkeel/keel/db"
+)
+
+type PasswordlessCodeType string
+
+const (
+	// A long code which is embedded in a link sent by email.
+	PasswordlessMagicLink PasswordlessCodeType = "magic_link"
+	// A short numeric code which the user enters manually.
+	PasswordlessOneTimeCode PasswordlessCodeType = "otp"
+)
+
+const (
+	// Character length of crypo-generated magic link code
+	magicLinkCodeLength = 32
+	// Number of digits in a one-time code
+	oneTimeCodeDigits = 6
+	// How long a passwordless code can be used for
+	passwordlessCodeExpiry = time.Duration(10) * time.Minute
+	// Number of incorrect attempts, across all codes issued for an email in the window, after which
+	// the outstanding codes for the email are discarded and no further codes are accepted
+	passwordlessCodeMaxAttempts = 5
+	// Number of codes which can be issued for an email in the window
+	passwordlessCodeMaxIssued = 5
+	// The period over which codes issued and incorrect attempts are counted for an email
+	passwordlessCodeWindow = time.Duration(1) * time.Hour
+)
+
+// ErrTooManyPasswordlessCodes is returned when too many codes have been issued for an email address,
+// or too many incorrect attempts have been made, within the window.
+var ErrTooManyPasswordlessCodes = errors.New("too many passwordless codes have been requested for this email address")
+
+// NewPasswordlessCode generates a new single-use code which can be exchanged for tokens by
+// whoever receives it at the given email address. Any codes previously issued for
+// the email address are discarded, so that only the most recent code can be used. Incorrect attempts
+// are counted against the email address rather than the code, so issuing a new code does not
+// reset them, and only a limited number of codes can be issued for the email in each window.
+func NewPasswordlessCode(ctx context.Context, email string, codeType PasswordlessCodeType) (string, error) {
+	ctx, span := tracer.Start(ctx, "New Passwordless Code")
+	defer span.End()
+
+	if email == "" {
+		return "", errors.New("email cannot be empty when generating new passwordless code")
+	}
+
+	var code string
+	switch codeType {
+	case PasswordlessMagicLink:
+		code = uniuri.NewLen(magicLinkCodeLength)
+	case PasswordlessOneTimeCode:
+		n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+		if err != nil {
+			return "", err
+		}
+		code = fmt.Sprintf("%0*d", oneTimeCodeDigits, n.Int64())
+	default:
+		return "", fmt.Errorf("unknown passwordless code type: %s", codeType)
+	}
+
+	hash, err := hashToken(code)
+	if err != nil {
+		return "", err
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(passwordlessCodeExpiry)
+
+	// Atomically count the code against the email, starting a new window if the previous one has passed
+	sql := `
+		INSERT INTO
+			keel_passwordless_attempt (email, issued, failures, window_started_at)
+		VALUES
+			(?, 1, 0, ?)
+		ON CONFLICT (email) DO UPDATE SET
+			issued = CASE WHEN keel_passwordless_attempt.window_started_at < ? THEN 1 ELSE keel_passwordless_attempt.issued + 1 END,
+			failures = CASE WHEN keel_passwordless_attempt.window_started_at < ? THEN 0 ELSE keel_passwordless_attempt.failures END,
+			window_started_at = CASE WHEN keel_passwordless_attempt.window_started_at < ? THEN EXCLUDED.window_started_at ELSE keel_passwordless_attempt.window_started_at END
+		RETURNING
+			issued, failures`
+
+	windowStart := now.Add(-passwordlessCodeWindow)
+	counts := []*passwordlessAttemptRow{}
+	err = database.GetDB().Raw(sql, email, now, windowStart, windowStart, windowStart).Scan(&counts).Error
+	if err != nil {
+		return "", err
+	}
+
+	if len(counts) != 1 || counts[0].Issued > passwordlessCodeMaxIssued || counts[0].Failures >= passwordlessCodeMaxAttempts {
+		return "", ErrTooManyPasswordlessCodes
+	}
+
+	sql = `
+		DELETE FROM
+			keel_passwordless_code
+		WHERE
+			email = ?`
+
+	err = database.GetDB().Exec(sql, email).Error
+	if err != nil {
+		return "", err
+	}
+
+	sql = `
+		INSERT INTO
+			keel_passwordless_code (code, email, expires_at, created_at)
+		VALUES
+			(?, ?, ?, ?)`
+
+	db := database.GetDB().Exec(sql, hash, email, expiresAt, now)
+	if db.Error != nil {
+		return "", db.Error
+	}
+
+	if db.RowsAffected != 1 {
+		return "", errors.New("failed to insert passwordless code into database")
+	}
+
+	return code, nil
+}
+
+// ConsumePasswordlessCode checks that the code was issued for the email address and has not expired,
+// and consumes it so that it cannot be used again. Each incorrect attempt is counted against the
+// email address, and its outstanding code is discarded once too many incorrect attempts have been made.
+func ConsumePasswordlessCode(ctx context.Context, email string, code string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "Consume Passwordless Code")
+	defer span.End()
+
+	if email == "" || code == "" {
+		return false, nil
+	}
+
+	codeHash, err := hashToken(code)
+	if err != nil {
+		return false, err
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// Reserve an attempt before checking the code, so that concurrent attempts cannot exceed the maximum
+	sql := `
+		UPDATE
+			keel_passwordless_attempt
+		SET
+			failures = failures + 1
+		WHERE
+			email = ? AND
+			failures < ?
+		RETURNING
+			issued, failures`
+
+	counts := []*passwordlessAttemptRow{}
+	err = database.GetDB().Raw(sql, email, passwordlessCodeMaxAttempts).Scan(&counts).Error
+	if err != nil {
+		return false, err
+	}
+
+	if len(counts) != 1 {
+		return false, nil
+	}
+
+	sql = `
+		DELETE FROM
+			keel_passwordless_code
+		WHERE
+			email = ? AND
+			code = ? AND
+			expires_at >= now()
+		RETURNING
+			code`
+
+	rows := []map[string]any{}
+	err = database.GetDB().Raw(sql, email, codeHash).Scan(&rows).Error
+	if err != nil {
+		return false, err
+	}
+
+	if len(rows) == 1 {
+		// The code was correct, so the attempts for the email start over
+		sql = `
+			DELETE FROM
+				keel_passwordless_attempt
+			WHERE
+				email = ?`
+
+		err = database.GetDB().Exec(sql, email).Error
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	if counts[0].Failures < passwordlessCodeMaxAttempts {
+		return false, nil
+	}
+
+	sql = `
+		DELETE FROM
+			keel_passwordless_code
+		WHERE
+			email = ?`
+
+	err = database.GetDB().Exec(sql, email).Error
+	if err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+type passwordlessAttemptRow struct {
+	Issued   int
+	Failures int
+}
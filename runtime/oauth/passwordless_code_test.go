@@ -0,0 +1,185 @@
+package oauth_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/runtime/oauth"
+	keeltesting "github.com/teamkeel/keel/testing"
+)
+
+func TestNewPasswordlessCode_OneTimeCodeIsSixDigits(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	code, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessOneTimeCode)
+	require.NoError(t, err)
+	require.Regexp(t, regexp.MustCompile(`^\d{6}$`), code)
+}
+
+func TestNewPasswordlessCode_MagicLinkCode(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	code, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessMagicLink)
+	require.NoError(t, err)
+	require.Len(t, code, 32)
+}
+
+func TestNewPasswordlessCode_ErrorOnEmptyEmail(t *testing.T) {
+	_, err := oauth.NewPasswordlessCode(context.Background(), "", oauth.PasswordlessOneTimeCode)
+	require.Error(t, err)
+}
+
+func TestNewPasswordlessCode_StoredHashed(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	code, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessMagicLink)
+	require.NoError(t, err)
+
+	var codes []map[string]any
+	database.GetDB().Raw("SELECT * FROM keel_passwordless_code").Scan(&codes)
+	require.Len(t, codes, 1)
+	require.NotEqual(t, code, codes[0]["code"])
+}
+
+func TestConsumePasswordlessCode_Success(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	code, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessOneTimeCode)
+	require.NoError(t, err)
+
+	isValid, err := oauth.ConsumePasswordlessCode(ctx, "keelson@keel.so", code)
+	require.NoError(t, err)
+	require.True(t, isValid)
+}
+
+func TestConsumePasswordlessCode_SingleUse(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	code, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessOneTimeCode)
+	require.NoError(t, err)
+
+	isValid, err := oauth.ConsumePasswordlessCode(ctx, "keelson@keel.so", code)
+	require.NoError(t, err)
+	require.True(t, isValid)
+
+	isValid, err = oauth.ConsumePasswordlessCode(ctx, "keelson@keel.so", code)
+	require.NoError(t, err)
+	require.False(t, isValid)
+}
+
+func TestConsumePasswordlessCode_WrongEmail(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	code, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessOneTimeCode)
+	require.NoError(t, err)
+
+	isValid, err := oauth.ConsumePasswordlessCode(ctx, "weaveton@keel.so", code)
+	require.NoError(t, err)
+	require.False(t, isValid)
+}
+
+func TestConsumePasswordlessCode_PreviousCodeDiscarded(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	first, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessMagicLink)
+	require.NoError(t, err)
+
+	second, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessMagicLink)
+	require.NoError(t, err)
+
+	isValid, err := oauth.ConsumePasswordlessCode(ctx, "keelson@keel.so", first)
+	require.NoError(t, err)
+	require.False(t, isValid)
+
+	isValid, err = oauth.ConsumePasswordlessCode(ctx, "keelson@keel.so", second)
+	require.NoError(t, err)
+	require.True(t, isValid)
+}
+
+func TestConsumePasswordlessCode_Expired(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	code, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessOneTimeCode)
+	require.NoError(t, err)
+
+	database.GetDB().Exec("UPDATE keel_passwordless_code SET expires_at = now() - interval '1 second'")
+
+	isValid, err := oauth.ConsumePasswordlessCode(ctx, "keelson@keel.so", code)
+	require.NoError(t, err)
+	require.False(t, isValid)
+}
+
+func TestConsumePasswordlessCode_DiscardedAfterTooManyAttempts(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	code, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessOneTimeCode)
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		isValid, err := oauth.ConsumePasswordlessCode(ctx, "keelson@keel.so", "wrong")
+		require.NoError(t, err)
+		require.False(t, isValid)
+	}
+
+	isValid, err := oauth.ConsumePasswordlessCode(ctx, "keelson@keel.so", code)
+	require.NoError(t, err)
+	require.False(t, isValid)
+}
+
+func TestConsumePasswordlessCode_AttemptsKeptAcrossNewCodes(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	_, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessOneTimeCode)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		isValid, err := oauth.ConsumePasswordlessCode(ctx, "keelson@keel.so", "wrong")
+		require.NoError(t, err)
+		require.False(t, isValid)
+	}
+
+	code, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessOneTimeCode)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		isValid, err := oauth.ConsumePasswordlessCode(ctx, "keelson@keel.so", "wrong")
+		require.NoError(t, err)
+		require.False(t, isValid)
+	}
+
+	isValid, err := oauth.ConsumePasswordlessCode(ctx, "keelson@keel.so", code)
+	require.NoError(t, err)
+	require.False(t, isValid)
+
+	_, err = oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessOneTimeCode)
+	require.True(t, errors.Is(err, oauth.ErrTooManyPasswordlessCodes))
+}
+
+func TestNewPasswordlessCode_TooManyIssued(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	for i := 0; i < 5; i++ {
+		_, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessOneTimeCode)
+		require.NoError(t, err)
+	}
+
+	_, err := oauth.NewPasswordlessCode(ctx, "keelson@keel.so", oauth.PasswordlessOneTimeCode)
+	require.True(t, errors.Is(err, oauth.ErrTooManyPasswordlessCodes))
+
+	_, err = oauth.NewPasswordlessCode(ctx, "weaveton@keel.so", oauth.PasswordlessOneTimeCode)
+	require.NoError(t, err)
+}
@@ -0,0 +1,70 @@
+package oauth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"regexp"
+)
+
+// Proof Key for Code Exchange (PKCE) code challenge methods
+// https://datatracker.ietf.org/doc/html/rfc7636#section-4.2
+const (
+	CodeChallengeMethodPlain = "plain"
+	CodeChallengeMethodS256  = "S256"
+)
+
+// A code verifier (and a plain code challenge) is a high-entropy cryptographic random string
+// using the unreserved characters [A-Z] / [a-z] / [0-9] / "-" / "." / "_" / "~", with a
+// minimum length of 43 characters and a maximum length of 128 characters.
+// https://datatracker.ietf.org/doc/html/rfc7636#section-4.1
+var codeVerifierRegex = regexp.MustCompile(`^[A-Za-z0-9\-._~]{43,128}$`)
+
+// CodeChallenge is the PKCE code challenge provided by the client when starting the authorization flow.
+// It is stored alongside the auth code and verified against the code verifier when the auth code is exchanged.
+type CodeChallenge struct {
+	Challenge string
+	Method    string
+}
+
+// NewCodeChallenge validates and creates a code challenge. If no method is provided, then "plain" is assumed.
+func NewCodeChallenge(challenge string, method string) (*CodeChallenge, error) {
+	if method == "" {
+		method = CodeChallengeMethodPlain
+	}
+
+	if method != CodeChallengeMethodPlain && method != CodeChallengeMethodS256 {
+		return nil, errors.New("code_challenge_method must be either 'S256' or 'plain'")
+	}
+
+	if !codeVerifierRegex.MatchString(challenge) {
+		return nil, errors.New("code_challenge must be between 43 and 128 characters and only contain unreserved characters")
+	}
+
+	return &CodeChallenge{
+		Challenge: challenge,
+		Method:    method,
+	}, nil
+}
+
+// Verify checks that the code verifier matches the code challenge.
+// https://datatracker.ietf.org/doc/html/rfc7636#section-4.6
+func (c *CodeChallenge) Verify(codeVerifier string) bool {
+	if !codeVerifierRegex.MatchString(codeVerifier) {
+		return false
+	}
+
+	var expected string
+	switch c.Method {
+	case CodeChallengeMethodPlain:
+		expected = codeVerifier
+	case CodeChallengeMethodS256:
+		hash := sha256.Sum256([]byte(codeVerifier))
+		expected = base64.RawURLEncoding.EncodeToString(hash[:])
+	default:
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(c.Challenge)) == 1
+}
@@ -0,0 +1,58 @@
+package oauth_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/runtime/oauth"
+)
+
+// The S256 code challenge is BASE64URL-ENCODE(SHA256(ASCII(code_verifier)))
+const (
+	testCodeVerifier  = "dBjftJeZ4CVP-mJ92ZrZS4MnrwCODc5YBdQhIoFHBvs"
+	testCodeChallenge = "L3JWHu2qjlyvH1eTl9tFlqc9TKbp9KTPl3d3MW55ZLM"
+)
+
+func TestCodeChallenge_S256Valid(t *testing.T) {
+	challenge, err := oauth.NewCodeChallenge(testCodeChallenge, oauth.CodeChallengeMethodS256)
+	require.NoError(t, err)
+	require.True(t, challenge.Verify(testCodeVerifier))
+}
+
+func TestCodeChallenge_S256Invalid(t *testing.T) {
+	challenge, err := oauth.NewCodeChallenge(testCodeChallenge, oauth.CodeChallengeMethodS256)
+	require.NoError(t, err)
+	require.False(t, challenge.Verify(strings.Repeat("a", 43)))
+	require.False(t, challenge.Verify(testCodeChallenge))
+	require.False(t, challenge.Verify(""))
+}
+
+func TestCodeChallenge_PlainValid(t *testing.T) {
+	challenge, err := oauth.NewCodeChallenge(testCodeVerifier, oauth.CodeChallengeMethodPlain)
+	require.NoError(t, err)
+	require.True(t, challenge.Verify(testCodeVerifier))
+	require.False(t, challenge.Verify(testCodeChallenge))
+}
+
+func TestCodeChallenge_DefaultsToPlain(t *testing.T) {
+	challenge, err := oauth.NewCodeChallenge(testCodeVerifier, "")
+	require.NoError(t, err)
+	require.Equal(t, oauth.CodeChallengeMethodPlain, challenge.Method)
+}
+
+func TestCodeChallenge_UnsupportedMethod(t *testing.T) {
+	_, err := oauth.NewCodeChallenge(testCodeChallenge, "S512")
+	require.ErrorContains(t, err, "code_challenge_method must be either 'S256' or 'plain'")
+}
+
+func TestCodeChallenge_InvalidChallenge(t *testing.T) {
+	_, err := oauth.NewCodeChallenge("tooshort", oauth.CodeChallengeMethodS256)
+	require.ErrorContains(t, err, "code_challenge must be between 43 and 128 characters")
+
+	_, err = oauth.NewCodeChallenge(strings.Repeat("a", 129), oauth.CodeChallengeMethodS256)
+	require.ErrorContains(t, err, "code_challenge must be between 43 and 128 characters")
+
+	_, err = oauth.NewCodeChallenge(strings.Repeat("a", 42)+"!", oauth.CodeChallengeMethodS256)
+	require.ErrorContains(t, err, "code_challenge must be between 43 and 128 characters")
+}
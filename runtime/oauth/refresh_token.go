@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/dchest/uniuri"
+	"github.com/segmentio/ksuid"
 	"github.com/teamkeel/keel/db"
 	"github.com/teamkeel/keel/runtime/runtimectx"
 	"golang.org/x/crypto/sha3"
@@ -20,6 +21,17 @@ const (
 // NewRefreshToken generates a new refresh token for the identity using the
 // configured or default expiry time.
 func NewRefreshToken(ctx context.Context, identityId string) (string, error) {
+	return newRefreshToken(ctx, identityId, false)
+}
+
+// NewMfaRefreshToken generates a new refresh token for an identity which has authenticated
+// with a second factor. Access tokens issued using this refresh token will also be marked
+// as having used multi-factor authentication.
+func NewMfaRefreshToken(ctx context.Context, identityId string) (string, error) {
+	return newRefreshToken(ctx, identityId, true)
+}
+
+func newRefreshToken(ctx context.Context, identityId string, mfa bool) (string, error) {
 	ctx, span := tracer.Start(ctx, "New Refresh Token")
 	defer span.End()
 
@@ -45,14 +57,15 @@ func NewRefreshToken(ctx context.Context, identityId string) (string, error) {
 
 	now := time.Now().UTC()
 	expiresAt := now.Add(config.RefreshTokenExpiry())
+	userAgent, ipAddress := sessionInfo(ctx)
 
 	sql := `
 		INSERT INTO 
-			keel_refresh_token (token, identity_id, expires_at, created_at) 
+			keel_refresh_token (token, session_id, identity_id, expires_at, created_at, last_used_at, mfa, user_agent, ip_address) 
 		VALUES 
-			(?, ?, ?, ?)`
+			(?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	db := database.GetDB().Exec(sql, hash, identityId, expiresAt, now)
+	db := database.GetDB().Exec(sql, hash, ksuid.New().String(), identityId, expiresAt, now, now, mfa, userAgent, ipAddress)
 	if db.Error != nil {
 		return "", db.Error
 	}
@@ -87,9 +100,12 @@ func RotateRefreshToken(ctx context.Context, refreshTokenRaw string) (isValid bo
 		return false, "", "", err
 	}
 
+	userAgent, ipAddress := sessionInfo(ctx)
+
 	// This query has the following (important) characteristics:
 	//  - find and delete the refresh token
-	//  - create a new refresh token with the identity_id and expire_at of the original token
+	//  - create a new refresh token with the session_id, identity_id, expire_at, created_at and mfa of the original token
+	//  - records when and from where the session was last used
 	//  - only creates the new token if the original token had not expired
 	sql := `
 		WITH revoked_token AS (
@@ -99,9 +115,9 @@ func RotateRefreshToken(ctx context.Context, refreshTokenRaw string) (isValid bo
 				token = ?
 			RETURNING *)
 		INSERT INTO 
-			keel_refresh_token (token, identity_id, expires_at, created_at) 
+			keel_refresh_token (token, session_id, identity_id, expires_at, created_at, last_used_at, mfa, user_agent, ip_address) 
 		SELECT
-			?, identity_id, expires_at, now()
+			?, session_id, identity_id, expires_at, created_at, now(), mfa, COALESCE(NULLIF(?, ''), user_agent), COALESCE(NULLIF(?, ''), ip_address)
 		FROM 
 			revoked_token
 		WHERE
@@ -109,7 +125,7 @@ func RotateRefreshToken(ctx context.Context, refreshTokenRaw string) (isValid bo
 		RETURNING *`
 
 	rows := []map[string]any{}
-	err = database.GetDB().Raw(sql, tokenHash, newTokenHash).Scan(&rows).Error
+	err = database.GetDB().Raw(sql, tokenHash, newTokenHash, userAgent, ipAddress).Scan(&rows).Error
 	if err != nil {
 		return false, "", "", err
 	}
@@ -128,7 +144,8 @@ func RotateRefreshToken(ctx context.Context, refreshTokenRaw string) (isValid bo
 }
 
 // ValidateRefreshToken validates that the provided refresh token has no expired,
-// and also returns the identity it is associated with. The refresh token is not revoked.
+// and also returns the identity it is associated with. The refresh token is not revoked,
+// but its session is marked as having been used.
 func ValidateRefreshToken(ctx context.Context, refreshTokenRaw string) (isValid bool, identityId string, err error) {
 	ctx, span := tracer.Start(ctx, "Validate Refresh Token")
 	defer span.End()
@@ -143,17 +160,23 @@ func ValidateRefreshToken(ctx context.Context, refreshTokenRaw string) (isValid
 		return false, "", err
 	}
 
+	userAgent, ipAddress := sessionInfo(ctx)
+
 	sql := `
-		SELECT
-			token, identity_id, expires_at, now()
-		FROM 
+		UPDATE 
 			keel_refresh_token
+		SET
+			last_used_at = now(),
+			user_agent = COALESCE(NULLIF(?, ''), user_agent),
+			ip_address = COALESCE(NULLIF(?, ''), ip_address)
 		WHERE 
 			token = ? AND
-			expires_at >= now()`
+			expires_at >= now()
+		RETURNING
+			token, identity_id, expires_at`
 
 	rows := []map[string]any{}
-	err = database.GetDB().Raw(sql, tokenHash).Scan(&rows).Error
+	err = database.GetDB().Raw(sql, userAgent, ipAddress, tokenHash).Scan(&rows).Error
 	if err != nil {
 		return false, "", err
 	}
@@ -171,6 +194,41 @@ func ValidateRefreshToken(ctx context.Context, refreshTokenRaw string) (isValid
 	return true, identityId, nil
 }
 
+// IsMfaRefreshToken determines if the refresh token was issued to an identity which
+// authenticated with a second factor.
+func IsMfaRefreshToken(ctx context.Context, refreshTokenRaw string) (bool, error) {
+	tokenHash, err := hashToken(refreshTokenRaw)
+	if err != nil {
+		return false, err
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	sql := `
+		SELECT
+			mfa
+		FROM 
+			keel_refresh_token
+		WHERE 
+			token = ?`
+
+	rows := []map[string]any{}
+	err = database.GetDB().Raw(sql, tokenHash).Scan(&rows).Error
+	if err != nil {
+		return false, err
+	}
+
+	if len(rows) != 1 {
+		return false, nil
+	}
+
+	mfa, _ := rows[0]["mfa"].(bool)
+	return mfa, nil
+}
+
 // RevokeRefreshToken will delete (revoke) the provided refresh token,
 // which will prevent it from being used again.
 func RevokeRefreshToken(ctx context.Context, refreshTokenRaw string) error {
@@ -0,0 +1,224 @@
+package oauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dchest/uniuri"
+	"github.com/teamkeel/keel/db"
+)
+
+const (
+	// Character length of crypo-generated service client id
+	clientIdLength = 24
+	// Character length of crypo-generated service client secret
+	clientSecretLength = 64
+)
+
+// ServiceClient is a machine-to-machine client which authenticates
+// using the client credentials grant with its client id and secret.
+type ServiceClient struct {
+	ClientId  string
+	Name      string
+	Roles     []string
+	CreatedAt time.Time
+}
+
+type serviceClientRow struct {
+	ClientId  string
+	Name      string
+	Secret    string
+	Roles     string
+	CreatedAt time.Time
+}
+
+func (r *serviceClientRow) toServiceClient() *ServiceClient {
+	roles := []string{}
+	if r.Roles != "" {
+		roles = strings.Split(r.Roles, ",")
+	}
+
+	return &ServiceClient{
+		ClientId:  r.ClientId,
+		Name:      r.Name,
+		Roles:     roles,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+// NewServiceClient creates a new service client which can be granted the provided roles.
+// The client secret is returned only once and only its hash is stored.
+func NewServiceClient(ctx context.Context, name string, roles []string) (*ServiceClient, string, error) {
+	ctx, span := tracer.Start(ctx, "New Service Client")
+	defer span.End()
+
+	if name == "" {
+		return nil, "", errors.New("service client name cannot be empty")
+	}
+
+	for _, role := range roles {
+		if role == "" || strings.ContainsAny(role, ", ") {
+			return nil, "", errors.New("service client role names cannot be empty or contain commas or spaces")
+		}
+	}
+
+	clientId := uniuri.NewLen(clientIdLength)
+	secret := uniuri.NewLen(clientSecretLength)
+	hash, err := hashToken(secret)
+	if err != nil {
+		return nil, "", err
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now().UTC()
+
+	sql := `
+		INSERT INTO
+			keel_service_client (client_id, name, secret, roles, created_at)
+		VALUES
+			(?, ?, ?, string_to_array(?, ','), ?)`
+
+	db := database.GetDB().Exec(sql, clientId, name, hash, strings.Join(roles, ","), now)
+	if db.Error != nil {
+		return nil, "", db.Error
+	}
+
+	if db.RowsAffected != 1 {
+		return nil, "", errors.New("failed to insert service client into database")
+	}
+
+	client := &ServiceClient{
+		ClientId:  clientId,
+		Name:      name,
+		Roles:     roles,
+		CreatedAt: now,
+	}
+
+	return client, secret, nil
+}
+
+// AuthenticateServiceClient verifies the client credentials and returns the service client.
+// If the client does not exist or the secret is incorrect, then nil is returned.
+func AuthenticateServiceClient(ctx context.Context, clientId string, secret string) (*ServiceClient, error) {
+	ctx, span := tracer.Start(ctx, "Authenticate Service Client")
+	defer span.End()
+
+	row, err := findServiceClient(ctx, clientId)
+	if err != nil {
+		return nil, err
+	}
+
+	if row == nil {
+		return nil, nil
+	}
+
+	hash, err := hashToken(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(row.Secret)) != 1 {
+		return nil, nil
+	}
+
+	return row.toServiceClient(), nil
+}
+
+// FindServiceClient returns the service client with the given client id, or nil if it does not exist.
+func FindServiceClient(ctx context.Context, clientId string) (*ServiceClient, error) {
+	row, err := findServiceClient(ctx, clientId)
+	if err != nil || row == nil {
+		return nil, err
+	}
+
+	return row.toServiceClient(), nil
+}
+
+// ListServiceClients returns all service clients ordered by when they were created.
+func ListServiceClients(ctx context.Context) ([]*ServiceClient, error) {
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := `
+		SELECT
+			client_id, name, secret, array_to_string(roles, ',') AS roles, created_at
+		FROM
+			keel_service_client
+		ORDER BY
+			created_at`
+
+	rows := []*serviceClientRow{}
+	err = database.GetDB().Raw(sql).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	clients := make([]*ServiceClient, len(rows))
+	for i, row := range rows {
+		clients[i] = row.toServiceClient()
+	}
+
+	return clients, nil
+}
+
+// DeleteServiceClient deletes the service client, which will prevent its credentials from being used again.
+// Access tokens already issued to the client will no longer be accepted.
+func DeleteServiceClient(ctx context.Context, clientId string) (bool, error) {
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	sql := `
+		DELETE FROM
+			keel_service_client
+		WHERE
+			client_id = ?`
+
+	db := database.GetDB().Exec(sql, clientId)
+	if db.Error != nil {
+		return false, db.Error
+	}
+
+	return db.RowsAffected == 1, nil
+}
+
+func findServiceClient(ctx context.Context, clientId string) (*serviceClientRow, error) {
+	if clientId == "" {
+		return nil, nil
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := `
+		SELECT
+			client_id, name, secret, array_to_string(roles, ',') AS roles, created_at
+		FROM
+			keel_service_client
+		WHERE
+			client_id = ?`
+
+	rows := []*serviceClientRow{}
+	err = database.GetDB().Raw(sql, clientId).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) != 1 {
+		return nil, nil
+	}
+
+	return rows[0], nil
+}
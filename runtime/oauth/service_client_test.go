@@ -0,0 +1,115 @@
+package oauth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/runtime/oauth"
+	keeltesting "github.com/teamkeel/keel/testing"
+)
+
+func TestNewServiceClient(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	client, secret, err := oauth.NewServiceClient(ctx, "billing", []string{"Admin", "Staff"})
+	require.NoError(t, err)
+	require.NotEmpty(t, client.ClientId)
+	require.NotEmpty(t, secret)
+	require.Equal(t, "billing", client.Name)
+	require.Equal(t, []string{"Admin", "Staff"}, client.Roles)
+}
+
+func TestNewServiceClient_ErrorOnEmptyName(t *testing.T) {
+	ctx := context.Background()
+
+	_, _, err := oauth.NewServiceClient(ctx, "", []string{})
+	require.Error(t, err)
+}
+
+func TestAuthenticateServiceClient_Valid(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	created, secret, err := oauth.NewServiceClient(ctx, "billing", []string{"Admin"})
+	require.NoError(t, err)
+
+	client, err := oauth.AuthenticateServiceClient(ctx, created.ClientId, secret)
+	require.NoError(t, err)
+	require.NotNil(t, client)
+	require.Equal(t, created.ClientId, client.ClientId)
+	require.Equal(t, "billing", client.Name)
+	require.Equal(t, []string{"Admin"}, client.Roles)
+}
+
+func TestAuthenticateServiceClient_NoRoles(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	created, secret, err := oauth.NewServiceClient(ctx, "billing", []string{})
+	require.NoError(t, err)
+
+	client, err := oauth.AuthenticateServiceClient(ctx, created.ClientId, secret)
+	require.NoError(t, err)
+	require.NotNil(t, client)
+	require.Empty(t, client.Roles)
+}
+
+func TestAuthenticateServiceClient_IncorrectSecret(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	created, _, err := oauth.NewServiceClient(ctx, "billing", []string{"Admin"})
+	require.NoError(t, err)
+
+	client, err := oauth.AuthenticateServiceClient(ctx, created.ClientId, "incorrect")
+	require.NoError(t, err)
+	require.Nil(t, client)
+}
+
+func TestAuthenticateServiceClient_UnknownClient(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	client, err := oauth.AuthenticateServiceClient(ctx, "unknown", "secret")
+	require.NoError(t, err)
+	require.Nil(t, client)
+}
+
+func TestListServiceClients(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	_, _, err := oauth.NewServiceClient(ctx, "billing", []string{"Admin"})
+	require.NoError(t, err)
+
+	_, _, err = oauth.NewServiceClient(ctx, "reporting", []string{})
+	require.NoError(t, err)
+
+	clients, err := oauth.ListServiceClients(ctx)
+	require.NoError(t, err)
+	require.Len(t, clients, 2)
+	require.Equal(t, "billing", clients[0].Name)
+	require.Equal(t, "reporting", clients[1].Name)
+}
+
+func TestDeleteServiceClient(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	created, secret, err := oauth.NewServiceClient(ctx, "billing", []string{"Admin"})
+	require.NoError(t, err)
+
+	deleted, err := oauth.DeleteServiceClient(ctx, created.ClientId)
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	client, err := oauth.AuthenticateServiceClient(ctx, created.ClientId, secret)
+	require.NoError(t, err)
+	require.Nil(t, client)
+
+	deleted, err = oauth.DeleteServiceClient(ctx, created.ClientId)
+	require.NoError(t, err)
+	require.False(t, deleted)
+}
@@ -0,0 +1,175 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/teamkeel/keel/db"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+)
+
+// Session is a signed in session for an identity, which is represented by its refresh token.
+// The session is retained when its refresh token is rotated.
+type Session struct {
+	Id         string
+	IdentityId string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	ExpiresAt  time.Time
+	UserAgent  string
+	IpAddress  string
+	Mfa        bool
+}
+
+type sessionRow struct {
+	SessionId  string
+	IdentityId string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	ExpiresAt  time.Time
+	UserAgent  *string
+	IpAddress  *string
+	Mfa        bool
+}
+
+func (r *sessionRow) toSession() *Session {
+	session := &Session{
+		Id:         r.SessionId,
+		IdentityId: r.IdentityId,
+		CreatedAt:  r.CreatedAt,
+		LastUsedAt: r.LastUsedAt,
+		ExpiresAt:  r.ExpiresAt,
+		Mfa:        r.Mfa,
+	}
+
+	if r.UserAgent != nil {
+		session.UserAgent = *r.UserAgent
+	}
+
+	if r.IpAddress != nil {
+		session.IpAddress = *r.IpAddress
+	}
+
+	return session
+}
+
+// ListSessions returns the identity's sessions which have not expired, ordered by the most recently used.
+func ListSessions(ctx context.Context, identityId string) ([]*Session, error) {
+	ctx, span := tracer.Start(ctx, "List Sessions")
+	defer span.End()
+
+	if identityId == "" {
+		return nil, errors.New("identity ID cannot be empty when listing sessions")
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := `
+		SELECT
+			session_id, identity_id, created_at, last_used_at, expires_at, user_agent, ip_address, mfa
+		FROM
+			keel_refresh_token
+		WHERE
+			identity_id = ? AND
+			expires_at >= now()
+		ORDER BY
+			COALESCE(last_used_at, created_at) DESC`
+
+	rows := []*sessionRow{}
+	err = database.GetDB().Raw(sql, identityId).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*Session, len(rows))
+	for i, row := range rows {
+		sessions[i] = row.toSession()
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes the refresh token of the identity's session, which signs it out once
+// its current access token expires. Returns false if the identity has no such session.
+func RevokeSession(ctx context.Context, identityId string, sessionId string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "Revoke Session")
+	defer span.End()
+
+	if identityId == "" || sessionId == "" {
+		return false, nil
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	sql := `
+		DELETE FROM
+			keel_refresh_token
+		WHERE
+			identity_id = ? AND
+			session_id = ?`
+
+	db := database.GetDB().Exec(sql, identityId, sessionId)
+	if db.Error != nil {
+		return false, db.Error
+	}
+
+	return db.RowsAffected > 0, nil
+}
+
+// RevokeAllSessions revokes the refresh tokens of all the identity's sessions,
+// signing it out everywhere. Returns the number of sessions revoked.
+func RevokeAllSessions(ctx context.Context, identityId string) (int64, error) {
+	ctx, span := tracer.Start(ctx, "Revoke All Sessions")
+	defer span.End()
+
+	if identityId == "" {
+		return 0, errors.New("identity ID cannot be empty when revoking sessions")
+	}
+
+	database, err := db.GetDatabase(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sql := `
+		DELETE FROM
+			keel_refresh_token
+		WHERE
+			identity_id = ?`
+
+	db := database.GetDB().Exec(sql, identityId)
+	if db.Error != nil {
+		return 0, db.Error
+	}
+
+	return db.RowsAffected, nil
+}
+
+// sessionInfo returns the user agent and client IP address of the current request, if known.
+// X-Forwarded-For is only used when the request was received from a trusted proxy.
+func sessionInfo(ctx context.Context) (userAgent string, ipAddress string) {
+	headers, err := runtimectx.GetRequestHeaders(ctx)
+	if err != nil {
+		return "", ""
+	}
+
+	if v, ok := headers["User-Agent"]; ok && len(v) > 0 {
+		userAgent = v[0]
+	}
+
+	config, err := runtimectx.GetOAuthConfig(ctx)
+	if err != nil {
+		return userAgent, ""
+	}
+
+	ipAddress = ClientIpAddress(runtimectx.GetRemoteAddress(ctx), headers["X-Forwarded-For"], config.LockoutTrustedProxies())
+
+	return userAgent, ipAddress
+}
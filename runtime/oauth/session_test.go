@@ -0,0 +1,189 @@
+package oauth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/config"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/runtime/runtimectx"
+	keeltesting "github.com/teamkeel/keel/testing"
+)
+
+func TestListSessions_RecordsRequestInfo(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	ctx = runtimectx.WithOAuthConfig(ctx, &config.AuthConfig{
+		Lockout: config.LockoutConfig{
+			TrustedProxies: []string{"10.0.0.0/8"},
+		},
+	})
+	ctx = runtimectx.WithRemoteAddress(ctx, "10.0.0.2:1234")
+	ctx = runtimectx.WithRequestHeaders(ctx, map[string][]string{
+		"User-Agent":      {"Mozilla/5.0"},
+		"X-Forwarded-For": {"198.51.100.9, 203.0.113.7, 10.0.0.1"},
+	})
+
+	_, err := oauth.NewRefreshToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	sessions, err := oauth.ListSessions(ctx, "identity_id")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	require.NotEmpty(t, sessions[0].Id)
+	require.Equal(t, "identity_id", sessions[0].IdentityId)
+	require.Equal(t, "Mozilla/5.0", sessions[0].UserAgent)
+	require.Equal(t, "203.0.113.7", sessions[0].IpAddress)
+	require.NotNil(t, sessions[0].LastUsedAt)
+	require.False(t, sessions[0].Mfa)
+	require.True(t, sessions[0].ExpiresAt.After(sessions[0].CreatedAt))
+}
+
+func TestListSessions_ForwardedForIgnoredWithoutTrustedProxy(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	ctx = runtimectx.WithRemoteAddress(ctx, "198.51.100.2:1234")
+	ctx = runtimectx.WithRequestHeaders(ctx, map[string][]string{
+		"X-Forwarded-For": {"203.0.113.7"},
+		"X-Real-Ip":       {"203.0.113.7"},
+	})
+
+	_, err := oauth.NewRefreshToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	sessions, err := oauth.ListSessions(ctx, "identity_id")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, "198.51.100.2", sessions[0].IpAddress)
+}
+
+func TestListSessions_OnlyForIdentity(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	_, err := oauth.NewRefreshToken(ctx, "identity_id")
+	require.NoError(t, err)
+	_, err = oauth.NewRefreshToken(ctx, "identity_id")
+	require.NoError(t, err)
+	_, err = oauth.NewRefreshToken(ctx, "another_identity_id")
+	require.NoError(t, err)
+
+	sessions, err := oauth.ListSessions(ctx, "identity_id")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	require.NotEqual(t, sessions[0].Id, sessions[1].Id)
+}
+
+func TestListSessions_RotationRetainsSession(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	refreshToken, err := oauth.NewRefreshToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	sessions, err := oauth.ListSessions(ctx, "identity_id")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	original := sessions[0]
+
+	ctx = runtimectx.WithRemoteAddress(ctx, "198.51.100.2:1234")
+	ctx = runtimectx.WithRequestHeaders(ctx, map[string][]string{
+		"User-Agent": {"curl/8.0"},
+	})
+
+	isValid, _, _, err := oauth.RotateRefreshToken(ctx, refreshToken)
+	require.NoError(t, err)
+	require.True(t, isValid)
+
+	sessions, err = oauth.ListSessions(ctx, "identity_id")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	require.Equal(t, original.Id, sessions[0].Id)
+	require.Equal(t, original.CreatedAt, sessions[0].CreatedAt)
+	require.Equal(t, original.ExpiresAt, sessions[0].ExpiresAt)
+	require.Equal(t, "curl/8.0", sessions[0].UserAgent)
+	require.Equal(t, "198.51.100.2", sessions[0].IpAddress)
+	require.False(t, sessions[0].LastUsedAt.Before(*original.LastUsedAt))
+}
+
+func TestRevokeSession_Valid(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	refreshToken1, err := oauth.NewRefreshToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	sessions, err := oauth.ListSessions(ctx, "identity_id")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	sessionId := sessions[0].Id
+
+	_, err = oauth.NewRefreshToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	revoked, err := oauth.RevokeSession(ctx, "identity_id", sessionId)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	isValid, _, err := oauth.ValidateRefreshToken(ctx, refreshToken1)
+	require.NoError(t, err)
+	require.False(t, isValid)
+
+	sessions, err = oauth.ListSessions(ctx, "identity_id")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.NotEqual(t, sessionId, sessions[0].Id)
+}
+
+func TestRevokeSession_OtherIdentityNotRevoked(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	refreshToken, err := oauth.NewRefreshToken(ctx, "identity_id")
+	require.NoError(t, err)
+
+	sessions, err := oauth.ListSessions(ctx, "identity_id")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	revoked, err := oauth.RevokeSession(ctx, "another_identity_id", sessions[0].Id)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	isValid, _, err := oauth.ValidateRefreshToken(ctx, refreshToken)
+	require.NoError(t, err)
+	require.True(t, isValid)
+}
+
+func TestRevokeAllSessions_Valid(t *testing.T) {
+	ctx, database, _ := keeltesting.MakeContext(t, context.TODO(), authTestSchema, true)
+	defer database.Close()
+
+	refreshToken1, err := oauth.NewRefreshToken(ctx, "identity_id")
+	require.NoError(t, err)
+	refreshToken2, err := oauth.NewRefreshToken(ctx, "identity_id")
+	require.NoError(t, err)
+	refreshToken3, err := oauth.NewRefreshToken(ctx, "another_identity_id")
+	require.NoError(t, err)
+
+	revoked, err := oauth.RevokeAllSessions(ctx, "identity_id")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), revoked)
+
+	isValid, _, err := oauth.ValidateRefreshToken(ctx, refreshToken1)
+	require.NoError(t, err)
+	require.False(t, isValid)
+
+	isValid, _, err = oauth.ValidateRefreshToken(ctx, refreshToken2)
+	require.NoError(t, err)
+	require.False(t, isValid)
+
+	isValid, _, err = oauth.ValidateRefreshToken(ctx, refreshToken3)
+	require.NoError(t, err)
+	require.True(t, isValid)
+}
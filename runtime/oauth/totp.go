@@ -0,0 +1,121 @@
+package oauth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Time-based one-time passwords as used by authenticator apps.
+// https://datatracker.ietf.org/doc/html/rfc6238
+const (
+	// Length in bytes of the shared secret, as recommended for HMAC-SHA1
+	totpSecretLength = 20
+	// Number of digits in a code
+	totpDigits = 6
+	// Number of seconds for which each code is valid
+	totpPeriod = 30
+	// Number of periods either side of the current one for which a code is accepted, to allow for clock drift
+	totpSkew = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newTotpSecret generates a new base32-encoded shared secret.
+func newTotpSecret() (string, error) {
+	b := make([]byte, totpSecretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// TotpUri generates the otpauth uri which authenticator apps scan as a QR code.
+// https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+func TotpUri(secret string, issuer string, accountName string) string {
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(totpDigits))
+	q.Set("period", fmt.Sprint(totpPeriod))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + accountName,
+		RawQuery: q.Encode(),
+	}
+
+	return u.String()
+}
+
+// totpStep returns the time step which the time falls in.
+func totpStep(t time.Time) int64 {
+	return t.Unix() / totpPeriod
+}
+
+// GenerateTotpCode generates the code for the secret which is valid at the given time.
+func GenerateTotpCode(secret string, t time.Time) (string, error) {
+	return totpCode(secret, totpStep(t))
+}
+
+// totpCode generates the code for the secret at the given time step.
+// https://datatracker.ietf.org/doc/html/rfc4226#section-5.3
+func totpCode(secret string, step int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, value%mod), nil
+}
+
+// verifyTotpCode checks the code against the time steps around the given time and returns
+// the step which matched. Steps up to and including lastUsedStep are not accepted, so that
+// a code cannot be replayed.
+func verifyTotpCode(secret string, code string, t time.Time, lastUsedStep int64) (bool, int64, error) {
+	if len(code) != totpDigits {
+		return false, 0, nil
+	}
+
+	current := totpStep(t)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastUsedStep {
+			continue
+		}
+
+		expected, err := totpCode(secret, step)
+		if err != nil {
+			return false, 0, err
+		}
+
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return true, step, nil
+		}
+	}
+
+	return false, 0, nil
+}
@@ -0,0 +1,50 @@
+package oauth_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/runtime/oauth"
+)
+
+// The SHA1 test vectors from https://datatracker.ietf.org/doc/html/rfc6238#appendix-B, truncated to 6 digits.
+func TestGenerateTotpCode_RfcTestVectors(t *testing.T) {
+	// Base32 encoding of the ASCII secret "12345678901234567890"
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range vectors {
+		code, err := oauth.GenerateTotpCode(secret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestGenerateTotpCode_InvalidSecret(t *testing.T) {
+	_, err := oauth.GenerateTotpCode("not base32!", time.Now())
+	require.Error(t, err)
+}
+
+func TestTotpUri(t *testing.T) {
+	uri := oauth.TotpUri("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", "Keel", "keelson@keel.so")
+
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", u.Scheme)
+	require.Equal(t, "totp", u.Host)
+	require.Equal(t, "/Keel:keelson@keel.so", u.Path)
+	require.Equal(t, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", u.Query().Get("secret"))
+	require.Equal(t, "Keel", u.Query().Get("issuer"))
+	require.Equal(t, "6", u.Query().Get("digits"))
+	require.Equal(t, "30", u.Query().Get("period"))
+}
@@ -40,9 +40,11 @@ func GetVersion() string {
 func NewHttpHandler(currSchema *proto.Schema) http.Handler {
 	var apiHandler common.HandlerFunc
 	var authHandler func(http.ResponseWriter, *http.Request) common.Response
+	var streamHandler http.Handler
 	if currSchema != nil {
 		apiHandler = NewApiHandler(currSchema)
 		authHandler = NewAuthHandler(currSchema)
+		streamHandler = NewStreamHandler(currSchema)
 	}
 
 	httpHandler := func(w http.ResponseWriter, r *http.Request) {
@@ -61,10 +63,16 @@ func NewHttpHandler(currSchema *proto.Schema) http.Handler {
 
 		r = r.WithContext(ctx)
 
+		// Streaming requests write their own responses for as long as the client is connected
+		if IsStreamRequest(r) {
+			streamHandler.ServeHTTP(w, r)
+			return
+		}
+
 		var response common.Response
 		path := r.URL.Path
 		switch {
-		case strings.HasPrefix(path, "/auth"):
+		case strings.HasPrefix(path, "/auth"), path == "/.well-known/openid-configuration":
 			response = authHandler(w, r)
 		default:
 			response = apiHandler(r)
@@ -94,9 +102,18 @@ func NewAuthHandler(schema *proto.Schema) func(http.ResponseWriter, *http.Reques
 	handleProviders := authapi.ProvidersHandler(schema)
 	handleToken := authapi.TokenEndpointHandler(schema)
 	handleRevoke := authapi.RevokeHandler(schema)
+	handlePasswordless := authapi.PasswordlessHandler(schema)
 	handleAuthorize := authapi.AuthorizeHandler(schema)
 	handleCallback := authapi.CallbackHandler(schema)
 	handleOpenApiRequest := authapi.OAuthOpenApiSchema()
+	handleJwks := authapi.JwksHandler()
+	handleOpenIdConfiguration := authapi.OpenIdConfigurationHandler(schema)
+	handleUserInfo := authapi.UserInfoHandler(schema)
+	handleMfaEnrol := authapi.MfaEnrolHandler(schema)
+	handleMfaConfirm := authapi.MfaConfirmHandler(schema)
+	handleMfaRemove := authapi.MfaRemoveHandler(schema)
+	handleLinks := authapi.LinksHandler(schema)
+	handleLinksRemove := authapi.LinksRemoveHandler(schema)
 
 	return func(w http.ResponseWriter, r *http.Request) common.Response {
 		// Collect request headers and add to runtime context
@@ -106,6 +123,7 @@ func NewAuthHandler(schema *proto.Schema) func(http.ResponseWriter, *http.Reques
 			headers[k] = r.Header.Values(k)
 		}
 		r = r.WithContext(runtimectx.WithRequestHeaders(r.Context(), headers))
+		r = r.WithContext(runtimectx.WithRemoteAddress(r.Context(), r.RemoteAddr))
 
 		switch {
 		case r.URL.Path == "/auth/providers":
@@ -114,12 +132,30 @@ func NewAuthHandler(schema *proto.Schema) func(http.ResponseWriter, *http.Reques
 			return handleToken(r)
 		case r.URL.Path == "/auth/revoke":
 			return handleRevoke(r)
+		case r.URL.Path == "/auth/passwordless":
+			return handlePasswordless(r)
 		case strings.HasPrefix(r.URL.Path, "/auth/authorize"):
 			return handleAuthorize(r)
 		case strings.HasPrefix(r.URL.Path, "/auth/callback"):
 			return handleCallback(r)
 		case r.URL.Path == "/auth/openapi.json":
 			return handleOpenApiRequest(r)
+		case r.URL.Path == "/auth/.well-known/jwks.json":
+			return handleJwks(r)
+		case r.URL.Path == "/auth/.well-known/openid-configuration", r.URL.Path == "/.well-known/openid-configuration":
+			return handleOpenIdConfiguration(r)
+		case r.URL.Path == "/auth/userinfo":
+			return handleUserInfo(r)
+		case r.URL.Path == "/auth/mfa/enrol":
+			return handleMfaEnrol(r)
+		case r.URL.Path == "/auth/mfa/confirm":
+			return handleMfaConfirm(r)
+		case r.URL.Path == "/auth/mfa/remove":
+			return handleMfaRemove(r)
+		case r.URL.Path == "/auth/links":
+			return handleLinks(r)
+		case r.URL.Path == "/auth/links/remove":
+			return handleLinksRemove(r)
 		default:
 			return common.Response{
 				Status: http.StatusNotFound,
@@ -131,9 +167,11 @@ func NewAuthHandler(schema *proto.Schema) func(http.ResponseWriter, *http.Reques
 // NewApiHandler handles requests to the customer APIs
 func NewApiHandler(s *proto.Schema) common.HandlerFunc {
 	handlers := map[string]common.HandlerFunc{}
+	apis := map[string]*proto.Api{}
 
 	for _, api := range s.Apis {
 		root := "/" + strings.ToLower(api.Name)
+		apis[root] = api
 
 		handlers[root+"/graphql"] = graphql.NewHandler(s, api)
 		handlers[root+"/rpc"] = jsonrpc.NewHandler(s, api)
@@ -157,19 +195,82 @@ func NewApiHandler(s *proto.Schema) common.HandlerFunc {
 			}
 		}
 
-		// Collect request headers and add to runtime context
-		// These are exposed in custom functions and in expressions
-		headers := map[string][]string{}
-		for k := range r.Header {
-			headers[k] = r.Header.Values(k)
-		}
-		ctx = runtimectx.WithRequestHeaders(ctx, headers)
-		r = r.WithContext(ctx)
+		r = r.WithContext(withApiContext(ctx, r, apis))
 
 		return handler(r)
 	})
 }
 
+// NewStreamHandler handles requests to the customer APIs which stream their responses, such as GraphQL
+// subscriptions over WebSockets and the change feeds of the JSON API.
+func NewStreamHandler(s *proto.Schema) http.Handler {
+	handlers := map[string]http.Handler{}
+	apis := map[string]*proto.Api{}
+
+	for _, api := range s.Apis {
+		root := "/" + strings.ToLower(api.Name)
+		apis[root] = api
+
+		handlers[root+"/graphql"] = graphql.NewSubscriptionHandler(s, api)
+
+		for _, model := range proto.GetModelsWithActionTypeForApi(s, api, proto.ActionType_ACTION_TYPE_GET) {
+			handlers[root+"/json/"+strings.ToLower(model.Name)+"/changes"] = httpjson.NewChangesHandler(s, model)
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler, ok := handlers[strings.ToLower(r.URL.Path)]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("Not found"))
+			return
+		}
+
+		log.WithFields(log.Fields{
+			"url":    r.URL,
+			"method": r.Method,
+			"host":   r.Host,
+		}).Info("Runtime stream request")
+
+		handler.ServeHTTP(w, r.WithContext(withApiContext(r.Context(), r, apis)))
+	})
+}
+
+// IsStreamRequest determines if the request is to be served by the stream handler
+// rather than receiving a single response.
+func IsStreamRequest(r *http.Request) bool {
+	path := strings.ToLower(r.URL.Path)
+	switch {
+	case strings.HasSuffix(path, "/graphql"):
+		return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+	case strings.HasSuffix(path, "/changes"):
+		// Only the change feed of a model at /<api>/json/<model>/changes, which cannot be the path of an action
+		segments := strings.Split(path, "/")
+		return len(segments) == 5 && segments[2] == "json"
+	default:
+		return false
+	}
+}
+
+// withApiContext adds the request headers, the remote address and the API being used to the context.
+func withApiContext(ctx context.Context, r *http.Request, apis map[string]*proto.Api) context.Context {
+	// Collect request headers and add to runtime context
+	// These are exposed in custom functions and in expressions
+	headers := map[string][]string{}
+	for k := range r.Header {
+		headers[k] = r.Header.Values(k)
+	}
+	ctx = runtimectx.WithRequestHeaders(ctx, headers)
+	ctx = runtimectx.WithRemoteAddress(ctx, r.RemoteAddr)
+
+	// The API being used is needed for API-level settings such as the max page size
+	if api, ok := apis["/"+strings.Split(strings.ToLower(r.URL.Path), "/")[1]]; ok {
+		ctx = runtimectx.WithApi(ctx, api)
+	}
+
+	return ctx
+}
+
 type JobHandler struct {
 	schema *proto.Schema
 }
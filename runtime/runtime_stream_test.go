@@ -0,0 +1,189 @@
+package runtime_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/events"
+	"github.com/teamkeel/keel/runtime"
+	"github.com/teamkeel/keel/runtime/actions"
+	"github.com/teamkeel/keel/runtime/oauth"
+	"github.com/teamkeel/keel/schema/parser"
+	keeltesting "github.com/teamkeel/keel/testing"
+)
+
+func TestIsStreamRequest(t *testing.T) {
+	t.Parallel()
+
+	websocket := httptest.NewRequest(http.MethodGet, "/api/graphql", nil)
+	websocket.Header.Set("Upgrade", "websocket")
+	assert.True(t, runtime.IsStreamRequest(websocket))
+
+	assert.False(t, runtime.IsStreamRequest(httptest.NewRequest(http.MethodPost, "/api/graphql", nil)))
+	assert.True(t, runtime.IsStreamRequest(httptest.NewRequest(http.MethodGet, "/api/json/post/changes", nil)))
+	assert.True(t, runtime.IsStreamRequest(httptest.NewRequest(http.MethodGet, "/Api/JSON/BlogPost/Changes", nil)))
+	assert.False(t, runtime.IsStreamRequest(httptest.NewRequest(http.MethodGet, "/api/json/getPost", nil)))
+	assert.False(t, runtime.IsStreamRequest(httptest.NewRequest(http.MethodGet, "/api/rpc/changes", nil)))
+	assert.False(t, runtime.IsStreamRequest(httptest.NewRequest(http.MethodGet, "/api/json/changes", nil)))
+	assert.False(t, runtime.IsStreamRequest(httptest.NewRequest(http.MethodPost, "/api/json/changes", nil)))
+	assert.False(t, runtime.IsStreamRequest(httptest.NewRequest(http.MethodGet, "/api/json/post/comments/changes", nil)))
+}
+
+func TestChangesRequiresGetPermission(t *testing.T) {
+	t.Parallel()
+
+	schema := protoSchema(t, `
+		model Post {
+			fields {
+				title Text
+			}
+			actions {
+				get getPost(id)
+			}
+		}`)
+
+	handler := runtime.NewStreamHandler(schema)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/json/post/changes", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/api/json/post/changes", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	// A stream ticket can only be created with an access token
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/json/post/changes", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/json/comment/changes", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+const changesSchema = `
+model Post {
+	fields {
+		title Text
+		author Identity
+	}
+	actions {
+		get getPost(id)
+	}
+	@permission(expression: post.author == ctx.identity, actions: [get])
+}`
+
+type serverSentEvent struct {
+	id    string
+	event string
+	data  string
+}
+
+// readEvent reads the next event from the stream, skipping any comments.
+func readEvent(t *testing.T, reader *bufio.Reader) serverSentEvent {
+	e := serverSentEvent{}
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && e.event != "":
+			return e
+		case strings.HasPrefix(line, "id: "):
+			e.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			e.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			e.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestChangesDeliversPermittedRecordsAndResumes(t *testing.T) {
+	pollInterval := events.FeedPollInterval
+	events.FeedPollInterval = 50 * time.Millisecond
+	t.Cleanup(func() { events.FeedPollInterval = pollInterval })
+
+	ctx, database, s := keeltesting.MakeContext(t, context.Background(), changesSchema, true)
+	defer database.Close()
+
+	alice, err := actions.CreateIdentity(ctx, s, "alice@keel.xyz", "1234", oauth.KeelIssuer)
+	require.NoError(t, err)
+	bob, err := actions.CreateIdentity(ctx, s, "bob@keel.xyz", "1234", oauth.KeelIssuer)
+	require.NoError(t, err)
+
+	token, _, err := oauth.GenerateAccessToken(ctx, alice[parser.FieldNameId].(string))
+	require.NoError(t, err)
+
+	handler := runtime.NewStreamHandler(s)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	// Exchange the access token for a stream ticket, as an EventSource would
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/api/json/post/changes", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var body struct {
+		Ticket string `json:"ticket"`
+	}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	res.Body.Close()
+
+	stream, err := client.Get(server.URL + "/api/json/post/changes?ticket=" + body.Ticket)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+
+	// Allow the stream to start reading the audit trail
+	time.Sleep(200 * time.Millisecond)
+
+	err = database.GetDB().Exec(`INSERT INTO post (id, title, author_id) VALUES ('bobs', 'Bob''s post', ?)`, bob[parser.FieldNameId]).Error
+	require.NoError(t, err)
+	err = database.GetDB().Exec(`INSERT INTO post (id, title, author_id) VALUES ('alices', 'Alice''s post', ?)`, alice[parser.FieldNameId]).Error
+	require.NoError(t, err)
+
+	first := readEvent(t, bufio.NewReader(stream.Body))
+	stream.Body.Close()
+
+	assert.Equal(t, "post.created", first.event)
+	assert.Contains(t, first.data, `"alices"`)
+	assert.NotContains(t, first.data, `"bobs"`)
+
+	// Changes made while disconnected are delivered when resuming after the last event received
+	err = database.GetDB().Exec(`INSERT INTO post (id, title, author_id) VALUES ('bobs_second', 'Bob''s second post', ?)`, bob[parser.FieldNameId]).Error
+	require.NoError(t, err)
+	err = database.GetDB().Exec(`INSERT INTO post (id, title, author_id) VALUES ('alices_second', 'Alice''s second post', ?)`, alice[parser.FieldNameId]).Error
+	require.NoError(t, err)
+
+	req, err = http.NewRequest(http.MethodGet, server.URL+"/api/json/post/changes", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Last-Event-ID", first.id)
+
+	resumed, err := client.Do(req)
+	require.NoError(t, err)
+	defer resumed.Body.Close()
+	require.Equal(t, http.StatusOK, resumed.StatusCode)
+
+	next := readEvent(t, bufio.NewReader(resumed.Body))
+	assert.Equal(t, "post.created", next.event)
+	assert.Contains(t, next.data, `"alices_second"`)
+}
@@ -0,0 +1,22 @@
+package runtimectx
+
+import (
+	"context"
+
+	"github.com/teamkeel/keel/proto"
+)
+
+const (
+	apiContextKey contextKey = "api"
+)
+
+// WithApi sets the API through which the current request was made.
+func WithApi(ctx context.Context, api *proto.Api) context.Context {
+	return context.WithValue(ctx, apiContextKey, api)
+}
+
+// GetApi returns the API through which the current request was made, if any.
+func GetApi(ctx context.Context) (*proto.Api, bool) {
+	v, ok := ctx.Value(apiContextKey).(*proto.Api)
+	return v, ok
+}
@@ -7,17 +7,21 @@ type contextKey string
 const ContextTarget string = "ctx"
 
 const (
-	ContextIdentityField        = "identity"
-	ContextIsAuthenticatedField = "isAuthenticated"
-	ContextNowField             = "now"
-	ContextEnvField             = "env"
-	ContextSecretField          = "secret"
+	ContextIdentityField           = "identity"
+	ContextIsAuthenticatedField    = "isAuthenticated"
+	ContextIsMfaAuthenticatedField = "isMfaAuthenticated"
+	ContextNowField                = "now"
+	ContextEnvField                = "env"
+	ContextSecretField             = "secret"
+	ContextClientField             = "client"
 )
 
 var ContextFieldTypes = map[string]proto.Type{
-	ContextIdentityField:        proto.Type_TYPE_MODEL,
-	ContextIsAuthenticatedField: proto.Type_TYPE_BOOL,
-	ContextNowField:             proto.Type_TYPE_DATETIME,
-	ContextEnvField:             proto.Type_TYPE_OBJECT,
-	ContextSecretField:          proto.Type_TYPE_SECRET,
+	ContextIdentityField:           proto.Type_TYPE_MODEL,
+	ContextIsAuthenticatedField:    proto.Type_TYPE_BOOL,
+	ContextIsMfaAuthenticatedField: proto.Type_TYPE_BOOL,
+	ContextNowField:                proto.Type_TYPE_DATETIME,
+	ContextEnvField:                proto.Type_TYPE_OBJECT,
+	ContextSecretField:             proto.Type_TYPE_SECRET,
+	ContextClientField:             proto.Type_TYPE_OBJECT,
 }
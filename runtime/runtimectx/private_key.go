@@ -10,20 +10,52 @@ type privateKeyContextKey string
 
 var privateKeyContext privateKeyContextKey = "privateKey"
 
+// PrivateKeySet is the set of private keys used to sign and verify Keel-issued tokens.
+type PrivateKeySet struct {
+	// The key used to sign new tokens.
+	Primary *rsa.PrivateKey
+	// All keys which are accepted when verifying tokens, including the primary key.
+	Active []*rsa.PrivateKey
+}
+
+// GetPrivateKey returns the primary private key which is used to sign new tokens.
 func GetPrivateKey(ctx context.Context) (*rsa.PrivateKey, error) {
+	keys, err := GetPrivateKeys(ctx)
+	if err != nil || keys == nil {
+		return nil, err
+	}
+
+	return keys.Primary, nil
+}
+
+// GetPrivateKeys returns the primary and all active private keys.
+func GetPrivateKeys(ctx context.Context) (*PrivateKeySet, error) {
 	v := ctx.Value(privateKeyContext)
 	if v == nil {
 		return nil, nil
 	}
 
-	privateKey, ok := v.(*rsa.PrivateKey)
+	keys, ok := v.(*PrivateKeySet)
 
 	if !ok {
 		return nil, errors.New("private key in the context has wrong type")
 	}
-	return privateKey, nil
+	return keys, nil
 }
 
+// WithPrivateKey sets a single private key which is used to both sign and verify tokens.
 func WithPrivateKey(ctx context.Context, privateKey *rsa.PrivateKey) context.Context {
-	return context.WithValue(ctx, privateKeyContext, privateKey)
+	if privateKey == nil {
+		return context.WithValue(ctx, privateKeyContext, &PrivateKeySet{})
+	}
+
+	return WithPrivateKeys(ctx, &PrivateKeySet{
+		Primary: privateKey,
+		Active:  []*rsa.PrivateKey{privateKey},
+	})
+}
+
+// WithPrivateKeys sets the primary key used to sign tokens and the active keys accepted when verifying them.
+func WithPrivateKeys(ctx context.Context, keys *PrivateKeySet) context.Context {
+	return context.WithValue(ctx, privateKeyContext, keys)
 }
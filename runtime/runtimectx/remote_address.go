@@ -0,0 +1,20 @@
+package runtimectx
+
+import (
+	"context"
+)
+
+const (
+	remoteAddressContextKey contextKey = "remoteAddress"
+)
+
+// WithRemoteAddress adds the network address of the connection which sent the request.
+func WithRemoteAddress(ctx context.Context, remoteAddress string) context.Context {
+	return context.WithValue(ctx, remoteAddressContextKey, remoteAddress)
+}
+
+// GetRemoteAddress retrieves the network address of the connection which sent the request, if known.
+func GetRemoteAddress(ctx context.Context) string {
+	v, _ := ctx.Value(remoteAddressContextKey).(string)
+	return v
+}
@@ -67,7 +67,7 @@ func Completions(schemaFiles []*reader.SchemaFile, pos *node.Position, cfg *conf
 	// switch on nearest (previous) keyword
 	switch enclosingBlock {
 	case parser.KeywordModel:
-		attributes := getAttributeCompletions(tokenAtPos, []string{parser.AttributePermission, parser.AttributeUnique, parser.AttributeOn})
+		attributes := getAttributeCompletions(tokenAtPos, []string{parser.AttributePermission, parser.AttributeUnique, parser.AttributeOn, parser.AttributePii})
 		return append(attributes, modelBlockKeywords...)
 	case parser.KeywordRole:
 		return roleBlockKeywords
@@ -239,6 +239,22 @@ func getInputCompletions(asts []*parser.AST, tokenAtPos *TokensAtPosition) []*Co
 	return getBlockCompletions(asts, tokenAtPos, parser.KeywordInput)
 }
 
+// fieldAttributes returns the attributes which can be used on a field in the given block
+func fieldAttributes(keyword string) []string {
+	attributes := []string{
+		parser.AttributeUnique,
+		parser.AttributeDefault,
+		parser.AttributeRelation,
+	}
+
+	// Only model fields can have permissions or be marked as personal data
+	if keyword == parser.KeywordFields {
+		attributes = append(attributes, parser.AttributePermission, parser.AttributePii)
+	}
+
+	return attributes
+}
+
 func getBlockCompletions(asts []*parser.AST, tokenAtPos *TokensAtPosition, keyword string) []*CompletionItem {
 	// First we find the start of the current block
 	startOfBlock := tokenAtPos.StartOfBlock()
@@ -250,11 +266,7 @@ func getBlockCompletions(asts []*parser.AST, tokenAtPos *TokensAtPosition, keywo
 	if tokenAtPos.Value() == "@" ||
 		tokenAtPos.ValueAt(-1) == "@" ||
 		startOfBlock.Prev().Value() != keyword {
-		return getAttributeCompletions(tokenAtPos, []string{
-			parser.AttributeUnique,
-			parser.AttributeDefault,
-			parser.AttributeRelation,
-		})
+		return getAttributeCompletions(tokenAtPos, fieldAttributes(keyword))
 	}
 
 	// Now we have to work out if we're expecting a field name, a field type, or an
@@ -319,11 +331,7 @@ func getBlockCompletions(asts []*parser.AST, tokenAtPos *TokensAtPosition, keywo
 		// The current token is on the same line as the previous token
 		// In this case we provide attribute name completions
 		if tokenAtPos.Line() == tokenAtPos.Prev().Line() {
-			return getAttributeCompletions(tokenAtPos, []string{
-				parser.AttributeUnique,
-				parser.AttributeDefault,
-				parser.AttributeRelation,
-			})
+			return getAttributeCompletions(tokenAtPos, fieldAttributes(keyword))
 		}
 
 		// We on a new line which means current token is field name for
@@ -447,6 +455,17 @@ var modelBlockKeywords = []*CompletionItem{
 	},
 }
 
+var fieldPermissionActionTypeKeywords = []*CompletionItem{
+	{
+		Label: parser.ActionTypeRead,
+		Kind:  KindKeyword,
+	},
+	{
+		Label: parser.ActionTypeWrite,
+		Kind:  KindKeyword,
+	},
+}
+
 var actionBlockKeywords = []*CompletionItem{
 	{
 		Label: parser.ActionTypeCreate,
@@ -880,7 +899,7 @@ func getPermissionArgCompletions(asts []*parser.AST, t *TokensAtPosition, cfg *c
 				Kind:  KindLabel,
 			},
 		}
-		if getTypeOfEnclosingBlock(t) == parser.KeywordModel {
+		if lo.Contains([]string{parser.KeywordModel, parser.KeywordFields}, getTypeOfEnclosingBlock(t)) {
 			labels = append(labels, &CompletionItem{
 				Label: "actions",
 				Kind:  KindLabel,
@@ -895,6 +914,9 @@ func getPermissionArgCompletions(asts []*parser.AST, t *TokensAtPosition, cfg *c
 	case "expression":
 		return getExpressionCompletions(asts, t, cfg)
 	case "actions":
+		if listStart != nil && getTypeOfEnclosingBlock(t) == parser.KeywordFields {
+			return fieldPermissionActionTypeKeywords
+		}
 		if listStart != nil {
 			return lo.Filter(actionBlockKeywords, func(c *CompletionItem, _ int) bool {
 				return c.Label != parser.KeywordWith
@@ -990,11 +1012,34 @@ func getExpressionCompletions(asts []*parser.AST, t *TokensAtPosition, cfg *conf
 					Description: "Authentication Indicator",
 					Kind:        KindField,
 				},
+				{
+					Label:       "isMfaAuthenticated",
+					Description: "Multi-factor Authentication Indicator",
+					Kind:        KindField,
+				},
 				{
 					Label:       "headers",
 					Description: "Request Headers",
 					Kind:        KindField,
 				},
+				{
+					Label:       "client",
+					Description: "Service Client",
+					Kind:        KindField,
+				},
+			}
+		case previousIdents[1] == "client" && len(previousIdents) == 2:
+			completions = []*CompletionItem{
+				{
+					Label:       "id",
+					Description: "Text",
+					Kind:        KindField,
+				},
+				{
+					Label:       "name",
+					Description: "Text",
+					Kind:        KindField,
+				},
 			}
 		case previousIdents[1] == "env" && len(previousIdents) == 2:
 			completions = getEnvironmentVariableCompletions(cfg)
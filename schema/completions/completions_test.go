@@ -111,7 +111,7 @@ func TestModelCompletions(t *testing.T) {
 			model A {
 			  <Cursor>
 			}`,
-			expected: []string{"@permission", "@unique", "@on", "fields", "actions"},
+			expected: []string{"@permission", "@unique", "@on", "@pii", "fields", "actions"},
 		},
 		// attributes tests
 		{
@@ -120,7 +120,7 @@ func TestModelCompletions(t *testing.T) {
 			model A {
               @<Cursor>
             }`,
-			expected: []string{"@permission", "@unique", "@on", "fields", "actions"},
+			expected: []string{"@permission", "@unique", "@on", "@pii", "fields", "actions"},
 		},
 	}
 
@@ -434,7 +434,7 @@ func TestFieldCompletions(t *testing.T) {
 					}
 				}
 			}`,
-			expected: []string{"@unique", "@default", "@relation"},
+			expected: []string{"@unique", "@default", "@relation", "@permission", "@pii"},
 		},
 		{
 			name: "field-attributes-bare-at",
@@ -443,7 +443,7 @@ func TestFieldCompletions(t *testing.T) {
 					name Text @<Cursor>
 				}
 			}`,
-			expected: []string{"@unique", "@default", "@relation"},
+			expected: []string{"@unique", "@default", "@relation", "@permission", "@pii"},
 		},
 		{
 			name: "field-attributes-whitespace",
@@ -453,7 +453,7 @@ func TestFieldCompletions(t *testing.T) {
 					name Text <Cursor>
 				}
 			}`,
-			expected: []string{"@unique", "@default", "@relation"},
+			expected: []string{"@unique", "@default", "@relation", "@permission", "@pii"},
 		},
 	}
 
@@ -758,7 +758,22 @@ func TestWhereAttributeCompletions(t *testing.T) {
 					}
 				}
 			}`,
-			expected: []string{"env", "headers", "identity", "isAuthenticated", "now", "secrets"},
+			expected: []string{"client", "env", "headers", "identity", "isAuthenticated", "isMfaAuthenticated", "now", "secrets"},
+		},
+		{
+			name: "where-attribute-ctx-client",
+			schema: `
+			model Record {
+				fields {
+					clientId Text
+				}
+				actions {
+					list listRecords() {
+						@where(record.clientId == ctx.client.<Cursor>)
+					}
+				}
+			}`,
+			expected: []string{"id", "name"},
 		},
 		{
 			name: "where-attribute-ctx-identity",
@@ -1019,7 +1034,7 @@ func TestSetAttributeCompletions(t *testing.T) {
 					}
 				}
 			}`,
-			expected: []string{"env", "headers", "identity", "isAuthenticated", "now", "secrets"},
+			expected: []string{"client", "env", "headers", "identity", "isAuthenticated", "isMfaAuthenticated", "now", "secrets"},
 		},
 		{
 			name: "set-attribute-ctx-identity",
@@ -1122,7 +1137,7 @@ func TestFunctionCompletions(t *testing.T) {
 					read getPerson(<Cursor>
 				}
 			}`,
-			expected: []string{"Any", "GetPersonInput", "RequestPasswordResetInput", "RequestPasswordResetResponse", "ResetPasswordInput", "ResetPasswordResponse", "createdAt", "id", "updatedAt"},
+			expected: []string{"Any", "AssignRoleInput", "DeleteIdentityInput", "DeleteIdentityResponse", "DisableIdentityInput", "EnableIdentityInput", "EraseIdentityDataInput", "EraseIdentityDataResponse", "ExportIdentityDataInput", "ForcePasswordResetInput", "ForcePasswordResetResponse", "GetPersonInput", "IdentityRoleMembership", "IdentitySession", "IdentitySummary", "ListIdentitiesInput", "ListIdentitiesResponse", "ListRoleMembershipsInput", "ListRoleMembershipsResponse", "ListSessionsInput", "ListSessionsResponse", "RequestEmailVerificationInput", "RequestEmailVerificationResponse", "RequestPasswordResetInput", "RequestPasswordResetResponse", "ResetPasswordInput", "ResetPasswordResponse", "RevokeAllSessionsInput", "RevokeAllSessionsResponse", "RevokeRoleInput", "RevokeRoleResponse", "RevokeSessionInput", "RevokeSessionResponse", "VerifyEmailInput", "VerifyEmailResponse", "createdAt", "id", "updatedAt"},
 		},
 		{
 			name: "arbitrary-function-input-completions-multi-file",
@@ -1135,7 +1150,7 @@ func TestFunctionCompletions(t *testing.T) {
 			otherSchema: `
 			message GetPersonInput {}
 			`,
-			expected: []string{"Any", "GetPersonInput", "RequestPasswordResetInput", "RequestPasswordResetResponse", "ResetPasswordInput", "ResetPasswordResponse", "createdAt", "id", "updatedAt"},
+			expected: []string{"Any", "AssignRoleInput", "DeleteIdentityInput", "DeleteIdentityResponse", "DisableIdentityInput", "EnableIdentityInput", "EraseIdentityDataInput", "EraseIdentityDataResponse", "ExportIdentityDataInput", "ForcePasswordResetInput", "ForcePasswordResetResponse", "GetPersonInput", "IdentityRoleMembership", "IdentitySession", "IdentitySummary", "ListIdentitiesInput", "ListIdentitiesResponse", "ListRoleMembershipsInput", "ListRoleMembershipsResponse", "ListSessionsInput", "ListSessionsResponse", "RequestEmailVerificationInput", "RequestEmailVerificationResponse", "RequestPasswordResetInput", "RequestPasswordResetResponse", "ResetPasswordInput", "ResetPasswordResponse", "RevokeAllSessionsInput", "RevokeAllSessionsResponse", "RevokeRoleInput", "RevokeRoleResponse", "RevokeSessionInput", "RevokeSessionResponse", "VerifyEmailInput", "VerifyEmailResponse", "createdAt", "id", "updatedAt"},
 		},
 		// returns keyword tests
 		{
@@ -1149,7 +1164,7 @@ func TestFunctionCompletions(t *testing.T) {
 				}
 			}
 			`,
-			expected: []string{"GetPersonInput", "GetPersonResponse", "RequestPasswordResetInput", "RequestPasswordResetResponse", "ResetPasswordInput", "ResetPasswordResponse"},
+			expected: []string{"AssignRoleInput", "DeleteIdentityInput", "DeleteIdentityResponse", "DisableIdentityInput", "EnableIdentityInput", "EraseIdentityDataInput", "EraseIdentityDataResponse", "ExportIdentityDataInput", "ForcePasswordResetInput", "ForcePasswordResetResponse", "GetPersonInput", "GetPersonResponse", "IdentityRoleMembership", "IdentitySession", "IdentitySummary", "ListIdentitiesInput", "ListIdentitiesResponse", "ListRoleMembershipsInput", "ListRoleMembershipsResponse", "ListSessionsInput", "ListSessionsResponse", "RequestEmailVerificationInput", "RequestEmailVerificationResponse", "RequestPasswordResetInput", "RequestPasswordResetResponse", "ResetPasswordInput", "ResetPasswordResponse", "RevokeAllSessionsInput", "RevokeAllSessionsResponse", "RevokeRoleInput", "RevokeRoleResponse", "RevokeSessionInput", "RevokeSessionResponse", "VerifyEmailInput", "VerifyEmailResponse"},
 		},
 		{
 			name: "arbitrary-function-returns-keyword-completions",
@@ -1174,7 +1189,7 @@ func TestFunctionCompletions(t *testing.T) {
 				}
 			}
 			`,
-			expected: []string{"Any", "RequestPasswordResetInput", "RequestPasswordResetResponse", "ResetPasswordInput", "ResetPasswordResponse", "createdAt", "id", "updatedAt"},
+			expected: []string{"Any", "AssignRoleInput", "DeleteIdentityInput", "DeleteIdentityResponse", "DisableIdentityInput", "EnableIdentityInput", "EraseIdentityDataInput", "EraseIdentityDataResponse", "ExportIdentityDataInput", "ForcePasswordResetInput", "ForcePasswordResetResponse", "IdentityRoleMembership", "IdentitySession", "IdentitySummary", "ListIdentitiesInput", "ListIdentitiesResponse", "ListRoleMembershipsInput", "ListRoleMembershipsResponse", "ListSessionsInput", "ListSessionsResponse", "RequestEmailVerificationInput", "RequestEmailVerificationResponse", "RequestPasswordResetInput", "RequestPasswordResetResponse", "ResetPasswordInput", "ResetPasswordResponse", "RevokeAllSessionsInput", "RevokeAllSessionsResponse", "RevokeRoleInput", "RevokeRoleResponse", "RevokeSessionInput", "RevokeSessionResponse", "VerifyEmailInput", "VerifyEmailResponse", "createdAt", "id", "updatedAt"},
 		},
 		{
 			name: "arbitrary-function-create-with-completion",
@@ -1230,7 +1245,7 @@ func TestPermissionCompletions(t *testing.T) {
 			model A {
               @p<Cursor>
             }`,
-			expected: []string{"@permission", "actions", "fields"},
+			expected: []string{"@permission", "@pii", "actions", "fields"},
 		},
 		{
 			name: "model-permission-attribute-labels",
@@ -1296,7 +1311,7 @@ func TestPermissionCompletions(t *testing.T) {
 				)
 			}
 			`,
-			expected: []string{"env", "headers", "identity", "isAuthenticated", "now", "secrets"},
+			expected: []string{"client", "env", "headers", "identity", "isAuthenticated", "isMfaAuthenticated", "now", "secrets"},
 		},
 		{
 			name: "permission-attribute-actions",
@@ -1320,6 +1335,21 @@ func TestPermissionCompletions(t *testing.T) {
 			`,
 			expected: parser.ActionTypes,
 		},
+		{
+			name: "permission-attribute-field-actions",
+			schema: `
+			model Person {
+				fields {
+					salary Decimal {
+						@permission(
+							actions: [<Cursor>]
+						)
+					}
+				}
+			}
+			`,
+			expected: []string{"read", "write"},
+		},
 		{
 			name: "permission-attribute-roles",
 			schema: `
@@ -1801,7 +1831,7 @@ func TestMessageCompletions(t *testing.T) {
 				foo <Cursor>
 			}
 			`,
-			expected: []string{"AnotherMessage", "Boolean", "Date", "Decimal", "ID", "Identity", "MyMessage", "File", "Markdown", "Number", "Password", "RequestPasswordResetInput", "RequestPasswordResetResponse", "ResetPasswordInput", "ResetPasswordResponse", "Secret", "Text", "Timestamp", "Vector"},
+			expected: []string{"AnotherMessage", "Boolean", "Date", "Decimal", "ID", "Identity", "MyMessage", "File", "Markdown", "Number", "Password", "AssignRoleInput", "DeleteIdentityInput", "DeleteIdentityResponse", "DisableIdentityInput", "EnableIdentityInput", "EraseIdentityDataInput", "EraseIdentityDataResponse", "ExportIdentityDataInput", "ForcePasswordResetInput", "ForcePasswordResetResponse", "IdentityRoleMembership", "IdentitySession", "IdentitySummary", "ListIdentitiesInput", "ListIdentitiesResponse", "ListRoleMembershipsInput", "ListRoleMembershipsResponse", "ListSessionsInput", "ListSessionsResponse", "RequestEmailVerificationInput", "RequestEmailVerificationResponse", "RequestPasswordResetInput", "RequestPasswordResetResponse", "ResetPasswordInput", "ResetPasswordResponse", "RevokeAllSessionsInput", "RevokeAllSessionsResponse", "RevokeRoleInput", "RevokeRoleResponse", "RevokeSessionInput", "RevokeSessionResponse", "VerifyEmailInput", "VerifyEmailResponse", "Secret", "Text", "Timestamp", "Vector"},
 		},
 	}
 
@@ -19,6 +19,11 @@ var operatorsForType = map[string][]string{
 		parser.OperatorEquals,
 		parser.OperatorNotEquals,
 		parser.OperatorAssignment,
+		parser.OperatorEqualsIgnoreCase,
+		parser.OperatorStartsWithIgnoreCase,
+		parser.OperatorEndsWithIgnoreCase,
+		parser.OperatorContainsIgnoreCase,
+		parser.OperatorMatches,
 	},
 	parser.FieldTypeID: {
 		parser.OperatorEquals,
@@ -96,6 +101,11 @@ var operatorsForType = map[string][]string{
 		parser.OperatorEquals,
 		parser.OperatorNotEquals,
 		parser.OperatorAssignment,
+		parser.OperatorEqualsIgnoreCase,
+		parser.OperatorStartsWithIgnoreCase,
+		parser.OperatorEndsWithIgnoreCase,
+		parser.OperatorContainsIgnoreCase,
+		parser.OperatorMatches,
 	},
 	parser.FieldTypeVector: {},
 }
@@ -0,0 +1,54 @@
+package expressions
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// supportedPatternEscapes are the escapes of a letter which are supported by both Go's RE2 syntax, used when a
+// matches condition is evaluated in memory, and Postgres' regular expressions, used when it is evaluated in SQL.
+// Any other character can be escaped to match it literally.
+const supportedPatternEscapes = "dDsSwWtnrfvaA"
+
+// ValidatePattern validates a pattern used with the matches operator. Only a subset of regular expression syntax is
+// supported, which is RE2 syntax without flags (such as (?i)), word boundaries (\b and \B), \z, \Q...\E, Unicode
+// classes (\p and \P), and hex or octal escapes. A pattern in this subset has the same meaning in memory, when it
+// is matched with MatchPattern, and in the database, except that the database's locale may permit \d, \s and \w
+// to also match non-ASCII characters.
+func ValidatePattern(pattern string) error {
+	_, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %s", strings.TrimPrefix(err.Error(), "error parsing regexp: "))
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == '\\' && i+1 < len(pattern):
+			c := pattern[i+1]
+			if isAlphanumeric(c) && !strings.ContainsRune(supportedPatternEscapes, rune(c)) {
+				return fmt.Errorf("invalid pattern: the escape \\%c is not supported", c)
+			}
+			i++
+		case strings.HasPrefix(pattern[i:], "(?") && !strings.HasPrefix(pattern[i:], "(?:"):
+			return fmt.Errorf("invalid pattern: flags are not supported")
+		}
+	}
+
+	return nil
+}
+
+func isAlphanumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// MatchPattern reports whether the value matches a pattern used with the matches operator. As with Postgres' ~
+// operator, . also matches a newline.
+func MatchPattern(pattern string, value string) (bool, error) {
+	err := ValidatePattern(pattern)
+	if err != nil {
+		return false, err
+	}
+
+	return regexp.MatchString("(?s)"+pattern, value)
+}
@@ -0,0 +1,28 @@
+package expressions_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/teamkeel/keel/schema/expressions"
+)
+
+func TestMatchPattern_DotMatchesNewline(t *testing.T) {
+	matched, err := expressions.MatchPattern("^first.second$", "first\nsecond")
+	require.NoError(t, err)
+	require.True(t, matched)
+}
+
+func TestMatchPattern_AnchorsMatchOnlyAtEnds(t *testing.T) {
+	matched, err := expressions.MatchPattern("^second", "first\nsecond")
+	require.NoError(t, err)
+	require.False(t, matched)
+}
+
+func TestMatchPattern_UnsupportedSyntax(t *testing.T) {
+	_, err := expressions.MatchPattern("(?i)first", "first")
+	require.EqualError(t, err, "invalid pattern: flags are not supported")
+
+	_, err = expressions.MatchPattern(`\bfirst`, "first")
+	require.EqualError(t, err, `invalid pattern: the escape \b is not supported`)
+}
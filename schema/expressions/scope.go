@@ -234,6 +234,10 @@ func DefaultExpressionScope(asts []*parser.AST) *ExpressionScope {
 						Name: "isAuthenticated",
 						Type: parser.FieldTypeBoolean,
 					},
+					{
+						Name: "isMfaAuthenticated",
+						Type: parser.FieldTypeBoolean,
+					},
 					{
 						Name: "now",
 						Type: parser.FieldTypeDatetime,
@@ -249,6 +253,22 @@ func DefaultExpressionScope(asts []*parser.AST) *ExpressionScope {
 						Name: "headers",
 						Type: TypeStringMap,
 					},
+					{
+						Name: "client",
+						Object: &ExpressionObjectEntity{
+							Name: "Client",
+							Fields: []*ExpressionScopeEntity{
+								{
+									Name: "id",
+									Type: parser.FieldTypeText,
+								},
+								{
+									Name: "name",
+									Type: parser.FieldTypeText,
+								},
+							},
+						},
+					},
 					{
 						Name: "secrets",
 						Object: &ExpressionObjectEntity{
@@ -143,6 +143,26 @@ func makeIDQueryInputMessage(name string, modelName *wrapperspb.StringValue) *pr
 	}}
 }
 
+func makeHierarchyQueryInputMessage(name string) *proto.Message {
+	return &proto.Message{Name: name, Fields: []*proto.MessageField{
+		{
+			MessageName: name,
+			Name:        "id",
+			Type: &proto.TypeInfo{
+				Type: proto.Type_TYPE_ID,
+			},
+		},
+		{
+			MessageName: name,
+			Name:        "depth",
+			Optional:    true,
+			Type: &proto.TypeInfo{
+				Type: proto.Type_TYPE_INT,
+			},
+		},
+	}}
+}
+
 func makeStringQueryInputMessage(name string) *proto.Message {
 	return &proto.Message{Name: name, Fields: []*proto.MessageField{
 		{
@@ -196,6 +216,46 @@ func makeStringQueryInputMessage(name string) *proto.Message {
 				Repeated: true,
 			},
 		},
+		{
+			MessageName: name,
+			Name:        "equalsIgnoreCase",
+			Optional:    true,
+			Type: &proto.TypeInfo{
+				Type: proto.Type_TYPE_STRING,
+			},
+		},
+		{
+			MessageName: name,
+			Name:        "startsWithIgnoreCase",
+			Optional:    true,
+			Type: &proto.TypeInfo{
+				Type: proto.Type_TYPE_STRING,
+			},
+		},
+		{
+			MessageName: name,
+			Name:        "endsWithIgnoreCase",
+			Optional:    true,
+			Type: &proto.TypeInfo{
+				Type: proto.Type_TYPE_STRING,
+			},
+		},
+		{
+			MessageName: name,
+			Name:        "containsIgnoreCase",
+			Optional:    true,
+			Type: &proto.TypeInfo{
+				Type: proto.Type_TYPE_STRING,
+			},
+		},
+		{
+			MessageName: name,
+			Name:        "matches",
+			Optional:    true,
+			Type: &proto.TypeInfo{
+				Type: proto.Type_TYPE_STRING,
+			},
+		},
 	}}
 }
 
@@ -840,6 +900,83 @@ func (scm *Builder) makeListQueryInputMessage(typeInfo *proto.TypeInfo) (*proto.
 	}
 }
 
+// Creates the filter group message for a list action and adds the "and", "or" and "not" group fields to its where message.
+// A filter group contains all the implicit filter inputs of the action (all optional), as well as further nested groups.
+func (scm *Builder) makeFilterGroupMessage(whereMessage *proto.Message, action *parser.ActionNode) {
+	filters := lo.Filter(whereMessage.Fields, func(f *proto.MessageField, _ int) bool {
+		return f.IsModelField() || f.IsMessage()
+	})
+
+	// No implicit filters to group, or the group field names are already taken by model field inputs.
+	if len(filters) == 0 || lo.SomeBy(whereMessage.Fields, func(f *proto.MessageField) bool {
+		return lo.Contains([]string{proto.FilterGroupAnd, proto.FilterGroupOr, proto.FilterGroupNot}, f.Name)
+	}) {
+		return
+	}
+
+	groupMessage := &proto.Message{
+		Name:   makeFilterGroupMessageName(action.Name.Value),
+		Fields: []*proto.MessageField{},
+	}
+
+	for _, f := range filters {
+		groupMessage.Fields = append(groupMessage.Fields, &proto.MessageField{
+			Name:        f.Name,
+			Type:        f.Type,
+			Target:      f.Target,
+			Optional:    true,
+			Nullable:    f.Nullable,
+			MessageName: groupMessage.Name,
+		})
+	}
+
+	for _, m := range []*proto.Message{whereMessage, groupMessage} {
+		for _, name := range []string{proto.FilterGroupAnd, proto.FilterGroupOr, proto.FilterGroupNot} {
+			m.Fields = append(m.Fields, &proto.MessageField{
+				Name: name,
+				Type: &proto.TypeInfo{
+					Type:        proto.Type_TYPE_MESSAGE,
+					MessageName: wrapperspb.String(groupMessage.Name),
+					Repeated:    name != proto.FilterGroupNot,
+				},
+				Optional:    true,
+				MessageName: m.Name,
+			})
+		}
+	}
+
+	scm.proto.Messages = append(scm.proto.Messages, groupMessage)
+}
+
+// Adds the "descendantsOf" and "ancestorsOf" filters to the where message of a list action on a model with
+// a self-referencing relationship, such as a Category with a parent Category.
+func (scm *Builder) makeHierarchyFilters(whereMessage *proto.Message, model *parser.ModelNode) {
+	field := query.HierarchyField(model)
+
+	// No hierarchy to filter on, or the filter names are already taken by other inputs.
+	if field == nil || lo.SomeBy(whereMessage.Fields, func(f *proto.MessageField) bool {
+		return lo.Contains([]string{proto.HierarchyDescendantsOf, proto.HierarchyAncestorsOf}, f.Name)
+	}) {
+		return
+	}
+
+	if !lo.SomeBy(scm.proto.Messages, func(m *proto.Message) bool { return m.Name == proto.HierarchyQueryInputMessageName }) {
+		scm.proto.Messages = append(scm.proto.Messages, makeHierarchyQueryInputMessage(proto.HierarchyQueryInputMessageName))
+	}
+
+	for _, name := range []string{proto.HierarchyDescendantsOf, proto.HierarchyAncestorsOf} {
+		whereMessage.Fields = append(whereMessage.Fields, &proto.MessageField{
+			Name: name,
+			Type: &proto.TypeInfo{
+				Type:        proto.Type_TYPE_MESSAGE,
+				MessageName: wrapperspb.String(proto.HierarchyQueryInputMessageName),
+			},
+			Optional:    true,
+			MessageName: whereMessage.Name,
+		})
+	}
+}
+
 func makeListOrderByMessages(actionName string, fieldNames []string) []*proto.Message {
 	messages := []*proto.Message{}
 
@@ -1099,6 +1236,11 @@ func (scm *Builder) makeActionInputMessages(model *parser.ModelNode, action *par
 			}
 		}
 
+		if !action.IsFunction() {
+			scm.makeHierarchyFilters(whereMessage, model)
+			scm.makeFilterGroupMessage(whereMessage, action)
+		}
+
 		scm.proto.Messages = append(scm.proto.Messages, whereMessage)
 
 		sortableFields, err := query.ActionSortableFieldNames(action)
@@ -1120,42 +1262,12 @@ func (scm *Builder) makeActionInputMessages(model *parser.ModelNode, action *par
 						MessageName: wrapperspb.String(makeWhereMessageName(action.Name.Value)),
 					},
 				},
-				// Include pagination fields
-				{
-					Name:        "first",
-					MessageName: makeInputMessageName(action.Name.Value),
-					Optional:    true,
-					Type: &proto.TypeInfo{
-						Type: proto.Type_TYPE_INT,
-					},
-				},
-				{
-					Name:        "after",
-					MessageName: makeInputMessageName(action.Name.Value),
-					Optional:    true,
-					Type: &proto.TypeInfo{
-						Type: proto.Type_TYPE_STRING,
-					},
-				},
-				{
-					Name:        "last",
-					MessageName: makeInputMessageName(action.Name.Value),
-					Optional:    true,
-					Type: &proto.TypeInfo{
-						Type: proto.Type_TYPE_INT,
-					},
-				},
-				{
-					Name:        "before",
-					MessageName: makeInputMessageName(action.Name.Value),
-					Optional:    true,
-					Type: &proto.TypeInfo{
-						Type: proto.Type_TYPE_STRING,
-					},
-				},
 			},
 		}
 
+		// Include pagination fields
+		inputMessage.Fields = append(inputMessage.Fields, makePaginationInputFields(action)...)
+
 		orderByMessages := makeListOrderByMessages(action.Name.Value, sortableFields)
 		if len(orderByMessages) > 0 {
 			orderByMessageField := &proto.MessageField{
@@ -1179,6 +1291,68 @@ func (scm *Builder) makeActionInputMessages(model *parser.ModelNode, action *par
 	}
 }
 
+// makePaginationInputFields returns the paging inputs of a list action, which
+// depend on whether the action uses cursor or offset pagination.
+func makePaginationInputFields(action *parser.ActionNode) []*proto.MessageField {
+	messageName := makeInputMessageName(action.Name.Value)
+
+	if query.ActionPaginationMode(action) == parser.PaginationModeOffset {
+		return []*proto.MessageField{
+			{
+				Name:        "limit",
+				MessageName: messageName,
+				Optional:    true,
+				Type: &proto.TypeInfo{
+					Type: proto.Type_TYPE_INT,
+				},
+			},
+			{
+				Name:        "offset",
+				MessageName: messageName,
+				Optional:    true,
+				Type: &proto.TypeInfo{
+					Type: proto.Type_TYPE_INT,
+				},
+			},
+		}
+	}
+
+	return []*proto.MessageField{
+		{
+			Name:        "first",
+			MessageName: messageName,
+			Optional:    true,
+			Type: &proto.TypeInfo{
+				Type: proto.Type_TYPE_INT,
+			},
+		},
+		{
+			Name:        "after",
+			MessageName: messageName,
+			Optional:    true,
+			Type: &proto.TypeInfo{
+				Type: proto.Type_TYPE_STRING,
+			},
+		},
+		{
+			Name:        "last",
+			MessageName: messageName,
+			Optional:    true,
+			Type: &proto.TypeInfo{
+				Type: proto.Type_TYPE_INT,
+			},
+		},
+		{
+			Name:        "before",
+			MessageName: messageName,
+			Optional:    true,
+			Type: &proto.TypeInfo{
+				Type: proto.Type_TYPE_STRING,
+			},
+		},
+	}
+}
+
 func (scm *Builder) makeModel(decl *parser.DeclarationNode) {
 	parserModel := decl.Model
 	protoModel := &proto.Model{
@@ -1250,6 +1424,15 @@ func (scm *Builder) makeAPI(decl *parser.DeclarationNode) {
 
 			protoAPI.ApiModels = append(protoAPI.ApiModels, protoModel)
 		}
+
+		if section.Attribute != nil && section.Attribute.Name.Value == parser.AttributePagination {
+			for _, arg := range section.Attribute.Arguments {
+				operand, _ := arg.Expression.ToValue()
+				if arg.Label.Value == parser.PaginationArgMaxPageSize {
+					protoAPI.MaxPageSize = int32(*operand.Number)
+				}
+			}
+		}
 	}
 	scm.proto.Apis = append(scm.proto.Apis, protoAPI)
 }
@@ -1714,6 +1897,8 @@ func (scm *Builder) applyModelAttribute(parserModel *parser.ModelNode, protoMode
 		perm := scm.permissionAttributeToProtoPermission(attribute)
 		perm.ModelName = protoModel.Name
 		protoModel.Permissions = append(protoModel.Permissions, perm)
+	case parser.AttributePii:
+		protoModel.Pii = true
 	case parser.AttributeOn:
 		subscriberArg, _ := attribute.Arguments[1].Expression.ToValue()
 		subscriberName := subscriberArg.Ident.Fragments[0].Fragment
@@ -1886,6 +2071,16 @@ func (scm *Builder) applyActionAttributes(action *parser.ActionNode, protoAction
 				}
 				protoAction.OrderBy = append(protoAction.OrderBy, orderBy)
 			}
+		case parser.AttributePagination:
+			for _, arg := range attribute.Arguments {
+				operand, _ := arg.Expression.ToValue()
+				switch arg.Label.Value {
+				case parser.PaginationArgMode:
+					protoAction.PaginationMode = mapToPaginationMode(operand.ToString())
+				case parser.PaginationArgMaxPageSize:
+					protoAction.MaxPageSize = int32(*operand.Number)
+				}
+			}
 		}
 	}
 }
@@ -1910,6 +2105,12 @@ func (scm *Builder) applyFieldAttributes(parserField *parser.FieldNode, protoFie
 				defaultValue.UseZeroValue = true
 			}
 			protoField.DefaultValue = defaultValue
+		case parser.AttributePermission:
+			perm := scm.permissionAttributeToProtoPermission(fieldAttribute)
+			perm.ModelName = protoField.ModelName
+			protoField.Permissions = append(protoField.Permissions, perm)
+		case parser.AttributePii:
+			protoField.Pii = true
 		case parser.AttributeRelation:
 			// We cannot process this field attribute here. But here is an explanation
 			// of why that is so - for future readers.
@@ -1999,6 +2200,15 @@ func mapToOrderByDirection(parsedDirection string) proto.OrderDirection {
 	}
 }
 
+func mapToPaginationMode(parsedMode string) proto.PaginationMode {
+	switch parsedMode {
+	case parser.PaginationModeOffset:
+		return proto.PaginationMode_PAGINATION_MODE_OFFSET
+	default:
+		return proto.PaginationMode_PAGINATION_MODE_CURSOR
+	}
+}
+
 func (scm *Builder) applyJobAttribute(protoJob *proto.Job, attribute *parser.AttributeNode) {
 	switch attribute.Name.Value {
 	case parser.AttributePermission:
@@ -2057,6 +2267,10 @@ func makeWhereMessageName(opName string) string {
 	return fmt.Sprintf("%sWhere", casing.ToCamel(opName))
 }
 
+func makeFilterGroupMessageName(opName string) string {
+	return makeInputMessageName(opName, proto.FilterGroupMessageSuffix)
+}
+
 func makeOrderByMessageName(opName string, fieldName string) string {
 	return fmt.Sprintf("%sOrderBy%s", casing.ToCamel(opName), casing.ToCamel(fieldName))
 }
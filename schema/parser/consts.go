@@ -136,10 +136,46 @@ const (
 )
 
 const (
-	RequestPasswordResetActionName = "requestPasswordReset"
-	PasswordResetActionName        = "resetPassword"
+	RequestPasswordResetActionName     = "requestPasswordReset"
+	PasswordResetActionName            = "resetPassword"
+	RequestEmailVerificationActionName = "requestEmailVerification"
+	VerifyEmailActionName              = "verifyEmail"
+	ListSessionsActionName             = "listSessions"
+	RevokeSessionActionName            = "revokeSession"
+	RevokeAllSessionsActionName        = "revokeAllSessions"
+	ListRoleMembershipsActionName      = "listRoleMemberships"
+	AssignRoleActionName               = "assignRole"
+	RevokeRoleActionName               = "revokeRole"
+	ListIdentitiesActionName           = "listIdentities"
+	DisableIdentityActionName          = "disableIdentity"
+	EnableIdentityActionName           = "enableIdentity"
+	ForcePasswordResetActionName       = "forcePasswordReset"
+	DeleteIdentityActionName           = "deleteIdentity"
+	ExportIdentityDataActionName       = "exportIdentityData"
+	EraseIdentityDataActionName        = "eraseIdentityData"
 )
 
+// BuiltInIdentityActionNames are the actions added to the Identity model by Keel, which perform their own authorisation
+var BuiltInIdentityActionNames = []string{
+	RequestPasswordResetActionName,
+	PasswordResetActionName,
+	RequestEmailVerificationActionName,
+	VerifyEmailActionName,
+	ListSessionsActionName,
+	RevokeSessionActionName,
+	RevokeAllSessionsActionName,
+	ListRoleMembershipsActionName,
+	AssignRoleActionName,
+	RevokeRoleActionName,
+	ListIdentitiesActionName,
+	DisableIdentityActionName,
+	EnableIdentityActionName,
+	ForcePasswordResetActionName,
+	DeleteIdentityActionName,
+	ExportIdentityDataActionName,
+	EraseIdentityDataActionName,
+}
+
 const (
 	AttributeUnique     = "unique"
 	AttributePermission = "permission"
@@ -155,9 +191,18 @@ const (
 	AttributeFunction   = "function"
 	AttributeOn         = "on"
 	AttributeEmbed      = "embed"
+	AttributePagination = "pagination"
+	AttributePii        = "pii"
 )
 
 const (
 	OrderByAscending  = "asc"
 	OrderByDescending = "desc"
 )
+
+const (
+	PaginationArgMode        = "mode"
+	PaginationArgMaxPageSize = "maxPageSize"
+	PaginationModeCursor     = "cursor"
+	PaginationModeOffset     = "offset"
+)
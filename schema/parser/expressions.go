@@ -85,7 +85,7 @@ type Operator struct {
 	node.Node
 
 	// Todo need to figure out how we can share with the consts below
-	Symbol string `@( "=" "=" | "!" "=" | ">" "=" | "<" "=" | ">" | "<" | "not" "in" | "in" | "+" "=" | "-" "=" | "=")`
+	Symbol string `@( "=" "=" | "!" "=" | ">" "=" | "<" "=" | ">" | "<" | "not" "in" | "in" | "equalsIgnoreCase" | "startsWithIgnoreCase" | "endsWithIgnoreCase" | "containsIgnoreCase" | "matches" | "+" "=" | "-" "=" | "=")`
 }
 
 func (o *Operator) ToString() string {
@@ -108,6 +108,11 @@ var (
 	OperatorNotIn                = "notin"
 	OperatorIncrement            = "+="
 	OperatorDecrement            = "-="
+	OperatorEqualsIgnoreCase     = "equalsIgnoreCase"
+	OperatorStartsWithIgnoreCase = "startsWithIgnoreCase"
+	OperatorEndsWithIgnoreCase   = "endsWithIgnoreCase"
+	OperatorContainsIgnoreCase   = "containsIgnoreCase"
+	OperatorMatches              = "matches"
 )
 
 var AssignmentOperators = []string{
@@ -123,6 +128,11 @@ var LogicalOperators = []string{
 	OperatorLessThanOrEqualTo,
 	OperatorIn,
 	OperatorNotIn,
+	OperatorEqualsIgnoreCase,
+	OperatorStartsWithIgnoreCase,
+	OperatorEndsWithIgnoreCase,
+	OperatorContainsIgnoreCase,
+	OperatorMatches,
 }
 
 func (condition *Condition) ToString() string {
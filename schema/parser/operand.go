@@ -173,6 +173,13 @@ func (ident *Ident) IsContextIsAuthenticatedField() bool {
 	return false
 }
 
+func (ident *Ident) IsContextIsMfaAuthenticatedField() bool {
+	if ident.IsContext() && len(ident.Fragments) == 2 {
+		return ident.Fragments[1].Fragment == "isMfaAuthenticated"
+	}
+	return false
+}
+
 func (ident *Ident) IsContextNowField() bool {
 	if ident.IsContext() && len(ident.Fragments) == 2 {
 		return ident.Fragments[1].Fragment == "now"
@@ -194,6 +201,13 @@ func (ident *Ident) IsContextEnvField() bool {
 	return false
 }
 
+func (ident *Ident) IsContextClientField() bool {
+	if ident.IsContext() && len(ident.Fragments) == 3 {
+		return ident.Fragments[1].Fragment == "client"
+	}
+	return false
+}
+
 func (ident *Ident) IsContextSecretField() bool {
 	if ident.IsContext() && len(ident.Fragments) == 3 {
 		return ident.Fragments[1].Fragment == "secrets"
@@ -377,6 +377,29 @@ func ActionSortableFieldNames(action *parser.ActionNode) ([]string, error) {
 	return fields, nil
 }
 
+// ActionPaginationMode returns the mode set on the @pagination attribute.
+// If no mode has been set, cursor pagination is used.
+func ActionPaginationMode(action *parser.ActionNode) string {
+	for _, attr := range action.Attributes {
+		if attr.Name.Value != parser.AttributePagination {
+			continue
+		}
+
+		for _, arg := range attr.Arguments {
+			if arg.Label == nil || arg.Label.Value != parser.PaginationArgMode {
+				continue
+			}
+
+			mode, err := arg.Expression.ToValue()
+			if err == nil && mode.Ident != nil && mode.Ident.ToString() == parser.PaginationModeOffset {
+				return parser.PaginationModeOffset
+			}
+		}
+	}
+
+	return parser.PaginationModeCursor
+}
+
 func ModelFieldNames(model *parser.ModelNode) []string {
 	names := []string{}
 	for _, field := range ModelFields(model, ExcludeBuiltInFields) {
@@ -409,6 +432,22 @@ func ModelFieldsOfType(model *parser.ModelNode, typeName string) []*parser.Field
 	return fields
 }
 
+// HierarchyField returns the model's self-referencing belongs-to relationship field (such as "parent"),
+// which arranges the model's records into a tree. Nil is returned unless there is exactly one such field.
+func HierarchyField(model *parser.ModelNode) *parser.FieldNode {
+	fields := []*parser.FieldNode{}
+	for _, field := range ModelFieldsOfType(model, model.Name.Value) {
+		foreignKey := ModelField(model, fmt.Sprintf("%sId", field.Name.Value))
+		if !field.Repeated && foreignKey != nil && foreignKey.BuiltIn {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) != 1 {
+		return nil
+	}
+	return fields[0]
+}
+
 // AllHasManyRelationFields provides a list of all the fields in the schema
 // which are of type Model and which are repeated.
 func AllHasManyRelationFields(asts []*parser.AST) []*parser.FieldNode {
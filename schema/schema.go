@@ -620,12 +620,252 @@ func (scm *Builder) insertIdentityModel(declarations *parser.AST, schemaFile *re
 		},
 	}
 
+	requestEmailVerificationAction := &parser.ActionNode{
+		BuiltIn: true,
+		Type:    parser.NameNode{Value: parser.ActionTypeWrite},
+		Name:    parser.NameNode{Value: parser.RequestEmailVerificationActionName},
+		Inputs: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "RequestEmailVerificationInput"}}}, Optional: false,
+			},
+		},
+		Returns: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "RequestEmailVerificationResponse"}}}, Optional: false,
+			},
+		},
+	}
+
+	verifyEmailAction := &parser.ActionNode{
+		BuiltIn: true,
+		Type:    parser.NameNode{Value: parser.ActionTypeWrite},
+		Name:    parser.NameNode{Value: parser.VerifyEmailActionName},
+		Inputs: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "VerifyEmailInput"}}}, Optional: false,
+			},
+		},
+		Returns: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "VerifyEmailResponse"}}}, Optional: false,
+			},
+		},
+	}
+
+	listSessionsAction := &parser.ActionNode{
+		BuiltIn: true,
+		Type:    parser.NameNode{Value: parser.ActionTypeRead},
+		Name:    parser.NameNode{Value: parser.ListSessionsActionName},
+		Inputs: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "ListSessionsInput"}}}, Optional: false,
+			},
+		},
+		Returns: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "ListSessionsResponse"}}}, Optional: false,
+			},
+		},
+	}
+
+	revokeSessionAction := &parser.ActionNode{
+		BuiltIn: true,
+		Type:    parser.NameNode{Value: parser.ActionTypeWrite},
+		Name:    parser.NameNode{Value: parser.RevokeSessionActionName},
+		Inputs: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "RevokeSessionInput"}}}, Optional: false,
+			},
+		},
+		Returns: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "RevokeSessionResponse"}}}, Optional: false,
+			},
+		},
+	}
+
+	revokeAllSessionsAction := &parser.ActionNode{
+		BuiltIn: true,
+		Type:    parser.NameNode{Value: parser.ActionTypeWrite},
+		Name:    parser.NameNode{Value: parser.RevokeAllSessionsActionName},
+		Inputs: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "RevokeAllSessionsInput"}}}, Optional: false,
+			},
+		},
+		Returns: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "RevokeAllSessionsResponse"}}}, Optional: false,
+			},
+		},
+	}
+
+	listRoleMembershipsAction := &parser.ActionNode{
+		BuiltIn: true,
+		Type:    parser.NameNode{Value: parser.ActionTypeRead},
+		Name:    parser.NameNode{Value: parser.ListRoleMembershipsActionName},
+		Inputs: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "ListRoleMembershipsInput"}}}, Optional: false,
+			},
+		},
+		Returns: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "ListRoleMembershipsResponse"}}}, Optional: false,
+			},
+		},
+	}
+
+	assignRoleAction := &parser.ActionNode{
+		BuiltIn: true,
+		Type:    parser.NameNode{Value: parser.ActionTypeWrite},
+		Name:    parser.NameNode{Value: parser.AssignRoleActionName},
+		Inputs: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "AssignRoleInput"}}}, Optional: false,
+			},
+		},
+		Returns: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "IdentityRoleMembership"}}}, Optional: false,
+			},
+		},
+	}
+
+	revokeRoleAction := &parser.ActionNode{
+		BuiltIn: true,
+		Type:    parser.NameNode{Value: parser.ActionTypeWrite},
+		Name:    parser.NameNode{Value: parser.RevokeRoleActionName},
+		Inputs: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "RevokeRoleInput"}}}, Optional: false,
+			},
+		},
+		Returns: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "RevokeRoleResponse"}}}, Optional: false,
+			},
+		},
+	}
+
+	listIdentitiesAction := &parser.ActionNode{
+		BuiltIn: true,
+		Type:    parser.NameNode{Value: parser.ActionTypeRead},
+		Name:    parser.NameNode{Value: parser.ListIdentitiesActionName},
+		Inputs: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "ListIdentitiesInput"}}}, Optional: false,
+			},
+		},
+		Returns: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "ListIdentitiesResponse"}}}, Optional: false,
+			},
+		},
+	}
+
+	disableIdentityAction := &parser.ActionNode{
+		BuiltIn: true,
+		Type:    parser.NameNode{Value: parser.ActionTypeWrite},
+		Name:    parser.NameNode{Value: parser.DisableIdentityActionName},
+		Inputs: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "DisableIdentityInput"}}}, Optional: false,
+			},
+		},
+		Returns: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "IdentitySummary"}}}, Optional: false,
+			},
+		},
+	}
+
+	enableIdentityAction := &parser.ActionNode{
+		BuiltIn: true,
+		Type:    parser.NameNode{Value: parser.ActionTypeWrite},
+		Name:    parser.NameNode{Value: parser.EnableIdentityActionName},
+		Inputs: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "EnableIdentityInput"}}}, Optional: false,
+			},
+		},
+		Returns: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "IdentitySummary"}}}, Optional: false,
+			},
+		},
+	}
+
+	forcePasswordResetAction := &parser.ActionNode{
+		BuiltIn: true,
+		Type:    parser.NameNode{Value: parser.ActionTypeWrite},
+		Name:    parser.NameNode{Value: parser.ForcePasswordResetActionName},
+		Inputs: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "ForcePasswordResetInput"}}}, Optional: false,
+			},
+		},
+		Returns: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "ForcePasswordResetResponse"}}}, Optional: false,
+			},
+		},
+	}
+
+	deleteIdentityAction := &parser.ActionNode{
+		BuiltIn: true,
+		Type:    parser.NameNode{Value: parser.ActionTypeWrite},
+		Name:    parser.NameNode{Value: parser.DeleteIdentityActionName},
+		Inputs: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "DeleteIdentityInput"}}}, Optional: false,
+			},
+		},
+		Returns: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "DeleteIdentityResponse"}}}, Optional: false,
+			},
+		},
+	}
+
+	exportIdentityDataAction := &parser.ActionNode{
+		BuiltIn: true,
+		Type:    parser.NameNode{Value: parser.ActionTypeRead},
+		Name:    parser.NameNode{Value: parser.ExportIdentityDataActionName},
+		Inputs: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "ExportIdentityDataInput"}}}, Optional: false,
+			},
+		},
+		Returns: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "Any"}}}, Optional: false,
+			},
+		},
+	}
+
+	eraseIdentityDataAction := &parser.ActionNode{
+		BuiltIn: true,
+		Type:    parser.NameNode{Value: parser.ActionTypeWrite},
+		Name:    parser.NameNode{Value: parser.EraseIdentityDataActionName},
+		Inputs: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "EraseIdentityDataInput"}}}, Optional: false,
+			},
+		},
+		Returns: []*parser.ActionInputNode{
+			{
+				Type: parser.Ident{Fragments: []*parser.IdentFragment{{Fragment: "EraseIdentityDataResponse"}}}, Optional: false,
+			},
+		},
+	}
+
 	fieldsSection := &parser.ModelSectionNode{
 		Fields: identityFields,
 	}
 
 	actionsSection := &parser.ModelSectionNode{
-		Actions: []*parser.ActionNode{requestPasswordReset, resetPasswordAction},
+		Actions: []*parser.ActionNode{requestPasswordReset, resetPasswordAction, requestEmailVerificationAction, verifyEmailAction, listSessionsAction, revokeSessionAction, revokeAllSessionsAction, listRoleMembershipsAction, assignRoleAction, revokeRoleAction, listIdentitiesAction, disableIdentityAction, enableIdentityAction, forcePasswordResetAction, deleteIdentityAction, exportIdentityDataAction, eraseIdentityDataAction},
 	}
 
 	identityModelDeclaration.Model.Sections = append(identityModelDeclaration.Model.Sections, fieldsSection, actionsSection)
@@ -710,13 +950,828 @@ func (scm *Builder) insertIdentityModel(declarations *parser.AST, schemaFile *re
 		},
 	}
 
+	requestEmailVerificationInputDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "RequestEmailVerificationInput",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "email",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "redirectUrl",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+				},
+			},
+		},
+	}
+
+	requestEmailVerificationResponseDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "RequestEmailVerificationResponse",
+			},
+			Fields: []*parser.FieldNode{},
+		},
+	}
+
+	verifyEmailInputDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "VerifyEmailInput",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "token",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+				},
+			},
+		},
+	}
+
+	verifyEmailResponseDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "VerifyEmailResponse",
+			},
+			Fields: []*parser.FieldNode{},
+		},
+	}
+
+	identitySessionDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "IdentitySession",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "id",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "createdAt",
+					},
+					Type: parser.NameNode{
+						Value: "Timestamp",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "lastUsedAt",
+					},
+					Type: parser.NameNode{
+						Value: "Timestamp",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "expiresAt",
+					},
+					Type: parser.NameNode{
+						Value: "Timestamp",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "userAgent",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "ipAddress",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "mfa",
+					},
+					Type: parser.NameNode{
+						Value: "Boolean",
+					},
+				},
+			},
+		},
+	}
+
+	listSessionsInputDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "ListSessionsInput",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "identityId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+					Optional: true,
+				},
+			},
+		},
+	}
+
+	listSessionsResponseDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "ListSessionsResponse",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "sessions",
+					},
+					Type: parser.NameNode{
+						Value: "IdentitySession",
+					},
+					Repeated: true,
+				},
+			},
+		},
+	}
+
+	revokeSessionInputDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "RevokeSessionInput",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "id",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "identityId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+					Optional: true,
+				},
+			},
+		},
+	}
+
+	revokeSessionResponseDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "RevokeSessionResponse",
+			},
+			Fields: []*parser.FieldNode{},
+		},
+	}
+
+	revokeAllSessionsInputDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "RevokeAllSessionsInput",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "identityId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+					Optional: true,
+				},
+			},
+		},
+	}
+
+	revokeAllSessionsResponseDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "RevokeAllSessionsResponse",
+			},
+			Fields: []*parser.FieldNode{},
+		},
+	}
+
+	identityRoleMembershipDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "IdentityRoleMembership",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "id",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "identityId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "role",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "model",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "recordId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "createdAt",
+					},
+					Type: parser.NameNode{
+						Value: "Timestamp",
+					},
+				},
+			},
+		},
+	}
+
+	listRoleMembershipsInputDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "ListRoleMembershipsInput",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "identityId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "role",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+					Optional: true,
+				},
+			},
+		},
+	}
+
+	listRoleMembershipsResponseDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "ListRoleMembershipsResponse",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "memberships",
+					},
+					Type: parser.NameNode{
+						Value: "IdentityRoleMembership",
+					},
+					Repeated: true,
+				},
+			},
+		},
+	}
+
+	assignRoleInputDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "AssignRoleInput",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "identityId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "role",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "model",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "recordId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+					Optional: true,
+				},
+			},
+		},
+	}
+
+	revokeRoleInputDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "RevokeRoleInput",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "identityId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "role",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "model",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "recordId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+					Optional: true,
+				},
+			},
+		},
+	}
+
+	revokeRoleResponseDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "RevokeRoleResponse",
+			},
+			Fields: []*parser.FieldNode{},
+		},
+	}
+
+	identitySummaryDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "IdentitySummary",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "id",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "email",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "emailVerified",
+					},
+					Type: parser.NameNode{
+						Value: "Boolean",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "externalId",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "issuer",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "name",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "disabled",
+					},
+					Type: parser.NameNode{
+						Value: "Boolean",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "disabledAt",
+					},
+					Type: parser.NameNode{
+						Value: "Timestamp",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "createdAt",
+					},
+					Type: parser.NameNode{
+						Value: "Timestamp",
+					},
+				},
+			},
+		},
+	}
+
+	listIdentitiesInputDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "ListIdentitiesInput",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "search",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "disabled",
+					},
+					Type: parser.NameNode{
+						Value: "Boolean",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "first",
+					},
+					Type: parser.NameNode{
+						Value: "Number",
+					},
+					Optional: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "after",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+					Optional: true,
+				},
+			},
+		},
+	}
+
+	listIdentitiesResponseDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "ListIdentitiesResponse",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "identities",
+					},
+					Type: parser.NameNode{
+						Value: "IdentitySummary",
+					},
+					Repeated: true,
+				},
+				{
+					Name: parser.NameNode{
+						Value: "hasNextPage",
+					},
+					Type: parser.NameNode{
+						Value: "Boolean",
+					},
+				},
+			},
+		},
+	}
+
+	disableIdentityInputDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "DisableIdentityInput",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "identityId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+				},
+			},
+		},
+	}
+
+	enableIdentityInputDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "EnableIdentityInput",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "identityId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+				},
+			},
+		},
+	}
+
+	forcePasswordResetInputDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "ForcePasswordResetInput",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "identityId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "redirectUrl",
+					},
+					Type: parser.NameNode{
+						Value: "Text",
+					},
+				},
+			},
+		},
+	}
+
+	forcePasswordResetResponseDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "ForcePasswordResetResponse",
+			},
+			Fields: []*parser.FieldNode{},
+		},
+	}
+
+	deleteIdentityInputDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "DeleteIdentityInput",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "identityId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+				},
+			},
+		},
+	}
+
+	deleteIdentityResponseDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "DeleteIdentityResponse",
+			},
+			Fields: []*parser.FieldNode{},
+		},
+	}
+
+	exportIdentityDataInputDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "ExportIdentityDataInput",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "identityId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+				},
+			},
+		},
+	}
+
+	eraseIdentityDataInputDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "EraseIdentityDataInput",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "identityId",
+					},
+					Type: parser.NameNode{
+						Value: "ID",
+					},
+				},
+			},
+		},
+	}
+
+	eraseIdentityDataResponseDeclaration := &parser.DeclarationNode{
+		Message: &parser.MessageNode{
+			BuiltIn: true,
+			Name: parser.NameNode{
+				Value: "EraseIdentityDataResponse",
+			},
+			Fields: []*parser.FieldNode{
+				{
+					Name: parser.NameNode{
+						Value: "deleted",
+					},
+					Type: parser.NameNode{
+						Value: "Number",
+					},
+				},
+				{
+					Name: parser.NameNode{
+						Value: "anonymised",
+					},
+					Type: parser.NameNode{
+						Value: "Number",
+					},
+				},
+			},
+		},
+	}
+
 	declarations.Declarations = append(
 		declarations.Declarations,
 		identityModelDeclaration,
 		requestPasswordResetInputDeclaration,
 		requestPasswordResetResponseDeclaration,
 		resetPasswordInputDeclaration,
-		resetPasswordResponseDeclaration)
+		resetPasswordResponseDeclaration,
+		requestEmailVerificationInputDeclaration,
+		requestEmailVerificationResponseDeclaration,
+		verifyEmailInputDeclaration,
+		verifyEmailResponseDeclaration,
+		identitySessionDeclaration,
+		listSessionsInputDeclaration,
+		listSessionsResponseDeclaration,
+		revokeSessionInputDeclaration,
+		revokeSessionResponseDeclaration,
+		revokeAllSessionsInputDeclaration,
+		revokeAllSessionsResponseDeclaration,
+		identityRoleMembershipDeclaration,
+		listRoleMembershipsInputDeclaration,
+		listRoleMembershipsResponseDeclaration,
+		assignRoleInputDeclaration,
+		revokeRoleInputDeclaration,
+		revokeRoleResponseDeclaration,
+		identitySummaryDeclaration,
+		listIdentitiesInputDeclaration,
+		listIdentitiesResponseDeclaration,
+		disableIdentityInputDeclaration,
+		enableIdentityInputDeclaration,
+		forcePasswordResetInputDeclaration,
+		forcePasswordResetResponseDeclaration,
+		deleteIdentityInputDeclaration,
+		deleteIdentityResponseDeclaration,
+		exportIdentityDataInputDeclaration,
+		eraseIdentityDataInputDeclaration,
+		eraseIdentityDataResponseDeclaration)
 }
 
 func (scm *Builder) addEnvironmentVariables(declarations *parser.AST) {
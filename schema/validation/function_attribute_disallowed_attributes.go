@@ -8,7 +8,7 @@ import (
 	"github.com/teamkeel/keel/schema/validation/errorhandling"
 )
 
-// FunctionDisallowedBehavioursRule will validate against usages of @set, @where and nested inputs
+// FunctionDisallowedBehavioursRule will validate against usages of @set, @where, @pagination and nested inputs
 // for any actions marked with the @function attribute as we do not support these sets of
 // functionality in @function's
 func FunctionDisallowedBehavioursRule(asts []*parser.AST, errs *errorhandling.ValidationErrors) Visitor {
@@ -43,6 +43,20 @@ func FunctionDisallowedBehavioursRule(asts []*parser.AST, errs *errorhandling.Va
 					),
 				)
 			}
+
+			// Pagination is handled by the function itself, so the attribute would otherwise be silently ignored
+			if found, attr := checkPresenceOfAttribute(n, parser.AttributePagination); found {
+				errs.AppendError(
+					errorhandling.NewValidationErrorWithDetails(
+						errorhandling.AttributeNotAllowedError,
+						errorhandling.ErrorDetails{
+							Message: fmt.Sprintf("@%s attributes are not supported when using the @function attribute", parser.AttributePagination),
+							Hint:    "Paginate the results within the function instead.",
+						},
+						attr,
+					),
+				)
+			}
 		},
 	}
 }
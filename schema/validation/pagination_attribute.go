@@ -0,0 +1,166 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/teamkeel/keel/schema/parser"
+	"github.com/teamkeel/keel/schema/validation/errorhandling"
+)
+
+func PaginationAttributeRule(asts []*parser.AST, errs *errorhandling.ValidationErrors) Visitor {
+	var currentOperation *parser.ActionNode
+	var currentAPI *parser.APINode
+	var currentAttribute *parser.AttributeNode
+	var paginationAttributeDefined bool
+	var argumentLabels []string
+
+	return Visitor{
+		EnterAction: func(action *parser.ActionNode) {
+			currentOperation = action
+			paginationAttributeDefined = false
+		},
+		LeaveAction: func(_ *parser.ActionNode) {
+			currentOperation = nil
+			paginationAttributeDefined = false
+		},
+		EnterAPI: func(api *parser.APINode) {
+			currentAPI = api
+			paginationAttributeDefined = false
+		},
+		LeaveAPI: func(_ *parser.APINode) {
+			currentAPI = nil
+			paginationAttributeDefined = false
+		},
+		EnterAttribute: func(attribute *parser.AttributeNode) {
+			currentAttribute = attribute
+			argumentLabels = []string{}
+
+			if attribute.Name.Value != parser.AttributePagination {
+				return
+			}
+
+			if currentOperation == nil && currentAPI == nil {
+				return
+			}
+
+			if currentOperation != nil && currentOperation.Type.Value != parser.ActionTypeList {
+				errs.AppendError(errorhandling.NewValidationErrorWithDetails(
+					errorhandling.AttributeNotAllowedError,
+					errorhandling.ErrorDetails{
+						Message: "@pagination can only be used on list actions",
+					},
+					attribute.Name,
+				))
+			}
+
+			if paginationAttributeDefined {
+				errs.AppendError(errorhandling.NewValidationErrorWithDetails(
+					errorhandling.AttributeNotAllowedError,
+					errorhandling.ErrorDetails{
+						Message: "@pagination can only be defined once",
+					},
+					attribute.Name,
+				))
+			}
+
+			paginationAttributeDefined = true
+
+			if len(attribute.Arguments) == 0 {
+				errs.AppendError(errorhandling.NewValidationErrorWithDetails(
+					errorhandling.AttributeArgumentError,
+					errorhandling.ErrorDetails{
+						Message: "@pagination requires at least one argument",
+						Hint:    "For example, @pagination(mode: offset, maxPageSize: 100)",
+					},
+					attribute,
+				))
+			}
+		},
+		LeaveAttribute: func(_ *parser.AttributeNode) {
+			currentAttribute = nil
+			argumentLabels = []string{}
+		},
+		EnterAttributeArgument: func(arg *parser.AttributeArgumentNode) {
+			if currentAttribute == nil || currentAttribute.Name.Value != parser.AttributePagination {
+				return
+			}
+
+			if currentOperation == nil && currentAPI == nil {
+				return
+			}
+
+			allowedLabels := []string{parser.PaginationArgMode, parser.PaginationArgMaxPageSize}
+			hint := "For example, @pagination(mode: offset, maxPageSize: 100)"
+			if currentOperation == nil {
+				allowedLabels = []string{parser.PaginationArgMaxPageSize}
+				hint = "For example, @pagination(maxPageSize: 100)"
+			}
+
+			if arg.Label == nil || !lo.Contains(allowedLabels, arg.Label.Value) {
+				errs.AppendError(errorhandling.NewValidationErrorWithDetails(
+					errorhandling.AttributeArgumentError,
+					errorhandling.ErrorDetails{
+						Message: fmt.Sprintf("@pagination arguments must be labelled with %s", strings.Join(allowedLabels, " or ")),
+						Hint:    hint,
+					},
+					arg,
+				))
+				return
+			}
+
+			if lo.Contains(argumentLabels, arg.Label.Value) {
+				errs.AppendError(errorhandling.NewValidationErrorWithDetails(
+					errorhandling.AttributeArgumentError,
+					errorhandling.ErrorDetails{
+						Message: fmt.Sprintf("@pagination argument '%s' already defined", arg.Label.Value),
+					},
+					arg.Label,
+				))
+				return
+			}
+
+			argumentLabels = append(argumentLabels, arg.Label.Value)
+
+			operand, err := arg.Expression.ToValue()
+			if err != nil {
+				errs.AppendError(errorhandling.NewValidationErrorWithDetails(
+					errorhandling.AttributeArgumentError,
+					errorhandling.ErrorDetails{
+						Message: "@pagination argument is not correctly formatted",
+						Hint:    hint,
+					},
+					arg,
+				))
+				return
+			}
+
+			switch arg.Label.Value {
+			case parser.PaginationArgMode:
+				if operand.Ident == nil || len(operand.Ident.Fragments) != 1 ||
+					(operand.Ident.Fragments[0].Fragment != parser.PaginationModeCursor && operand.Ident.Fragments[0].Fragment != parser.PaginationModeOffset) {
+					errs.AppendError(errorhandling.NewValidationErrorWithDetails(
+						errorhandling.AttributeArgumentError,
+						errorhandling.ErrorDetails{
+							Message: "@pagination mode must either be cursor or offset",
+							Hint:    hint,
+						},
+						arg.Expression,
+					))
+				}
+			case parser.PaginationArgMaxPageSize:
+				if operand.Number == nil || *operand.Number < 1 {
+					errs.AppendError(errorhandling.NewValidationErrorWithDetails(
+						errorhandling.AttributeArgumentError,
+						errorhandling.ErrorDetails{
+							Message: "@pagination maxPageSize must be a whole number greater than zero",
+							Hint:    hint,
+						},
+						arg.Expression,
+					))
+				}
+			}
+		},
+	}
+}
@@ -17,6 +17,7 @@ func PermissionsAttributeArguments(asts []*parser.AST, errs *errorhandling.Valid
 	var model *parser.ModelNode
 	var action *parser.ActionNode
 	var job *parser.JobNode
+	var field *parser.FieldNode
 
 	return Visitor{
 		EnterModel: func(m *parser.ModelNode) {
@@ -31,6 +32,12 @@ func PermissionsAttributeArguments(asts []*parser.AST, errs *errorhandling.Valid
 		LeaveAction: func(_ *parser.ActionNode) {
 			action = nil
 		},
+		EnterField: func(f *parser.FieldNode) {
+			field = f
+		},
+		LeaveField: func(_ *parser.FieldNode) {
+			field = nil
+		},
 		EnterJob: func(j *parser.JobNode) {
 			job = j
 		},
@@ -46,6 +53,21 @@ func PermissionsAttributeArguments(asts []*parser.AST, errs *errorhandling.Valid
 			hasExpression := false
 			hasRoles := false
 
+			// A @permission on a model field restricts who can read and write the field
+			isField := model != nil && field != nil
+
+			if isField && query.Model(asts, field.Type.Value) != nil {
+				errs.AppendError(errorhandling.NewValidationErrorWithDetails(
+					errorhandling.AttributeArgumentError,
+					errorhandling.ErrorDetails{
+						Message: "@permission cannot be used on relationship fields",
+						Hint:    "use @permission on the related model instead",
+					},
+					attr.Name,
+				))
+				return
+			}
+
 			for _, arg := range attr.Arguments {
 				if arg.Label == nil || arg.Label.Value == "" {
 					errs.AppendError(errorhandling.NewValidationErrorWithDetails(
@@ -76,6 +98,14 @@ func PermissionsAttributeArguments(asts []*parser.AST, errs *errorhandling.Valid
 						continue
 					}
 
+					if isField {
+						errs.Concat(validateIdentArray(arg.Expression, []string{
+							parser.ActionTypeRead,
+							parser.ActionTypeWrite,
+						}, "valid field permission type"))
+						continue
+					}
+
 					errs.Concat(validateIdentArray(arg.Expression, []string{
 						parser.ActionTypeGet,
 						parser.ActionTypeCreate,
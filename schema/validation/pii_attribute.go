@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"github.com/teamkeel/keel/schema/parser"
+	"github.com/teamkeel/keel/schema/query"
+	"github.com/teamkeel/keel/schema/validation/errorhandling"
+)
+
+// PiiAttributeRule validates that @pii attributes are valid according to the following rules:
+// - @pii doesn't take any arguments
+// - @pii can't be used on relationship fields
+// - @pii can only be used on a required field if it is a Text or Markdown field, as the value
+// of the field is anonymised when erasing an identity's data
+// - @pii can't be used on a required unique field
+func PiiAttributeRule(asts []*parser.AST, errs *errorhandling.ValidationErrors) Visitor {
+	var currentField *parser.FieldNode
+
+	return Visitor{
+		EnterField: func(f *parser.FieldNode) {
+			currentField = f
+		},
+		LeaveField: func(f *parser.FieldNode) {
+			currentField = nil
+		},
+		EnterAttribute: func(attr *parser.AttributeNode) {
+			if attr.Name.Value != parser.AttributePii {
+				return
+			}
+
+			if len(attr.Arguments) > 0 {
+				errs.AppendError(errorhandling.NewValidationErrorWithDetails(
+					errorhandling.AttributeArgumentError,
+					errorhandling.ErrorDetails{
+						Message: "@pii does not take any arguments",
+					},
+					attr,
+				))
+				return
+			}
+
+			if currentField == nil {
+				return
+			}
+
+			if query.Model(asts, currentField.Type.Value) != nil {
+				errs.AppendError(errorhandling.NewValidationErrorWithDetails(
+					errorhandling.AttributeNotAllowedError,
+					errorhandling.ErrorDetails{
+						Message: "@pii cannot be used on relationship fields",
+						Hint:    "to erase the related records, use @pii on the related model instead",
+					},
+					attr.Name,
+				))
+				return
+			}
+
+			if currentField.Optional {
+				return
+			}
+
+			switch {
+			case currentField.Repeated || (currentField.Type.Value != parser.FieldTypeText && currentField.Type.Value != parser.FieldTypeMarkdown):
+				errs.AppendError(errorhandling.NewValidationErrorWithDetails(
+					errorhandling.AttributeNotAllowedError,
+					errorhandling.ErrorDetails{
+						Message: "@pii can only be used on optional fields, or on Text and Markdown fields",
+						Hint:    "make the field optional so that its value can be removed",
+					},
+					attr.Name,
+				))
+			case query.FieldIsUnique(currentField):
+				errs.AppendError(errorhandling.NewValidationErrorWithDetails(
+					errorhandling.AttributeNotAllowedError,
+					errorhandling.ErrorDetails{
+						Message: "@pii cannot be used on a required unique field",
+						Hint:    "make the field optional so that its value can be removed",
+					},
+					attr.Name,
+				))
+			}
+		},
+	}
+}
@@ -60,12 +60,15 @@ var attributeLocations = map[string][]string{
 		parser.AttributePermission,
 		parser.AttributeUnique,
 		parser.AttributeOn,
+		parser.AttributePii,
 	},
 	parser.KeywordField: {
 		parser.AttributeUnique,
 		parser.AttributeDefault,
 		parser.AttributePrimaryKey,
 		parser.AttributeRelation,
+		parser.AttributePermission,
+		parser.AttributePii,
 	},
 	parser.KeywordActions: {
 		parser.AttributeSet,
@@ -76,11 +79,15 @@ var attributeLocations = map[string][]string{
 		parser.AttributeSortable,
 		parser.AttributeFunction,
 		parser.AttributeEmbed,
+		parser.AttributePagination,
 	},
 	parser.KeywordJob: {
 		parser.AttributePermission,
 		parser.AttributeSchedule,
 	},
+	parser.KeywordApi: {
+		parser.AttributePagination,
+	},
 }
 
 func checkAttributes(attributes []*parser.AttributeNode, definedOn string, parentName string) (errs errorhandling.ValidationErrors) {
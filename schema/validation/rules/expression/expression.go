@@ -2,6 +2,7 @@ package expression
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/samber/lo"
 	"github.com/teamkeel/keel/schema/expressions"
@@ -563,5 +564,37 @@ func runSideEffectOperandRules(asts []*parser.AST, condition *parser.Condition,
 
 	errors = append(errors, InvalidOperatorForOperandsRule(asts, condition, context, permittedOperators)...)
 
+	if len(errors) > 0 {
+		return errors
+	}
+
+	errors = append(errors, MatchesPatternRule(asts, condition, context)...)
+
+	return errors
+}
+
+// MatchesPatternRule validates that a literal pattern used with the matches operator is valid,
+// and only uses the regular expression syntax which is supported.
+func MatchesPatternRule(asts []*parser.AST, condition *parser.Condition, context expressions.ExpressionContext) (errors []error) {
+	if condition.Operator == nil || condition.Operator.Symbol != parser.OperatorMatches || condition.RHS == nil || condition.RHS.String == nil {
+		return nil
+	}
+
+	pattern := strings.TrimSuffix(strings.TrimPrefix(*condition.RHS.String, `"`), `"`)
+
+	err := expressions.ValidatePattern(pattern)
+	if err != nil {
+		errors = append(errors,
+			errorhandling.NewValidationErrorWithDetails(
+				errorhandling.TypeError,
+				errorhandling.ErrorDetails{
+					Message: err.Error(),
+					Hint:    "patterns support RE2 syntax without flags, word boundaries, Unicode classes, or hex and octal escapes",
+				},
+				condition.RHS,
+			),
+		)
+	}
+
 	return errors
 }
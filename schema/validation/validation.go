@@ -75,6 +75,7 @@ var visitorFuncs = []VisitorFunc{
 	UniqueAttributeRule,
 	OrderByAttributeRule,
 	SortableAttributeRule,
+	PaginationAttributeRule,
 	SetAttributeExpressionRules,
 	Jobs,
 	MessagesRule,
@@ -84,6 +85,7 @@ var visitorFuncs = []VisitorFunc{
 	FunctionDisallowedBehavioursRule,
 	OnAttributeRule,
 	EmbedAttributeRule,
+	PiiAttributeRule,
 	RelationshipsRules,
 	ApiModelActions,
 	StudioFeatures,
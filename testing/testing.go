@@ -23,7 +23,6 @@ import (
 	"github.com/teamkeel/keel/runtime"
 	"github.com/teamkeel/keel/runtime/actions"
 	"github.com/teamkeel/keel/runtime/apis/httpjson"
-	"github.com/teamkeel/keel/runtime/auth"
 	"github.com/teamkeel/keel/runtime/runtimectx"
 	"github.com/teamkeel/keel/schema"
 	"github.com/teamkeel/keel/storage"
@@ -317,15 +316,11 @@ func HandleJobExecutorRequest(ctx context.Context, schema *proto.Schema, jobName
 		return err
 	}
 
-	identity, err := actions.HandleAuthorizationHeader(ctx, schema, r.Header)
+	ctx, err = actions.HandleAuthorizationHeader(ctx, schema, r.Header)
 	if err != nil {
 		return err
 	}
 
-	if identity != nil {
-		ctx = auth.WithIdentity(ctx, identity)
-	}
-
 	var inputs map[string]any
 	// if no json body has been sent, just return an empty map for the inputs
 	if string(body) == "" {
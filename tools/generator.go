@@ -98,8 +98,8 @@ func (g *Generator) scaffoldTools() {
 				Action: action,
 			}
 
-			// List actions have pagination
-			if action.IsList() {
+			// List actions have pagination, which tools support using cursors
+			if action.IsList() && action.GetPaginationMode() == proto.PaginationMode_PAGINATION_MODE_CURSOR {
 				t.Config.Pagination = &toolsproto.CursorPaginationConfig{
 					Start: &toolsproto.CursorPaginationConfig_FieldConfig{
 						RequestInput:  "after",
@@ -402,6 +402,11 @@ func (g *Generator) makeInputsForMessage(actionType proto.ActionType, msg *proto
 	fields := []*toolsproto.RequestFieldConfig{}
 
 	for i, f := range msg.GetFields() {
+		// Nested filter groups are self-referencing and are not configurable as tool inputs.
+		if f.IsFilterGroup() {
+			continue
+		}
+
 		if f.IsMessage() {
 			submsg := g.Schema.FindMessage(f.Type.MessageName.Value)
 			if submsg == nil {
@@ -450,6 +455,14 @@ func (g *Generator) makeInputsForMessage(actionType proto.ActionType, msg *proto
 			config.EnumName = &f.Type.EnumName.Value
 		}
 
+		// Inputs which write to a field of the action's model with write permission rules
+		isValuesInput := (actionType == proto.ActionType_ACTION_TYPE_CREATE && pathPrefix == "") || (actionType == proto.ActionType_ACTION_TYPE_UPDATE && pathPrefix == ".values")
+		if isValuesInput && len(f.Target) == 1 && f.Type.ModelName != nil && f.Type.FieldName != nil {
+			if field := proto.FindField(g.Schema.Models, f.Type.ModelName.Value, f.Type.FieldName.Value); field != nil {
+				config.WriteRestricted = len(field.WritePermissions()) > 0
+			}
+		}
+
 		if f.Type.ModelName != nil && f.Type.FieldName != nil && proto.FindField(g.Schema.Models, f.Type.ModelName.Value, f.Type.FieldName.Value).Unique {
 			// generate action link placeholders
 			if lookupToolsIDs := g.findListTools(f.Type.ModelName.Value); len(lookupToolsIDs) > 0 {
@@ -612,8 +625,9 @@ func (g *Generator) makeResponsesForModel(model *proto.Model, pathPrefix string,
 				}
 				return casing.ToSentenceCase(f.Name)
 			}(),
-			Visible:      true,
-			DisplayOrder: computeFieldOrder(&order, len(model.GetFields()), f.Name),
+			Visible:        true,
+			DisplayOrder:   computeFieldOrder(&order, len(model.GetFields()), f.Name),
+			ReadRestricted: len(f.ReadPermissions()) > 0,
 			Sortable: func() bool {
 				for _, fn := range sortableFields {
 					if fn == f.Name {